@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/spf13/cobra"
+)
+
+const defaultArchiveOlderThan = "14d"
+
+var errArchiveOlderThanInvalid = errors.New(`age needs to be a positive number of days or weeks (eg. "14d", "4w")`)
+
+// newArchiveCmd creates the archive command
+func newArchiveCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	olderThan *string,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive",
+		Short: "Archive tasks with no recent task log entries",
+		Long: `Archive active tasks that have no task log entries that ended within a
+given number of days or weeks (eg. "--older-than 30d", or "--older-than 4w").
+
+This is the same as the "archive-stale" action in the TUI, but with a
+configurable cutoff. The task being tracked, and tasks created within the
+cutoff, are never archived.
+`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			age, err := parseArchiveAge(*olderThan)
+			if err != nil {
+				return err
+			}
+
+			count, err := pers.ArchiveStaleTasks(*db, time.Now().AddDate(0, 0, -age), true)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Archived %d task(s)\n", count)
+			return nil
+		},
+	}
+}
+
+// parseArchiveAge returns the number of days in value, which is a positive
+// number followed by "d" (days) or "w" (weeks).
+func parseArchiveAge(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return 0, fmt.Errorf("%w: %q", errArchiveOlderThanInvalid, value)
+	}
+
+	var daysPerUnit int
+	switch value[len(value)-1] {
+	case 'd':
+		daysPerUnit = 1
+	case 'w':
+		daysPerUnit = 7
+	default:
+		return 0, fmt.Errorf("%w: %q", errArchiveOlderThanInvalid, value)
+	}
+
+	num, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || num <= 0 {
+		return 0, fmt.Errorf("%w: %q", errArchiveOlderThanInvalid, value)
+	}
+
+	return num * daysPerUnit, nil
+}
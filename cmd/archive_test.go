@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArchiveAge(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected int
+	}{
+		{value: "14d", expected: 14},
+		{value: "4w", expected: 28},
+		{value: " 30D ", expected: 30},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseArchiveAge(tt.value)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	for _, value := range []string{"", "d", "14", "14h", "0d", "-2w", "two weeks"} {
+		t.Run("invalid "+value, func(t *testing.T) {
+			_, err := parseArchiveAge(value)
+
+			assert.ErrorIs(t, err, errArchiveOlderThanInvalid)
+		})
+	}
+}
+
+func TestNewArchiveCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newArchiveCmd(nil, mockPreRun, new(string))
+
+		assert.Equal(t, "archive", cmd.Use)
+		assert.Equal(t, "Archive tasks with no recent task log entries", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("archives tasks with no entries within the cutoff", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		now := time.Now()
+
+		recentID, err := persistence.InsertTask(db, "recent")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db, recentID, now.AddDate(0, 0, -10).Add(-time.Hour), now.AddDate(0, 0, -10), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		staleID, err := persistence.InsertTask(db, "stale")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db, staleID, now.AddDate(0, 0, -40).Add(-time.Hour), now.AddDate(0, 0, -40), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		trackedID, err := persistence.InsertTask(db, "tracked")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, trackedID, now.AddDate(0, 0, -60))
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE task SET created_at = ?", now.AddDate(0, 0, -90).UTC())
+		require.NoError(t, err)
+
+		olderThan := "30d"
+		cmd := newArchiveCmd(&db, mockPreRun, &olderThan)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Archived 1 task(s)\n", out.String())
+		recent, err := persistence.FetchTaskByID(db, recentID)
+		require.NoError(t, err)
+		assert.True(t, recent.Active)
+		stale, err := persistence.FetchTaskByID(db, staleID)
+		require.NoError(t, err)
+		assert.False(t, stale.Active)
+		tracked, err := persistence.FetchTaskByID(db, trackedID)
+		require.NoError(t, err)
+		assert.True(t, tracked.Active)
+	})
+
+	t.Run("shorter cutoff archives more tasks", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		now := time.Now()
+
+		taskID, err := persistence.InsertTask(db, "recent")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db, taskID, now.AddDate(0, 0, -10).Add(-time.Hour), now.AddDate(0, 0, -10), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE task SET created_at = ?", now.AddDate(0, 0, -90).UTC())
+		require.NoError(t, err)
+
+		olderThan := "1w"
+		cmd := newArchiveCmd(&db, mockPreRun, &olderThan)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Archived 1 task(s)\n", out.String())
+	})
+
+	t.Run("leaves recently created tasks alone", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		taskID, err := persistence.InsertTask(db, "new")
+		require.NoError(t, err)
+
+		olderThan := "14d"
+		cmd := newArchiveCmd(&db, mockPreRun, &olderThan)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Archived 0 task(s)\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.True(t, task.Active)
+	})
+
+	t.Run("fails for an invalid cutoff", func(t *testing.T) {
+		olderThan := "soon"
+		cmd := newArchiveCmd(nil, mockPreRun, &olderThan)
+
+		err := cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, errArchiveOlderThanInvalid)
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 	"github.com/dhth/hours/internal/ui"
 	"github.com/spf13/cobra"
@@ -42,6 +45,87 @@ func resolvePeriodAndRange(
 	return period, dateRange, nil
 }
 
+// defaultPeriod returns the period to use when none is passed to cmd: the one
+// last used with it, or fallback if none has been saved yet.
+func defaultPeriod(db *sql.DB, cmd *cobra.Command, args []string, fallback string) (string, error) {
+	if len(args) > 0 {
+		return fallback, nil
+	}
+
+	period, err := pers.GetLastPeriod(db, cmd.Name())
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntGetLastPeriod, err.Error())
+	}
+
+	if period == "" {
+		return fallback, nil
+	}
+
+	return period, nil
+}
+
+// rememberPeriod returns a hook that saves the period passed to a command, so
+// that it's used the next time the command is run without one.
+func rememberPeriod(db **sql.DB, noRemember *bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if *noRemember || len(args) == 0 {
+			return nil
+		}
+
+		if err := pers.SetLastPeriod(*db, cmd.Name(), args[0]); err != nil {
+			return fmt.Errorf("%w: %s", errCouldntSaveLastPeriod, err.Error())
+		}
+
+		return nil
+	}
+}
+
+// sinceCutoffPeriod is the period label used for --since-cutoff
+const sinceCutoffPeriod = "since-cutoff"
+
+// resolveSinceCutoff returns the period label and date range for
+// --since-cutoff, which can't be combined with a period argument.
+func resolveSinceCutoff(cmd *cobra.Command, args []string, dayCutoffStr string) (string, types.DateRange, error) {
+	if len(args) > 0 {
+		return "", types.DateRange{}, errSinceCutoffWithPeriod
+	}
+
+	cutoff, err := resolveDayCutoff(cmd, dayCutoffStr)
+	if err != nil {
+		return "", types.DateRange{}, err
+	}
+
+	return sinceCutoffPeriod, getSinceCutoffDateRange(types.RealTimeProvider{}.Now(), cutoff), nil
+}
+
+// resolveSinceUntil returns the date range for --since and --until, which
+// can't be combined with a period argument or --since-cutoff.
+func resolveSinceUntil(args []string, since, until string, sinceCutoff bool) (types.DateRange, error) {
+	switch {
+	case len(args) > 0:
+		return types.DateRange{}, errSinceUntilWithPeriod
+	case sinceCutoff:
+		return types.DateRange{}, errSinceUntilWithSinceCutoff
+	case since == "":
+		return types.DateRange{}, errUntilWithoutSince
+	}
+
+	return types.GetDateRangeFromSinceUntil(since, until, types.RealTimeProvider{}.Now(), nil)
+}
+
+// getHeaderMeta returns the details to prepend to the output of a records
+// command when enabled is true, and nil otherwise.
+func getHeaderMeta(cmd *cobra.Command, period string, enabled bool) *ui.HeaderMeta {
+	if !enabled {
+		return nil
+	}
+
+	return &ui.HeaderMeta{
+		Command:     effectiveCommand(cmd, period),
+		GeneratedAt: time.Now(),
+	}
+}
+
 // newGenerateCmd creates the generate command (gen)
 func newGenerateCmd(
 	db **sql.DB,
@@ -51,6 +135,7 @@ func newGenerateCmd(
 	genNumDays *uint8,
 	genNumTasks *uint8,
 	genSkipConfirmation *bool,
+	genDryRun *bool,
 ) *cobra.Command {
 	return &cobra.Command{
 		Use:   "gen",
@@ -59,9 +144,19 @@ func newGenerateCmd(
 This is intended for new users of 'hours' so they can get a sense of its
 capabilities without actually tracking any time. It's recommended to always use
 this with a --dbpath/-d flag that points to a throwaway database.
+
+--dry-run prints how many tasks and task log entries would be generated,
+without opening the database at all.
 `,
-		PreRunE: preRun,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// a dry run doesn't need the database, which would otherwise be
+			// created if it doesn't exist yet
+			if *genDryRun {
+				return nil
+			}
+			return preRun(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			if *genNumDays > genNumDaysThreshold {
 				return fmt.Errorf("%w (%d)", errNumDaysExceedsThreshold, genNumDaysThreshold)
 			}
@@ -69,6 +164,17 @@ this with a --dbpath/-d flag that points to a throwaway database.
 				return fmt.Errorf("%w (%d)", errNumTasksExceedsThreshold, genNumTasksThreshold)
 			}
 
+			if *genDryRun {
+				estimate := ui.EstimateGeneratedData(*genNumDays, *genNumTasks)
+				fmt.Fprintf(cmd.OutOrStdout(), `Dry run: nothing was written to the database file: %s
+
+'gen' would create:
+- %d tasks
+- %d to %d task log entries, spread over the last %d days
+`, *dbPath, estimate.NumTasks, estimate.MinNumTLs, estimate.MaxNumTLs, *genNumDays)
+				return nil
+			}
+
 			if !*genSkipConfirmation {
 				fmt.Print(lipgloss.NewStyle().Foreground(lipgloss.Color(warningColor)).Render(`
 WARNING: You shouldn't run 'gen' on hours' actively used database as it'll
@@ -113,15 +219,83 @@ hours --dbpath=%s stats today -i
 	}
 }
 
+// recordsOptions holds the flags shared by the report, log, and stats commands
+type recordsOptions struct {
+	interactive   bool
+	plain         bool
+	taskStatusStr string
+	tag           string
+	taskID        int
+	headerMeta    bool
+	noRemember    bool
+}
+
+// filter returns the task log filter described by the flags.
+func (o recordsOptions) filter(taskStatus types.TaskStatus) pers.TLFilter {
+	return pers.TLFilter{
+		TaskStatus: taskStatus,
+		Tag:        o.tag,
+		TaskID:     taskIDFilter(o.taskID),
+	}
+}
+
+// uiOptions returns the options for rendering records described by the flags.
+func (o recordsOptions) uiOptions(cmd *cobra.Command, period string, taskStatus types.TaskStatus, limit int) ui.RecordsOptions {
+	return ui.RecordsOptions{
+		Plain:       o.plain,
+		Period:      period,
+		Filter:      o.filter(taskStatus),
+		Limit:       limit,
+		Interactive: o.interactive,
+		HeaderMeta:  getHeaderMeta(cmd, period, o.headerMeta),
+	}
+}
+
+// reportOptions holds the flags of the report command
+type reportOptions struct {
+	recordsOptions
+	agg          bool
+	logTag       string
+	format       string
+	delimiter    string
+	limit        int
+	firstDayOnly bool
+	lastDayOnly  bool
+}
+
+// logOptions holds the flags of the log command
+type logOptions struct {
+	recordsOptions
+	limit        int
+	timesheet    bool
+	sinceCutoff  bool
+	dayCutoffStr string
+	since        string
+	until        string
+}
+
+// statsOptions holds the flags of the stats command
+type statsOptions struct {
+	recordsOptions
+	limit        int
+	sinceCutoff  bool
+	dayCutoffStr string
+	extremes     bool
+	includeZero  bool
+	json         bool
+	calendar     bool
+	sparkline    bool
+	groupBy      string
+	byCategory   bool
+}
+
 // newReportCmd creates the report command
 func newReportCmd(
 	db **sql.DB,
 	preRun func(cmd *cobra.Command, args []string) error,
 	style *ui.Style,
-	reportAgg *bool,
-	recordsInteractive *bool,
-	recordsOutputPlain *bool,
-	taskStatusStr *string,
+	cfg *config,
+	opts *reportOptions,
 ) *cobra.Command {
 	return &cobra.Command{
 		Use:   "report [PERIOD]",
@@ -134,31 +308,113 @@ cumulative time spent on each task per day.
 
 Accepts an argument, which can be one of the following:
 
-  today      for today's report
-  yest       for yesterday's report
-  3d         for a report on the last 3 days (default)
-  week       for a report on the current week
-  date       for a report for a specific date (eg. "2024/06/08")
-  range      for a report for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08..."; shouldn't be greater than %d days)
+  today       for today's report
+  yest        for yesterday's report
+  3d          for a report on the last 3 days (default)
+  week        for a report on the current week
+  date        for a report for a specific date (eg. "2024/06/08")
+  range       for a report for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
+
+Note: A report can't span more than %d days; longer periods (eg. "month" or
+"last-month") are rejected; use "log" or "stats" for those.
 
 Note: If a task log continues past midnight in your local timezone, it
 will be reported on the day it ends.
+
+Note: The task log that's currently being tracked is never included in
+reports; --no-active can be passed to state this explicitly.
+
+Note: --tag filters by tags on tasks, whereas --log-tag filters by tags on
+individual task log entries (added when saving an entry in the TUI).
+
+Note: --task only shows entries for the task with the given ID.
+
+Note: "--format md" outputs the report as a GitHub flavored Markdown table,
+which is handy for pasting into standup notes or wikis.
+
+Note: "--format csv" outputs a row with the time spent (in seconds) on each
+task on each day, followed by a row with the total, for importing into a
+spreadsheet. --delimiter changes the character values are separated with (eg.
+";" for locales that use a comma as the decimal separator). --header-meta is
+ignored for this format. If any day has more entries than --limit, the command
+fails rather than leaving entries out of the CSV.
+
+Note: At most --limit entries are shown for a single day; a notice is
+printed below the report when entries were left out.
+
+Note: --first-day-only/--last-day-only only show the entries for the first/last
+day of the period, while keeping a column for every day in it; this is handy
+for checking which day task logs that span midnight are reported on.
 `, reportNumDaysThreshold),
-		Args:    cobra.MaximumNArgs(1),
-		PreRunE: preRun,
-		RunE: func(_ *cobra.Command, args []string) error {
-			taskStatus, err := types.ParseTaskStatus(*taskStatusStr)
+		Args:     cobra.MaximumNArgs(1),
+		PreRunE:  preRun,
+		PostRunE: rememberPeriod(db, &opts.noRemember),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskStatus, err := types.ParseTaskStatus(opts.taskStatusStr)
+			if err != nil {
+				return err
+			}
+
+			if opts.limit <= 0 {
+				return fmt.Errorf("%w: %d", errLimitInvalid, opts.limit)
+			}
+
+			fallbackPeriod, err := defaultPeriod(*db, cmd, args, cfg.reportPeriod())
 			if err != nil {
 				return err
 			}
 
 			numDaysUpperBound := reportNumDaysThreshold
-			period, dateRange, err := resolvePeriodAndRange(args, "3d", recordsInteractive, &numDaysUpperBound)
+			period, dateRange, err := resolvePeriodAndRange(args, fallbackPeriod, &opts.interactive, &numDaysUpperBound)
 			if err != nil {
 				return err
 			}
 
-			return ui.RenderReport(*db, *style, os.Stdout, *recordsOutputPlain, dateRange, period, taskStatus, *reportAgg, *recordsInteractive)
+			var reportFormat ui.ReportFormat
+			switch opts.format {
+			case reportFormatTable:
+				reportFormat = ui.ReportFormatTable
+			case reportFormatMarkdown:
+				reportFormat = ui.ReportFormatMarkdown
+			case reportFormatCSV:
+				reportFormat = ui.ReportFormatCSV
+			default:
+				return fmt.Errorf("%w: %q; allowed values: %s, %s, %s", errReportFormatInvalid, opts.format, reportFormatTable, reportFormatMarkdown, reportFormatCSV)
+			}
+
+			csvDelimiter := ','
+			if reportFormat == ui.ReportFormatCSV {
+				csvDelimiter, err = parseReportDelimiter(opts.delimiter)
+				if err != nil {
+					return err
+				}
+			} else if cmd.Flags().Changed("delimiter") {
+				return errReportDelimiterWithFormat
+			}
+
+			dayFilter := ui.ReportAllDays
+			switch {
+			case opts.firstDayOnly && opts.lastDayOnly:
+				return errDayFilterAmbiguous
+			case (opts.firstDayOnly || opts.lastDayOnly) && opts.interactive:
+				return errDayFilterInteractive
+			case opts.firstDayOnly:
+				dayFilter = ui.ReportFirstDayOnly
+			case opts.lastDayOnly:
+				dayFilter = ui.ReportLastDayOnly
+			}
+
+			recordsOpts := opts.uiOptions(cmd, period, taskStatus, opts.limit)
+			recordsOpts.Filter.LogTag = opts.logTag
+
+			return ui.RenderReport(*db, *style, os.Stdout, ui.ReportOptions{
+				RecordsOptions: recordsOpts,
+				DateRange:      dateRange,
+				DayFilter:      dayFilter,
+				Agg:            opts.agg,
+				Format:         reportFormat,
+				CSVDelimiter:   csvDelimiter,
+			})
 		},
 	}
 }
@@ -168,9 +424,8 @@ func newLogCmd(
 	db **sql.DB,
 	preRun func(cmd *cobra.Command, args []string) error,
 	style *ui.Style,
-	recordsInteractive *bool,
-	recordsOutputPlain *bool,
-	taskStatusStr *string,
+	cfg *config,
+	opts *logOptions,
 ) *cobra.Command {
 	return &cobra.Command{
 		Use:   "log [PERIOD]",
@@ -179,30 +434,74 @@ func newLogCmd(
 
 Accepts an argument, which can be one of the following:
 
-  today      for log entries from today (default)
-  yest       for log entries from yesterday
-  3d         for log entries from the last 3 days
-  week       for log entries from the current week
-  date       for log entries from a specific date (eg. "2024/06/08")
-  range      for log entries for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
+  today       for log entries from today (default)
+  yest        for log entries from yesterday
+  3d          for log entries from the last 3 days
+  week        for log entries from the current week
+  month       for log entries from the current month (alias: this-month)
+  last-month  for log entries from the previous month
+  date        for log entries from a specific date (eg. "2024/06/08")
+  range       for log entries for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
+
+Alternatively, --since-cutoff shows log entries since the most recent day
+cutoff (set via --day-cutoff), which is useful if your day doesn't end at
+midnight.
+
+Instead of a period, --since (and optionally --until, which defaults to now)
+can be provided, each as a date (eg. "2024/06/08") or a date and a time (eg.
+"2024/06/08 14:30"). A date on its own includes that entire day for --until.
 
 Note: If a task log continues past midnight in your local timezone, it'll
 appear in the log for the day it ends.
+
+Note: At most --limit entries are shown; a notice is printed below the log
+when entries were left out.
+
+--timesheet prints each entry as a line of plain text instead (eg.
+"09:00–10:30 (1h30m) Task: comment"), in the order they began, which is handy
+for pasting into a journal.
 `,
-		Args:    cobra.MaximumNArgs(1),
-		PreRunE: preRun,
-		RunE: func(_ *cobra.Command, args []string) error {
-			taskStatus, err := types.ParseTaskStatus(*taskStatusStr)
+		Args:     cobra.MaximumNArgs(1),
+		PreRunE:  preRun,
+		PostRunE: rememberPeriod(db, &opts.noRemember),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskStatus, err := types.ParseTaskStatus(opts.taskStatusStr)
 			if err != nil {
 				return err
 			}
 
-			period, dateRange, err := resolvePeriodAndRange(args, "today", recordsInteractive, nil)
+			if opts.limit <= 0 {
+				return fmt.Errorf("%w: %d", errLimitInvalid, opts.limit)
+			}
+
+			if opts.timesheet && opts.interactive {
+				return errTimesheetInteractive
+			}
+
+			var period string
+			var dateRange types.DateRange
+			switch {
+			case opts.since != "" || opts.until != "":
+				dateRange, err = resolveSinceUntil(args, opts.since, opts.until, opts.sinceCutoff)
+			case opts.sinceCutoff:
+				period, dateRange, err = resolveSinceCutoff(cmd, args, opts.dayCutoffStr)
+			default:
+				var fallbackPeriod string
+				fallbackPeriod, err = defaultPeriod(*db, cmd, args, cfg.logPeriod())
+				if err != nil {
+					return err
+				}
+				period, dateRange, err = resolvePeriodAndRange(args, fallbackPeriod, &opts.interactive, nil)
+			}
 			if err != nil {
 				return err
 			}
 
-			return ui.RenderTaskLog(*db, *style, os.Stdout, *recordsOutputPlain, dateRange, period, taskStatus, *recordsInteractive)
+			return ui.RenderTaskLog(*db, *style, os.Stdout, ui.LogOptions{
+				RecordsOptions: opts.uiOptions(cmd, period, taskStatus, opts.limit),
+				DateRange:      dateRange,
+				Timesheet:      opts.timesheet,
+			})
 		},
 	}
 }
@@ -212,9 +511,8 @@ func newStatsCmd(
 	db **sql.DB,
 	preRun func(cmd *cobra.Command, args []string) error,
 	style *ui.Style,
-	recordsInteractive *bool,
-	recordsOutputPlain *bool,
-	taskStatusStr *string,
+	cfg *config,
+	opts *statsOptions,
 ) *cobra.Command {
 	return &cobra.Command{
 		Use:   "stats [PERIOD]",
@@ -223,43 +521,170 @@ func newStatsCmd(
 
 Accepts an argument, which can be one of the following:
 
-  today       show stats for today
-  yest        show stats for yesterday
-  3d          show stats for the last 3 days (default)
-  week        show stats for the current week
-  this-month  show stats for the current month
-  date        show stats for a specific date (eg. "2024/06/08")
-  range       show stats for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
-  all         show stats for all log entries
+  today         show stats for today
+  yest          show stats for yesterday
+  3d            show stats for the last 3 days (default)
+  week          show stats for the current week
+  this-month    show stats for the current month (alias: month)
+  last-month    show stats for the previous month
+  this-quarter  show stats for the current quarter
+  last-quarter  show stats for the previous quarter
+  this-year     show stats for the current year
+  date          show stats for a specific date (eg. "2024/06/08")
+  range         show stats for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
+  all           show stats for all log entries
+
+Alternatively, --since-cutoff shows stats since the most recent day cutoff
+(set via --day-cutoff), which is useful if your day doesn't end at midnight.
+
+--extremes shows the busiest and the quietest day in the period instead.
+Days with no time tracked are skipped, unless --include-zero is set.
+
+--json outputs the stats as a JSON array of objects with the fields "taskId",
+"summary", "numEntries", and "secsSpent", ordered by task ID.
+
+--calendar shows a week by week calendar of the period instead, with each day
+shaded according to the time tracked on it.
+
+--sparkline adds a line below the stats with a glyph for each day in the
+period, scaled to the day with the most time tracked.
+
+--group-by shows the stats for each day, week (starting on Monday), or month
+in the period separately (eg. "stats this-quarter --group-by week").
+
+--by-category shows the time tracked for each task log category instead, with
+entries without a category grouped as "uncategorized".
+
+--limit caps the number of tasks shown, keeping the ones with the most time
+tracked; it applies to the default output, --interactive, and --json. In the
+table, the remaining tasks are summed up in a "+N more" row, and the total
+covers all tasks.
+
+Below the stats is the longest streak of consecutive days with any time
+tracked in the period.
 
 Note: If a task log continues past midnight in your local timezone, it'll
 be considered in the stats for the day it ends.
 `,
-		Args:    cobra.MaximumNArgs(1),
-		PreRunE: preRun,
-		RunE: func(_ *cobra.Command, args []string) error {
-			taskStatus, err := types.ParseTaskStatus(*taskStatusStr)
+		Args:     cobra.MaximumNArgs(1),
+		PreRunE:  preRun,
+		PostRunE: rememberPeriod(db, &opts.noRemember),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskStatus, err := types.ParseTaskStatus(opts.taskStatusStr)
 			if err != nil {
 				return err
 			}
 
-			var period string
-			if len(args) == 0 {
-				period = "3d"
-			} else {
-				period = args[0]
+			if opts.limit <= 0 {
+				return fmt.Errorf("%w: %d", errLimitInvalid, opts.limit)
 			}
 
+			var period string
 			var dateRangePtr *types.DateRange
-			if period != "all" {
-				_, dateRange, err := resolvePeriodAndRange(args, "3d", recordsInteractive, nil)
+			var fallbackPeriod string
+			if !opts.sinceCutoff {
+				fallbackPeriod, err = defaultPeriod(*db, cmd, args, cfg.statsPeriod())
+				if err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case opts.sinceCutoff:
+				var dateRange types.DateRange
+				period, dateRange, err = resolveSinceCutoff(cmd, args, opts.dayCutoffStr)
 				if err != nil {
 					return err
 				}
 				dateRangePtr = &dateRange
+			case len(args) > 0 && args[0] == "all", len(args) == 0 && fallbackPeriod == "all":
+				period = "all"
+			default:
+				var dateRange types.DateRange
+				period, dateRange, err = resolvePeriodAndRange(args, fallbackPeriod, &opts.interactive, nil)
+				if err != nil {
+					return err
+				}
+				dateRangePtr = &dateRange
+			}
+
+			if opts.byCategory {
+				if opts.interactive {
+					return errByCategoryInteractive
+				}
+				if opts.calendar || opts.extremes || opts.json || opts.sparkline || opts.groupBy != "" {
+					return errByCategoryWithOtherOutput
+				}
+				if dateRangePtr == nil {
+					return errByCategoryWithAllPeriod
+				}
+				return ui.RenderStatsByCategory(*db, *style, os.Stdout, opts.plain, *dateRangePtr, opts.filter(taskStatus), getHeaderMeta(cmd, period, opts.headerMeta))
+			}
+
+			if opts.groupBy != "" {
+				granularity, err := types.ParseGranularity(opts.groupBy)
+				if err != nil {
+					return fmt.Errorf("%w; allowed values: %s", err, strings.Join(types.ValidGranularityValues, ", "))
+				}
+				if opts.interactive {
+					return errGroupByInteractive
+				}
+				if opts.calendar || opts.extremes || opts.json || opts.sparkline {
+					return errGroupByWithOtherOutput
+				}
+				if dateRangePtr == nil {
+					return errGroupByWithAllPeriod
+				}
+				return ui.RenderStatsGrouped(*db, *style, os.Stdout, opts.plain, *dateRangePtr, opts.filter(taskStatus), granularity, getHeaderMeta(cmd, period, opts.headerMeta))
+			}
+
+			if opts.calendar {
+				if opts.interactive {
+					return errCalendarInteractive
+				}
+				if opts.extremes || opts.json {
+					return errCalendarWithOtherOutput
+				}
+				if dateRangePtr == nil {
+					return errCalendarWithAllPeriod
+				}
+				return ui.RenderCalendar(*db, *style, os.Stdout, opts.plain, *dateRangePtr, opts.filter(taskStatus))
+			}
+
+			if opts.json {
+				if opts.interactive {
+					return errStatsJSONInteractive
+				}
+				if opts.extremes {
+					return errExtremesWithJSON
+				}
+				return ui.RenderStatsJSON(*db, os.Stdout, dateRangePtr, opts.filter(taskStatus), opts.limit)
 			}
 
-			return ui.RenderStats(*db, *style, os.Stdout, *recordsOutputPlain, dateRangePtr, period, taskStatus, *recordsInteractive)
+			if opts.extremes {
+				if opts.interactive {
+					return errExtremesInteractive
+				}
+				if dateRangePtr == nil {
+					return errExtremesWithAllPeriod
+				}
+				return ui.RenderStatsExtremes(*db, *style, os.Stdout, opts.plain, *dateRangePtr, opts.filter(taskStatus), opts.includeZero)
+			}
+
+			if opts.sparkline {
+				if opts.interactive {
+					return errSparklineInteractive
+				}
+				if dateRangePtr == nil {
+					return errSparklineWithAllPeriod
+				}
+			}
+
+			return ui.RenderStats(*db, *style, os.Stdout, ui.StatsOptions{
+				RecordsOptions: opts.uiOptions(cmd, period, taskStatus, opts.limit),
+				DateRange:      dateRangePtr,
+				Sparkline:      opts.sparkline,
+			})
 		},
 	}
 }
@@ -269,6 +694,8 @@ func newActiveCmd(
 	db **sql.DB,
 	preRun func(cmd *cobra.Command, args []string) error,
 	activeTemplate *string,
+	activeJSON *bool,
+	activeExitCode *bool,
 ) *cobra.Command {
 	return &cobra.Command{
 		Use:   "active",
@@ -280,12 +707,37 @@ following placeholders:
 
   {{task}}:  for the task summary
   {{time}}:  for the time spent so far on the active log entry
+  {{week}}:  for the time spent on the task this week (see "set-week-reset")
 
 eg. hours active -t ' {{task}} ({{time}}) '
+
+Alternatively, --json outputs the active task as a JSON object, eg.
+{"active":true,"task":"...","seconds":1234}, or {"active":false} when nothing
+is being tracked.
+
+With --exit-code, the command exits with code 1 (without printing an error)
+when nothing is being tracked.
 `,
 		PreRunE: preRun,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return ui.ShowActiveTask(*db, os.Stdout, *activeTemplate)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var active bool
+			var err error
+			if *activeJSON {
+				active, err = ui.ShowActiveTaskJSON(*db, os.Stdout)
+			} else {
+				active, err = ui.ShowActiveTask(*db, os.Stdout, *activeTemplate)
+			}
+			if err != nil {
+				return err
+			}
+
+			if !active && *activeExitCode {
+				// the exit code is all that's needed here, not an error message
+				cmd.SilenceErrors = true
+				return errNoActiveTask
+			}
+
+			return nil
 		},
 	}
 }
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/dhth/hours/internal/persistence"
@@ -35,6 +37,9 @@ func setupTestDB(t *testing.T) *sql.DB {
 	err = persistence.InitDB(db)
 	require.NoError(t, err)
 
+	err = persistence.UpgradeDB(db, 1)
+	require.NoError(t, err)
+
 	return db
 }
 
@@ -47,7 +52,7 @@ func TestNewGenerateCmd(t *testing.T) {
 		genSkipConfirmation := true
 		var db *sql.DB
 
-		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation)
+		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, new(bool))
 
 		assert.Equal(t, "gen", cmd.Use)
 		assert.Equal(t, "Generate dummy log entries (helpful for beginners)", cmd.Short)
@@ -64,7 +69,7 @@ func TestNewGenerateCmd(t *testing.T) {
 		genSkipConfirmation := true
 		var db *sql.DB
 
-		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation)
+		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, new(bool))
 
 		err := cmd.RunE(cmd, []string{})
 		assert.ErrorIs(t, err, errNumDaysExceedsThreshold)
@@ -78,12 +83,47 @@ func TestNewGenerateCmd(t *testing.T) {
 		genSkipConfirmation := true
 		var db *sql.DB
 
-		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation)
+		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, new(bool))
 
 		err := cmd.RunE(cmd, []string{})
 		assert.ErrorIs(t, err, errNumTasksExceedsThreshold)
 	})
 
+	t.Run("dry run writes nothing and reports what would be generated", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		dbPath := testDBPath
+		dbPathFull := testDBPath
+		genNumDays := uint8(10)
+		genNumTasks := uint8(5)
+		genSkipConfirmation := false
+		genDryRun := true
+		preRunCalled := false
+		preRun := func(_ *cobra.Command, _ []string) error {
+			preRunCalled = true
+			return nil
+		}
+
+		cmd := newGenerateCmd(&db, preRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, &genDryRun)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		require.NoError(t, cmd.PreRunE(cmd, []string{}))
+		err := cmd.RunE(cmd, []string{})
+
+		require.NoError(t, err)
+		assert.False(t, preRunCalled)
+		var numTasks, numTLs int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM task").Scan(&numTasks))
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM task_log").Scan(&numTLs))
+		assert.Zero(t, numTasks)
+		assert.Zero(t, numTLs)
+
+		estimate := ui.EstimateGeneratedData(genNumDays, genNumTasks)
+		assert.Contains(t, buf.String(), fmt.Sprintf("- %d tasks\n", estimate.NumTasks))
+		assert.Contains(t, buf.String(), fmt.Sprintf("- %d to %d task log entries", estimate.MinNumTLs, estimate.MaxNumTLs))
+	})
+
 	t.Run("command has correct thresholds", func(t *testing.T) {
 		// Verify the thresholds are set correctly (they are untyped int constants)
 		assert.Equal(t, 30, genNumDaysThreshold)
@@ -94,13 +134,15 @@ func TestNewGenerateCmd(t *testing.T) {
 func TestNewReportCmd(t *testing.T) {
 	t.Run("command properties", func(t *testing.T) {
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
 
 		assert.Equal(t, "report [PERIOD]", cmd.Use)
 		assert.Equal(t, "Output a report based on task log entries", cmd.Short)
@@ -111,29 +153,117 @@ func TestNewReportCmd(t *testing.T) {
 
 	t.Run("invalid task status", func(t *testing.T) {
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := invalidStatus
 		var db *sql.DB
 
-		cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: invalidStatus,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
 
 		err := cmd.RunE(cmd, []string{})
 		assert.Error(t, err)
 	})
 
+	t.Run("invalid format", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: "html",
+			limit:  ui.DefaultReportLimit,
+		})
+
+		err := cmd.RunE(cmd, []string{"today"})
+		assert.ErrorIs(t, err, errReportFormatInvalid)
+	})
+
+	t.Run("invalid csv delimiter", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format:    reportFormatCSV,
+			delimiter: ";;",
+			limit:     ui.DefaultReportLimit,
+		})
+
+		err := cmd.RunE(cmd, []string{"today"})
+		assert.ErrorIs(t, err, errReportDelimiterInvalid)
+	})
+
+	t.Run("both boundary day filters", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format:       reportFormatTable,
+			limit:        ui.DefaultReportLimit,
+			firstDayOnly: true,
+			lastDayOnly:  true,
+		})
+
+		err := cmd.RunE(cmd, []string{"3d"})
+		assert.ErrorIs(t, err, errDayFilterAmbiguous)
+	})
+
+	t.Run("boundary day filter with interactive", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				interactive:   true,
+				taskStatusStr: testTaskStatus,
+			},
+			format:       reportFormatTable,
+			limit:        ui.DefaultReportLimit,
+			firstDayOnly: true,
+		})
+
+		err := cmd.RunE(cmd, []string{"3d"})
+		assert.ErrorIs(t, err, errDayFilterInteractive)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+		})
+
+		err := cmd.RunE(cmd, []string{"today"})
+		assert.ErrorIs(t, err, errLimitInvalid)
+	})
+
 	t.Run("uses 3d as default period", func(t *testing.T) {
 		// This test verifies the default period logic without executing the command
 		// since we can't run with nil database
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
 
 		// Verify command structure
 		assert.NotNil(t, cmd.RunE)
@@ -144,12 +274,14 @@ func TestNewReportCmd(t *testing.T) {
 func TestNewLogCmd(t *testing.T) {
 	t.Run("command properties", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultLogLimit,
+		})
 
 		assert.Equal(t, "log [PERIOD]", cmd.Use)
 		assert.Equal(t, "Output task log entries", cmd.Short)
@@ -160,12 +292,14 @@ func TestNewLogCmd(t *testing.T) {
 
 	t.Run("invalid task status", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := invalidStatus
 		var db *sql.DB
 
-		cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: invalidStatus,
+			},
+			limit: ui.DefaultLogLimit,
+		})
 
 		err := cmd.RunE(cmd, []string{})
 		assert.Error(t, err)
@@ -173,12 +307,14 @@ func TestNewLogCmd(t *testing.T) {
 
 	t.Run("uses today as default period", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultLogLimit,
+		})
 
 		// Verify command structure
 		assert.NotNil(t, cmd.RunE)
@@ -189,12 +325,14 @@ func TestNewLogCmd(t *testing.T) {
 func TestNewStatsCmd(t *testing.T) {
 	t.Run("command properties", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		assert.Equal(t, "stats [PERIOD]", cmd.Use)
 		assert.Equal(t, "Output statistics for tracked time", cmd.Short)
@@ -205,25 +343,43 @@ func TestNewStatsCmd(t *testing.T) {
 
 	t.Run("invalid task status", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := invalidStatus
 		var db *sql.DB
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: invalidStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		err := cmd.RunE(cmd, []string{})
 		assert.Error(t, err)
 	})
 
+	t.Run("invalid limit", func(t *testing.T) {
+		style := ui.Style{}
+		var db *sql.DB
+
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+		})
+
+		err := cmd.RunE(cmd, []string{"today"})
+		assert.ErrorIs(t, err, errLimitInvalid)
+	})
+
 	t.Run("uses 3d as default period", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := false
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		// Verify command structure
 		assert.NotNil(t, cmd.RunE)
@@ -231,12 +387,70 @@ func TestNewStatsCmd(t *testing.T) {
 	})
 }
 
+func TestLastUsedPeriod(t *testing.T) {
+	newTestReportCmd := func(db *sql.DB, noRemember bool) *cobra.Command {
+		style := ui.Style{}
+
+		return newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+				noRemember:    noRemember,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
+	}
+
+	t.Run("the stored period is used when none is given", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		require.NoError(t, persistence.SetLastPeriod(db, "report", "last-month"))
+
+		cmd := newTestReportCmd(db, false)
+
+		// a month is longer than what a report can span, so this errors only if
+		// the stored period is picked up
+		assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "time period is too large")
+		assert.NoError(t, cmd.RunE(cmd, []string{"today"}))
+	})
+
+	t.Run("the period passed is stored after a successful run", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newTestReportCmd(db, false)
+		args := []string{"yest"}
+		require.NoError(t, cmd.RunE(cmd, args))
+		require.NoError(t, cmd.PostRunE(cmd, args))
+
+		got, err := persistence.GetLastPeriod(db, "report")
+		require.NoError(t, err)
+		assert.Equal(t, "yest", got)
+	})
+
+	t.Run("--no-remember skips storing the period", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		require.NoError(t, persistence.SetLastPeriod(db, "report", "today"))
+
+		cmd := newTestReportCmd(db, true)
+		args := []string{"yest"}
+		require.NoError(t, cmd.RunE(cmd, args))
+		require.NoError(t, cmd.PostRunE(cmd, args))
+
+		got, err := persistence.GetLastPeriod(db, "report")
+		require.NoError(t, err)
+		assert.Equal(t, "today", got)
+	})
+}
+
 func TestNewActiveCmd(t *testing.T) {
 	t.Run("command properties", func(t *testing.T) {
 		activeTemplate := "{{task}} ({{time}})"
 		var db *sql.DB
 
-		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate)
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, new(bool), new(bool))
 
 		assert.Equal(t, "active", cmd.Use)
 		assert.Equal(t, `Show the task being actively tracked by "hours"`, cmd.Short)
@@ -251,7 +465,7 @@ func TestNewActiveCmd(t *testing.T) {
 		activeTemplate := "custom: {{task}}"
 		var db *sql.DB
 
-		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate)
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, new(bool), new(bool))
 
 		assert.NotNil(t, cmd.RunE)
 	})
@@ -263,12 +477,15 @@ func TestCommandCreationWithDB(t *testing.T) {
 		defer db.Close()
 
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
 
 		// Execute with a valid period but plain output to avoid interactive mode
 		// The command will run without crashing, but may have no data
@@ -283,27 +500,96 @@ func TestCommandCreationWithDB(t *testing.T) {
 		defer db.Close()
 
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultLogLimit,
+		})
 
 		// Execute with "today" as period
 		err := cmd.RunE(cmd, []string{"today"})
 		assert.NoError(t, err)
 	})
 
+	t.Run("newLogCmd with --since and --until", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+		recordsOpts := recordsOptions{
+			plain:         true,
+			taskStatusStr: testTaskStatus,
+		}
+
+		testCases := []struct {
+			name    string
+			args    []string
+			since   string
+			until   string
+			wantErr bool
+			err     error
+		}{
+			{name: "since only", since: "2024/06/08"},
+			{name: "since and until", since: "2024/06/08 09:00", until: "2024/06/12"},
+			{name: "positional period without flags", args: []string{"week"}},
+			{name: "until before since", since: "2024/06/12", until: "2024/06/08", wantErr: true},
+			{name: "until without since", until: "2024/06/12", wantErr: true, err: errUntilWithoutSince},
+			{name: "flags together with a period", args: []string{"week"}, since: "2024/06/08", wantErr: true, err: errSinceUntilWithPeriod},
+		}
+
+		for _, tt := range testCases {
+			t.Run(tt.name, func(t *testing.T) {
+				cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+					recordsOptions: recordsOpts,
+					limit:          ui.DefaultLogLimit,
+					since:          tt.since,
+					until:          tt.until,
+				})
+
+				err := cmd.RunE(cmd, tt.args)
+
+				if !tt.wantErr {
+					assert.NoError(t, err)
+					return
+				}
+
+				require.Error(t, err)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				}
+			})
+		}
+
+		t.Run("flags together with --since-cutoff", func(t *testing.T) {
+			cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+				recordsOptions: recordsOpts,
+				limit:          ui.DefaultLogLimit,
+				sinceCutoff:    true,
+				since:          "2024/06/08",
+			})
+
+			err := cmd.RunE(cmd, nil)
+
+			assert.ErrorIs(t, err, errSinceUntilWithSinceCutoff)
+		})
+	})
+
 	t.Run("newStatsCmd with database", func(t *testing.T) {
 		db := setupTestDB(t)
 		defer db.Close()
 
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		// Execute with "3d" as period
 		err := cmd.RunE(cmd, []string{"3d"})
@@ -316,37 +602,186 @@ func TestCommandCreationWithDB(t *testing.T) {
 
 		activeTemplate := ui.ActiveTaskPlaceholder
 
-		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate)
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, new(bool), new(bool))
 
 		// Execute - should not crash even with empty database
 		err := cmd.RunE(cmd, []string{})
 		assert.NoError(t, err)
 	})
 
+	t.Run("newActiveCmd with --exit-code and no active task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		activeTemplate := ui.ActiveTaskPlaceholder
+		activeJSON := true
+		activeExitCode := true
+
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, &activeJSON, &activeExitCode)
+
+		err := cmd.RunE(cmd, []string{})
+		assert.ErrorIs(t, err, errNoActiveTask)
+		assert.True(t, cmd.SilenceErrors)
+	})
+
 	t.Run("newStatsCmd with all period", func(t *testing.T) {
 		db := setupTestDB(t)
 		defer db.Close()
 
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		// Execute with "all" as period - should use nil date range
 		err := cmd.RunE(cmd, []string{"all"})
 		assert.NoError(t, err)
 	})
+
+	t.Run("newStatsCmd with extremes", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+
+		opts := statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit:    ui.DefaultStatsLimit,
+			extremes: true,
+		}
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &opts)
+
+		assert.NoError(t, cmd.RunE(cmd, []string{"week"}))
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"all"}), errExtremesWithAllPeriod)
+
+		opts.interactive = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errExtremesInteractive)
+	})
+
+	t.Run("newStatsCmd with sparkline", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+
+		opts := statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit:     ui.DefaultStatsLimit,
+			sparkline: true,
+		}
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &opts)
+
+		assert.NoError(t, cmd.RunE(cmd, []string{"week"}))
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"all"}), errSparklineWithAllPeriod)
+
+		opts.interactive = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errSparklineInteractive)
+	})
+
+	t.Run("newStatsCmd with calendar", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+
+		opts := statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit:    ui.DefaultStatsLimit,
+			calendar: true,
+		}
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &opts)
+
+		assert.NoError(t, cmd.RunE(cmd, []string{"this-month"}))
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"all"}), errCalendarWithAllPeriod)
+
+		opts.extremes = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errCalendarWithOtherOutput)
+
+		opts.interactive = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errCalendarInteractive)
+	})
+
+	t.Run("newStatsCmd with group by", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+
+		opts := statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit:   ui.DefaultStatsLimit,
+			groupBy: "week",
+		}
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &opts)
+
+		assert.NoError(t, cmd.RunE(cmd, []string{"this-month"}))
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"all"}), errGroupByWithAllPeriod)
+
+		opts.json = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errGroupByWithOtherOutput)
+
+		opts.interactive = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errGroupByInteractive)
+
+		opts.groupBy = "year"
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), types.ErrIncorrectGranularity)
+	})
+
+	t.Run("newStatsCmd by category", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+
+		opts := statsOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			limit:      ui.DefaultStatsLimit,
+			byCategory: true,
+		}
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &opts)
+
+		assert.NoError(t, cmd.RunE(cmd, []string{"this-month"}))
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"all"}), errByCategoryWithAllPeriod)
+
+		opts.groupBy = "week"
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errByCategoryWithOtherOutput)
+
+		opts.interactive = true
+		assert.ErrorIs(t, cmd.RunE(cmd, []string{"week"}), errByCategoryInteractive)
+	})
 }
 
 func TestCommandArgsValidation(t *testing.T) {
 	t.Run("report command accepts max 1 arg", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newReportCmd(&db, mockPreRun, &style, nil, nil, nil, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+		})
 
 		// cobra.MaximumNArgs(1) should be set
 		assert.NotNil(t, cmd.Args)
@@ -354,20 +789,27 @@ func TestCommandArgsValidation(t *testing.T) {
 
 	t.Run("log command accepts max 1 arg", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newLogCmd(&db, mockPreRun, &style, nil, nil, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+		})
 
 		assert.NotNil(t, cmd.Args)
 	})
 
 	t.Run("stats command accepts max 1 arg", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, nil, nil, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		assert.NotNil(t, cmd.Args)
 	})
@@ -376,7 +818,7 @@ func TestCommandArgsValidation(t *testing.T) {
 		activeTemplate := ui.ActiveTaskPlaceholder
 		var db *sql.DB
 
-		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate)
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, new(bool), new(bool))
 
 		// Active command doesn't set Args field - it accepts no arguments by default
 		assert.Nil(t, cmd.Args)
@@ -392,37 +834,48 @@ func TestPreRunEAssignment(t *testing.T) {
 		genSkipConfirmation := true
 		var db *sql.DB
 
-		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation)
+		cmd := newGenerateCmd(&db, mockPreRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, new(bool))
 
 		assert.NotNil(t, cmd.PreRunE)
 	})
 
 	t.Run("report command has PreRunE", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newReportCmd(&db, mockPreRun, &style, nil, nil, nil, &taskStatusStr)
+		cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+		})
 
 		assert.NotNil(t, cmd.PreRunE)
 	})
 
 	t.Run("log command has PreRunE", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newLogCmd(&db, mockPreRun, &style, nil, nil, &taskStatusStr)
+		cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+		})
 
 		assert.NotNil(t, cmd.PreRunE)
 	})
 
 	t.Run("stats command has PreRunE", func(t *testing.T) {
 		style := ui.Style{}
-		taskStatusStr := testTaskStatus
 		var db *sql.DB
 
-		cmd := newStatsCmd(&db, mockPreRun, &style, nil, nil, &taskStatusStr)
+		cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+			recordsOptions: recordsOptions{
+				taskStatusStr: testTaskStatus,
+			},
+			limit: ui.DefaultStatsLimit,
+		})
 
 		assert.NotNil(t, cmd.PreRunE)
 	})
@@ -431,7 +884,7 @@ func TestPreRunEAssignment(t *testing.T) {
 		activeTemplate := ui.ActiveTaskPlaceholder
 		var db *sql.DB
 
-		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate)
+		cmd := newActiveCmd(&db, mockPreRun, &activeTemplate, new(bool), new(bool))
 
 		assert.NotNil(t, cmd.PreRunE)
 	})
@@ -443,29 +896,53 @@ func TestPeriodParsing(t *testing.T) {
 
 	t.Run("report command parses various periods", func(t *testing.T) {
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
 		periods := []string{"today", "yest", "3d", "week"}
 		for _, period := range periods {
-			cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: testTaskStatus,
+				},
+				format: reportFormatTable,
+				limit:  ui.DefaultReportLimit,
+			})
 			// Execute with valid database
 			err := cmd.RunE(cmd, []string{period})
 			assert.NoError(t, err, "period %s should not cause error", period)
 		}
 	})
 
+	t.Run("report command rejects month periods as they exceed the day threshold", func(t *testing.T) {
+		style := ui.Style{}
+
+		periods := []string{"month", "this-month", "last-month"}
+		for _, period := range periods {
+			cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: testTaskStatus,
+				},
+				format: reportFormatTable,
+				limit:  ui.DefaultReportLimit,
+			})
+			err := cmd.RunE(cmd, []string{period})
+			assert.ErrorContains(t, err, "time period is too large", "period %s should cause error", period)
+		}
+	})
+
 	t.Run("log command parses various periods", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		periods := []string{"today", "yest", "3d", "week"}
+		periods := []string{"today", "yest", "3d", "week", "month", "this-month", "last-month"}
 		for _, period := range periods {
-			cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: testTaskStatus,
+				},
+				limit: ui.DefaultLogLimit,
+			})
 			err := cmd.RunE(cmd, []string{period})
 			assert.NoError(t, err, "period %s should not cause error", period)
 		}
@@ -473,13 +950,16 @@ func TestPeriodParsing(t *testing.T) {
 
 	t.Run("stats command parses various periods", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
-		taskStatusStr := testTaskStatus
 
-		periods := []string{"today", "yest", "3d", "week", "this-month"}
+		periods := []string{"today", "yest", "3d", "week", "month", "this-month", "last-month", "this-quarter", "last-quarter", "this-year"}
 		for _, period := range periods {
-			cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: testTaskStatus,
+				},
+				limit: ui.DefaultStatsLimit,
+			})
 			err := cmd.RunE(cmd, []string{period})
 			assert.NoError(t, err, "period %s should not cause error", period)
 		}
@@ -490,17 +970,20 @@ func TestTaskStatusParsing(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	validStatuses := []string{"any", "active", "inactive"}
+	validStatuses := []string{"any", "active", "inactive", "tracking"}
 
 	t.Run("report command with valid task statuses", func(t *testing.T) {
 		style := ui.Style{}
-		reportAgg := false
-		recordsInteractive := false
-		recordsOutputPlain := true
 
 		for _, status := range validStatuses {
-			taskStatusStr := status
-			cmd := newReportCmd(&db, mockPreRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newReportCmd(&db, mockPreRun, &style, &config{}, &reportOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: status,
+				},
+				format: reportFormatTable,
+				limit:  ui.DefaultReportLimit,
+			})
 			err := cmd.RunE(cmd, []string{"today"})
 			assert.NoError(t, err, "status %s should not cause error", status)
 		}
@@ -508,12 +991,15 @@ func TestTaskStatusParsing(t *testing.T) {
 
 	t.Run("log command with valid task statuses", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
 
 		for _, status := range validStatuses {
-			taskStatusStr := status
-			cmd := newLogCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newLogCmd(&db, mockPreRun, &style, &config{}, &logOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: status,
+				},
+				limit: ui.DefaultLogLimit,
+			})
 			err := cmd.RunE(cmd, []string{"today"})
 			assert.NoError(t, err, "status %s should not cause error", status)
 		}
@@ -521,12 +1007,15 @@ func TestTaskStatusParsing(t *testing.T) {
 
 	t.Run("stats command with valid task statuses", func(t *testing.T) {
 		style := ui.Style{}
-		recordsInteractive := false
-		recordsOutputPlain := true
 
 		for _, status := range validStatuses {
-			taskStatusStr := status
-			cmd := newStatsCmd(&db, mockPreRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
+			cmd := newStatsCmd(&db, mockPreRun, &style, &config{}, &statsOptions{
+				recordsOptions: recordsOptions{
+					plain:         true,
+					taskStatusStr: status,
+				},
+				limit: ui.DefaultStatsLimit,
+			})
 			err := cmd.RunE(cmd, []string{"3d"})
 			assert.NoError(t, err, "status %s should not cause error", status)
 		}
@@ -535,7 +1024,7 @@ func TestTaskStatusParsing(t *testing.T) {
 
 func TestValidTaskStatusValues(t *testing.T) {
 	// Verify that the ValidTaskStatusValues contains the expected values
-	expectedValues := []string{"any", "active", "inactive"}
+	expectedValues := []string{"any", "active", "inactive", "tracking"}
 
 	for _, expected := range expectedValues {
 		found := false
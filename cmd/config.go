@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	configFileName           = "config.json"
+	defaultReportPeriodValue = "3d"
+	defaultLogPeriodValue    = "today"
+	defaultStatsPeriodValue  = "3d"
+)
+
+var errCouldntLoadConfig = errors.New("couldn't load config")
+
+// config holds user preferences read from hours' config file. Every field is
+// optional; unset fields fall back to hours' built-in defaults.
+type config struct {
+	DefaultReportPeriod string `json:"defaultReportPeriod,omitempty"`
+	DefaultLogPeriod    string `json:"defaultLogPeriod,omitempty"`
+	DefaultStatsPeriod  string `json:"defaultStatsPeriod,omitempty"`
+}
+
+func (c config) reportPeriod() string {
+	return valueOrDefault(c.DefaultReportPeriod, defaultReportPeriodValue)
+}
+
+func (c config) logPeriod() string {
+	return valueOrDefault(c.DefaultLogPeriod, defaultLogPeriodValue)
+}
+
+func (c config) statsPeriod() string {
+	return valueOrDefault(c.DefaultStatsPeriod, defaultStatsPeriodValue)
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func getConfigFilePath(goos, userHomeDir, userConfigDir, fileName string) string {
+	if goos == "darwin" {
+		return filepath.Join(userHomeDir, macOSConfigParentDirName, configDirName, fileName)
+	}
+
+	return filepath.Join(userConfigDir, configDirName, fileName)
+}
+
+// loadConfig reads the config file at path. A missing file isn't an error;
+// an empty config is returned instead.
+func loadConfig(path string) (config, error) {
+	var cfg config
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("%w (%s): %s", errCouldntLoadConfig, path, err.Error())
+	}
+
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return config{}, fmt.Errorf("%w (%s): %s", errCouldntLoadConfig, path, err.Error())
+	}
+
+	return cfg, nil
+}
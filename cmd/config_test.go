@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhth/hours/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigReturnsEmptyConfigWhenMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+
+	cfg, err := loadConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, config{}, cfg)
+	assert.Equal(t, "3d", cfg.reportPeriod())
+	assert.Equal(t, "today", cfg.logPeriod())
+	assert.Equal(t, "3d", cfg.statsPeriod())
+}
+
+func TestLoadConfigReadsDefaultPeriods(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+	require.NoError(
+		t,
+		os.WriteFile(path, []byte(`{"defaultReportPeriod":"week","defaultLogPeriod":"yest","defaultStatsPeriod":"this-month"}`), 0o644),
+	)
+
+	cfg, err := loadConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "week", cfg.reportPeriod())
+	assert.Equal(t, "yest", cfg.logPeriod())
+	assert.Equal(t, "this-month", cfg.statsPeriod())
+}
+
+func TestLoadConfigFailsForInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+	require.NoError(t, os.WriteFile(path, []byte("{not-json"), 0o644))
+
+	_, err := loadConfig(path)
+
+	assert.ErrorIs(t, err, errCouldntLoadConfig)
+}
+
+func TestConfiguredDefaultPeriodIsUsedWhenNoArgsGiven(t *testing.T) {
+	t.Run("resolvePeriodAndRange uses the configured default", func(t *testing.T) {
+		cfg := config{DefaultLogPeriod: "yest"}
+		recordsInteractive := false
+
+		period, dateRange, err := resolvePeriodAndRange(nil, cfg.logPeriod(), &recordsInteractive, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "yest", period)
+		assert.Equal(t, 1, dateRange.NumDays)
+	})
+
+	t.Run("an argument overrides the configured default", func(t *testing.T) {
+		cfg := config{DefaultLogPeriod: "yest"}
+		recordsInteractive := false
+
+		period, _, err := resolvePeriodAndRange([]string{"week"}, cfg.logPeriod(), &recordsInteractive, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "week", period)
+	})
+
+	t.Run("report command uses the configured default", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		style := ui.Style{}
+		cfg := config{DefaultReportPeriod: "last-month"}
+
+		cmd := newReportCmd(&db, mockPreRun, &style, &cfg, &reportOptions{
+			recordsOptions: recordsOptions{
+				plain:         true,
+				taskStatusStr: testTaskStatus,
+			},
+			format: reportFormatTable,
+			limit:  ui.DefaultReportLimit,
+		})
+
+		// a month is longer than what a report can span, so this errors only if
+		// the configured default is picked up
+		assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "time period is too large")
+		assert.NoError(t, cmd.RunE(cmd, []string{"today"}))
+	})
+}
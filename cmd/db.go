@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errBackupOutMissing      = errors.New("--out needs to be provided")
+	errBackupOutExists       = errors.New("backup destination already exists; pass --force to overwrite it")
+	errCouldntBackUpDB       = errors.New("couldn't back up database")
+	errCouldntReplaceOldFile = errors.New("couldn't replace existing file at backup destination")
+	errCouldntOptimizeDB     = errors.New("couldn't optimize database")
+	errCouldntGetDBFileSize  = errors.New("couldn't get size of database file")
+)
+
+// newDBCmd creates the db command, which groups the database maintenance
+// subcommands
+func newDBCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "db",
+		Short: "Maintain hours' database",
+	}
+}
+
+// newDBBackupCmd creates the db backup command
+func newDBBackupCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	out *string,
+	force *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup",
+		Short: "Copy the database to a file",
+		Long: `Copy the database to a file.
+
+The copy is a regular SQLite database, taken consistently even while hours is
+running elsewhere. It can be used in place of the original via --dbpath.
+
+An existing file at the destination is only overwritten when --force is passed.
+`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if *out == "" {
+				return errBackupOutMissing
+			}
+
+			outPath := *out
+			if _, err := os.Stat(outPath); err == nil && !*force {
+				return fmt.Errorf("%w: %s", errBackupOutExists, outPath)
+			}
+
+			// the backup is written next to the destination first, so that an
+			// existing file there is only replaced once the backup succeeded
+			tmpPath, err := tempBackupPath(outPath)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntBackUpDB, err.Error())
+			}
+
+			if err := pers.BackupDBTo(*db, tmpPath); err != nil {
+				_ = os.Remove(tmpPath)
+				return fmt.Errorf("%w: %s", errCouldntBackUpDB, err.Error())
+			}
+
+			if err := os.Rename(tmpPath, outPath); err != nil {
+				_ = os.Remove(tmpPath)
+				return fmt.Errorf("%w: %s", errCouldntReplaceOldFile, err.Error())
+			}
+
+			size, err := fileSize(outPath)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntBackUpDB, err.Error())
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Backed up database to %s (%d bytes)\n", outPath, size)
+			return nil
+		},
+	}
+}
+
+// newDBOptimizeCmd creates the db optimize command
+func newDBOptimizeCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	dbPath *string,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "optimize",
+		Short: "Shrink the database file and refresh its statistics",
+		Long: `Shrink the database file and refresh its statistics.
+
+SQLite doesn't give space back to the file system when rows are deleted. This
+rebuilds the database file (via VACUUM) so that it only takes up as much space as
+it needs to, and refreshes the statistics SQLite uses to plan queries (via
+ANALYZE). No data is changed.
+`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			sizeBefore, err := fileSize(*dbPath)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntGetDBFileSize, err.Error())
+			}
+
+			if err := pers.OptimizeDB(*db); err != nil {
+				return fmt.Errorf("%w: %s", errCouldntOptimizeDB, err.Error())
+			}
+
+			sizeAfter, err := fileSize(*dbPath)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntGetDBFileSize, err.Error())
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Optimized database: %d bytes -> %d bytes\n", sizeBefore, sizeAfter)
+			return nil
+		},
+	}
+}
+
+// tempBackupPath returns a path in the same directory as outPath that no file
+// exists at, as VACUUM INTO refuses to write to an existing file.
+func tempBackupPath(outPath string) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(outPath), "."+filepath.Base(outPath)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := f.Name()
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Remove(tmpPath); err != nil {
+		return "", err
+	}
+
+	return tmpPath, nil
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
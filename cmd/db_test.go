@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDBBackupCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newDBBackupCmd(nil, mockPreRun, new(string), new(bool))
+
+		assert.Equal(t, "backup", cmd.Use)
+		assert.Equal(t, "Copy the database to a file", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("backs up the database to a file that can be opened", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "task one")
+		require.NoError(t, err)
+		_, err = persistence.InsertTask(db, "task two")
+		require.NoError(t, err)
+
+		out := filepath.Join(t.TempDir(), "hours-backup.db")
+		cmd := newDBBackupCmd(&db, mockPreRun, &out, new(bool))
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		// WHEN
+		err = cmd.RunE(cmd, nil)
+
+		// THEN
+		require.NoError(t, err)
+		info, err := os.Stat(out)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Backed up database to %s (%d bytes)\n", out, info.Size()), buf.String())
+
+		backupDB, err := persistence.GetDB(out)
+		require.NoError(t, err)
+		defer backupDB.Close()
+		tasks, err := persistence.FetchTasks(backupDB, true, 10)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		summaries := []string{tasks[0].Summary, tasks[1].Summary}
+		assert.ElementsMatch(t, []string{"task one", "task two"}, summaries)
+	})
+
+	t.Run("refuses to overwrite an existing file unless forced", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		out := filepath.Join(t.TempDir(), "hours-backup.db")
+		require.NoError(t, os.WriteFile(out, []byte("existing"), 0o600))
+
+		// WHEN
+		cmd := newDBBackupCmd(&db, mockPreRun, &out, new(bool))
+		err = cmd.RunE(cmd, nil)
+
+		// THEN
+		assert.ErrorIs(t, err, errBackupOutExists)
+		contents, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "existing", string(contents))
+
+		force := true
+		cmd = newDBBackupCmd(&db, mockPreRun, &out, &force)
+		cmd.SetOut(&bytes.Buffer{})
+		require.NoError(t, cmd.RunE(cmd, nil))
+
+		backupDB, err := persistence.GetDB(out)
+		require.NoError(t, err)
+		defer backupDB.Close()
+		tasks, err := persistence.FetchTasks(backupDB, true, 10)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 1)
+	})
+
+	t.Run("keeps the existing file when a forced backup fails", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		require.NoError(t, db.Close())
+
+		dir := t.TempDir()
+		out := filepath.Join(dir, "hours-backup.db")
+		require.NoError(t, os.WriteFile(out, []byte("existing"), 0o600))
+
+		// WHEN
+		force := true
+		cmd := newDBBackupCmd(&db, mockPreRun, &out, &force)
+		err := cmd.RunE(cmd, nil)
+
+		// THEN
+		assert.ErrorIs(t, err, errCouldntBackUpDB)
+		contents, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "existing", string(contents))
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("fails without --out", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newDBBackupCmd(&db, mockPreRun, new(string), new(bool))
+		err := cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, errBackupOutMissing)
+	})
+}
+
+func setupTestFileDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "hours.db")
+	db, err := persistence.GetDB(dbPath)
+	require.NoError(t, err)
+
+	err = persistence.InitDB(db)
+	require.NoError(t, err)
+
+	err = persistence.UpgradeDB(db, 1)
+	require.NoError(t, err)
+
+	return db, dbPath
+}
+
+func TestNewDBOptimizeCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newDBOptimizeCmd(nil, mockPreRun, new(string))
+
+		assert.Equal(t, "optimize", cmd.Use)
+		assert.Equal(t, "Shrink the database file and refresh its statistics", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("shrinks a database with deleted rows and keeps its tasks", func(t *testing.T) {
+		// GIVEN
+		db, dbPath := setupTestFileDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertTask(db, "another task")
+		require.NoError(t, err)
+		comment := strings.Repeat("a long comment ", 50)
+		beginTS := time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC)
+		for i := range 200 {
+			begin := beginTS.Add(time.Duration(i) * time.Hour)
+			_, err = persistence.InsertManualTL(db, taskID, begin, begin.Add(30*time.Minute), &comment, nil, nil, false)
+			require.NoError(t, err)
+		}
+		_, err = persistence.DeleteTLsBetweenTS(db, beginTS, beginTS.Add(300*time.Hour), nil)
+		require.NoError(t, err)
+
+		sizeBefore, err := fileSize(dbPath)
+		require.NoError(t, err)
+
+		cmd := newDBOptimizeCmd(&db, mockPreRun, &dbPath)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		// WHEN
+		err = cmd.RunE(cmd, nil)
+
+		// THEN
+		require.NoError(t, err)
+		sizeAfter, err := fileSize(dbPath)
+		require.NoError(t, err)
+		assert.Less(t, sizeAfter, sizeBefore)
+		assert.Equal(t, fmt.Sprintf("Optimized database: %d bytes -> %d bytes\n", sizeBefore, sizeAfter), buf.String())
+
+		tasks, err := persistence.FetchTasks(db, true, 10)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 2)
+	})
+
+	t.Run("works on an empty database", func(t *testing.T) {
+		db, dbPath := setupTestFileDB(t)
+		defer db.Close()
+
+		cmd := newDBOptimizeCmd(&db, mockPreRun, &dbPath)
+		cmd.SetOut(&bytes.Buffer{})
+		err := cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+	})
+}
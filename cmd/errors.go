@@ -6,10 +6,18 @@ import (
 	"fmt"
 	"os"
 
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/ui/theme"
 )
 
 func handleError(err error) {
+	if errors.Is(err, pers.ErrDBNotEmpty) {
+		fmt.Fprintf(os.Stderr, `
+Pass --force to replace all existing tasks and task logs with the ones in the backup.
+`)
+		return
+	}
+
 	if errors.Is(err, errCouldntGenerateData) {
 		fmt.Fprintf(os.Stderr, "\n%s\n", msgReportIssue)
 		return
@@ -46,13 +54,16 @@ A valid theme file looks like this:
 
 	if errors.Is(err, theme.ErrThemeColorsAreInvalid) {
 		fmt.Fprintf(os.Stderr, `
-Color codes can only be provided in ANSI 16, ANSI 256, or HEX formats.
+Color codes can only be provided in ANSI 16, ANSI 256, or HEX formats, or as CSS
+color names.
 
 For example:
 
 "activeTask": "9"           # red in ANSI 16
 "activeTask": "201"         # hot pink in ANSI 256
 "activeTask": "#0000FF"     # blue in HEX (true color)
+"activeTask": "#00F"        # blue in shorthand HEX
+"activeTask": "royalblue"   # a CSS color name
 
 Fun fact: There are 16,777,216 true color choices. Go nuts.
 `)
@@ -3,10 +3,12 @@ package cmd
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"testing"
 
+	"github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/ui/theme"
 	"github.com/stretchr/testify/assert"
 )
@@ -104,3 +106,13 @@ func TestHandleError_UnhandledError(t *testing.T) {
 
 	assert.Empty(t, output)
 }
+
+func TestHandleError_DBNotEmpty(t *testing.T) {
+	err := fmt.Errorf("%w: %w", errCouldntImportBackup, persistence.ErrDBNotEmpty)
+
+	output := captureStderr(t, func() {
+		handleError(err)
+	})
+
+	assert.Contains(t, output, "Pass --force")
+}
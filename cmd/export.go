@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const exportFormatCSV = "csv"
+
+var exportCSVHeader = []string{"id", "task_id", "task_summary", "begin_ts", "end_ts", "secs_spent", "comment", "tags"}
+
+// newExportCmd creates the export command
+func newExportCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	format *string,
+	all *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export all saved task log entries",
+		Long: `Export all saved task log entries, across all time, along with the summary
+of the task they belong to.
+
+Unlike "report" and "log", there's no period or limit; entries are written out
+one at a time as they're read from the database, so this works for databases
+of any size. The output is meant to be imported into a spreadsheet.
+
+With --all, every task and task log (including the one being tracked right
+now) is written out as JSON instead, preserving IDs, timestamps, comments,
+tags, and active status, along with the weekly goal and remembered periods.
+This is meant to be used as a full backup, and can be restored into an empty
+database via "hours import".
+`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if *all {
+				data, err := pers.ExportAll(*db)
+				if err != nil {
+					return fmt.Errorf("%w: %s", errCouldntExportTaskLogs, err.Error())
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			if *format != exportFormatCSV {
+				return fmt.Errorf("%w: %q", errExportFormatInvalid, *format)
+			}
+
+			w := csv.NewWriter(cmd.OutOrStdout())
+			if err := w.Write(exportCSVHeader); err != nil {
+				return err
+			}
+
+			for entry, err := range pers.AllTLEntries(*db) {
+				if err != nil {
+					return fmt.Errorf("%w: %s", errCouldntExportTaskLogs, err.Error())
+				}
+				if err := w.Write(exportCSVRecord(entry)); err != nil {
+					return err
+				}
+			}
+
+			w.Flush()
+			return w.Error()
+		},
+	}
+}
+
+func exportCSVRecord(entry types.TaskLogEntry) []string {
+	var comment string
+	if entry.Comment != nil {
+		comment = *entry.Comment
+	}
+
+	return []string{
+		strconv.Itoa(entry.ID),
+		strconv.Itoa(entry.TaskID),
+		entry.TaskSummary,
+		entry.BeginTS.Format(time.RFC3339),
+		entry.EndTS.Format(time.RFC3339),
+		strconv.Itoa(entry.SecsSpent),
+		comment,
+		types.FormatLogTags(entry.Tags),
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExportCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newExportCmd(nil, mockPreRun, new(string), new(bool))
+
+		assert.Equal(t, "export", cmd.Use)
+		assert.Equal(t, "Export all saved task log entries", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("writes all saved entries as csv", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		comment := `fixed "the" bug, finally`
+		beginTS := time.Date(2020, time.January, 2, 9, 0, 0, 0, time.Local)
+		endTS := beginTS.Add(time.Hour)
+		_, err = persistence.InsertManualTL(db, taskID, beginTS, endTS, &comment, nil, nil, false)
+		require.NoError(t, err)
+		for i := range 3 {
+			begin := time.Now().AddDate(0, 0, -i-1)
+			_, err = persistence.InsertManualTL(db, taskID, begin, begin.Add(30*time.Minute), nil, nil, nil, false)
+			require.NoError(t, err)
+		}
+		_, err = persistence.InsertNewTL(db, taskID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		format := exportFormatCSV
+		cmd := newExportCmd(&db, mockPreRun, &format, new(bool))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), `"fixed ""the"" bug, finally"`)
+		records, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		assert.Equal(t, exportCSVHeader, records[0])
+		assert.Equal(t, []string{
+			"1",
+			fmt.Sprintf("%d", taskID),
+			"a task",
+			beginTS.Format(time.RFC3339),
+			endTS.Format(time.RFC3339),
+			"3600",
+			comment,
+			"",
+		}, records[1])
+	})
+
+	t.Run("fails for unknown format", func(t *testing.T) {
+		format := "xlsx"
+		cmd := newExportCmd(nil, mockPreRun, &format, new(bool))
+
+		err := cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, errExportFormatInvalid)
+	})
+}
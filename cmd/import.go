@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/spf13/cobra"
+)
+
+// newImportCmd creates the import command
+func newImportCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	force *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <FILE>",
+		Short: "Restore tasks and task logs from a backup",
+		Long: `Restore tasks and task logs from a backup written by "hours export --all".
+
+IDs, timestamps, comments, tags, and active status are restored exactly as they
+were, as are the weekly goal and remembered periods. The import happens in a single transaction; if anything goes wrong,
+nothing is saved.
+
+The database is expected to be empty. Pass --force to replace all existing
+tasks and task logs with the ones in the backup.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntReadBackup, err.Error())
+			}
+
+			err = pers.ImportAllForced(*db, data, *force)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errCouldntImportBackup, err)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Backup imported")
+			return nil
+		},
+	}
+}
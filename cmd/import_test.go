@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImportCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newImportCmd(nil, mockPreRun, new(bool))
+
+		assert.Equal(t, "import <FILE>", cmd.Use)
+		assert.Equal(t, "Restore tasks and task logs from a backup", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("restores a backup written by export --all", func(t *testing.T) {
+		sourceDB := setupTestDB(t)
+		defer sourceDB.Close()
+		taskID, err := persistence.InsertTask(sourceDB, "a task")
+		require.NoError(t, err)
+		comment := "a comment"
+		beginTS := time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC)
+		_, err = persistence.InsertManualTL(sourceDB, taskID, beginTS, beginTS.Add(time.Hour), &comment, nil, nil, false)
+		require.NoError(t, err)
+
+		all := true
+		exportCmd := newExportCmd(&sourceDB, mockPreRun, new(string), &all)
+		var exported bytes.Buffer
+		exportCmd.SetOut(&exported)
+		require.NoError(t, exportCmd.RunE(exportCmd, nil))
+
+		backupPath := filepath.Join(t.TempDir(), "backup.json")
+		require.NoError(t, os.WriteFile(backupPath, exported.Bytes(), 0o600))
+
+		targetDB := setupTestDB(t)
+		defer targetDB.Close()
+		cmd := newImportCmd(&targetDB, mockPreRun, new(bool))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{backupPath})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Backup imported\n", out.String())
+		sourceLogs, err := persistence.FetchSyncTaskLogs(sourceDB)
+		require.NoError(t, err)
+		targetLogs, err := persistence.FetchSyncTaskLogs(targetDB)
+		require.NoError(t, err)
+		assert.Equal(t, sourceLogs, targetLogs)
+	})
+
+	t.Run("fails for a non-empty database unless forced", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "existing task")
+		require.NoError(t, err)
+
+		backupPath := filepath.Join(t.TempDir(), "backup.json")
+		require.NoError(t, os.WriteFile(backupPath, []byte(`{"version": 1, "tasks": [], "taskLogs": []}`), 0o600))
+
+		cmd := newImportCmd(&db, mockPreRun, new(bool))
+		err = cmd.RunE(cmd, []string{backupPath})
+		assert.ErrorIs(t, err, errCouldntImportBackup)
+		assert.ErrorIs(t, err, persistence.ErrDBNotEmpty)
+
+		force := true
+		cmd = newImportCmd(&db, mockPreRun, &force)
+		cmd.SetOut(&bytes.Buffer{})
+		err = cmd.RunE(cmd, []string{backupPath})
+		require.NoError(t, err)
+		tasks, err := persistence.FetchSyncTasks(db)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+	})
+
+	t.Run("fails for a missing file", func(t *testing.T) {
+		cmd := newImportCmd(nil, mockPreRun, new(bool))
+
+		err := cmd.RunE(cmd, []string{filepath.Join(t.TempDir(), "missing.json")})
+
+		assert.ErrorIs(t, err, errCouldntReadBackup)
+	})
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	togglTaskFromProject     = "project"
+	togglTaskFromDescription = "description"
+	togglDateTimeFormat      = "2006-01-02 15:04:05"
+	togglFallbackTaskSummary = "Imported from Toggl"
+	utf8BOM                  = "\ufeff"
+)
+
+var (
+	errCouldntOpenTogglFile = errors.New("couldn't open Toggl export")
+	errTogglHeaderInvalid   = errors.New("a required column is missing in the Toggl export")
+	errTogglLineInvalid     = errors.New("line in Toggl export is invalid")
+	errTogglFileEmpty       = errors.New("no time entries found in the Toggl export")
+	errTogglTaskFromInvalid = errors.New("task source is invalid")
+	errTogglDurationInvalid = errors.New("duration needs to be of the format HH:MM:SS")
+)
+
+var togglRequiredColumnNames = []string{"Description", "Start date", "Start time", "Duration"}
+
+// togglEntry is a single time entry from a Toggl CSV export, mapped to the
+// task it'll be saved against.
+type togglEntry struct {
+	taskSummary string
+	beginTS     time.Time
+	endTS       time.Time
+	comment     *string
+}
+
+type togglImportResult struct {
+	numEntries  int
+	numSkipped  int
+	numNewTasks int
+}
+
+// parseTogglDuration parses durations as exported by Toggl (eg. "1:05:00",
+// "27:30:15"), where the hours component isn't limited to 24.
+func parseTogglDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("%w: %q", errTogglDurationInvalid, value)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 || (i > 0 && num > 59) {
+			return 0, fmt.Errorf("%w: %q", errTogglDurationInvalid, value)
+		}
+		nums[i] = num
+	}
+
+	return time.Duration(nums[0])*time.Hour + time.Duration(nums[1])*time.Minute + time.Duration(nums[2])*time.Second, nil
+}
+
+// parseTogglCSV parses a detailed time entries export from Toggl. Columns are
+// looked up by name, so their order doesn't matter. Tasks are named after the
+// entry's project, or its description, depending on taskFrom; when named after
+// the project, the description becomes the task log's comment.
+func parseTogglCSV(reader io.Reader, taskFrom string) ([]togglEntry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errTogglFileEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntReadInput, err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, utf8BOM))] = i
+	}
+	for _, name := range togglRequiredColumnNames {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %q", errTogglHeaderInvalid, name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []togglEntry
+	lineNum := 1
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		lineNum++
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %s", errTogglLineInvalid, lineNum, err.Error())
+		}
+
+		beginTS, err := time.ParseInLocation(togglDateTimeFormat, field(record, "Start date")+" "+field(record, "Start time"), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %w", errTogglLineInvalid, lineNum, err)
+		}
+
+		duration, err := parseTogglDuration(field(record, "Duration"))
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %w", errTogglLineInvalid, lineNum, err)
+		}
+
+		description := field(record, "Description")
+		project := field(record, "Project")
+
+		var taskSummary string
+		var comment *string
+		switch taskFrom {
+		case togglTaskFromDescription:
+			taskSummary = description
+		default:
+			taskSummary = project
+			if description != "" {
+				comment = &description
+			}
+		}
+		if taskSummary == "" {
+			taskSummary = togglFallbackTaskSummary
+		}
+
+		entries = append(entries, togglEntry{
+			taskSummary: taskSummary,
+			beginTS:     beginTS,
+			endTS:       beginTS.Add(duration),
+			comment:     comment,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, errTogglFileEmpty
+	}
+
+	return entries, nil
+}
+
+// importTogglEntries saves entries as task log entries, in a single
+// transaction. Entries are saved against existing tasks with a matching
+// summary, if any; other tasks are created as needed. Entries shorter than a
+// minute are skipped.
+func importTogglEntries(db *sql.DB, entries []togglEntry, allowOverlap bool) (togglImportResult, error) {
+	var result togglImportResult
+
+	toImport := make([]types.ImportedTaskLog, 0, len(entries))
+	for _, entry := range entries {
+		if err := types.IsTaskLogDurationValid(entry.beginTS, entry.endTS); err != nil {
+			result.numSkipped++
+			continue
+		}
+
+		toImport = append(toImport, types.ImportedTaskLog{
+			TaskSummary: entry.taskSummary,
+			BeginTS:     entry.beginTS,
+			EndTS:       entry.endTS,
+			Comment:     entry.comment,
+		})
+	}
+
+	numNewTasks, err := pers.ImportTaskLogs(db, toImport, allowOverlap)
+	if err != nil {
+		return result, err
+	}
+
+	result.numEntries = len(toImport)
+	result.numNewTasks = numNewTasks
+
+	return result, nil
+}
+
+// newImportTogglCmd creates the import-toggl command
+func newImportTogglCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	taskFrom *string,
+	allowOverlap *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-toggl <FILE>",
+		Short: "Import time entries from a Toggl CSV export",
+		Long: `Import time entries from a Toggl CSV export.
+
+Expects a detailed time entries export, with at least the columns
+"Description", "Start date" (eg. "2024-06-08"), "Start time" (eg. "09:30:00"),
+and "Duration" (eg. "01:15:00"). Times are read in your local timezone.
+
+Each entry is saved as a task log entry against a task named after its
+"Project" (the default; the description is used as the comment), or after its
+description (--task-from description). Tasks that already exist with the same
+summary are reused; others are created. Entries shorter than a minute are
+skipped. Everything is imported in a single transaction; if any entry is
+rejected, nothing is saved.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch *taskFrom {
+			case togglTaskFromProject, togglTaskFromDescription:
+			default:
+				return fmt.Errorf("%w: %q; allowed values: %s, %s", errTogglTaskFromInvalid, *taskFrom, togglTaskFromProject, togglTaskFromDescription)
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntOpenTogglFile, err.Error())
+			}
+			defer file.Close()
+
+			entries, err := parseTogglCSV(file, *taskFrom)
+			if err != nil {
+				return err
+			}
+
+			result, err := importTogglEntries(*db, entries, *allowOverlap)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d task log entries (%d new task(s))\n", result.numEntries, result.numNewTasks)
+			if result.numSkipped > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Skipped %d entries shorter than a minute\n", result.numSkipped)
+			}
+
+			return nil
+		},
+	}
+}
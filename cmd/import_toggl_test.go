@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const togglTestCSV = utf8BOM + `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()
+Jane,jane@example.com,Acme,Website,,Fix header,No,2024-06-03,09:00:00,2024-06-03,10:30:00,01:30:00,,
+Jane,jane@example.com,Acme,Website,,Review copy,No,2024-06-03,13:15:00,2024-06-03,13:45:00,00:30:00,,
+Jane,jane@example.com,,Internal,,"Planning, weekly",No,2024-06-04,10:00:00,2024-06-04,12:00:00,02:00:00,,
+Jane,jane@example.com,,Internal,,Quick call,No,2024-06-04,14:00:00,2024-06-04,14:00:20,00:00:20,,
+`
+
+func TestParseTogglDuration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "regular duration", value: "01:30:00", expected: 90 * time.Minute},
+		{name: "single digit hours", value: "1:05:09", expected: time.Hour + 5*time.Minute + 9*time.Second},
+		{name: "more than a day", value: "27:00:00", expected: 27 * time.Hour},
+		{name: "missing seconds", value: "01:30", wantErr: true},
+		{name: "minutes out of range", value: "01:75:00", wantErr: true},
+		{name: "not a number", value: "1h:00:00", wantErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTogglDuration(tt.value)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errTogglDurationInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseTogglCSV(t *testing.T) {
+	t.Run("maps columns by name", func(t *testing.T) {
+		entries, err := parseTogglCSV(strings.NewReader(togglTestCSV), togglTaskFromProject)
+
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+		assert.Equal(t, "Website", entries[0].taskSummary)
+		require.NotNil(t, entries[0].comment)
+		assert.Equal(t, "Fix header", *entries[0].comment)
+		assert.Equal(t, time.Date(2024, 6, 3, 9, 0, 0, 0, time.Local), entries[0].beginTS)
+		assert.Equal(t, time.Date(2024, 6, 3, 10, 30, 0, 0, time.Local), entries[0].endTS)
+		require.NotNil(t, entries[2].comment)
+		assert.Equal(t, "Planning, weekly", *entries[2].comment)
+	})
+
+	t.Run("names tasks after descriptions if asked to", func(t *testing.T) {
+		entries, err := parseTogglCSV(strings.NewReader(togglTestCSV), togglTaskFromDescription)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Fix header", entries[0].taskSummary)
+		assert.Nil(t, entries[0].comment)
+	})
+
+	t.Run("fails if a required column is missing", func(t *testing.T) {
+		csv := "Description,Start date,Duration\nFix header,2024-06-03,01:30:00\n"
+
+		_, err := parseTogglCSV(strings.NewReader(csv), togglTaskFromProject)
+
+		assert.ErrorIs(t, err, errTogglHeaderInvalid)
+	})
+
+	t.Run("fails for an invalid start time", func(t *testing.T) {
+		csv := "Description,Start date,Start time,Duration\nFix header,06/03/2024,09:00:00,01:30:00\n"
+
+		_, err := parseTogglCSV(strings.NewReader(csv), togglTaskFromProject)
+
+		assert.ErrorIs(t, err, errTogglLineInvalid)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("fails if there are no entries", func(t *testing.T) {
+		csv := "Description,Start date,Start time,Duration\n"
+
+		_, err := parseTogglCSV(strings.NewReader(csv), togglTaskFromProject)
+
+		assert.ErrorIs(t, err, errTogglFileEmpty)
+	})
+}
+
+func TestNewImportTogglCmd(t *testing.T) {
+	writeCSV := func(t *testing.T) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "toggl.csv")
+		require.NoError(t, os.WriteFile(path, []byte(togglTestCSV), 0o600))
+		return path
+	}
+
+	t.Run("imports tasks and task logs", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		existingTaskID, err := persistence.InsertTask(db, "Internal")
+		require.NoError(t, err)
+
+		taskFrom := togglTaskFromProject
+		cmd := newImportTogglCmd(&db, mockPreRun, &taskFrom, new(bool))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{writeCSV(t)})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Imported 3 task log entries (1 new task(s))\nSkipped 1 entries shorter than a minute\n", out.String())
+
+		tasks, err := persistence.FetchTasks(db, true, 10)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		secsSpent := make(map[string]int)
+		for _, task := range tasks {
+			secsSpent[task.Summary] = task.SecsSpent
+		}
+		assert.Equal(t, 2*3600, secsSpent["Website"])
+		assert.Equal(t, 2*3600, secsSpent["Internal"])
+
+		tls, err := persistence.FetchTLEntriesForTask(db, existingTaskID, false, 10)
+		require.NoError(t, err)
+		require.Len(t, tls, 1)
+		require.NotNil(t, tls[0].Comment)
+		assert.Equal(t, "Planning, weekly", *tls[0].Comment)
+	})
+
+	t.Run("saves nothing if an entry is rejected", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		existingTaskID, err := persistence.InsertTask(db, "Internal")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db,
+			existingTaskID,
+			time.Date(2024, 6, 4, 10, 30, 0, 0, time.Local),
+			time.Date(2024, 6, 4, 11, 0, 0, 0, time.Local),
+			nil,
+			nil,
+			nil,
+			false,
+		)
+		require.NoError(t, err)
+
+		taskFrom := togglTaskFromProject
+		cmd := newImportTogglCmd(&db, mockPreRun, &taskFrom, new(bool))
+		cmd.SetOut(&bytes.Buffer{})
+
+		err = cmd.RunE(cmd, []string{writeCSV(t)})
+
+		require.ErrorIs(t, err, persistence.ErrTaskLogOverlaps)
+		tasks, err := persistence.FetchTasks(db, true, 10)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "Internal", tasks[0].Summary)
+		assert.Equal(t, 30*60, tasks[0].SecsSpent)
+	})
+
+	t.Run("fails for an invalid task source", func(t *testing.T) {
+		taskFrom := "client"
+		cmd := newImportTogglCmd(nil, mockPreRun, &taskFrom, new(bool))
+
+		err := cmd.RunE(cmd, []string{"toggl.csv"})
+
+		assert.ErrorIs(t, err, errTogglTaskFromInvalid)
+	})
+}
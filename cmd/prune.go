@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// newPruneCmd creates the prune command
+func newPruneCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	taskID *int,
+	skipConfirmation *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune <PERIOD>",
+		Short: "Delete saved task log entries in a time period",
+		Long: `Delete saved task log entries that ended in a time period.
+
+This is intended for cleaning up after a botched import. The time spent on the
+affected tasks is reduced accordingly. Providing --task only deletes entries
+for that task. The active task log entry is never deleted.
+
+Accepts the same periods as "report", eg. "yest", "week", or
+"2025/01/01...2025/01/07".
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dateRange, err := types.GetDateRangeFromPeriod(args[0], types.RealTimeProvider{}.Now(), true, nil)
+			if err != nil {
+				return err
+			}
+
+			var scope *int
+			scopeMsg := "all tasks"
+			if *taskID != 0 {
+				if _, err := pers.FetchTaskByID(*db, *taskID); err != nil {
+					return fmt.Errorf("%w (ID: %d)", err, *taskID)
+				}
+				scope = taskID
+				scopeMsg = fmt.Sprintf("task %d", *taskID)
+			}
+
+			if !*skipConfirmation {
+				fmt.Fprintf(cmd.OutOrStdout(), "This will delete task log entries for %s that ended between %s and %s.\n\n",
+					scopeMsg,
+					dateRange.Start.Format(timeFormat),
+					dateRange.End.Format(timeFormat),
+				)
+				confirm, err := getConfirmation()
+				if err != nil {
+					return err
+				}
+				if !confirm {
+					return fmt.Errorf("%w", errIncorrectCodeEntered)
+				}
+			}
+
+			numDeleted, err := pers.DeleteTLsBetweenTS(*db, dateRange.Start, dateRange.End, scope)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d task log entry(s)\n", numDeleted)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPruneCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newPruneCmd(nil, mockPreRun, new(int), new(bool))
+
+		assert.Equal(t, "prune <PERIOD>", cmd.Use)
+		assert.Equal(t, "Delete saved task log entries in a time period", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("deletes entries in the period and updates time spent", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		now := time.Now()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		y := now.AddDate(0, 0, -1)
+		yesterday := time.Date(y.Year(), y.Month(), y.Day(), 12, 0, 0, 0, time.Local)
+		_, err = persistence.InsertManualTL(db, taskID, yesterday.Add(-2*time.Hour), yesterday.Add(-time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		lastWeek := now.AddDate(0, 0, -8)
+		_, err = persistence.InsertManualTL(db, taskID, lastWeek.Add(-90*time.Minute), lastWeek, nil, nil, nil, false)
+		require.NoError(t, err)
+
+		skip := true
+		cmd := newPruneCmd(&db, mockPreRun, new(int), &skip)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"yest"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted 1 task log entry(s)\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 90*60, task.SecsSpent)
+	})
+
+	t.Run("only deletes entries for the given task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		y := time.Now().AddDate(0, 0, -1)
+		yesterday := time.Date(y.Year(), y.Month(), y.Day(), 12, 0, 0, 0, time.Local)
+		for _, summary := range []string{"task 1", "task 2"} {
+			taskID, err := persistence.InsertTask(db, summary)
+			require.NoError(t, err)
+			_, err = persistence.InsertManualTL(db, taskID, yesterday.Add(-2*time.Hour), yesterday.Add(-time.Hour), nil, nil, nil, false)
+			require.NoError(t, err)
+		}
+
+		taskID := 2
+		skip := true
+		cmd := newPruneCmd(&db, mockPreRun, &taskID, &skip)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err := cmd.RunE(cmd, []string{"yest"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted 1 task log entry(s)\n", out.String())
+		task1, err := persistence.FetchTaskByID(db, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3600, task1.SecsSpent)
+		task2, err := persistence.FetchTaskByID(db, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task2.SecsSpent)
+	})
+
+	t.Run("fails for an unknown task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		taskID := 42
+		skip := true
+		cmd := newPruneCmd(&db, mockPreRun, &taskID, &skip)
+
+		err := cmd.RunE(cmd, []string{"yest"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+
+	t.Run("fails for an invalid period", func(t *testing.T) {
+		skip := true
+		cmd := newPruneCmd(nil, mockPreRun, new(int), &skip)
+
+		err := cmd.RunE(cmd, []string{"not-a-period"})
+
+		assert.Error(t, err)
+	})
+}
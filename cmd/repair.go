@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errRepairTargetMissing   = errors.New("either a task ID or --all needs to be provided")
+	errRepairTargetAmbiguous = errors.New("a task ID and --all can't be provided together")
+)
+
+// newRepairCmd creates the repair command
+func newRepairCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	all *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repair [TASK_ID]",
+		Short: "Recalculate the time spent on tasks from their task log entries",
+		Long: `Recalculate the time spent on tasks from their task log entries.
+
+The total time spent on a task is stored alongside it, and can drift from the
+sum of its task log entries (eg, after editing the database by hand). This
+recalculates the total for the task with the given ID, or for all tasks when
+--all is provided.
+`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) > 0 && *all:
+				return errRepairTargetAmbiguous
+			case *all:
+				numRepaired, err := pers.RecalculateAllTasksSecsSpent(*db)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Repaired time spent on %d task(s)\n", numRepaired)
+				return nil
+			case len(args) == 0:
+				return errRepairTargetMissing
+			}
+
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			secsSpent, err := pers.RecalculateTaskSecsSpent(*db, taskID)
+			if err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Time spent on task %d is now %s\n", taskID, types.HumanizeDuration(secsSpent))
+
+			return nil
+		},
+	}
+}
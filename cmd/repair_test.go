@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRepairCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newRepairCmd(nil, mockPreRun, new(bool))
+
+		assert.Equal(t, "repair [TASK_ID]", cmd.Use)
+		assert.Equal(t, "Recalculate the time spent on tasks from their task log entries", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("repairs a desynced task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		end := time.Now().Add(-time.Hour)
+		_, err = persistence.InsertManualTL(db, taskID, end.Add(-90*time.Minute), end, nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = db.Exec(`UPDATE task SET secs_spent = 42 WHERE id = ?`, taskID)
+		require.NoError(t, err)
+
+		cmd := newRepairCmd(&db, mockPreRun, new(bool))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Time spent on task 1 is now 1h 30m\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 90*60, task.SecsSpent)
+	})
+
+	t.Run("repairs all desynced tasks", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		end := time.Now().Add(-time.Hour)
+		for _, summary := range []string{"task 1", "task 2", "task 3"} {
+			taskID, err := persistence.InsertTask(db, summary)
+			require.NoError(t, err)
+			_, err = persistence.InsertManualTL(db, taskID, end.Add(-time.Hour), end, nil, nil, nil, false)
+			require.NoError(t, err)
+		}
+		_, err := db.Exec(`UPDATE task SET secs_spent = 0 WHERE id IN (1, 3)`)
+		require.NoError(t, err)
+
+		all := true
+		cmd := newRepairCmd(&db, mockPreRun, &all)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Repaired time spent on 2 task(s)\n", out.String())
+		tasks, err := persistence.FetchTasks(db, true, 10)
+		require.NoError(t, err)
+		for _, task := range tasks {
+			assert.Equal(t, 3600, task.SecsSpent, "task %d", task.ID)
+		}
+	})
+
+	t.Run("fails without a task ID or --all", func(t *testing.T) {
+		cmd := newRepairCmd(nil, mockPreRun, new(bool))
+
+		err := cmd.RunE(cmd, []string{})
+
+		assert.ErrorIs(t, err, errRepairTargetMissing)
+	})
+
+	t.Run("fails with both a task ID and --all", func(t *testing.T) {
+		all := true
+		cmd := newRepairCmd(nil, mockPreRun, &all)
+
+		err := cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, errRepairTargetAmbiguous)
+	})
+
+	t.Run("fails for a non-existent task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newRepairCmd(&db, mockPreRun, new(bool))
+
+		err := cmd.RunE(cmd, []string{"99"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"database/sql"
 	_ "embed"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -13,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	clientpkg "github.com/dhth/hours/internal/client"
 	c "github.com/dhth/hours/internal/common"
@@ -30,8 +30,12 @@ const (
 	genNumDaysThreshold    = 30
 	genNumTasksThreshold   = 20
 	reportNumDaysThreshold = 7
+	reportFormatTable      = "table"
+	reportFormatMarkdown   = "md"
+	reportFormatCSV        = "csv"
 
 	envVarTheme      = "HOURS_THEME"
+	envVarDayCutoff  = "HOURS_DAY_CUTOFF"
 	defaultThemeName = "default"
 	warningColor     = "#fb4934"
 )
@@ -53,6 +57,50 @@ var (
 	errCouldntCheckIfThemeExists = errors.New("couldn't check if theme already exists")
 	errThemeAlreadyExists        = errors.New("theme already exists")
 	errCouldntMarshalTheme       = errors.New("couldn't marshal theme")
+	errDailyMaxInvalid           = errors.New("daily max needs to be a positive duration")
+	errWeeklyGoalInvalid         = errors.New("weekly goal can't be a negative duration")
+	errCouldntSaveWeeklyGoal     = errors.New("couldn't save weekly goal")
+	errIdleThresholdInvalid      = errors.New("idle threshold needs to be a positive duration")
+	errPomodoroWorkInvalid       = errors.New("pomodoro work length needs to be a positive duration")
+	errPomodoroBreakInvalid      = errors.New("pomodoro break length needs to be a positive duration")
+	errTrackingReminderInvalid   = errors.New("reminder interval needs to be a positive duration")
+	errShiftStepInvalid          = errors.New("shift step needs to be a positive duration")
+	errRoundInvalid              = errors.New("rounding increment can't be a negative duration")
+	errMaxSummaryLengthInvalid   = errors.New("max summary length is invalid")
+	errReportFormatInvalid       = errors.New("report format is invalid")
+	errReportDelimiterInvalid    = errors.New("report delimiter needs to be a single character other than a quote or a line break")
+	errReportDelimiterWithFormat = errors.New("--delimiter can only be used together with --format csv")
+	errExportFormatInvalid       = errors.New("export format is invalid")
+	errCouldntExportTaskLogs     = errors.New("couldn't export task logs")
+	errCouldntReadBackup         = errors.New("couldn't read backup file")
+	errCouldntImportBackup       = errors.New("couldn't import backup")
+	errLimitInvalid              = errors.New("limit needs to be a positive number")
+	errDayCutoffInvalid          = errors.New("day cutoff needs to be a time of day")
+	errSinceCutoffWithPeriod     = errors.New("a period can't be provided together with --since-cutoff")
+	errSinceUntilWithPeriod      = errors.New("a period can't be provided together with --since/--until")
+	errSinceUntilWithSinceCutoff = errors.New("--since/--until can't be used together with --since-cutoff")
+	errUntilWithoutSince         = errors.New("--until needs --since")
+	errExtremesWithAllPeriod     = errors.New("--extremes needs a bounded period, and can't be used with \"all\"")
+	errExtremesInteractive       = errors.New("--extremes can't be used together with --interactive")
+	errStatsJSONInteractive      = errors.New("--json can't be used together with --interactive")
+	errExtremesWithJSON          = errors.New("--extremes can't be used together with --json")
+	errNoActiveTask              = errors.New("no task is being tracked")
+	errCalendarInteractive       = errors.New("--calendar can't be used together with --interactive")
+	errTimesheetInteractive      = errors.New("--timesheet can't be used together with --interactive")
+	errDayFilterAmbiguous        = errors.New("--first-day-only and --last-day-only can't be used together")
+	errDayFilterInteractive      = errors.New("--first-day-only/--last-day-only can't be used together with --interactive")
+	errCalendarWithOtherOutput   = errors.New("--calendar can't be used together with --extremes or --json")
+	errCalendarWithAllPeriod     = errors.New("--calendar needs a bounded period, and can't be used with \"all\"")
+	errSparklineInteractive      = errors.New("--sparkline can't be used together with --interactive")
+	errSparklineWithAllPeriod    = errors.New("--sparkline needs a bounded period, and can't be used with \"all\"")
+	errGroupByInteractive        = errors.New("--group-by can't be used together with --interactive")
+	errGroupByWithOtherOutput    = errors.New("--group-by can't be used together with --calendar, --extremes, --json, or --sparkline")
+	errGroupByWithAllPeriod      = errors.New("--group-by needs a bounded period, and can't be used with \"all\"")
+	errByCategoryInteractive     = errors.New("--by-category can't be used together with --interactive")
+	errByCategoryWithOtherOutput = errors.New("--by-category can't be used together with --calendar, --extremes, --group-by, --json, or --sparkline")
+	errByCategoryWithAllPeriod   = errors.New("--by-category needs a bounded period, and can't be used with \"all\"")
+	errCouldntGetLastPeriod      = errors.New("couldn't get the last used period")
+	errCouldntSaveLastPeriod     = errors.New("couldn't save the last used period")
 
 	msgReportIssue = fmt.Sprintf("This isn't supposed to happen; let %s know about this error via \n%s.", c.Author, c.RepoIssuesURL)
 )
@@ -133,6 +181,8 @@ func NewRootCommand() (*cobra.Command, error) {
 		userConfigDir       string
 		themesDir           string
 		syncConfigPath      string
+		configPath          string
+		cfg                 config
 		dbPath              string
 		dbPathFull          string
 		db                  *sql.DB
@@ -140,14 +190,49 @@ func NewRootCommand() (*cobra.Command, error) {
 		style               ui.Style
 		syncConfig          ui.SyncConfig
 		syncConfigStatusErr string
-		reportAgg           bool
-		recordsInteractive  bool
-		recordsOutputPlain  bool
-		taskStatusStr       string
+		reportOpts          reportOptions
+		logOpts             logOptions
+		statsOpts           statsOptions
+		reportNoActive      bool
 		activeTemplate      string
+		activeJSON          bool
+		activeExitCode      bool
 		genNumDays          uint8
 		genNumTasks         uint8
 		genSkipConfirmation bool
+		genDryRun           bool
+		stopComment         string
+		stopAt              string
+		addBegin            string
+		addEnd              string
+		addComment          string
+		dailyMax            time.Duration
+		weeklyGoal          time.Duration
+		idleThreshold       time.Duration
+		pomodoroWork        time.Duration
+		pomodoroBreak       time.Duration
+		pomodoroAutoStop    bool
+		trackingReminder    time.Duration
+		mergeSameDay        bool
+		shiftStep           time.Duration
+		roundTo             time.Duration
+		maxSummaryLength    int
+		editLogBegin        string
+		editLogEnd          string
+		editLogComment      string
+		editLogTaskID       int
+		pruneTaskID         int
+		pruneSkipConfirm    bool
+		archiveOlderThan    string
+		exportFormat        string
+		exportAll           bool
+		importForce         bool
+		dbBackupOut         string
+		dbBackupForce       bool
+		themeExportOut      string
+		allowOverlap        bool
+		togglTaskFrom       string
+		repairAll           bool
 	)
 
 	preRun := func(cmd *cobra.Command, _ []string) error {
@@ -187,9 +272,9 @@ Clean up error: %s
 %s
 
 `, msgReportIssue)
-		case errors.Is(err, pers.ErrDBDowngraded):
-			fmt.Fprintf(os.Stderr, `Looks like you downgraded hours. You should either delete hours' database file (you
-will lose data by doing that), or upgrade hours to the latest version.
+		case errors.Is(err, pers.ErrDBSchemaTooNew):
+			fmt.Fprintf(os.Stderr, `hours' database was last used by a newer version of hours than this one. Upgrade
+hours to the latest version to keep using it.
 
 `)
 		case errors.Is(err, pers.ErrDBMigrationFailed):
@@ -228,6 +313,10 @@ Sorry for breaking the upgrade step!
 
 		syncConfig, syncConfigStatusErr = loadSyncConfig(syncConfigPath)
 
+		if cfg, err = loadConfig(configPath); err != nil {
+			return err
+		}
+
 		return nil
 	}
 
@@ -241,27 +330,92 @@ summary statistics for your tracked time.
 `,
 		SilenceUsage: true,
 		PreRunE:      preRun,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return ui.RenderUI(
-				db,
-				style,
-				types.RealTimeProvider{},
-				syncConfig,
-				syncConfigStatusErr,
-				syncConfigPath,
-				func(config ui.SyncConfig) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if dailyMax < 0 {
+				return fmt.Errorf("%w: %s", errDailyMaxInvalid, dailyMax)
+			}
+			if weeklyGoal < 0 {
+				return fmt.Errorf("%w: %s", errWeeklyGoalInvalid, weeklyGoal)
+			}
+			if cmd.Flags().Changed("weekly-goal") {
+				if err := pers.SetWeeklyGoal(db, int(weeklyGoal.Seconds())); err != nil {
+					return fmt.Errorf("%w: %s", errCouldntSaveWeeklyGoal, err.Error())
+				}
+			}
+			if idleThreshold < 0 {
+				return fmt.Errorf("%w: %s", errIdleThresholdInvalid, idleThreshold)
+			}
+			if pomodoroWork < 0 {
+				return fmt.Errorf("%w: %s", errPomodoroWorkInvalid, pomodoroWork)
+			}
+			if pomodoroBreak <= 0 {
+				return fmt.Errorf("%w: %s", errPomodoroBreakInvalid, pomodoroBreak)
+			}
+			if trackingReminder < 0 {
+				return fmt.Errorf("%w: %s", errTrackingReminderInvalid, trackingReminder)
+			}
+			if shiftStep <= 0 {
+				return fmt.Errorf("%w: %s", errShiftStepInvalid, shiftStep)
+			}
+			if roundTo < 0 {
+				return fmt.Errorf("%w: %s", errRoundInvalid, roundTo)
+			}
+			if maxSummaryLength <= 0 || maxSummaryLength > pers.MaxTaskSummaryLength {
+				return fmt.Errorf("%w: %d; it needs to be between 1 and %d", errMaxSummaryLengthInvalid, maxSummaryLength, pers.MaxTaskSummaryLength)
+			}
+
+			return ui.RenderUI(db, style, ui.Options{
+				TimeProvider:        types.RealTimeProvider{},
+				SyncConfig:          syncConfig,
+				SyncConfigStatusErr: syncConfigStatusErr,
+				SyncConfigPath:      syncConfigPath,
+				SaveSyncConfig: func(config ui.SyncConfig) error {
 					return saveSyncConfig(syncConfigPath, config)
 				},
-				clientpkg.RunOnce,
-			)
+				RunSync:       clientpkg.RunOnce,
+				DailyMax:      dailyMax,
+				IdleThreshold: idleThreshold,
+				Pomodoro: ui.PomodoroConfig{
+					Work:     pomodoroWork,
+					Break:    pomodoroBreak,
+					AutoStop: pomodoroAutoStop,
+				},
+				TrackingReminder: trackingReminder,
+				MergeSameDay:     mergeSameDay,
+				ShiftStep:        shiftStep,
+				RoundTo:          roundTo,
+				MaxSummaryLength: maxSummaryLength,
+				ThemeName:        themeName,
+				ThemesDir:        themesDir,
+			})
 		},
 	}
 
-	generateCmd := newGenerateCmd(&db, preRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation)
-	reportCmd := newReportCmd(&db, preRun, &style, &reportAgg, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
-	logCmd := newLogCmd(&db, preRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
-	statsCmd := newStatsCmd(&db, preRun, &style, &recordsInteractive, &recordsOutputPlain, &taskStatusStr)
-	activeCmd := newActiveCmd(&db, preRun, &activeTemplate)
+	generateCmd := newGenerateCmd(&db, preRun, &dbPath, &dbPathFull, &genNumDays, &genNumTasks, &genSkipConfirmation, &genDryRun)
+	reportCmd := newReportCmd(&db, preRun, &style, &cfg, &reportOpts)
+	logCmd := newLogCmd(&db, preRun, &style, &cfg, &logOpts)
+	statsCmd := newStatsCmd(&db, preRun, &style, &cfg, &statsOpts)
+	activeCmd := newActiveCmd(&db, preRun, &activeTemplate, &activeJSON, &activeExitCode)
+	startCmd := newStartCmd(&db, preRun)
+	stopCmd := newStopCmd(&db, preRun, &stopComment, &stopAt, &mergeSameDay, &roundTo)
+	addCmd := newAddCmd(&db, preRun, &addBegin, &addEnd, &addComment, &allowOverlap, &roundTo)
+	editLogCmd := newEditLogCmd(&db, preRun, &editLogBegin, &editLogEnd, &editLogComment, &editLogTaskID, &allowOverlap)
+	addBatchCmd := newAddBatchCmd(&db, preRun, &allowOverlap)
+	repairCmd := newRepairCmd(&db, preRun, &repairAll)
+	tagTaskCmd := newTagTaskCmd(&db, preRun)
+	untagTaskCmd := newUntagTaskCmd(&db, preRun)
+	renameTagCmd := newRenameTagCmd(&db, preRun)
+	deleteTagCmd := newDeleteTagCmd(&db, preRun)
+	pruneCmd := newPruneCmd(&db, preRun, &pruneTaskID, &pruneSkipConfirm)
+	archiveCmd := newArchiveCmd(&db, preRun, &archiveOlderThan)
+	setWeekResetCmd := newSetWeekResetCmd(&db, preRun)
+	exportCmd := newExportCmd(&db, preRun, &exportFormat, &exportAll)
+	importCmd := newImportCmd(&db, preRun, &importForce)
+	dbCmd := newDBCmd()
+	dbBackupCmd := newDBBackupCmd(&db, preRun, &dbBackupOut, &dbBackupForce)
+	dbOptimizeCmd := newDBOptimizeCmd(&db, preRun, &dbPathFull)
+	importTogglCmd := newImportTogglCmd(&db, preRun, &togglTaskFrom, &allowOverlap)
+	taskCmd := newTaskCmd(&db, preRun)
 
 	themesCmd := &cobra.Command{
 		Use:   "themes",
@@ -299,29 +453,21 @@ You can edit it as per your liking.
 	listThemesCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List built-in and custom themes set up for hours",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			themes := theme.BuiltIn()
-			walkErr := filepath.Walk(themesDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					if errors.Is(err, fs.ErrNotExist) {
-						return nil
-					}
-
-					return err
-				}
-				ext := filepath.Ext(path)
-				if !info.IsDir() && ext == ".json" {
-					base := filepath.Base(path)
-					themes = append(themes, fmt.Sprintf("%s%s", theme.CustomThemePrefix, strings.TrimSuffix(base, ext)))
-				}
-				return nil
-			})
+		Long: `List built-in and custom themes set up for hours.
 
-			if walkErr != nil {
-				return fmt.Errorf("%w: %s", errCouldntListThemes, walkErr.Error())
+Custom themes are the JSON files in hours' themes directory, and are listed with
+the "custom:" prefix. The theme currently in use (via --theme, or the
+HOURS_THEME environment variable) is marked with "(current)".
+`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			resolveThemeFromEnvOrFlag(cmd, &themeName, envVarTheme)
+
+			themes, err := listThemes(themesDir, themeName)
+			if err != nil {
+				return err
 			}
 
-			fmt.Printf("%s\n", strings.Join(themes, "\n"))
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", strings.Join(themes, "\n"))
 			return nil
 		},
 	}
@@ -353,7 +499,7 @@ You can choose to provide only the attributes you want to change.
 				return err
 			}
 
-			themeBytes, err := json.MarshalIndent(thm, "", "  ")
+			themeBytes, err := theme.Export(thm)
 			if err != nil {
 				return fmt.Errorf("%w: %w", errCouldntMarshalTheme, err)
 			}
@@ -364,6 +510,49 @@ You can choose to provide only the attributes you want to change.
 		},
 	}
 
+	validateThemeCmd := &cobra.Command{
+		Use:   "validate <PATH_OR_THEME_NAME>",
+		Short: "Check a theme's colors without starting hours",
+		Long: `Check a theme's colors without starting hours.
+
+Accepts either the path to a theme file, or the name of a theme (custom themes
+are prefixed with "custom:"). Fields with invalid colors (eg. "tasks[2]") are
+printed one per line, and the command exits with a non-zero status; otherwise
+"valid" is printed.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validateTheme(cmd.OutOrStdout(), args[0], themesDir)
+		},
+	}
+
+	exportThemeCmd := &cobra.Command{
+		Use:   "export <THEME_NAME>",
+		Short: "Export a theme's config as JSON",
+		Long: `Export a theme's config as JSON, including the colors used for tasks.
+
+The output is meant to be used as a starting point for a custom theme; save it
+to hours' themes directory and tweak it as per your liking.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportTheme(cmd.OutOrStdout(), args[0], themesDir, themeExportOut)
+		},
+	}
+
+	previewThemeCmd := &cobra.Command{
+		Use:   "preview <THEME_NAME>",
+		Short: "Preview how hours looks with a theme",
+		Long: `Preview how hours looks with a theme, using made up data.
+
+Custom themes can be previewed by prefixing their name with "custom:".
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return previewTheme(cmd.OutOrStdout(), args[0], themesDir, types.RealTimeProvider{})
+		},
+	}
+
 	var err error
 	userHomeDir, err = os.UserHomeDir()
 	if err != nil {
@@ -377,58 +566,221 @@ You can choose to provide only the attributes you want to change.
 
 	themesDir = filepath.Join(userConfigDir, configDirName, themeDirName)
 	syncConfigPath = getSyncConfigPath(runtime.GOOS, userHomeDir, userConfigDir)
+	configPath = getConfigFilePath(runtime.GOOS, userHomeDir, userConfigDir, configFileName)
 
 	defaultDBPath := filepath.Join(userHomeDir, defaultDBName)
 
 	// Use shared flag helpers to reduce duplication
 	addDBPathFlag(rootCmd, &dbPath, defaultDBPath)
 	addThemeFlag(rootCmd, &themeName, defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+	rootCmd.Flags().DurationVar(&dailyMax, "daily-max", 0, `time you don't want to track beyond in a day (eg. "10h"); you'll be warned when you go over it`)
+	rootCmd.Flags().DurationVar(&weeklyGoal, "weekly-goal", 0, `time you aim to track in a week (eg. "40h"); it's remembered for later runs, and progress towards it is shown in the footer ("0" clears it)`)
+	rootCmd.Flags().DurationVar(&idleThreshold, "idle-threshold", 0, `time without any interaction after which you'll be offered to trim the active task log (eg. "30m"); off by default`)
+	rootCmd.Flags().DurationVar(&pomodoroWork, "pomodoro", 0, `work length after which you'll be prompted to take a break from the active task log (eg. "25m"); off by default`)
+	rootCmd.Flags().DurationVar(&pomodoroBreak, "pomodoro-break", 5*time.Minute, "break length suggested in pomodoro mode")
+	rootCmd.Flags().BoolVar(&pomodoroAutoStop, "pomodoro-auto-stop", false, "whether to also finish the active task log when a break is due in pomodoro mode")
+	rootCmd.Flags().DurationVar(&trackingReminder, "remind-every", 0, `let you know how long the active task log has been running each time this much more time passes (eg. "1h"); off by default`)
+	rootCmd.Flags().DurationVar(&shiftStep, "shift-step", 5*time.Minute, "how far J/K move a timestamp in the TUI's forms")
+	rootCmd.Flags().IntVar(&maxSummaryLength, "max-summary-length", pers.MaxTaskSummaryLength, fmt.Sprintf("maximum number of characters in a task summary entered in the TUI (at most %d)", pers.MaxTaskSummaryLength))
+	rootCmd.Flags().DurationVar(&roundTo, "round", 0, `round the time spent on finished task log entries to the nearest multiple of this (eg. "15m"), moving their end time; off by default`)
+	rootCmd.Flags().BoolVar(&mergeSameDay, "merge-same-day", false, "whether to merge a finished task log into the task's earlier entry from the same day, if there's one")
 
 	// generateCmd flags
 	generateCmd.Flags().Uint8Var(&genNumDays, "num-days", 30, "number of days to generate fake data for")
 	generateCmd.Flags().Uint8Var(&genNumTasks, "num-tasks", 10, "number of tasks to generate fake data for")
 	generateCmd.Flags().BoolVarP(&genSkipConfirmation, "yes", "y", false, "to skip confirmation")
+	generateCmd.Flags().BoolVar(&genDryRun, "dry-run", false, "whether to only print how much data would be generated, without writing anything")
 	addDBPathFlag(generateCmd, &dbPath, defaultDBPath)
 
 	// reportCmd flags
-	reportCmd.Flags().BoolVarP(&reportAgg, "agg", "a", false, "whether to aggregate data by task for each day in report")
-	reportCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view report interactively")
-	reportCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output report without any formatting")
+	reportCmd.Flags().BoolVarP(&reportOpts.agg, "agg", "a", false, "whether to aggregate data by task for each day in report")
+	reportCmd.Flags().BoolVarP(&reportOpts.interactive, "interactive", "i", false, "whether to view report interactively")
+	reportCmd.Flags().BoolVarP(&reportOpts.plain, "plain", "p", false, "whether to output report without any formatting")
+	reportCmd.Flags().StringVar(&reportOpts.format, "format", reportFormatTable, fmt.Sprintf("output format for the report (ignored in interactive mode); allowed values: %s, %s, %s", reportFormatTable, reportFormatMarkdown, reportFormatCSV))
+	reportCmd.Flags().StringVar(&reportOpts.delimiter, "delimiter", ",", "character to separate values with when using --format csv")
+	reportCmd.Flags().IntVar(&reportOpts.limit, "limit", ui.DefaultReportLimit, "maximum number of entries to show for a single day")
+	reportCmd.Flags().BoolVar(&reportOpts.firstDayOnly, "first-day-only", false, "whether to only show entries for the first day of the period")
+	reportCmd.Flags().BoolVar(&reportOpts.lastDayOnly, "last-day-only", false, "whether to only show entries for the last day of the period")
 	addDBPathFlag(reportCmd, &dbPath, defaultDBPath)
-	addTaskStatusFlag(reportCmd, &taskStatusStr)
+	addTaskStatusFlag(reportCmd, &reportOpts.taskStatusStr)
+	addTagFlag(reportCmd, &reportOpts.tag)
+	addTaskIDFlag(reportCmd, &reportOpts.taskID)
+	addLogTagFlag(reportCmd, &reportOpts.logTag)
+	addHeaderMetaFlag(reportCmd, &reportOpts.headerMeta)
+	addNoRememberFlag(reportCmd, &reportOpts.noRemember)
+	addNoActiveFlag(reportCmd, &reportNoActive)
 	addThemeFlag(reportCmd, &themeName, defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
 	// logCmd flags
-	logCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output logs without any formatting")
-	logCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view logs interactively")
+	logCmd.Flags().BoolVarP(&logOpts.plain, "plain", "p", false, "whether to output logs without any formatting")
+	logCmd.Flags().BoolVarP(&logOpts.interactive, "interactive", "i", false, "whether to view logs interactively")
+	logCmd.Flags().IntVar(&logOpts.limit, "limit", ui.DefaultLogLimit, "maximum number of log entries to show")
+	logCmd.Flags().BoolVar(&logOpts.timesheet, "timesheet", false, `whether to output each entry as a line of plain text (eg. "09:00–10:30 (1h30m) Task: comment")`)
+	logCmd.Flags().StringVar(&logOpts.since, "since", "", `show log entries since this date or time (eg. "2024/06/08", "2024/06/08 14:30") instead of for a period`)
+	logCmd.Flags().StringVar(&logOpts.until, "until", "", "show log entries until this date (inclusive) or time; needs --since, and defaults to now")
 	addDBPathFlag(logCmd, &dbPath, defaultDBPath)
-	addTaskStatusFlag(logCmd, &taskStatusStr)
+	addTaskStatusFlag(logCmd, &logOpts.taskStatusStr)
+	addTagFlag(logCmd, &logOpts.tag)
+	addTaskIDFlag(logCmd, &logOpts.taskID)
+	addHeaderMetaFlag(logCmd, &logOpts.headerMeta)
+	addNoRememberFlag(logCmd, &logOpts.noRemember)
+	addSinceCutoffFlags(logCmd, &logOpts.sinceCutoff, &logOpts.dayCutoffStr)
 	addThemeFlag(logCmd, &themeName, defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
 	// statsCmd flags
-	statsCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output stats without any formatting")
-	statsCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view stats interactively")
+	statsCmd.Flags().BoolVarP(&statsOpts.plain, "plain", "p", false, "whether to output stats without any formatting")
+	statsCmd.Flags().BoolVarP(&statsOpts.interactive, "interactive", "i", false, "whether to view stats interactively")
 	addDBPathFlag(statsCmd, &dbPath, defaultDBPath)
-	addTaskStatusFlag(statsCmd, &taskStatusStr)
+	addTaskStatusFlag(statsCmd, &statsOpts.taskStatusStr)
+	addTagFlag(statsCmd, &statsOpts.tag)
+	addTaskIDFlag(statsCmd, &statsOpts.taskID)
+	addHeaderMetaFlag(statsCmd, &statsOpts.headerMeta)
+	addNoRememberFlag(statsCmd, &statsOpts.noRemember)
+	addSinceCutoffFlags(statsCmd, &statsOpts.sinceCutoff, &statsOpts.dayCutoffStr)
+	statsCmd.Flags().BoolVar(&statsOpts.extremes, "extremes", false, "whether to only show the days with the most and the least time tracked")
+	statsCmd.Flags().BoolVar(&statsOpts.includeZero, "include-zero", false, "whether to consider days with no time tracked for --extremes")
+	statsCmd.Flags().BoolVar(&statsOpts.json, "json", false, "whether to output stats as JSON (ignores --plain and --header-meta)")
+	statsCmd.Flags().BoolVar(&statsOpts.sparkline, "sparkline", false, "whether to show a line with a glyph for each day's tracked time below the stats")
+	statsCmd.Flags().BoolVar(&statsOpts.calendar, "calendar", false, "whether to show a calendar with each day shaded by the time tracked on it")
+	statsCmd.Flags().IntVar(&statsOpts.limit, "limit", ui.DefaultStatsLimit, "maximum number of tasks to show")
+	statsCmd.Flags().BoolVar(&statsOpts.byCategory, "by-category", false, "whether to show the time tracked for each task log category instead")
+	statsCmd.Flags().StringVar(&statsOpts.groupBy, "group-by", "", fmt.Sprintf("show stats for each period of this length in the range; allowed values: %s", strings.Join(types.ValidGranularityValues, ", ")))
 	addThemeFlag(statsCmd, &themeName, defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
 	// activeCmd flags
 	activeCmd.Flags().StringVarP(&activeTemplate, "template", "t", ui.ActiveTaskPlaceholder, "string template to use for outputting active task")
+	activeCmd.Flags().BoolVar(&activeJSON, "json", false, "whether to output the active task as JSON (ignores --template)")
+	activeCmd.Flags().BoolVar(&activeExitCode, "exit-code", false, "whether to exit with code 1 when no task is being tracked")
 	addDBPathFlag(activeCmd, &dbPath, defaultDBPath)
 
+	// startCmd flags
+	addDBPathFlag(startCmd, &dbPath, defaultDBPath)
+
+	// stopCmd flags
+	stopCmd.Flags().StringVarP(&stopComment, "comment", "c", "", "comment to save with the task log entry")
+	stopCmd.Flags().StringVar(&stopAt, "at", "", `time to stop tracking at (eg. "2024/06/08 17:30"); defaults to now`)
+	stopCmd.Flags().BoolVar(&mergeSameDay, "merge-same-day", false, "whether to merge the finished task log into the task's earlier entry from the same day, if there's one")
+	stopCmd.Flags().DurationVar(&roundTo, "round", 0, `round the time spent on finished task log entries to the nearest multiple of this (eg. "15m"), moving their end time; off by default`)
+	addDBPathFlag(stopCmd, &dbPath, defaultDBPath)
+
+	// addCmd flags
+	addCmd.Flags().StringVarP(&addBegin, "begin", "b", "", `begin time of the task log entry (eg. "2024/06/08 09:30")`)
+	addCmd.Flags().StringVarP(&addEnd, "end", "e", "", `end time of the task log entry (eg. "2024/06/08 10:45")`)
+	addCmd.Flags().StringVarP(&addComment, "comment", "c", "", "comment to save with the task log entry")
+	addCmd.Flags().BoolVar(&allowOverlap, "allow-overlap", false, "whether to allow the entry to overlap with existing entries for the same task")
+	addCmd.Flags().DurationVar(&roundTo, "round", 0, `round the time spent on finished task log entries to the nearest multiple of this (eg. "15m"), moving their end time; off by default`)
+	addDBPathFlag(addCmd, &dbPath, defaultDBPath)
+
+	// editLogCmd flags
+	editLogCmd.Flags().StringVarP(&editLogBegin, "begin", "b", "", `new begin time of the task log entry (eg. "2024/06/08 09:30")`)
+	editLogCmd.Flags().StringVarP(&editLogEnd, "end", "e", "", `new end time of the task log entry (eg. "2024/06/08 10:45")`)
+	editLogCmd.Flags().StringVarP(&editLogComment, "comment", "c", "", "new comment for the task log entry")
+	editLogCmd.Flags().IntVarP(&editLogTaskID, "task", "t", 0, "ID of the task to move the task log entry to")
+	editLogCmd.Flags().BoolVar(&allowOverlap, "allow-overlap", false, "whether to allow the entry to overlap with other entries for the same task")
+	addDBPathFlag(editLogCmd, &dbPath, defaultDBPath)
+
+	// addBatchCmd flags
+	addBatchCmd.Flags().BoolVar(&allowOverlap, "allow-overlap", false, "whether to allow entries to overlap with other entries for the same task")
+	addDBPathFlag(addBatchCmd, &dbPath, defaultDBPath)
+
+	// repairCmd flags
+	repairCmd.Flags().BoolVar(&repairAll, "all", false, "whether to repair the time spent on all tasks")
+	addDBPathFlag(repairCmd, &dbPath, defaultDBPath)
+
+	// tagTaskCmd flags
+	addDBPathFlag(tagTaskCmd, &dbPath, defaultDBPath)
+
+	// untagTaskCmd flags
+	addDBPathFlag(untagTaskCmd, &dbPath, defaultDBPath)
+
+	// renameTagCmd flags
+	addDBPathFlag(renameTagCmd, &dbPath, defaultDBPath)
+
+	// deleteTagCmd flags
+	addDBPathFlag(deleteTagCmd, &dbPath, defaultDBPath)
+
+	// pruneCmd flags
+	pruneCmd.Flags().IntVarP(&pruneTaskID, "task", "t", 0, "ID of the task to only delete task log entries for")
+	pruneCmd.Flags().BoolVarP(&pruneSkipConfirm, "yes", "y", false, "to skip confirmation")
+	addDBPathFlag(pruneCmd, &dbPath, defaultDBPath)
+
+	// archiveCmd flags
+	archiveCmd.Flags().StringVar(&archiveOlderThan, "older-than", defaultArchiveOlderThan, `archive tasks with no task log entries in this many days or weeks (eg. "30d", "4w")`)
+	addDBPathFlag(archiveCmd, &dbPath, defaultDBPath)
+
+	// setWeekResetCmd flags
+	addDBPathFlag(setWeekResetCmd, &dbPath, defaultDBPath)
+
+	// exportCmd flags
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormatCSV, fmt.Sprintf("output format for the export; allowed values: %s", exportFormatCSV))
+	exportCmd.Flags().BoolVar(&exportAll, "all", false, "export every task and task log as JSON, for a full backup")
+	addDBPathFlag(exportCmd, &dbPath, defaultDBPath)
+
+	// importCmd flags
+	importCmd.Flags().BoolVar(&importForce, "force", false, "replace all existing tasks and task logs with the ones in the backup")
+	addDBPathFlag(importCmd, &dbPath, defaultDBPath)
+
+	// importTogglCmd flags
+	importTogglCmd.Flags().StringVar(&togglTaskFrom, "task-from", togglTaskFromProject, fmt.Sprintf("what to name tasks after; allowed values: %s, %s", togglTaskFromProject, togglTaskFromDescription))
+	importTogglCmd.Flags().BoolVar(&allowOverlap, "allow-overlap", false, "whether to allow entries to overlap with existing entries for the same task")
+	addDBPathFlag(importTogglCmd, &dbPath, defaultDBPath)
+
+	// dbBackupCmd flags
+	dbBackupCmd.Flags().StringVar(&dbBackupOut, "out", "", "file to write the backup to")
+	dbBackupCmd.Flags().BoolVar(&dbBackupForce, "force", false, "overwrite the file at --out if it already exists")
+	addDBPathFlag(dbBackupCmd, &dbPath, defaultDBPath)
+
+	// dbOptimizeCmd flags
+	addDBPathFlag(dbOptimizeCmd, &dbPath, defaultDBPath)
+
+	// taskCmd flags
+	for _, cmd := range taskCmd.Commands() {
+		addDBPathFlag(cmd, &dbPath, defaultDBPath)
+	}
+
+	// listThemesCmd flags
+	addThemeFlag(listThemesCmd, &themeName, defaultThemeName, "UI theme to mark as the current one")
+
+	// exportThemeCmd flags
+	exportThemeCmd.Flags().StringVar(&themeExportOut, "out", "", "file to write the theme config to (printed to stdout if not provided)")
+
 	// showThemeConfigCmd flags
 	addThemeFlag(showThemeConfigCmd, &themeName, defaultThemeName, `UI theme to show (run "hours themes list" for allowed values)`)
 
+	dbCmd.AddCommand(dbBackupCmd)
+	dbCmd.AddCommand(dbOptimizeCmd)
+
 	themesCmd.AddCommand(addThemeCmd)
 	themesCmd.AddCommand(listThemesCmd)
 	themesCmd.AddCommand(sampleThemeCmd)
 	themesCmd.AddCommand(showThemeConfigCmd)
+	themesCmd.AddCommand(previewThemeCmd)
+	themesCmd.AddCommand(exportThemeCmd)
+	themesCmd.AddCommand(validateThemeCmd)
 
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(activeCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(editLogCmd)
+	rootCmd.AddCommand(addBatchCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(tagTaskCmd)
+	rootCmd.AddCommand(untagTaskCmd)
+	rootCmd.AddCommand(renameTagCmd)
+	rootCmd.AddCommand(deleteTagCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(setWeekResetCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(importTogglCmd)
+	rootCmd.AddCommand(taskCmd)
+	rootCmd.AddCommand(dbCmd)
 	rootCmd.AddCommand(themesCmd)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"path/filepath"
-
 	clientpkg "github.com/dhth/hours/internal/client"
 	syncpkg "github.com/dhth/hours/internal/sync"
 )
@@ -13,11 +11,7 @@ const (
 )
 
 func getSyncConfigPath(goos, userHomeDir, userConfigDir string) string {
-	if goos == "darwin" {
-		return filepath.Join(userHomeDir, macOSConfigParentDirName, configDirName, syncConfigFileName)
-	}
-
-	return filepath.Join(userConfigDir, configDirName, syncConfigFileName)
+	return getConfigFilePath(goos, userHomeDir, userConfigDir, syncConfigFileName)
 }
 
 func loadSyncConfig(path string) (syncpkg.Config, string) {
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/spf13/cobra"
+)
+
+// newTagTaskCmd creates the tag-task command
+func newTagTaskCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tag-task TASK_ID TAG",
+		Short: "Add a tag to a task",
+		Long: `Add a tag to a task.
+
+Tagged tasks can be picked out in reports, logs, and stats using --tag. Adding
+a tag the task already carries does nothing.
+`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := pers.AddTaskTag(*db, taskID, args[1]); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Tagged task %d with %q\n", taskID, strings.TrimSpace(args[1]))
+			return nil
+		},
+	}
+}
+
+// newUntagTaskCmd creates the untag-task command
+func newUntagTaskCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "untag-task TASK_ID TAG",
+		Short: "Remove a tag from a task",
+		Long: `Remove a tag from a task.
+
+Removing a tag the task doesn't carry does nothing. Use delete-tag to remove a
+tag from all tasks and task log entries.
+`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := pers.RemoveTaskTag(*db, taskID, args[1]); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed tag %q from task %d\n", strings.TrimSpace(args[1]), taskID)
+			return nil
+		},
+	}
+}
+
+// newRenameTagCmd creates the rename-tag command
+func newRenameTagCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename-tag OLD_TAG NEW_TAG",
+		Short: "Rename a tag on all tasks and task log entries",
+		Long: `Rename a tag on all tasks and task log entries.
+
+Tasks and task log entries that already carry the new tag simply lose the old
+one.
+`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := pers.RenameTag(*db, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Renamed tag on %d task(s) and %d task log entry(s)\n", result.NumTasks, result.NumTaskLogs)
+			return nil
+		},
+	}
+}
+
+// newDeleteTagCmd creates the delete-tag command
+func newDeleteTagCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete-tag TAG",
+		Short: "Remove a tag from all tasks and task log entries",
+		Long: `Remove a tag from all tasks and task log entries.
+
+The tasks and task log entries themselves are left untouched.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := pers.DeleteTag(*db, args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed tag from %d task(s) and %d task log entry(s)\n", result.NumTasks, result.NumTaskLogs)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTagTaskCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newTagTaskCmd(nil, mockPreRun)
+
+		assert.Equal(t, "tag-task TASK_ID TAG", cmd.Use)
+		assert.Equal(t, "Add a tag to a task", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("tags a task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTagTaskCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1", " work "})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Tagged task 1 with \"work\"\n", out.String())
+		tags, err := persistence.FetchTagsForTask(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, tags)
+	})
+
+	t.Run("fails for a tag with a comma", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTagTaskCmd(&db, mockPreRun)
+
+		err = cmd.RunE(cmd, []string{"1", "work,deep"})
+
+		assert.ErrorIs(t, err, persistence.ErrTagHasComma)
+	})
+
+	t.Run("fails for an unknown task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newTagTaskCmd(&db, mockPreRun)
+
+		err := cmd.RunE(cmd, []string{"1", "work"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
+
+func TestNewUntagTaskCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newUntagTaskCmd(nil, mockPreRun)
+
+		assert.Equal(t, "untag-task TASK_ID TAG", cmd.Use)
+		assert.Equal(t, "Remove a tag from a task", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("removes a tag from a task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		require.NoError(t, persistence.AddTaskTag(db, taskID, "work"))
+		require.NoError(t, persistence.AddTaskTag(db, taskID, "deep"))
+
+		cmd := newUntagTaskCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1", "work"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Removed tag \"work\" from task 1\n", out.String())
+		tags, err := persistence.FetchTagsForTask(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"deep"}, tags)
+	})
+}
+
+func TestNewRenameTagCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newRenameTagCmd(nil, mockPreRun)
+
+		assert.Equal(t, "rename-tag OLD_TAG NEW_TAG", cmd.Use)
+		assert.Equal(t, "Rename a tag on all tasks and task log entries", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("renames a tag on tasks and task log entries", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		require.NoError(t, persistence.AddTaskTag(db, taskID, "wrok"))
+		end := time.Now().Add(-time.Hour)
+		tlID, err := persistence.InsertManualTL(db, taskID, end.Add(-time.Hour), end, nil, nil, nil, false)
+		require.NoError(t, err)
+		require.NoError(t, persistence.SetTLTags(db, tlID, []string{"wrok", "deep"}))
+
+		cmd := newRenameTagCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"wrok", "work"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed tag on 1 task(s) and 1 task log entry(s)\n", out.String())
+		tags, err := persistence.FetchTagsForTask(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, tags)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"work", "deep"}, tl.Tags)
+	})
+}
+
+func TestNewDeleteTagCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newDeleteTagCmd(nil, mockPreRun)
+
+		assert.Equal(t, "delete-tag TAG", cmd.Use)
+		assert.Equal(t, "Remove a tag from all tasks and task log entries", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("removes a tag from tasks and task log entries", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		require.NoError(t, persistence.AddTaskTag(db, taskID, "obsolete"))
+		end := time.Now().Add(-time.Hour)
+		tlID, err := persistence.InsertManualTL(db, taskID, end.Add(-time.Hour), end, nil, nil, nil, false)
+		require.NoError(t, err)
+		require.NoError(t, persistence.SetTLTags(db, tlID, []string{"obsolete", "deep"}))
+
+		cmd := newDeleteTagCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"obsolete"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Removed tag from 1 task(s) and 1 task log entry(s)\n", out.String())
+		tags, err := persistence.FetchTagsForTask(db, taskID)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"deep"}, tl.Tags)
+	})
+}
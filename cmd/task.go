@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/ui/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errTaskSummaryEmpty       = errors.New("task summary can't be empty")
+	errCantArchiveTrackedTask = errors.New("can't archive a task that's being tracked")
+	errInvalidTaskColor       = errors.New("task color needs to be a hex or ANSI 256 color code")
+)
+
+const taskColorNone = "none"
+
+// newTaskCmd creates the task command, which groups the task management
+// subcommands
+func newTaskCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	taskCmd := &cobra.Command{
+		Use:   "task",
+		Short: "Manage tasks without opening the TUI",
+	}
+
+	taskCmd.AddCommand(newTaskRenameCmd(db, preRun))
+	taskCmd.AddCommand(newTaskArchiveCmd(db, preRun))
+	taskCmd.AddCommand(newTaskUnarchiveCmd(db, preRun))
+	taskCmd.AddCommand(newTaskColorCmd(db, preRun))
+
+	return taskCmd
+}
+
+// newTaskRenameCmd creates the task rename command
+func newTaskRenameCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rename <TASK_ID> <SUMMARY>",
+		Short:   "Change the summary of a task",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			summary := strings.TrimSpace(args[1])
+			if summary == "" {
+				return errTaskSummaryEmpty
+			}
+
+			if _, err := pers.FetchTaskByID(*db, taskID); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			if err := pers.UpdateTask(*db, taskID, summary); err != nil {
+				return err
+			}
+
+			return printTaskState(cmd, *db, taskID)
+		},
+	}
+}
+
+// newTaskArchiveCmd creates the task archive command
+func newTaskArchiveCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <TASK_ID>",
+		Short: "Mark a task as inactive",
+		Long: `Mark a task as inactive.
+
+Inactive tasks are hidden from the TUI's task list, and can be brought back via
+"hours task unarchive". The task being tracked can't be archived.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTaskActiveStatus(cmd, *db, args[0], false)
+		},
+	}
+}
+
+// newTaskUnarchiveCmd creates the task unarchive command
+func newTaskUnarchiveCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:     "unarchive <TASK_ID>",
+		Short:   "Mark an archived task as active again",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTaskActiveStatus(cmd, *db, args[0], true)
+		},
+	}
+}
+
+// newTaskColorCmd creates the task color command
+func newTaskColorCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "color <TASK_ID> <COLOR>",
+		Short: "Set the color a task is shown in",
+		Long: `Set the color a task is shown in.
+
+The color can be a hex color code (eg. "#fabd2f") or an ANSI 256 color code
+(eg. "208"). Passing "none" makes the task use the theme's colors again.
+`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			color := strings.TrimSpace(args[1])
+			if strings.EqualFold(color, taskColorNone) {
+				color = ""
+			} else if !theme.IsValidColor(color) {
+				return fmt.Errorf("%w: %q", errInvalidTaskColor, color)
+			}
+
+			if err := pers.SetTaskColor(*db, taskID, color); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			if color == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Task %d now uses the theme's colors\n", taskID)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Task %d is now shown in %s\n", taskID, color)
+			}
+			return nil
+		},
+	}
+}
+
+func setTaskActiveStatus(cmd *cobra.Command, db *sql.DB, taskIDArg string, active bool) error {
+	taskID, err := parseTaskID(taskIDArg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pers.FetchTaskByID(db, taskID); err != nil {
+		return fmt.Errorf("%w (ID: %d)", err, taskID)
+	}
+
+	if !active {
+		activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+		if err != nil {
+			return err
+		}
+
+		if activeTaskDetails.TaskID == taskID {
+			return fmt.Errorf(`%w; stop tracking it with "hours stop"`, errCantArchiveTrackedTask)
+		}
+	}
+
+	if err := pers.UpdateTaskActiveStatus(db, taskID, active); err != nil {
+		return err
+	}
+
+	return printTaskState(cmd, db, taskID)
+}
+
+func printTaskState(cmd *cobra.Command, db *sql.DB, taskID int) error {
+	task, err := pers.FetchTaskByID(db, taskID)
+	if err != nil {
+		return err
+	}
+
+	status := types.TSValueActive
+	if !task.Active {
+		status = types.TSValueInactive
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Task %d: %q (%s)\n", task.ID, task.Summary, status)
+	return nil
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newTaskCmd(nil, mockPreRun)
+
+		assert.Equal(t, "task", cmd.Use)
+		assert.NotEmpty(t, cmd.Short)
+
+		var subcommands []string
+		for _, sub := range cmd.Commands() {
+			subcommands = append(subcommands, sub.Name())
+			assert.NotNil(t, sub.PreRunE)
+			assert.NotNil(t, sub.RunE)
+		}
+		assert.ElementsMatch(t, []string{"rename", "archive", "unarchive", "color"}, subcommands)
+	})
+}
+
+func TestNewTaskRenameCmd(t *testing.T) {
+	t.Run("renames a task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "old summary")
+		require.NoError(t, err)
+
+		cmd := newTaskRenameCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1", "  new summary "})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Task 1: \"new summary\" (active)\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, "new summary", task.Summary)
+	})
+
+	t.Run("fails for an empty summary", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTaskRenameCmd(&db, mockPreRun)
+		err = cmd.RunE(cmd, []string{"1", "  "})
+
+		assert.ErrorIs(t, err, errTaskSummaryEmpty)
+	})
+
+	t.Run("fails for a summary that's too long", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTaskRenameCmd(&db, mockPreRun)
+		err = cmd.RunE(cmd, []string{"1", strings.Repeat("a", persistence.MaxTaskSummaryLength+1)})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskSummaryTooLong)
+	})
+
+	t.Run("fails for a task that doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newTaskRenameCmd(&db, mockPreRun)
+		err := cmd.RunE(cmd, []string{"42", "a summary"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+
+	t.Run("fails for an invalid task ID", func(t *testing.T) {
+		cmd := newTaskRenameCmd(nil, mockPreRun)
+
+		err := cmd.RunE(cmd, []string{"abc", "a summary"})
+
+		assert.ErrorIs(t, err, errTaskIDInvalid)
+	})
+}
+
+func TestNewTaskArchiveCmds(t *testing.T) {
+	t.Run("archives and unarchives a task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		archiveCmd := newTaskArchiveCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		archiveCmd.SetOut(&out)
+		err = archiveCmd.RunE(archiveCmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Task 1: \"a task\" (inactive)\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.False(t, task.Active)
+
+		unarchiveCmd := newTaskUnarchiveCmd(&db, mockPreRun)
+		out.Reset()
+		unarchiveCmd.SetOut(&out)
+		err = unarchiveCmd.RunE(unarchiveCmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Task 1: \"a task\" (active)\n", out.String())
+		task, err = persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.True(t, task.Active)
+	})
+
+	t.Run("fails to archive the task being tracked", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, taskID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		cmd := newTaskArchiveCmd(&db, mockPreRun)
+		err = cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, errCantArchiveTrackedTask)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.True(t, task.Active)
+	})
+
+	t.Run("fails for a task that doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newTaskUnarchiveCmd(&db, mockPreRun)
+		err := cmd.RunE(cmd, []string{"42"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
+
+func TestNewTaskColorCmd(t *testing.T) {
+	t.Run("sets and clears a task's color", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTaskColorCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1", "#fabd2f"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Task 1 is now shown in #fabd2f\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		require.NotNil(t, task.Color)
+		assert.Equal(t, "#fabd2f", *task.Color)
+
+		out.Reset()
+		err = cmd.RunE(cmd, []string{"1", "none"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Task 1 now uses the theme's colors\n", out.String())
+		task, err = persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Nil(t, task.Color)
+	})
+
+	t.Run("fails for an invalid color", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newTaskColorCmd(&db, mockPreRun)
+		err = cmd.RunE(cmd, []string{"1", "orange"})
+
+		assert.ErrorIs(t, err, errInvalidTaskColor)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Nil(t, task.Color)
+	})
+
+	t.Run("fails for a task that doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newTaskColorCmd(&db, mockPreRun)
+		err := cmd.RunE(cmd, []string{"1", "208"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
@@ -2,17 +2,24 @@ package cmd
 
 import (
 	_ "embed"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/ui"
 	"github.com/dhth/hours/internal/ui/theme"
 )
 
-const themeNameRegexPattern = `^[a-zA-Z0-9-]{1,20}$`
+const (
+	themeNameRegexPattern = `^[a-zA-Z0-9-]{1,20}$`
+	currentThemeMarker    = " (current)"
+)
 
 var themeNameRegExp = regexp.MustCompile(themeNameRegexPattern)
 
@@ -25,6 +32,7 @@ var (
 	errCouldntCreateThemesDir  = errors.New("couldn't create themes directory")
 	errCouldntCreateThemeFile  = errors.New("couldn't create theme file")
 	errCouldntWriteToThemeFile = errors.New("couldn't write to theme file")
+	errCouldntReadThemeFile    = errors.New("couldn't read theme file")
 )
 
 func addTheme(themeName string, themesDir string) (string, error) {
@@ -33,8 +41,7 @@ func addTheme(themeName string, themesDir string) (string, error) {
 		return zero, errThemeNameInvalid
 	}
 
-	defaultTheme := theme.Default()
-	themeBytes, err := json.MarshalIndent(defaultTheme, "", "  ")
+	themeBytes, err := theme.Export(theme.Default())
 	if err != nil {
 		return zero, fmt.Errorf("%w: %s", errMarshallingDefaultTheme, err.Error())
 	}
@@ -59,3 +66,128 @@ func addTheme(themeName string, themesDir string) (string, error) {
 
 	return themePath, nil
 }
+
+// previewTheme writes a sample of the UI, rendered using the theme with the
+// given name, to w.
+func previewTheme(w io.Writer, themeName string, themesDir string, timeProvider types.TimeProvider) error {
+	style, err := getStyle(themeName, themesDir)
+	if errors.Is(err, theme.ErrBuiltInThemeDoesntExist) {
+		return fmt.Errorf("%w; built-in themes: %s", err, strings.Join(theme.BuiltIn(), ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	preview, err := ui.RenderThemePreview(style, timeProvider)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, preview)
+	return nil
+}
+
+// listThemes returns the names of the built-in themes, followed by the ones of
+// the custom themes in themesDir (if it exists). The theme matching current is
+// marked as such.
+func listThemes(themesDir string, current string) ([]string, error) {
+	themes := theme.BuiltIn()
+	walkErr := filepath.Walk(themesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+		ext := filepath.Ext(path)
+		if !info.IsDir() && ext == ".json" {
+			base := filepath.Base(path)
+			themes = append(themes, fmt.Sprintf("%s%s", theme.CustomThemePrefix, strings.TrimSuffix(base, ext)))
+		}
+		return nil
+	})
+
+	if walkErr != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntListThemes, walkErr.Error())
+	}
+
+	current = strings.TrimSpace(current)
+	if current == defaultThemeName {
+		current = theme.DefaultBuiltIn()
+	}
+
+	for i, name := range themes {
+		if name == current {
+			themes[i] += currentThemeMarker
+		}
+	}
+
+	return themes, nil
+}
+
+// exportTheme writes the JSON config of the theme with the given name to
+// outPath, or to w if outPath is empty. The output can be used as is as a
+// custom theme.
+func exportTheme(w io.Writer, themeName string, themesDir string, outPath string) error {
+	thm, err := theme.Get(themeName, themesDir)
+	if err != nil {
+		return err
+	}
+
+	themeBytes, err := theme.Export(thm)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntMarshalTheme, err)
+	}
+
+	if outPath == "" {
+		fmt.Fprintf(w, "%s\n", themeBytes)
+		return nil
+	}
+
+	err = os.WriteFile(outPath, append(themeBytes, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntWriteToThemeFile, err.Error())
+	}
+
+	fmt.Fprintf(w, "Theme %q exported to %s\n", themeName, outPath)
+	return nil
+}
+
+// validateTheme checks the colors of a theme, given either the path to a
+// theme file, or the name of a theme (custom ones prefixed with "custom:").
+// Offending fields are written to w, one per line.
+func validateTheme(w io.Writer, pathOrName string, themesDir string) error {
+	themePath := pathOrName
+	if customThemeName, ok := strings.CutPrefix(pathOrName, theme.CustomThemePrefix); ok {
+		themePath = filepath.Join(themesDir, fmt.Sprintf("%s.json", customThemeName))
+	} else if _, err := os.Stat(pathOrName); err != nil {
+		// not a file; built-in themes are always valid, as long as they exist
+		if _, err := theme.Get(pathOrName, themesDir); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, "valid")
+		return nil
+	}
+
+	themeBytes, err := os.ReadFile(themePath)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntReadThemeFile, err.Error())
+	}
+
+	invalidColors, err := theme.InvalidColors(themeBytes)
+	if err != nil {
+		return err
+	}
+
+	if len(invalidColors) > 0 {
+		for _, field := range invalidColors {
+			fmt.Fprintln(w, field)
+		}
+		return fmt.Errorf("%w (%d field(s))", theme.ErrThemeColorsAreInvalid, len(invalidColors))
+	}
+
+	fmt.Fprintln(w, "valid")
+	return nil
+}
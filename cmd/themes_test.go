@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/ui/theme"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -117,3 +123,179 @@ func TestAddTheme_CreatesThemesDirectory(t *testing.T) {
 	// Verify file was created in the right place
 	assert.Equal(t, filepath.Join(themesDir, themeName+".json"), path)
 }
+
+func TestPreviewTheme(t *testing.T) {
+	timeProvider := types.TestTimeProvider{FixedTime: time.Date(2025, time.August, 16, 9, 0, 0, 0, time.Local)}
+
+	t.Run("renders a colored sample for a built-in theme", func(t *testing.T) {
+		profile := lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+		var out bytes.Buffer
+		err := previewTheme(&out, "dracula", t.TempDir(), timeProvider)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "Write the quarterly report")
+		assert.Contains(t, out.String(), "tracking:")
+		assert.Contains(t, out.String(), "\x1b[")
+	})
+
+	t.Run("renders a custom theme", func(t *testing.T) {
+		themesDir := t.TempDir()
+		_, err := addTheme("mine", themesDir)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		err = previewTheme(&out, "custom:mine", themesDir, timeProvider)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, out.String())
+	})
+
+	t.Run("fails for an unknown built-in theme", func(t *testing.T) {
+		var out bytes.Buffer
+		err := previewTheme(&out, "unknown", t.TempDir(), timeProvider)
+
+		assert.ErrorIs(t, err, theme.ErrBuiltInThemeDoesntExist)
+		assert.ErrorContains(t, err, "dracula")
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestListThemes(t *testing.T) {
+	t.Run("lists custom themes with the custom prefix", func(t *testing.T) {
+		themesDir := t.TempDir()
+		for _, name := range []string{"mine", "yours"} {
+			_, err := addTheme(name, themesDir)
+			require.NoError(t, err)
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(themesDir, "notes.txt"), []byte("not a theme"), 0o644))
+
+		themes, err := listThemes(themesDir, "dracula")
+
+		require.NoError(t, err)
+		assert.Contains(t, themes, "custom:mine")
+		assert.Contains(t, themes, "custom:yours")
+		assert.Len(t, themes, len(theme.BuiltIn())+2)
+	})
+
+	t.Run("marks the current theme", func(t *testing.T) {
+		themesDir := t.TempDir()
+		_, err := addTheme("mine", themesDir)
+		require.NoError(t, err)
+
+		themes, err := listThemes(themesDir, "custom:mine")
+
+		require.NoError(t, err)
+		assert.Contains(t, themes, "custom:mine (current)")
+		assert.Contains(t, themes, "dracula")
+	})
+
+	t.Run("marks the built-in theme the default one is based on", func(t *testing.T) {
+		themes, err := listThemes(t.TempDir(), defaultThemeName)
+
+		require.NoError(t, err)
+		assert.Contains(t, themes, theme.DefaultBuiltIn()+" (current)")
+	})
+
+	t.Run("handles a missing themes directory", func(t *testing.T) {
+		themes, err := listThemes(filepath.Join(t.TempDir(), "missing"), "dracula")
+
+		require.NoError(t, err)
+		assert.Len(t, themes, len(theme.BuiltIn()))
+		assert.Contains(t, themes, "dracula (current)")
+	})
+}
+
+func TestExportTheme(t *testing.T) {
+	t.Run("writes a theme that can be used as a custom theme", func(t *testing.T) {
+		themesDir := t.TempDir()
+		outPath := filepath.Join(themesDir, "exported.json")
+
+		var out bytes.Buffer
+		err := exportTheme(&out, "gruvbox-dark", themesDir, outPath)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), outPath)
+		exported, err := theme.Get("custom:exported", themesDir)
+		require.NoError(t, err)
+		expected, err := theme.Get("gruvbox-dark", themesDir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, exported)
+	})
+
+	t.Run("prints to stdout without an output file", func(t *testing.T) {
+		var out bytes.Buffer
+		err := exportTheme(&out, "default", t.TempDir(), "")
+
+		require.NoError(t, err)
+		var thm theme.Theme
+		require.NoError(t, json.Unmarshal(out.Bytes(), &thm))
+		assert.Equal(t, theme.Default(), thm)
+	})
+
+	t.Run("fails for an unknown theme", func(t *testing.T) {
+		var out bytes.Buffer
+		err := exportTheme(&out, "unknown", t.TempDir(), "")
+
+		assert.ErrorIs(t, err, theme.ErrBuiltInThemeDoesntExist)
+	})
+}
+
+func TestValidateTheme(t *testing.T) {
+	t.Run("reports the fields with invalid colors", func(t *testing.T) {
+		themePath := filepath.Join(t.TempDir(), "broken.json")
+		require.NoError(t, os.WriteFile(themePath, []byte(`{"helpMsg": "#zzz", "tasks": ["#ffffff", "not-a-color"]}`), 0o644))
+
+		var out bytes.Buffer
+		err := validateTheme(&out, themePath, t.TempDir())
+
+		assert.ErrorIs(t, err, theme.ErrThemeColorsAreInvalid)
+		assert.Equal(t, "helpMsg\ntasks[2]\n", out.String())
+	})
+
+	t.Run("accepts a valid custom theme by name", func(t *testing.T) {
+		themesDir := t.TempDir()
+		_, err := addTheme("mine", themesDir)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		err = validateTheme(&out, "custom:mine", themesDir)
+
+		require.NoError(t, err)
+		assert.Equal(t, "valid\n", out.String())
+	})
+
+	t.Run("accepts a built-in theme", func(t *testing.T) {
+		var out bytes.Buffer
+		err := validateTheme(&out, "dracula", t.TempDir())
+
+		require.NoError(t, err)
+		assert.Equal(t, "valid\n", out.String())
+	})
+
+	t.Run("fails for a file that isn't a theme", func(t *testing.T) {
+		themePath := filepath.Join(t.TempDir(), "broken.json")
+		require.NoError(t, os.WriteFile(themePath, []byte(`{"helpMsg": 1}`), 0o644))
+
+		var out bytes.Buffer
+		err := validateTheme(&out, themePath, t.TempDir())
+
+		assert.ErrorIs(t, err, theme.ErrThemeFileHasInvalidSchema)
+	})
+
+	t.Run("fails for a missing custom theme", func(t *testing.T) {
+		var out bytes.Buffer
+		err := validateTheme(&out, "custom:missing", t.TempDir())
+
+		assert.ErrorIs(t, err, errCouldntReadThemeFile)
+	})
+
+	t.Run("fails for an unknown theme", func(t *testing.T) {
+		var out bytes.Buffer
+		err := validateTheme(&out, "unknown", t.TempDir())
+
+		assert.ErrorIs(t, err, theme.ErrBuiltInThemeDoesntExist)
+	})
+}
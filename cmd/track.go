@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	batchFieldSeparator = "\t"
+	timeFormat          = "2006/01/02 15:04"
+	timeOnlyFormat      = "15:04"
+)
+
+var (
+	errTaskIDInvalid           = errors.New("task ID is invalid")
+	errTaskAlreadyBeingTracked = errors.New("a task is already being tracked")
+	errNoTaskBeingTracked      = errors.New("no task is being tracked")
+	errStopTimeInvalid         = errors.New("stop time is invalid")
+	errEndTimeInFuture         = errors.New("end time is in the future")
+	errBeginTimeInFuture       = errors.New("begin time is in the future")
+	errTaskLogIDInvalid        = errors.New("task log ID is invalid")
+	errTaskLogTimesInvalid     = errors.New("task log times are invalid")
+	errCouldntOpenBatchFile    = errors.New("couldn't open batch file")
+	errBatchLineInvalid        = errors.New("batch file line is invalid")
+	errBatchFileEmpty          = errors.New("batch file has no task log entries")
+)
+
+func parseTaskID(value string) (int, error) {
+	return parseID(value, errTaskIDInvalid)
+}
+
+func parseTaskLogID(value string) (int, error) {
+	return parseID(value, errTaskLogIDInvalid)
+}
+
+func parseID(value string, errInvalid error) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return -1, fmt.Errorf("%w: %q", errInvalid, value)
+	}
+
+	return id, nil
+}
+
+// newStartCmd creates the start command
+func newStartCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "start <TASK_ID>",
+		Short: "Start tracking time on a task",
+		Long: `Start tracking time on a task.
+
+This begins a new task log entry for the task with the given ID, starting now.
+Only one task can be tracked at a time.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			activeTaskDetails, err := pers.FetchActiveTaskDetails(*db)
+			if err != nil {
+				return err
+			}
+
+			if activeTaskDetails.TaskID != -1 {
+				return fmt.Errorf(`%w: %q; stop tracking it with "hours stop"`, errTaskAlreadyBeingTracked, activeTaskDetails.TaskSummary)
+			}
+
+			task, err := pers.FetchTaskByID(*db, taskID)
+			if err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			_, err = pers.InsertNewTL(*db, taskID, types.RealTimeProvider{}.Now())
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Started tracking %q\n", task.Summary)
+
+			return nil
+		},
+	}
+}
+
+// newStopCmd creates the stop command
+func newStopCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	comment *string,
+	at *string,
+	mergeSameDay *bool,
+	roundTo *time.Duration,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop tracking time on the active task",
+		Long: `Stop tracking time on the active task.
+
+This finishes the active task log entry, ending it now (or at the time provided
+via --at). The entry's existing comment is kept unless --comment is provided.
+
+With --merge-same-day, the entry is merged into the task's earlier entry from
+the same day, if there's one, extending it up to the end of this one. The time
+between the two entries doesn't count as time spent.
+`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			activeTaskDetails, err := pers.FetchActiveTaskDetails(*db)
+			if err != nil {
+				return err
+			}
+
+			if activeTaskDetails.TaskID == -1 {
+				return fmt.Errorf(`%w; start tracking one with "hours start <TASK_ID>"`, errNoTaskBeingTracked)
+			}
+
+			endTS := types.RealTimeProvider{}.Now().Truncate(time.Second)
+			if *at != "" {
+				endTS, err = types.ParseTaskLogTime(*at)
+				if err != nil {
+					return err
+				}
+			}
+
+			beginTS := activeTaskDetails.CurrentLogBeginTS
+			if err := types.IsTaskLogDurationValid(beginTS, endTS); err != nil {
+				return fmt.Errorf("%w (tracking began at %s): %w", errStopTimeInvalid, beginTS.Format(timeFormat), err)
+			}
+
+			tlComment := activeTaskDetails.CurrentLogComment
+			if trimmed := strings.TrimSpace(*comment); trimmed != "" {
+				tlComment = &trimmed
+			}
+
+			if *roundTo < 0 {
+				return fmt.Errorf("%w: %s", errRoundInvalid, *roundTo)
+			}
+
+			finished, err := pers.FinishActiveTLWithOptions(*db,
+				activeTaskDetails.CurrentLogID,
+				activeTaskDetails.TaskID,
+				beginTS,
+				endTS,
+				tlComment,
+				nil,
+				nil,
+				pers.FinishTLOptions{
+					MergeSameDay: *mergeSameDay,
+					RoundTo:      *roundTo,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Stopped tracking %q (%s)\n", activeTaskDetails.TaskSummary, types.HumanizeDuration(finished.SecsSpent))
+
+			return nil
+		},
+	}
+}
+
+// newAddCmd creates the add command
+func newAddCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	begin *string,
+	end *string,
+	comment *string,
+	allowOverlap *bool,
+	roundTo *time.Duration,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <TASK_ID>",
+		Short: "Add a task log entry for a task",
+		Long: `Add a task log entry for a task.
+
+This is useful for logging time after the fact, when you forgot to track it.
+Both --begin and --end need to be provided, and the end time can't be in the
+future. Entries that overlap with an existing entry for the same task are
+rejected, unless --allow-overlap is provided.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			beginTS, endTS, err := types.ParseTaskLogTimes(*begin, *end)
+			if err != nil {
+				return err
+			}
+
+			if endTS.After(types.RealTimeProvider{}.Now()) {
+				return fmt.Errorf("%w: %s", errEndTimeInFuture, endTS.Format(timeFormat))
+			}
+
+			task, err := pers.FetchTaskByID(*db, taskID)
+			if err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			var tlComment *string
+			if trimmed := strings.TrimSpace(*comment); trimmed != "" {
+				tlComment = &trimmed
+			}
+
+			if *roundTo < 0 {
+				return fmt.Errorf("%w: %s", errRoundInvalid, *roundTo)
+			}
+
+			tlID, err := pers.InsertManualTLRounded(*db, taskID, beginTS, endTS, tlComment, nil, nil, *allowOverlap, *roundTo)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Added task log entry %d for %q\n", tlID, task.Summary)
+
+			return nil
+		},
+	}
+}
+
+// newEditLogCmd creates the edit-log command
+func newEditLogCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	begin *string,
+	end *string,
+	comment *string,
+	taskID *int,
+	allowOverlap *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit-log <TASK_LOG_ID>",
+		Short: "Edit a saved task log entry",
+		Long: `Edit a saved task log entry.
+
+Only the fields provided via flags are changed; the rest keep their current
+values. Providing an empty --comment clears the entry's comment. Providing
+--task moves the entry to another task. Changes that make the entry overlap with
+another entry for the (new) task are rejected, unless --allow-overlap is
+provided.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tlID, err := parseTaskLogID(args[0])
+			if err != nil {
+				return err
+			}
+
+			tl, err := pers.FetchTLByID(*db, tlID)
+			if err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, tlID)
+			}
+
+			beginTS := tl.BeginTS
+			if *begin != "" {
+				beginTS, err = types.ParseTaskLogTime(*begin)
+				if err != nil {
+					return err
+				}
+			}
+
+			endTS := tl.EndTS
+			if *end != "" {
+				endTS, err = types.ParseTaskLogTime(*end)
+				if err != nil {
+					return err
+				}
+			}
+
+			now := types.RealTimeProvider{}.Now()
+			if beginTS.After(now) {
+				return fmt.Errorf("%w: %s", errBeginTimeInFuture, beginTS.Format(timeFormat))
+			}
+
+			if endTS.After(now) {
+				return fmt.Errorf("%w: %s", errEndTimeInFuture, endTS.Format(timeFormat))
+			}
+
+			if err := types.IsTaskLogDurationValid(beginTS, endTS); err != nil {
+				return fmt.Errorf("%w: %w", errTaskLogTimesInvalid, err)
+			}
+
+			newTaskID := tl.TaskID
+			if *taskID != 0 {
+				if _, err := pers.FetchTaskByID(*db, *taskID); err != nil {
+					return fmt.Errorf("%w (ID: %d)", err, *taskID)
+				}
+				newTaskID = *taskID
+			}
+
+			tlComment := tl.Comment
+			if trimmed := strings.TrimSpace(*comment); trimmed != "" {
+				tlComment = &trimmed
+			} else if cmd.Flags().Changed("comment") {
+				tlComment = nil
+			}
+
+			_, err = pers.EditAndMoveSavedTL(*db, tlID, newTaskID, beginTS, endTS, tlComment, tl.Category, tl.Tags, *allowOverlap)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated task log entry %d\n", tlID)
+
+			return nil
+		},
+	}
+}
+
+// parseTaskLogBatch parses tab separated task log entries, one per line, in the
+// format: TASK_ID, BEGIN, END, and an optional COMMENT. Empty lines and lines
+// starting with "#" are skipped.
+func parseTaskLogBatch(reader io.Reader) ([]types.ManualTaskLog, error) {
+	var entries []types.ManualTaskLog
+
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, batchFieldSeparator, 4)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%w (line %d): expected at least 3 tab separated fields, got %d", errBatchLineInvalid, lineNum, len(fields))
+		}
+
+		taskID, err := parseTaskID(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %w", errBatchLineInvalid, lineNum, err)
+		}
+
+		beginTS, endTS, err := types.ParseTaskLogTimes(strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2]))
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d): %w", errBatchLineInvalid, lineNum, err)
+		}
+
+		var comment *string
+		if len(fields) == 4 {
+			commentValue := strings.TrimSpace(fields[3])
+			if commentValue != "" {
+				comment = &commentValue
+			}
+		}
+
+		entries = append(entries, types.ManualTaskLog{
+			TaskID:  taskID,
+			BeginTS: beginTS,
+			EndTS:   endTS,
+			Comment: comment,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntReadInput, err.Error())
+	}
+
+	if len(entries) == 0 {
+		return nil, errBatchFileEmpty
+	}
+
+	return entries, nil
+}
+
+// newAddBatchCmd creates the add-batch command
+func newAddBatchCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+	allowOverlap *bool,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-batch <FILE>",
+		Short: "Add several task log entries from a file",
+		Long: `Add several task log entries from a file.
+
+Each line of the file holds one entry, with tab separated fields in the
+following order:
+
+  TASK_ID    ID of the task the entry belongs to
+  BEGIN      begin time (eg. "2024/06/08 09:30")
+  END        end time (eg. "2024/06/08 10:45")
+  COMMENT    comment for the entry (optional)
+
+Empty lines and lines starting with "#" are ignored.
+
+Entries that overlap with an existing entry for the same task (or with an
+earlier entry in the file) are rejected, unless --allow-overlap is provided.
+
+All entries are saved in a single transaction; if any of them is invalid,
+nothing is saved. This is considerably faster than running a separate "hours"
+process for each entry.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntOpenBatchFile, err.Error())
+			}
+			defer file.Close()
+
+			entries, err := parseTaskLogBatch(file)
+			if err != nil {
+				return err
+			}
+
+			ids, err := pers.InsertManualTLs(*db, entries, *allowOverlap)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Added %d task log entries\n", len(ids))
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,629 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected int
+		err      error
+	}{
+		{name: "valid ID", value: "12", expected: 12},
+		{name: "zero", value: "0", err: errTaskIDInvalid},
+		{name: "negative", value: "-1", err: errTaskIDInvalid},
+		{name: "non numeric", value: "abc", err: errTaskIDInvalid},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTaskID(tt.value)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestNewStartCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newStartCmd(nil, mockPreRun)
+
+		assert.Equal(t, "start <TASK_ID>", cmd.Use)
+		assert.Equal(t, "Start tracking time on a task", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.Args)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("starts tracking a task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newStartCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Started tracking \"a task\"\n", out.String())
+		activeTaskDetails, err := persistence.FetchActiveTaskDetails(db)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, activeTaskDetails.TaskID)
+		assert.WithinDuration(t, time.Now(), activeTaskDetails.CurrentLogBeginTS, time.Minute)
+	})
+
+	t.Run("fails if a task is already being tracked", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertTask(db, "another task")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, 1, time.Now())
+		require.NoError(t, err)
+
+		cmd := newStartCmd(&db, mockPreRun)
+		err = cmd.RunE(cmd, []string{"2"})
+
+		assert.ErrorIs(t, err, errTaskAlreadyBeingTracked)
+		assert.ErrorContains(t, err, "a task")
+	})
+
+	t.Run("fails if task doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newStartCmd(&db, mockPreRun)
+		err := cmd.RunE(cmd, []string{"42"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+
+	t.Run("fails for invalid task ID", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newStartCmd(&db, mockPreRun)
+		err := cmd.RunE(cmd, []string{"abc"})
+
+		assert.ErrorIs(t, err, errTaskIDInvalid)
+	})
+}
+
+func TestNewStopCmd(t *testing.T) {
+	newCmd := func(db **sql.DB, comment, at string) *cobra.Command {
+		return newStopCmd(db, mockPreRun, &comment, &at, new(bool), new(time.Duration))
+	}
+
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newCmd(nil, "", "")
+
+		assert.Equal(t, "stop", cmd.Use)
+		assert.Equal(t, "Stop tracking time on the active task", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.Args)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("stops tracking the active task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, taskID, time.Now().Truncate(time.Second).Add(-90*time.Minute))
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "", "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Stopped tracking \"a task\" (1h 30m)\n", out.String())
+		activeTaskDetails, err := persistence.FetchActiveTaskDetails(db)
+		require.NoError(t, err)
+		assert.Equal(t, -1, activeTaskDetails.TaskID)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.InDelta(t, 90*60, task.SecsSpent, 2)
+	})
+
+	t.Run("uses the provided end time and comment", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		beginTS := time.Date(2024, 6, 8, 9, 0, 0, 0, time.Local)
+		_, err = persistence.InsertNewTL(db, taskID, beginTS)
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "wrote docs", "2024/06/08 10:15")
+		cmd.SetOut(&bytes.Buffer{})
+
+		err = cmd.RunE(cmd, nil)
+
+		require.NoError(t, err)
+		entries, _, err := persistence.FetchTLEntriesBetweenTS(db, beginTS, beginTS.Add(24*time.Hour), persistence.TLFilter{TaskStatus: types.TaskStatusAny}, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, 75*60, entries[0].SecsSpent)
+		require.NotNil(t, entries[0].Comment)
+		assert.Equal(t, "wrote docs", *entries[0].Comment)
+	})
+
+	t.Run("fails if no task is being tracked", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "", "")
+		err := cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, errNoTaskBeingTracked)
+	})
+
+	t.Run("fails if end time is before begin time", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, taskID, time.Date(2024, 6, 8, 9, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "", "2024/06/08 08:00")
+		err = cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, errStopTimeInvalid)
+		activeTaskDetails, err := persistence.FetchActiveTaskDetails(db)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, activeTaskDetails.TaskID)
+	})
+
+	t.Run("fails for invalid end time", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertNewTL(db, taskID, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "", "yesterday")
+		err = cmd.RunE(cmd, nil)
+
+		assert.ErrorIs(t, err, types.ErrTimeIsInvalid)
+	})
+}
+
+func TestNewAddCmd(t *testing.T) {
+	newCmd := func(db **sql.DB, begin, end, comment string) *cobra.Command {
+		allowOverlap := false
+		return newAddCmd(db, mockPreRun, &begin, &end, &comment, &allowOverlap, new(time.Duration))
+	}
+
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newCmd(nil, "", "", "")
+
+		assert.Equal(t, "add <TASK_ID>", cmd.Use)
+		assert.Equal(t, "Add a task log entry for a task", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.Args)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("adds a task log entry", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "2024/06/08 09:00", "2024/06/08 10:30", "wrote docs")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Added task log entry 1 for \"a task\"\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 90*60, task.SecsSpent)
+	})
+
+	t.Run("rounds the time spent when asked to", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		begin, end, comment := "2024/06/08 09:00", "2024/06/08 09:38", ""
+		allowOverlap := false
+		roundTo := 15 * time.Minute
+		cmd := newAddCmd(&db, mockPreRun, &begin, &end, &comment, &allowOverlap, &roundTo)
+		cmd.SetOut(&bytes.Buffer{})
+
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 45*60, task.SecsSpent)
+	})
+
+	t.Run("fails if end time is before begin time", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "2024/06/08 10:30", "2024/06/08 09:00", "")
+		err = cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorContains(t, err, "end time is before begin time")
+	})
+
+	t.Run("fails if end time is in the future", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		end := time.Now().Add(48 * time.Hour)
+		cmd := newCmd(&db, end.Add(-time.Hour).Format(timeFormat), end.Format(timeFormat), "")
+		err = cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, errEndTimeInFuture)
+	})
+
+	t.Run("fails if entry overlaps with an existing one", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		_, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "2024/06/08 09:00", "2024/06/08 10:30", "")
+		require.NoError(t, cmd.RunE(cmd, []string{"1"}))
+
+		cmd = newCmd(&db, "2024/06/08 10:00", "2024/06/08 11:00", "")
+		err = cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskLogOverlaps)
+	})
+
+	t.Run("fails if task doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "2024/06/08 09:00", "2024/06/08 10:30", "")
+		err := cmd.RunE(cmd, []string{"42"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
+
+func TestNewEditLogCmd(t *testing.T) {
+	newCmd := func(db **sql.DB, begin, end, comment string, taskID int) *cobra.Command {
+		allowOverlap := false
+		return newEditLogCmd(db, mockPreRun, &begin, &end, &comment, &taskID, &allowOverlap)
+	}
+
+	setup := func(t *testing.T) (*sql.DB, int, int) {
+		t.Helper()
+		db := setupTestDB(t)
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		comment := "original comment"
+		tlID, err := persistence.InsertManualTL(db,
+			taskID,
+			time.Date(2024, 6, 8, 9, 0, 0, 0, time.Local),
+			time.Date(2024, 6, 8, 10, 0, 0, 0, time.Local),
+			&comment,
+			nil,
+			nil,
+			false,
+		)
+		require.NoError(t, err)
+
+		return db, taskID, tlID
+	}
+
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newCmd(nil, "", "", "", 0)
+
+		assert.Equal(t, "edit-log <TASK_LOG_ID>", cmd.Use)
+		assert.Equal(t, "Edit a saved task log entry", cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.Args)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("edits only the comment", func(t *testing.T) {
+		db, taskID, tlID := setup(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "", "", "updated comment", 0)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err := cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Updated task log entry 1\n", out.String())
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		require.NotNil(t, tl.Comment)
+		assert.Equal(t, "updated comment", *tl.Comment)
+		assert.Equal(t, "2024/06/08 09:00", tl.BeginTS.Format(timeFormat))
+		assert.Equal(t, "2024/06/08 10:00", tl.EndTS.Format(timeFormat))
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 60*60, task.SecsSpent)
+	})
+
+	t.Run("edits only the times", func(t *testing.T) {
+		db, taskID, tlID := setup(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "2024/06/08 08:30", "2024/06/08 10:45", "", 0)
+		cmd.SetOut(&bytes.Buffer{})
+
+		err := cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		require.NotNil(t, tl.Comment)
+		assert.Equal(t, "original comment", *tl.Comment)
+		assert.Equal(t, 135*60, tl.SecsSpent)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 135*60, task.SecsSpent)
+	})
+
+	t.Run("moves the entry to another task", func(t *testing.T) {
+		db, taskID, tlID := setup(t)
+		defer db.Close()
+		otherTaskID, err := persistence.InsertTask(db, "another task")
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "", "", "", otherTaskID)
+		cmd.SetOut(&bytes.Buffer{})
+
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		assert.Equal(t, otherTaskID, tl.TaskID)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task.SecsSpent)
+		otherTask, err := persistence.FetchTaskByID(db, otherTaskID)
+		require.NoError(t, err)
+		assert.Equal(t, 60*60, otherTask.SecsSpent)
+	})
+
+	t.Run("fails if end time is before begin time", func(t *testing.T) {
+		db, _, _ := setup(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "2024/06/08 10:30", "", "", 0)
+		err := cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, errTaskLogTimesInvalid)
+	})
+
+	t.Run("fails if begin time is in the future", func(t *testing.T) {
+		db, _, _ := setup(t)
+		defer db.Close()
+
+		begin := time.Now().Add(48 * time.Hour)
+		cmd := newCmd(&db, begin.Format(timeFormat), begin.Add(time.Hour).Format(timeFormat), "", 0)
+		err := cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, errBeginTimeInFuture)
+	})
+
+	t.Run("fails if task log doesn't exist", func(t *testing.T) {
+		db, _, _ := setup(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "", "", "updated comment", 0)
+		err := cmd.RunE(cmd, []string{"42"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskLogNotFound)
+	})
+
+	t.Run("clears the comment if an empty one is provided", func(t *testing.T) {
+		db, _, tlID := setup(t)
+		defer db.Close()
+
+		comment := ""
+		cmd := newCmd(&db, "", "", comment, 0)
+		cmd.Flags().StringVarP(&comment, "comment", "c", "", "")
+		require.NoError(t, cmd.Flags().Set("comment", ""))
+		cmd.SetOut(&bytes.Buffer{})
+
+		err := cmd.RunE(cmd, []string{"1"})
+
+		require.NoError(t, err)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		assert.Nil(t, tl.Comment)
+	})
+
+	t.Run("checks overlaps against the task the entry is moved to", func(t *testing.T) {
+		db, taskID, tlID := setup(t)
+		defer db.Close()
+		otherTaskID, err := persistence.InsertTask(db, "another task")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db,
+			otherTaskID,
+			time.Date(2024, 6, 8, 9, 30, 0, 0, time.Local),
+			time.Date(2024, 6, 8, 11, 0, 0, 0, time.Local),
+			nil,
+			nil,
+			nil,
+			false,
+		)
+		require.NoError(t, err)
+
+		cmd := newCmd(&db, "", "", "updated comment", otherTaskID)
+		err = cmd.RunE(cmd, []string{"1"})
+
+		require.ErrorIs(t, err, persistence.ErrTaskLogOverlaps)
+		tl, err := persistence.FetchTLByID(db, tlID)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, tl.TaskID)
+		require.NotNil(t, tl.Comment)
+		assert.Equal(t, "original comment", *tl.Comment)
+	})
+
+	t.Run("fails if target task doesn't exist", func(t *testing.T) {
+		db, _, _ := setup(t)
+		defer db.Close()
+
+		cmd := newCmd(&db, "", "", "", 42)
+		err := cmd.RunE(cmd, []string{"1"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
+
+func TestParseTaskLogBatch(t *testing.T) {
+	t.Run("parses valid entries", func(t *testing.T) {
+		input := `# task_id	begin	end	comment
+1	2024/06/08 09:00	2024/06/08 10:30	wrote docs
+
+2	2024/06/08 11:00	2024/06/08 11:45
+`
+
+		entries, err := parseTaskLogBatch(strings.NewReader(input))
+
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 90*time.Minute, entries[0].EndTS.Sub(entries[0].BeginTS))
+		require.NotNil(t, entries[0].Comment)
+		assert.Equal(t, "wrote docs", *entries[0].Comment)
+		assert.Equal(t, 2, entries[1].TaskID)
+		assert.Nil(t, entries[1].Comment)
+	})
+
+	t.Run("fails for too few fields", func(t *testing.T) {
+		_, err := parseTaskLogBatch(strings.NewReader("1\t2024/06/08 09:00\n"))
+
+		assert.ErrorIs(t, err, errBatchLineInvalid)
+		assert.ErrorContains(t, err, "line 1")
+	})
+
+	t.Run("fails for invalid task ID", func(t *testing.T) {
+		_, err := parseTaskLogBatch(strings.NewReader("1\t2024/06/08 09:00\t2024/06/08 10:00\nx\t2024/06/08 09:00\t2024/06/08 10:00\n"))
+
+		assert.ErrorIs(t, err, errTaskIDInvalid)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("fails for end before begin", func(t *testing.T) {
+		_, err := parseTaskLogBatch(strings.NewReader("1\t2024/06/08 10:00\t2024/06/08 09:00\n"))
+
+		assert.ErrorIs(t, err, errBatchLineInvalid)
+	})
+
+	t.Run("fails if there are no entries", func(t *testing.T) {
+		_, err := parseTaskLogBatch(strings.NewReader("# only a comment\n\n"))
+
+		assert.ErrorIs(t, err, errBatchFileEmpty)
+	})
+}
+
+func TestNewAddBatchCmd(t *testing.T) {
+	allowOverlap := false
+
+	t.Run("adds entries from file", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+
+		batchPath := filepath.Join(t.TempDir(), "logs.tsv")
+		content := "1\t2024/06/08 09:00\t2024/06/08 10:00\tfirst\n1\t2024/06/08 11:00\t2024/06/08 11:30\n"
+		require.NoError(t, os.WriteFile(batchPath, []byte(content), 0o644))
+
+		cmd := newAddBatchCmd(&db, mockPreRun, &allowOverlap)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{batchPath})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Added 2 task log entries\n", out.String())
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 90*60, task.SecsSpent)
+	})
+
+	t.Run("fails if an entry overlaps with an existing one", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "a task")
+		require.NoError(t, err)
+		_, err = persistence.InsertManualTL(db,
+			taskID,
+			time.Date(2024, 6, 8, 9, 0, 0, 0, time.Local),
+			time.Date(2024, 6, 8, 10, 0, 0, 0, time.Local),
+			nil,
+			nil,
+			nil,
+			false,
+		)
+		require.NoError(t, err)
+
+		batchPath := filepath.Join(t.TempDir(), "logs.tsv")
+		content := "1\t2024/06/08 11:00\t2024/06/08 11:30\n1\t2024/06/08 09:30\t2024/06/08 10:30\n"
+		require.NoError(t, os.WriteFile(batchPath, []byte(content), 0o644))
+
+		cmd := newAddBatchCmd(&db, mockPreRun, &allowOverlap)
+		err = cmd.RunE(cmd, []string{batchPath})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskLogOverlaps)
+		task, err := persistence.FetchTaskByID(db, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 60*60, task.SecsSpent)
+	})
+
+	t.Run("fails if file doesn't exist", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+
+		cmd := newAddBatchCmd(&db, mockPreRun, &allowOverlap)
+		err := cmd.RunE(cmd, []string{filepath.Join(t.TempDir(), "absent.tsv")})
+
+		assert.ErrorIs(t, err, errCouldntOpenBatchFile)
+	})
+}
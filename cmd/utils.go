@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/dhth/hours/internal/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func expandTilde(path string, homeDir string) string {
@@ -18,6 +21,21 @@ func expandTilde(path string, homeDir string) string {
 	return filepath.Join(homeDir, pathWithoutTilde)
 }
 
+// effectiveCommand returns the command line that produced a command's output,
+// with the period resolved and only including the flags that were explicitly set.
+func effectiveCommand(cmd *cobra.Command, period string) string {
+	parts := []string{cmd.CommandPath()}
+	// --since-cutoff shows up among the flags below
+	if period != "" && period != sinceCutoffPeriod {
+		parts = append(parts, period)
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		parts = append(parts, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+
+	return strings.Join(parts, " ")
+}
+
 // addDBPathFlag adds the --dbpath/-d flag to a command
 func addDBPathFlag(cmd *cobra.Command, dbPath *string, defaultDBPath string) {
 	cmd.Flags().StringVarP(dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
@@ -34,6 +52,107 @@ func addTaskStatusFlag(cmd *cobra.Command, taskStatusStr *string) {
 		fmt.Sprintf("only show data for tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
 }
 
+// addTagFlag adds the --tag flag to a command
+func addTagFlag(cmd *cobra.Command, tag *string) {
+	cmd.Flags().StringVar(tag, "tag", "", "only show data for tasks carrying this tag")
+}
+
+// addLogTagFlag adds the --log-tag flag to a command
+func addLogTagFlag(cmd *cobra.Command, logTag *string) {
+	cmd.Flags().StringVar(logTag, "log-tag", "", "only show data for task log entries carrying this tag")
+}
+
+// addTaskIDFlag adds the --task flag to a command
+func addTaskIDFlag(cmd *cobra.Command, taskID *int) {
+	cmd.Flags().IntVar(taskID, "task", 0, "only show data for the task with this ID")
+}
+
+// taskIDFilter returns the ID of the task to only show data for, or nil if
+// --task wasn't set.
+func taskIDFilter(taskID int) *int {
+	if taskID == 0 {
+		return nil
+	}
+
+	return &taskID
+}
+
+// addHeaderMetaFlag adds the --header-meta flag to a command
+func addHeaderMetaFlag(cmd *cobra.Command, headerMeta *bool) {
+	cmd.Flags().BoolVar(headerMeta, "header-meta", false,
+		"whether to prepend a line describing the command, date range, task status, and generation time (ignored in interactive mode)")
+}
+
+// addNoRememberFlag adds the --no-remember flag to a command
+func addNoRememberFlag(cmd *cobra.Command, noRemember *bool) {
+	cmd.Flags().BoolVar(noRemember, "no-remember", false,
+		"whether to skip saving the period as the default for the next run without one")
+}
+
+// addNoActiveFlag adds the --no-active flag to a command
+func addNoActiveFlag(cmd *cobra.Command, noActive *bool) {
+	cmd.Flags().BoolVar(noActive, "no-active", false,
+		"exclude the task log that's currently being tracked (this is the default; the flag lets scripts state it explicitly)")
+}
+
+// addSinceCutoffFlags adds the --since-cutoff and --day-cutoff flags to a command
+func addSinceCutoffFlags(cmd *cobra.Command, sinceCutoff *bool, dayCutoffStr *string) {
+	cmd.Flags().BoolVar(sinceCutoff, "since-cutoff", false,
+		"whether to show data since the most recent day cutoff (see --day-cutoff) instead of for a period")
+	cmd.Flags().StringVar(dayCutoffStr, "day-cutoff", "00:00",
+		fmt.Sprintf(`time at which a new day begins (eg. "04:00"); can also be set via %s`, envVarDayCutoff))
+}
+
+// resolveDayCutoff parses the day cutoff from the flag, falling back to the
+// environment variable if the flag wasn't explicitly set by the user
+func resolveDayCutoff(cmd *cobra.Command, dayCutoffStr string) (time.Time, error) {
+	if !cmd.Flags().Changed("day-cutoff") {
+		cutoffFromEnv := strings.TrimSpace(os.Getenv(envVarDayCutoff))
+		if cutoffFromEnv != "" {
+			dayCutoffStr = cutoffFromEnv
+		}
+	}
+
+	cutoff, err := time.Parse(timeOnlyFormat, dayCutoffStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`%w (eg. "04:00"): %q`, errDayCutoffInvalid, dayCutoffStr)
+	}
+
+	return cutoff, nil
+}
+
+// parseReportDelimiter returns the single character in delimiter, which can't
+// be one that encoding/csv reserves for quoting or line breaks.
+func parseReportDelimiter(delimiter string) (rune, error) {
+	runes := []rune(delimiter)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("%w: %q", errReportDelimiterInvalid, delimiter)
+	}
+
+	switch runes[0] {
+	case '"', '\r', '\n', utf8.RuneError:
+		return 0, fmt.Errorf("%w: %q", errReportDelimiterInvalid, delimiter)
+	}
+
+	return runes[0], nil
+}
+
+// getSinceCutoffDateRange returns the day long range that begins at the most
+// recent day cutoff boundary before now. With a cutoff of 04:00, this means
+// that at 02:00 the range starts at 04:00 on the previous calendar day.
+func getSinceCutoffDateRange(now time.Time, cutoff time.Time) types.DateRange {
+	start := time.Date(now.Year(), now.Month(), now.Day(), cutoff.Hour(), cutoff.Minute(), 0, 0, now.Location())
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	return types.DateRange{
+		Start:   start,
+		End:     start.AddDate(0, 0, 1),
+		NumDays: 1,
+	}
+}
+
 // resolveThemeFromEnvOrFlag resolves the theme name from environment variable
 // if the flag wasn't explicitly set by the user
 func resolveThemeFromEnvOrFlag(cmd *cobra.Command, themeName *string, envVar string) {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dhth/hours/internal/types"
 	"github.com/spf13/cobra"
@@ -148,6 +149,51 @@ func TestAddTaskStatusFlag(t *testing.T) {
 	})
 }
 
+func TestAddNoActiveFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	var noActive bool
+
+	addNoActiveFlag(cmd, &noActive)
+	err := cmd.Flags().Parse([]string{"--no-active"})
+
+	require.NoError(t, err)
+	flag := cmd.Flags().Lookup("no-active")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+	assert.True(t, noActive)
+}
+
+func TestEffectiveCommand(t *testing.T) {
+	t.Run("includes period and explicitly set flags only", func(t *testing.T) {
+		root := &cobra.Command{Use: "hours"}
+		cmd := &cobra.Command{Use: "report"}
+		root.AddCommand(cmd)
+		var taskStatusStr string
+		var headerMeta bool
+		addTaskStatusFlag(cmd, &taskStatusStr)
+		addHeaderMetaFlag(cmd, &headerMeta)
+		require.NoError(t, cmd.ParseFlags([]string{"-s", "active"}))
+
+		got := effectiveCommand(cmd, "week")
+
+		assert.Equal(t, "hours report week --task-status=active", got)
+	})
+
+	t.Run("doesn't repeat --since-cutoff as a period", func(t *testing.T) {
+		root := &cobra.Command{Use: "hours"}
+		cmd := &cobra.Command{Use: "log"}
+		root.AddCommand(cmd)
+		var sinceCutoff bool
+		var dayCutoffStr string
+		addSinceCutoffFlags(cmd, &sinceCutoff, &dayCutoffStr)
+		require.NoError(t, cmd.ParseFlags([]string{"--since-cutoff"}))
+
+		got := effectiveCommand(cmd, sinceCutoffPeriod)
+
+		assert.Equal(t, "hours log --since-cutoff=true", got)
+	})
+}
+
 func TestResolveThemeFromEnvOrFlag(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -259,3 +305,121 @@ func TestAllUtilityFunctionsIntegration(t *testing.T) {
 		assert.Equal(t, "s", statusFlag.Shorthand)
 	})
 }
+
+func TestGetSinceCutoffDateRange(t *testing.T) {
+	cutoff := time.Date(0, 1, 1, 4, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		now           time.Time
+		expectedStart time.Time
+	}{
+		{
+			name:          "before the cutoff starts at yesterday's cutoff",
+			now:           time.Date(2024, 6, 8, 2, 0, 0, 0, time.Local),
+			expectedStart: time.Date(2024, 6, 7, 4, 0, 0, 0, time.Local),
+		},
+		{
+			name:          "after the cutoff starts at today's cutoff",
+			now:           time.Date(2024, 6, 8, 5, 0, 0, 0, time.Local),
+			expectedStart: time.Date(2024, 6, 8, 4, 0, 0, 0, time.Local),
+		},
+		{
+			name:          "at the cutoff starts at today's cutoff",
+			now:           time.Date(2024, 6, 8, 4, 0, 0, 0, time.Local),
+			expectedStart: time.Date(2024, 6, 8, 4, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getSinceCutoffDateRange(tt.now, cutoff)
+
+			assert.Equal(t, tt.expectedStart, got.Start)
+			assert.Equal(t, tt.expectedStart.AddDate(0, 0, 1), got.End)
+			assert.Equal(t, 1, got.NumDays)
+		})
+	}
+}
+
+func TestResolveDayCutoff(t *testing.T) {
+	newCmd := func() (*cobra.Command, *string) {
+		cmd := &cobra.Command{Use: "test"}
+		var sinceCutoff bool
+		var dayCutoffStr string
+		addSinceCutoffFlags(cmd, &sinceCutoff, &dayCutoffStr)
+		return cmd, &dayCutoffStr
+	}
+
+	t.Run("uses the flag value if set", func(t *testing.T) {
+		t.Setenv(envVarDayCutoff, "06:00")
+		cmd, dayCutoffStr := newCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--day-cutoff", "04:30"}))
+
+		got, err := resolveDayCutoff(cmd, *dayCutoffStr)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, got.Hour())
+		assert.Equal(t, 30, got.Minute())
+	})
+
+	t.Run("falls back to the env var if the flag isn't set", func(t *testing.T) {
+		t.Setenv(envVarDayCutoff, "06:00")
+		cmd, dayCutoffStr := newCmd()
+
+		got, err := resolveDayCutoff(cmd, *dayCutoffStr)
+
+		require.NoError(t, err)
+		assert.Equal(t, 6, got.Hour())
+	})
+
+	t.Run("defaults to midnight", func(t *testing.T) {
+		t.Setenv(envVarDayCutoff, "")
+		cmd, dayCutoffStr := newCmd()
+
+		got, err := resolveDayCutoff(cmd, *dayCutoffStr)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.Hour())
+		assert.Equal(t, 0, got.Minute())
+	})
+
+	t.Run("fails for an invalid value", func(t *testing.T) {
+		cmd, dayCutoffStr := newCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--day-cutoff", "4am"}))
+
+		_, err := resolveDayCutoff(cmd, *dayCutoffStr)
+
+		assert.ErrorIs(t, err, errDayCutoffInvalid)
+	})
+}
+
+func TestParseReportDelimiter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		delimiter string
+		expected  rune
+		expectErr bool
+	}{
+		{name: "comma", delimiter: ",", expected: ','},
+		{name: "semicolon", delimiter: ";", expected: ';'},
+		{name: "tab", delimiter: "\t", expected: '\t'},
+		{name: "empty", delimiter: "", expectErr: true},
+		{name: "more than one character", delimiter: ";;", expectErr: true},
+		{name: "quote", delimiter: `"`, expectErr: true},
+		{name: "line break", delimiter: "\n", expectErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReportDelimiter(tt.delimiter)
+
+			if tt.expectErr {
+				assert.ErrorIs(t, err, errReportDelimiterInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const weekResetNone = "none"
+
+// newSetWeekResetCmd creates the set-week-reset command
+func newSetWeekResetCmd(
+	db **sql.DB,
+	preRun func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-week-reset TASK_ID WEEKDAY",
+		Short: `Set the weekday on which a task's "this week" total restarts`,
+		Long: `Set the weekday on which a task's "this week" total restarts.
+
+By default, the time spent on a task this week counts from Monday. This is
+meant for tasks with weekly quotas that restart on another day (eg. "thursday",
+or "thu"). Passing "none" makes the task follow the regular week again.
+`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+
+			var day *time.Weekday
+			if !strings.EqualFold(strings.TrimSpace(args[1]), weekResetNone) {
+				weekday, err := types.ParseWeekday(args[1])
+				if err != nil {
+					return err
+				}
+				day = &weekday
+			}
+
+			if err := pers.SetTaskWeekResetDay(*db, taskID, day); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, taskID)
+			}
+
+			if day == nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Week for task %d now starts on Monday\n", taskID)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Week for task %d now starts on %s\n", taskID, day.String())
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSetWeekResetCmd(t *testing.T) {
+	t.Run("command properties", func(t *testing.T) {
+		cmd := newSetWeekResetCmd(nil, mockPreRun)
+
+		assert.Equal(t, "set-week-reset TASK_ID WEEKDAY", cmd.Use)
+		assert.Equal(t, `Set the weekday on which a task's "this week" total restarts`, cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		assert.NotNil(t, cmd.PreRunE)
+		assert.NotNil(t, cmd.RunE)
+	})
+
+	t.Run("sets and clears the reset day", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		taskID, err := persistence.InsertTask(db, "weekly quota")
+		require.NoError(t, err)
+		now := time.Now()
+		begin := types.StartOfWeek(now, now.Weekday()).AddDate(0, 0, -1).Add(12 * time.Hour)
+		_, err = persistence.InsertManualTL(db, taskID, begin, begin.Add(time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		cmd := newSetWeekResetCmd(&db, mockPreRun)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err = cmd.RunE(cmd, []string{"1", now.Weekday().String()[:3]})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Week for task 1 now starts on "+now.Weekday().String()+"\n", out.String())
+		secs, err := persistence.FetchTaskWeekTotal(db, taskID, now)
+		require.NoError(t, err)
+		assert.Zero(t, secs)
+
+		out.Reset()
+		err = cmd.RunE(cmd, []string{"1", "none"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Week for task 1 now starts on Monday\n", out.String())
+	})
+
+	t.Run("fails for an invalid weekday", func(t *testing.T) {
+		cmd := newSetWeekResetCmd(nil, mockPreRun)
+
+		err := cmd.RunE(cmd, []string{"1", "someday"})
+
+		assert.ErrorIs(t, err, types.ErrWeekdayInvalid)
+	})
+
+	t.Run("fails for an unknown task", func(t *testing.T) {
+		db := setupTestDB(t)
+		defer db.Close()
+		cmd := newSetWeekResetCmd(&db, mockPreRun)
+
+		err := cmd.RunE(cmd, []string{"42", "mon"})
+
+		assert.ErrorIs(t, err, persistence.ErrTaskNotFound)
+	})
+}
@@ -0,0 +1,360 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const backupVersion = 1
+
+var (
+	ErrDBNotEmpty               = errors.New("database isn't empty")
+	ErrBackupVersionUnsupported = errors.New("backup version is not supported")
+)
+
+// backup is a full copy of the data in the database (every task, task log,
+// setting, and remembered period), as written by ExportAll and read by
+// ImportAll. Nullable columns are kept as pointers so that they're restored
+// exactly as they were. The db_versions table isn't included, since it only
+// records which migrations have been run on the database itself.
+type backup struct {
+	Version     int                `json:"version"`
+	Tasks       []backupTask       `json:"tasks"`
+	TaskLogs    []backupTaskLog    `json:"taskLogs"`
+	Settings    *backupSettings    `json:"settings"`
+	LastPeriods []backupLastPeriod `json:"lastPeriods"`
+}
+
+type backupTask struct {
+	ID           int       `json:"id"`
+	SyncID       *string   `json:"syncId"`
+	Summary      string    `json:"summary"`
+	SecsSpent    int       `json:"secsSpent"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	Color        *string   `json:"color"`
+	WeekResetDay *int      `json:"weekResetDay"`
+	Tags         []string  `json:"tags"`
+}
+
+type backupTaskLog struct {
+	ID        int        `json:"id"`
+	SyncID    *string    `json:"syncId"`
+	TaskID    int        `json:"taskId"`
+	BeginTS   time.Time  `json:"beginTs"`
+	EndTS     *time.Time `json:"endTs"`
+	SecsSpent int        `json:"secsSpent"`
+	Comment   *string    `json:"comment"`
+	Active    bool       `json:"active"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+	Tags      *string    `json:"tags"`
+	Category  *string    `json:"category"`
+}
+
+type backupSettings struct {
+	WeeklyGoalSecs int `json:"weeklyGoalSecs"`
+}
+
+type backupLastPeriod struct {
+	Command string `json:"command"`
+	Period  string `json:"period"`
+}
+
+// ExportAll returns all the data in the database as JSON, meant to be restored
+// later via ImportAll.
+func ExportAll(db *sql.DB) ([]byte, error) {
+	tasks, err := fetchBackupTasks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	taskLogs, err := fetchBackupTaskLogs(db)
+	if err != nil {
+		return nil, err
+	}
+
+	weeklyGoalSecs, err := GetWeeklyGoal(db)
+	if err != nil {
+		return nil, err
+	}
+
+	lastPeriods, err := fetchBackupLastPeriods(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(backup{
+		Version:     backupVersion,
+		Tasks:       tasks,
+		TaskLogs:    taskLogs,
+		Settings:    &backupSettings{WeeklyGoalSecs: weeklyGoalSecs},
+		LastPeriods: lastPeriods,
+	}, "", "  ")
+}
+
+// ImportAll restores a backup written by ExportAll into an empty database.
+func ImportAll(db *sql.DB, data []byte) error {
+	return ImportAllForced(db, data, false)
+}
+
+// ImportAllForced restores a backup written by ExportAll. If the database
+// isn't empty, ErrDBNotEmpty is returned, unless force is true, in which case
+// all existing data is replaced by the data in the backup. Settings and
+// remembered periods are left as they are when restoring a backup written
+// before they were included.
+func ImportAllForced(db *sql.DB, data []byte, force bool) error {
+	var b backup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+
+	if b.Version != backupVersion {
+		return fmt.Errorf("%w: %d", ErrBackupVersionUnsupported, b.Version)
+	}
+
+	return runInTx(db, func(tx *sql.Tx) error {
+		var numRows int
+		err := tx.QueryRow(`
+SELECT (SELECT COUNT(*) FROM task) + (SELECT COUNT(*) FROM task_log);
+`).Scan(&numRows)
+		if err != nil {
+			return err
+		}
+
+		if numRows > 0 {
+			if !force {
+				return ErrDBNotEmpty
+			}
+
+			for _, tbl := range []string{"task_tag", "task_log", "task"} {
+				if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s;", tbl)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if b.Settings != nil {
+			_, err := tx.Exec(`
+INSERT INTO settings (id, weekly_goal_secs)
+VALUES (1, ?)
+ON CONFLICT(id) DO UPDATE SET weekly_goal_secs = excluded.weekly_goal_secs;
+`, b.Settings.WeeklyGoalSecs)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(`DELETE FROM last_period;`); err != nil {
+				return err
+			}
+
+			for _, lp := range b.LastPeriods {
+				_, err := tx.Exec(`
+INSERT INTO last_period (command, period)
+VALUES (?, ?);
+`, lp.Command, lp.Period)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, task := range b.Tasks {
+			_, err := tx.Exec(`
+INSERT INTO task (id, sync_id, summary, secs_spent, active, created_at, updated_at, color, week_reset_day)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+`, task.ID, task.SyncID, task.Summary, task.SecsSpent, task.Active, task.CreatedAt.UTC(), task.UpdatedAt.UTC(), task.Color, task.WeekResetDay)
+			if err != nil {
+				return err
+			}
+
+			for _, tag := range task.Tags {
+				_, err := tx.Exec(`
+INSERT INTO task_tag (task_id, tag)
+VALUES (?, ?);
+`, task.ID, tag)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, tl := range b.TaskLogs {
+			_, err := tx.Exec(`
+INSERT INTO task_log (id, sync_id, task_id, begin_ts, end_ts, secs_spent, comment, active, created_at, updated_at, tags, category)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+`, tl.ID, tl.SyncID, tl.TaskID, tl.BeginTS.UTC(), nullableTime(tl.EndTS), tl.SecsSpent, tl.Comment, tl.Active, nullableTime(tl.CreatedAt), nullableTime(tl.UpdatedAt), tl.Tags, tl.Category)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func fetchBackupTasks(db *sql.DB) ([]backupTask, error) {
+	rows, err := db.Query(`
+SELECT id, sync_id, summary, secs_spent, active, created_at, updated_at, color, week_reset_day
+FROM task
+ORDER BY id ASC;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []backupTask{}
+	indexByID := make(map[int]int)
+	for rows.Next() {
+		var task backupTask
+		var weekResetDay sql.NullInt64
+		err := rows.Scan(
+			&task.ID,
+			&task.SyncID,
+			&task.Summary,
+			&task.SecsSpent,
+			&task.Active,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.Color,
+			&weekResetDay,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if weekResetDay.Valid {
+			day := int(weekResetDay.Int64)
+			task.WeekResetDay = &day
+		}
+		task.Tags = []string{}
+
+		indexByID[task.ID] = len(tasks)
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := db.Query(`
+SELECT task_id, tag
+FROM task_tag
+ORDER BY task_id ASC, tag ASC;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var taskID int
+		var tag string
+		if err := tagRows.Scan(&taskID, &tag); err != nil {
+			return nil, err
+		}
+
+		if i, ok := indexByID[taskID]; ok {
+			tasks[i].Tags = append(tasks[i].Tags, tag)
+		}
+	}
+
+	return tasks, tagRows.Err()
+}
+
+func fetchBackupTaskLogs(db *sql.DB) ([]backupTaskLog, error) {
+	rows, err := db.Query(`
+SELECT id, sync_id, task_id, begin_ts, end_ts, secs_spent, comment, active, created_at, updated_at, tags, category
+FROM task_log
+ORDER BY id ASC;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taskLogs := []backupTaskLog{}
+	for rows.Next() {
+		var tl backupTaskLog
+		var endTS, createdAt, updatedAt sql.NullTime
+		err := rows.Scan(
+			&tl.ID,
+			&tl.SyncID,
+			&tl.TaskID,
+			&tl.BeginTS,
+			&endTS,
+			&tl.SecsSpent,
+			&tl.Comment,
+			&tl.Active,
+			&createdAt,
+			&updatedAt,
+			&tl.Tags,
+			&tl.Category,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tl.EndTS = nullTimePtr(endTS)
+		tl.CreatedAt = nullTimePtr(createdAt)
+		tl.UpdatedAt = nullTimePtr(updatedAt)
+
+		taskLogs = append(taskLogs, tl)
+	}
+
+	return taskLogs, rows.Err()
+}
+
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time
+	return &t
+}
+
+func fetchBackupLastPeriods(db *sql.DB) ([]backupLastPeriod, error) {
+	rows, err := db.Query(`
+SELECT command, period
+FROM last_period
+ORDER BY command ASC;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastPeriods := []backupLastPeriod{}
+	for rows.Next() {
+		var lp backupLastPeriod
+		if err := rows.Scan(&lp.Command, &lp.Period); err != nil {
+			return nil, err
+		}
+
+		lastPeriods = append(lastPeriods, lp)
+	}
+
+	return lastPeriods, rows.Err()
+}
+
+// BackupDBTo writes a consistent copy of the whole database to path, using
+// VACUUM INTO. path must not exist yet.
+func BackupDBTo(db *sql.DB, path string) error {
+	_, err := db.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// OptimizeDB rebuilds the database file to reclaim space left behind by
+// deleted rows, and refreshes the statistics SQLite's query planner uses.
+func OptimizeDB(db *sql.DB) error {
+	if _, err := db.Exec("VACUUM"); err != nil {
+		return err
+	}
+	_, err := db.Exec("ANALYZE")
+	return err
+}
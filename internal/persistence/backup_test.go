@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedBackupDB(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	taskID, err := InsertTask(db, "backed up task")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "another task")
+	require.NoError(t, err)
+
+	require.NoError(t, SetTaskColor(db, taskID, "#fabd2f"))
+	monday := time.Monday
+	require.NoError(t, SetTaskWeekResetDay(db, taskID, &monday))
+	require.NoError(t, AddTaskTag(db, taskID, "work"))
+	require.NoError(t, UpdateTaskActiveStatus(db, otherTaskID, false))
+
+	comment := "a comment"
+	beginTS := time.Date(2026, time.February, 1, 10, 0, 0, 0, time.UTC)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(2*time.Hour), &comment, nil, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, SetTLTags(db, tlID, []string{"deep-work"}))
+	_, err = InsertManualTL(db, otherTaskID, beginTS.Add(3*time.Hour), beginTS.Add(4*time.Hour), nil, nil, nil, false)
+	require.NoError(t, err)
+	_, err = InsertNewTL(db, taskID, beginTS.Add(5*time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, SetWeeklyGoal(db, 40*60*60))
+	require.NoError(t, SetLastPeriod(db, "report", "week"))
+}
+
+func TestExportImportAllRoundTrip(t *testing.T) {
+	// GIVEN
+	db := newTestDB(t)
+	defer db.Close()
+	seedBackupDB(t, db)
+
+	tasksBefore, err := FetchSyncTasks(db)
+	require.NoError(t, err)
+	taskLogsBefore, err := FetchSyncTaskLogs(db)
+	require.NoError(t, err)
+	tagsBefore, err := FetchTagsForTask(db, 1)
+	require.NoError(t, err)
+
+	// WHEN
+	data, err := ExportAll(db)
+	require.NoError(t, err)
+	cleanupDB(t, db)
+	err = ImportAll(db, data)
+
+	// THEN
+	require.NoError(t, err)
+
+	tasksAfter, err := FetchSyncTasks(db)
+	require.NoError(t, err)
+	taskLogsAfter, err := FetchSyncTaskLogs(db)
+	require.NoError(t, err)
+	tagsAfter, err := FetchTagsForTask(db, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, tasksBefore, tasksAfter)
+	assert.Equal(t, taskLogsBefore, taskLogsAfter)
+	assert.Equal(t, tagsBefore, tagsAfter)
+
+	weeklyGoalSecs, err := GetWeeklyGoal(db)
+	require.NoError(t, err)
+	assert.Equal(t, 40*60*60, weeklyGoalSecs)
+	lastPeriod, err := GetLastPeriod(db, "report")
+	require.NoError(t, err)
+	assert.Equal(t, "week", lastPeriod)
+
+	reExported, err := ExportAll(db)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(reExported))
+}
+
+func TestImportAllRejectsNonEmptyDB(t *testing.T) {
+	// GIVEN
+	db := newTestDB(t)
+	defer db.Close()
+	seedBackupDB(t, db)
+
+	data, err := ExportAll(db)
+	require.NoError(t, err)
+
+	// WHEN
+	err = ImportAll(db, data)
+
+	// THEN
+	assert.ErrorIs(t, err, ErrDBNotEmpty)
+	taskLogs, err := FetchSyncTaskLogs(db)
+	require.NoError(t, err)
+	assert.Len(t, taskLogs, 3)
+}
+
+func TestImportAllForcedReplacesExistingData(t *testing.T) {
+	// GIVEN
+	db := newTestDB(t)
+	defer db.Close()
+	seedBackupDB(t, db)
+
+	data, err := ExportAll(db)
+	require.NoError(t, err)
+
+	_, err = InsertTask(db, "added after the backup")
+	require.NoError(t, err)
+
+	// WHEN
+	err = ImportAllForced(db, data, true)
+
+	// THEN
+	require.NoError(t, err)
+	reExported, err := ExportAll(db)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(reExported))
+}
+
+func TestImportAllRejectsUnknownVersion(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	err := ImportAll(db, []byte(`{"version": 99, "tasks": [], "taskLogs": []}`))
+
+	assert.ErrorIs(t, err, ErrBackupVersionUnsupported)
+}
+
+func TestImportAllKeepsSettingsForBackupsWithoutThem(t *testing.T) {
+	// GIVEN
+	db := newTestDB(t)
+	defer db.Close()
+	require.NoError(t, SetWeeklyGoal(db, 10*60*60))
+	require.NoError(t, SetLastPeriod(db, "log", "yest"))
+
+	// WHEN
+	err := ImportAll(db, []byte(`{"version": 1, "tasks": [], "taskLogs": []}`))
+
+	// THEN
+	require.NoError(t, err)
+	weeklyGoalSecs, err := GetWeeklyGoal(db)
+	require.NoError(t, err)
+	assert.Equal(t, 10*60*60, weeklyGoalSecs)
+	lastPeriod, err := GetLastPeriod(db, "log")
+	require.NoError(t, err)
+	assert.Equal(t, "yest", lastPeriod)
+}
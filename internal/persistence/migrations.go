@@ -7,10 +7,10 @@ import (
 	"time"
 )
 
-const latestDBVersion = 2 // only upgrade this after adding a migration in getMigrations
+const latestDBVersion = 9 // only upgrade this after adding a migration in getMigrations
 
 var (
-	ErrDBDowngraded          = errors.New("database downgraded")
+	ErrDBSchemaTooNew        = errors.New("database schema is newer than this version of hours supports")
 	ErrDBMigrationFailed     = errors.New("database migration failed")
 	ErrCouldntFetchDBVersion = errors.New("couldn't fetch version")
 )
@@ -60,6 +60,55 @@ WHERE updated_at IS NULL;
 
 CREATE UNIQUE INDEX IF NOT EXISTS idx_task_log_sync_id
 ON task_log(sync_id);
+`
+
+	migrations[3] = `
+ALTER TABLE task
+ADD COLUMN color TEXT;
+`
+
+	migrations[4] = `
+CREATE TABLE IF NOT EXISTS task_tag (
+    task_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (task_id, tag),
+    FOREIGN KEY(task_id) REFERENCES task(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tag_tag
+ON task_tag(tag);
+`
+
+	migrations[5] = `
+ALTER TABLE task_log
+ADD COLUMN tags TEXT;
+`
+
+	migrations[6] = `
+CREATE TABLE IF NOT EXISTS settings (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    weekly_goal_secs INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT OR IGNORE INTO settings (id)
+VALUES (1);
+`
+
+	migrations[7] = `
+ALTER TABLE task
+ADD COLUMN week_reset_day INTEGER;
+`
+
+	migrations[8] = `
+ALTER TABLE task_log
+ADD COLUMN category TEXT;
+`
+
+	migrations[9] = `
+CREATE TABLE IF NOT EXISTS last_period (
+    command TEXT PRIMARY KEY,
+    period TEXT NOT NULL
+);
 `
 
 	return migrations
@@ -90,10 +139,11 @@ func UpgradeDBIfNeeded(db *sql.DB) error {
 	}
 
 	if latestVersionInDB.version > latestDBVersion {
-		return fmt.Errorf("%w; debug info: version=%d, created at=%q)",
-			ErrDBDowngraded,
+		return fmt.Errorf("%w; debug info: version=%d, created at=%q, latest version supported=%d",
+			ErrDBSchemaTooNew,
 			latestVersionInDB.version,
 			latestVersionInDB.createdAt.Format(time.RFC3339),
+			latestDBVersion,
 		)
 	}
 
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -104,7 +105,7 @@ VALUES (1, 1, ?, ?, 3600, 'completed', false),
 	// THEN
 	latestVersion, err := fetchLatestDBVersion(testDB)
 	require.NoError(t, err)
-	assert.Equal(t, 2, latestVersion.version)
+	assert.Equal(t, latestDBVersion, latestVersion.version)
 
 	var taskCount int
 	var distinctTaskSyncIDs int
@@ -155,3 +156,41 @@ WHERE id = 2;
 	assert.Equal(t, activeBegin, activeCreatedAt)
 	assert.Equal(t, activeBegin, activeUpdatedAt)
 }
+
+func TestUpgradeDBIfNeededFailsForNewerDBVersion(t *testing.T) {
+	// GIVEN
+	testDB, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer testDB.Close()
+
+	require.NoError(t, InitDB(testDB))
+	require.NoError(t, UpgradeDB(testDB, 1))
+	_, err = testDB.Exec(`
+INSERT INTO db_versions (version, created_at)
+VALUES (?, ?);
+`, latestDBVersion+1, time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+
+	// WHEN
+	err = UpgradeDBIfNeeded(testDB)
+
+	// THEN
+	require.ErrorIs(t, err, ErrDBSchemaTooNew)
+	assert.Contains(t, err.Error(), fmt.Sprintf("version=%d", latestDBVersion+1))
+}
+
+func TestUpgradeDBIfNeededIsANoopForLatestDBVersion(t *testing.T) {
+	// GIVEN
+	testDB, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer testDB.Close()
+
+	require.NoError(t, InitDB(testDB))
+	require.NoError(t, UpgradeDB(testDB, 1))
+
+	// WHEN
+	err = UpgradeDBIfNeeded(testDB)
+
+	// THEN
+	assert.NoError(t, err)
+}
@@ -1,10 +1,15 @@
 package persistence
 
 import (
+	"cmp"
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dhth/hours/internal/types"
 )
@@ -23,14 +28,31 @@ var (
 	ErrTaskLogNotFound            = errors.New("db: task log entry not found")
 	ErrTaskNotFound               = errors.New("db: task not found")
 	ErrNegativeSecsSpent          = errors.New("db: secs_spent would become negative")
+	ErrTaskLogOverlaps            = errors.New("db: task log overlaps with an existing entry for the same task")
+	ErrEmptyTaskTag               = errors.New("db: task tag is empty")
+	ErrTagHasComma                = errors.New("db: tag can't contain a comma")
+	ErrInvalidTaskColor           = errors.New("db: invalid task color")
+	ErrNegativeWeeklyGoal         = errors.New("db: weekly goal can't be negative")
+	ErrTaskSummaryTooLong         = errors.New("db: task summary is too long")
+	ErrTaskCreatedAtInFuture      = errors.New("db: task creation time can't be in the future")
 )
 
+// MaxTaskSummaryLength is the maximum number of characters (not bytes) in a
+// task summary.
+const MaxTaskSummaryLength = 255
+
 type QuickSwitchResult struct {
 	LastActiveTaskID    int
 	CurrentlyActiveTLID int
 }
 
 func InsertNewTL(db *sql.DB, taskID int, beginTs time.Time) (int, error) {
+	return InsertNewTLWithComment(db, taskID, beginTs, nil)
+}
+
+// InsertNewTLWithComment is like InsertNewTL, but saves comment with the new
+// active task log entry.
+func InsertNewTLWithComment(db *sql.DB, taskID int, beginTs time.Time, comment *string) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
 		syncID, err := newSyncID()
 		if err != nil {
@@ -39,15 +61,15 @@ func InsertNewTL(db *sql.DB, taskID int, beginTs time.Time) (int, error) {
 
 		now := time.Now().UTC()
 		stmt, err := tx.Prepare(`
-	INSERT INTO task_log (task_id, begin_ts, active, sync_id, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?);
+	INSERT INTO task_log (task_id, begin_ts, comment, active, sync_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
 `)
 		if err != nil {
 			return -1, err
 		}
 		defer stmt.Close()
 
-		res, err := stmt.Exec(taskID, beginTs.UTC(), true, syncID, now, now)
+		res, err := stmt.Exec(taskID, beginTs.UTC(), comment, true, syncID, now, now)
 		if err != nil {
 			return -1, err
 		}
@@ -78,6 +100,37 @@ WHERE active is true;
 	return err
 }
 
+// FetchRecentCommentsForTask returns the distinct, non-empty comments of a
+// task's saved log entries, most recently used first.
+func FetchRecentCommentsForTask(db *sql.DB, taskID int, limit int) ([]string, error) {
+	rows, err := db.Query(`
+SELECT comment
+FROM task_log
+WHERE task_id = ?
+AND active = false
+AND comment IS NOT NULL
+AND comment != ''
+GROUP BY comment
+ORDER BY MAX(end_ts) DESC
+LIMIT ?;
+`, taskID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []string
+	for rows.Next() {
+		var comment string
+		if err := rows.Scan(&comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
 func DeleteActiveTL(db *sql.DB) error {
 	stmt, err := db.Prepare(`
 DELETE FROM task_log
@@ -93,62 +146,218 @@ WHERE active=true;
 	return err
 }
 
-func FinishActiveTL(db *sql.DB, taskLogID int, taskID int, beginTs, endTs time.Time, secsSpent int, comment *string) error {
+func FinishActiveTL(db *sql.DB, taskLogID int, taskID int, beginTs, endTs time.Time, secsSpent int, comment, category *string, tags []string) error {
 	return runInTx(db, func(tx *sql.Tx) error {
-		now := time.Now().UTC()
-		stmt, err := tx.Prepare(`
+		return finishActiveTL(tx, taskLogID, taskID, beginTs, endTs, secsSpent, comment, category, tags)
+	})
+}
+
+// FinishTLOptions holds the adjustments made to the active task log entry when
+// it's finished.
+type FinishTLOptions struct {
+	// MergeSameDay extends the task's saved entry that ended earlier on the day
+	// the active one began (if there's one) up to the active one's end, instead
+	// of saving the active one separately.
+	MergeSameDay bool
+	// RoundTo rounds the time spent to the nearest multiple of it (see
+	// types.RoundTLEnd), moving the end time accordingly. A non-positive value
+	// doesn't round.
+	RoundTo time.Duration
+}
+
+// FinishedTL describes where the active task log entry ended up after being
+// finished.
+type FinishedTL struct {
+	// ID is the ID of the entry holding the finished log; it's the one of the
+	// entry it was merged into, if it was.
+	ID int
+	// SecsSpent is the time added to the task.
+	SecsSpent int
+}
+
+// FinishActiveTLWithOptions is like FinishActiveTL, but applies opts, and works
+// out the time spent from beginTs and endTs.
+func FinishActiveTLWithOptions(db *sql.DB, taskLogID int, taskID int, beginTs, endTs time.Time, comment, category *string, tags []string, opts FinishTLOptions) (FinishedTL, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (FinishedTL, error) {
+		return finishActiveTLWithOptions(tx, taskLogID, taskID, beginTs, endTs, comment, category, tags, opts)
+	})
+}
+
+func finishActiveTLWithOptions(tx *sql.Tx, taskLogID int, taskID int, beginTs, endTs time.Time, comment, category *string, tags []string, opts FinishTLOptions) (FinishedTL, error) {
+	if opts.MergeSameDay {
+		finished, merged, err := mergeActiveTLIntoSameDayTL(tx, taskLogID, taskID, beginTs, endTs, comment, category, tags, opts.RoundTo)
+		if err != nil || merged {
+			return finished, err
+		}
+	}
+
+	endTs = types.RoundTLEnd(beginTs, endTs, opts.RoundTo, time.Now())
+	secsSpent := int(endTs.Sub(beginTs).Seconds())
+	if err := finishActiveTL(tx, taskLogID, taskID, beginTs, endTs, secsSpent, comment, category, tags); err != nil {
+		return FinishedTL{}, err
+	}
+
+	return FinishedTL{taskLogID, secsSpent}, nil
+}
+
+// mergeActiveTLIntoSameDayTL extends the task's saved log entry that ended
+// earlier on the day the active one began up to endTs, and removes the active
+// one. Only the time spent on the two entries counts towards the merged one,
+// not the time between them, so its time spent is less than its range when
+// there's a gap. The active entry is rounded the same way as when it's
+// finished on its own. The merged entry keeps its category unless category is
+// provided, and gets tags added to the ones it already has. It reports false if
+// there's no such entry.
+func mergeActiveTLIntoSameDayTL(tx *sql.Tx, taskLogID int, taskID int, beginTs, endTs time.Time, comment, category *string, tags []string, roundTo time.Duration) (FinishedTL, bool, error) {
+	dayStart := time.Date(beginTs.Year(), beginTs.Month(), beginTs.Day(), 0, 0, 0, 0, beginTs.Location())
+
+	var sameDayTLID int
+	var sameDaySecsSpent int
+	var sameDayComment *string
+	var sameDayTags *string
+	err := tx.QueryRow(`
+SELECT id, secs_spent, comment, tags
+FROM task_log
+WHERE task_id = ?
+AND active = 0
+AND end_ts >= ?
+AND end_ts <= ?
+ORDER BY end_ts DESC
+LIMIT 1;
+`, taskID, dayStart.UTC(), beginTs.UTC()).Scan(&sameDayTLID, &sameDaySecsSpent, &sameDayComment, &sameDayTags)
+	if errors.Is(err, sql.ErrNoRows) {
+		return FinishedTL{}, false, nil
+	} else if err != nil {
+		return FinishedTL{}, false, err
+	}
+
+	if sameDayTags != nil {
+		tags = append(types.ParseLogTags(*sameDayTags), tags...)
+	}
+
+	endTs = types.RoundTLEnd(beginTs, endTs, roundTo, time.Now())
+	secsAdded := int(endTs.Sub(beginTs).Seconds())
+	mergedSecsSpent := sameDaySecsSpent + secsAdded
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(`
+UPDATE task_log
+SET end_ts = ?,
+    secs_spent = ?,
+    comment = ?,
+    category = COALESCE(?, category),
+    tags = ?,
+    updated_at = ?
+WHERE id = ?;
+`, endTs.UTC(), mergedSecsSpent, mergeTLComments(sameDayComment, comment), category, formatTLTags(tags), now, sameDayTLID)
+	if err != nil {
+		return FinishedTL{}, false, err
+	}
+
+	_, err = tx.Exec(`
+DELETE FROM task_log
+WHERE id = ?
+AND active = 1;
+`, taskLogID)
+	if err != nil {
+		return FinishedTL{}, false, err
+	}
+
+	_, err = tx.Exec(`
+UPDATE task
+SET secs_spent = secs_spent+?,
+    updated_at = ?
+WHERE id = ?;
+`, secsAdded, now, taskID)
+	if err != nil {
+		return FinishedTL{}, false, err
+	}
+
+	return FinishedTL{sameDayTLID, secsAdded}, true, nil
+}
+
+func finishActiveTL(tx *sql.Tx, taskLogID int, taskID int, beginTs, endTs time.Time, secsSpent int, comment, category *string, tags []string) error {
+	now := time.Now().UTC()
+	stmt, err := tx.Prepare(`
 UPDATE task_log
 SET active = 0,
     begin_ts = ?,
     end_ts = ?,
     secs_spent = ?,
 	    comment = ?,
+	    category = ?,
+	    tags = ?,
 	    updated_at = ?
 WHERE id = ?
 AND active = 1;
 `)
-		if err != nil {
-			return err
-		}
-		defer stmt.Close()
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
 
-		_, err = stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, now, taskLogID)
-		if err != nil {
-			return err
-		}
+	_, err = stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, category, formatTLTags(tags), now, taskLogID)
+	if err != nil {
+		return err
+	}
 
-		tStmt, err := tx.Prepare(`
+	tStmt, err := tx.Prepare(`
 UPDATE task
 SET secs_spent = secs_spent+?,
     updated_at = ?
 WHERE id = ?;
     `)
-		if err != nil {
-			return err
+	if err != nil {
+		return err
+	}
+	defer tStmt.Close()
+
+	_, err = tStmt.Exec(secsSpent, now, taskID)
+
+	return err
+}
+
+// mergeTLComments joins the comments of two log entries being merged, skipping
+// the ones that are empty.
+func mergeTLComments(first, second *string) *string {
+	var parts []string
+	for _, comment := range []*string{first, second} {
+		if comment != nil && strings.TrimSpace(*comment) != "" {
+			parts = append(parts, *comment)
 		}
-		defer tStmt.Close()
+	}
 
-		_, err = tStmt.Exec(secsSpent, now, taskID)
+	if len(parts) == 0 {
+		return nil
+	}
 
-		return err
-	})
+	merged := strings.Join(parts, "\n")
+	return &merged
 }
 
-func QuickSwitchActiveTL(db *sql.DB, newActiveTaskID int, ts time.Time) (QuickSwitchResult, error) {
+// QuickSwitchActiveTL finishes the active task log entry at ts (applying opts)
+// and starts tracking time on another task from then on.
+func QuickSwitchActiveTL(db *sql.DB, newActiveTaskID int, ts time.Time, opts FinishTLOptions) (QuickSwitchResult, error) {
 	return runInTxAndReturnA(db, func(tx *sql.Tx) (QuickSwitchResult, error) {
 		// fetch currently active task
 		currentlyActiveTaskRow := tx.QueryRow(`
-SELECT t.id, tl.begin_ts
+SELECT tl.id, t.id, tl.begin_ts, tl.comment, tl.category, tl.tags
 FROM task_log tl left join task t on tl.task_id = t.id
 WHERE tl.active=true;
 `)
 
 		var zero QuickSwitchResult
+		var currentlyActiveTLID int
 		var currentlyActiveTaskID int
 		var currentlyActiveTaskBeginTS time.Time
+		var comment, category, tags *string
 		err := currentlyActiveTaskRow.Scan(
+			&currentlyActiveTLID,
 			&currentlyActiveTaskID,
 			&currentlyActiveTaskBeginTS,
+			&comment,
+			&category,
+			&tags,
 		)
 		if errors.Is(err, sql.ErrNoRows) {
 			return zero, ErrNoTaskActive
@@ -156,53 +365,26 @@ WHERE tl.active=true;
 			return zero, fmt.Errorf("%w: %s", ErrCouldntGetActiveTask, err.Error())
 		}
 
-		tsUTC := ts.UTC()
+		var tlTags []string
+		if tags != nil {
+			tlTags = types.ParseLogTags(*tags)
+		}
 
-		secsSpent := int(tsUTC.Sub(currentlyActiveTaskBeginTS).Seconds())
-		now := time.Now().UTC()
+		tsUTC := ts.UTC()
 
 		// finish currently active task log
-		tlUpdateStmt, err := tx.Prepare(`
-UPDATE task_log
-SET active = 0,
-    end_ts = ?,
-	    secs_spent =?,
-	    updated_at = ?
-WHERE task_id = ?
-AND active = 1;
-`)
-		if err != nil {
-			return zero, fmt.Errorf("%w: %s", ErrCouldntPrepareStatement, err.Error())
-		}
-		defer tlUpdateStmt.Close()
-
-		_, err = tlUpdateStmt.Exec(tsUTC, secsSpent, now, currentlyActiveTaskID)
+		_, err = finishActiveTLWithOptions(tx, currentlyActiveTLID, currentlyActiveTaskID, currentlyActiveTaskBeginTS.In(ts.Location()), ts, comment, category, tlTags, opts)
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntFinishActiveTL, err.Error())
 		}
 
-		// update last active task's seconds spent
-		tUpdateStmt, err := tx.Prepare(`
-UPDATE task
-SET secs_spent = secs_spent+?,
-    updated_at = ?
-WHERE id = ?;
-    `)
-		if err != nil {
-			return zero, fmt.Errorf("%w: %s", ErrCouldntPrepareStatement, err.Error())
-		}
-		defer tUpdateStmt.Close()
-
-		_, err = tUpdateStmt.Exec(secsSpent, now, currentlyActiveTaskID)
-		if err != nil {
-			return zero, fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
-		}
-
 		syncID, err := newSyncID()
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntGenerateSyncID, err.Error())
 		}
 
+		now := time.Now().UTC()
+
 		// insert new task log
 		tlInsertStmt, err := tx.Prepare(`
 	INSERT INTO task_log (task_id, begin_ts, active, sync_id, created_at, updated_at)
@@ -218,44 +400,43 @@ WHERE id = ?;
 			return zero, fmt.Errorf("%w: %s", ErrCouldntCreateTL, err.Error())
 		}
 
-		currentlyActiveTLID, err := insertRes.LastInsertId()
+		newlyActiveTLID, err := insertRes.LastInsertId()
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntLastInsertID, err.Error())
 		}
 
-		return QuickSwitchResult{currentlyActiveTaskID, int(currentlyActiveTLID)}, nil
+		return QuickSwitchResult{currentlyActiveTaskID, int(newlyActiveTLID)}, nil
 	})
 }
 
-func InsertManualTL(db *sql.DB, taskID int, beginTs time.Time, endTs time.Time, comment *string) (int, error) {
+// InsertManualTL inserts a finished task log entry. Unless allowOverlap is
+// true, it returns ErrTaskLogOverlaps if the entry overlaps with a saved entry
+// for the same task.
+func InsertManualTL(db *sql.DB, taskID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, allowOverlap bool) (int, error) {
+	return InsertManualTLRounded(db, taskID, beginTs, endTs, comment, category, tags, allowOverlap, 0)
+}
+
+// InsertManualTLRounded is like InsertManualTL, but rounds the time spent to
+// the nearest multiple of roundTo (see types.RoundTLEnd), moving the end time
+// accordingly. A non-positive roundTo doesn't round.
+func InsertManualTLRounded(db *sql.DB, taskID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, allowOverlap bool, roundTo time.Duration) (int, error) {
+	endTs = types.RoundTLEnd(beginTs, endTs, roundTo, time.Now())
+
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
-		syncID, err := newSyncID()
-		if err != nil {
-			return -1, fmt.Errorf("%w: %s", ErrCouldntGenerateSyncID, err.Error())
+		if !allowOverlap {
+			if err := checkTLOverlapInTx(tx, -1, taskID, beginTs, endTs); err != nil {
+				return -1, err
+			}
 		}
 
 		now := time.Now().UTC()
-		stmt, err := tx.Prepare(`
-	INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, active, sync_id, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
-`)
+		lastID, err := insertManualTLInTx(tx, taskID, beginTs, endTs, comment, category, tags, now)
 		if err != nil {
 			return -1, err
 		}
-		defer stmt.Close()
 
 		secsSpent := int(endTs.Sub(beginTs).Seconds())
 
-		res, err := stmt.Exec(taskID, beginTs.UTC(), endTs.UTC(), secsSpent, comment, false, syncID, now, now)
-		if err != nil {
-			return -1, err
-		}
-
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
-		}
-
 		tStmt, err := tx.Prepare(`
 UPDATE task
 SET secs_spent = secs_spent+?,
@@ -272,65 +453,40 @@ WHERE id = ?;
 			return -1, err
 		}
 
-		return int(lastID), nil
+		return lastID, nil
 	})
 }
 
-func EditSavedTL(db *sql.DB, tlID int, beginTs time.Time, endTs time.Time, comment *string) (int, error) {
-	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
-		var tl types.TaskLogEntry
-		row := tx.QueryRow(`
-SELECT id, task_id, begin_ts, end_ts, secs_spent, comment
-FROM task_log
-WHERE id=?;
-    `, tlID)
-
-		if row.Err() != nil {
-			return -1, fmt.Errorf("%w: %s", ErrCouldntGetTaskLogDetails, row.Err().Error())
-		}
-		err := row.Scan(&tl.ID,
-			&tl.TaskID,
-			&tl.BeginTS,
-			&tl.EndTS,
-			&tl.SecsSpent,
-			&tl.Comment,
-		)
-		if err != nil {
-			return -1, fmt.Errorf("%w: %s", ErrCouldntGetTaskLogDetails, err.Error())
-		}
-
-		previousSecsSpent := tl.SecsSpent
-		taskID := tl.TaskID
-
-		stmt, err := tx.Prepare(`
-UPDATE task_log
-SET begin_ts = ?,
-    end_ts = ?,
-    secs_spent = ?,
-	    comment = ?,
-	    updated_at = ?
-WHERE id=?;
-`)
-		if err != nil {
-			return -1, err
-		}
-		defer stmt.Close()
-
-		secsSpent := int(endTs.Sub(beginTs).Seconds())
-
+// InsertManualTLs inserts several finished task log entries in a single
+// transaction. Time spent on each affected task is updated once, after all
+// entries have been inserted. Unless allowOverlap is true, entries are checked
+// for overlaps like in InsertManualTL, including against the ones before them
+// in entries. If any entry is rejected, or any of the tasks doesn't exist,
+// nothing is saved.
+func InsertManualTLs(db *sql.DB, entries []types.ManualTaskLog, allowOverlap bool) ([]int, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) ([]int, error) {
 		now := time.Now().UTC()
-		res, err := stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, now, tlID)
-		if err != nil {
-			return -1, err
-		}
+		ids := make([]int, 0, len(entries))
+		secsSpentByTask := make(map[int]int)
+		var taskIDs []int
+
+		for i, entry := range entries {
+			if !allowOverlap {
+				if err := checkTLOverlapInTx(tx, -1, entry.TaskID, entry.BeginTS, entry.EndTS); err != nil {
+					return nil, fmt.Errorf("%w (entry %d of the batch)", err, i+1)
+				}
+			}
 
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
-		}
+			lastID, err := insertManualTLInTx(tx, entry.TaskID, entry.BeginTS, entry.EndTS, entry.Comment, entry.Category, nil, now)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, lastID)
 
-		if previousSecsSpent == secsSpent {
-			return int(lastID), nil
+			if _, ok := secsSpentByTask[entry.TaskID]; !ok {
+				taskIDs = append(taskIDs, entry.TaskID)
+			}
+			secsSpentByTask[entry.TaskID] += int(entry.EndTS.Sub(entry.BeginTS).Seconds())
 		}
 
 		tStmt, err := tx.Prepare(`
@@ -340,97 +496,431 @@ SET secs_spent = secs_spent+?,
 WHERE id = ?;
     `)
 		if err != nil {
-			return -1, err
+			return nil, err
 		}
 		defer tStmt.Close()
 
-		_, err = tStmt.Exec(secsSpent-previousSecsSpent, now, taskID)
-		if err != nil {
-			return -1, fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
+		for _, taskID := range taskIDs {
+			res, err := tStmt.Exec(secsSpentByTask[taskID], now, taskID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
+			}
+
+			rowsAffected, err := res.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			if rowsAffected == 0 {
+				return nil, fmt.Errorf("%w (ID: %d)", ErrTaskNotFound, taskID)
+			}
 		}
 
-		return int(lastID), nil
+		return ids, nil
 	})
 }
 
-func FetchActiveTaskDetails(db *sql.DB) (types.ActiveTaskDetails, error) {
-	row := db.QueryRow(`
-SELECT t.id, t.summary, tl.begin_ts, tl.comment
-FROM task_log tl left join task t on tl.task_id = t.id
-WHERE tl.active=true;
-`)
-
-	var activeTaskDetails types.ActiveTaskDetails
-	err := row.Scan(
-		&activeTaskDetails.TaskID,
-		&activeTaskDetails.TaskSummary,
-		&activeTaskDetails.CurrentLogBeginTS,
-		&activeTaskDetails.CurrentLogComment,
-	)
-	if errors.Is(err, sql.ErrNoRows) {
-		activeTaskDetails.TaskID = -1
-		return activeTaskDetails, nil
-	} else if err != nil {
-		return activeTaskDetails, err
-	}
-	activeTaskDetails.CurrentLogBeginTS = activeTaskDetails.CurrentLogBeginTS.Local()
-	return activeTaskDetails, nil
-}
-
-func InsertTask(db *sql.DB, summary string) (int, error) {
-	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+// ImportTaskLogs saves entries against the tasks with a matching summary,
+// creating the tasks that don't exist yet, all in a single transaction. Active
+// tasks are preferred over inactive ones with the same summary. Unless
+// allowOverlap is true, entries are checked for overlaps like in
+// InsertManualTL. If any entry is rejected, nothing is saved. It returns the
+// number of tasks created.
+func ImportTaskLogs(db *sql.DB, entries []types.ImportedTaskLog, allowOverlap bool) (int, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (int, error) {
 		now := time.Now().UTC()
-		syncID, err := newSyncID()
-		if err != nil {
-			return -1, fmt.Errorf("%w: %s", ErrCouldntGenerateSyncID, err.Error())
-		}
+		taskIDs := make(map[string]int)
+		secsSpentByTask := make(map[int]int)
+		var numNewTasks int
+
+		for _, entry := range entries {
+			taskID, ok := taskIDs[entry.TaskSummary]
+			if !ok {
+				var err error
+				taskID, err = fetchTaskIDBySummaryInTx(tx, entry.TaskSummary)
+				if errors.Is(err, sql.ErrNoRows) {
+					if err = checkTaskSummaryLength(entry.TaskSummary); err != nil {
+						return -1, err
+					}
+					taskID, err = insertTaskInTx(tx, entry.TaskSummary, now)
+					numNewTasks++
+				}
+				if err != nil {
+					return -1, err
+				}
+				taskIDs[entry.TaskSummary] = taskID
+			}
 
-		stmt, err := tx.Prepare(`
-		INSERT into task (summary, active, sync_id, created_at, updated_at)
-		VALUES (?, true, ?, ?, ?);
-`)
-		if err != nil {
-			return -1, err
-		}
-		defer stmt.Close()
+			if !allowOverlap {
+				if err := checkTLOverlapInTx(tx, -1, taskID, entry.BeginTS, entry.EndTS); err != nil {
+					return -1, fmt.Errorf("%w (entry for %q beginning at %s)", err, entry.TaskSummary, entry.BeginTS.Format(time.DateTime))
+				}
+			}
 
-		res, err := stmt.Exec(summary, syncID, now, now)
-		if err != nil {
-			return -1, err
+			if _, err := insertManualTLInTx(tx, taskID, entry.BeginTS, entry.EndTS, entry.Comment, nil, nil, now); err != nil {
+				return -1, err
+			}
+			secsSpentByTask[taskID] += int(entry.EndTS.Sub(entry.BeginTS).Seconds())
 		}
 
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
+		for taskID, secsSpent := range secsSpentByTask {
+			_, err := tx.Exec(`
+UPDATE task
+SET secs_spent = secs_spent+?,
+    updated_at = ?
+WHERE id = ?;
+`, secsSpent, now, taskID)
+			if err != nil {
+				return -1, fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
+			}
 		}
 
-		return int(lastID), nil
+		return numNewTasks, nil
 	})
 }
 
-func UpdateTask(db *sql.DB, id int, summary string) error {
-	stmt, err := db.Prepare(`
-UPDATE task
-SET summary = ?,
-    updated_at = ?
-WHERE id = ?
-`)
-	if err != nil {
-		return err
+// fetchTaskIDBySummaryInTx returns the ID of the task with the given summary,
+// preferring active tasks, and then the most recently updated ones. It returns
+// sql.ErrNoRows if there's no such task.
+func fetchTaskIDBySummaryInTx(tx *sql.Tx, summary string) (int, error) {
+	var id int
+	err := tx.QueryRow(`
+SELECT id
+FROM task
+WHERE summary = ?
+ORDER BY active DESC, updated_at DESC
+LIMIT 1;
+`, summary).Scan(&id)
+
+	return id, err
+}
+
+func insertManualTLInTx(tx *sql.Tx, taskID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, now time.Time) (int, error) {
+	syncID, err := newSyncID()
+	if err != nil {
+		return -1, fmt.Errorf("%w: %s", ErrCouldntGenerateSyncID, err.Error())
+	}
+
+	stmt, err := tx.Prepare(`
+	INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, category, tags, active, sync_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+`)
+	if err != nil {
+		return -1, err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(summary, time.Now().UTC(), id)
+	secsSpent := int(endTs.Sub(beginTs).Seconds())
+
+	res, err := stmt.Exec(taskID, beginTs.UTC(), endTs.UTC(), secsSpent, comment, category, formatTLTags(tags), false, syncID, now, now)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(lastID), nil
+}
+
+// checkTLOverlapInTx returns ErrTaskLogOverlaps if a saved entry for the task,
+// other than the one with ID tlID, intersects the range [beginTs, endTs).
+// Entries that only touch the range at its boundaries don't count as
+// overlapping.
+func checkTLOverlapInTx(tx *sql.Tx, tlID int, taskID int, beginTs, endTs time.Time) error {
+	row := tx.QueryRow(`
+SELECT id
+FROM task_log
+WHERE task_id = ?
+AND active = false
+AND id != ?
+AND begin_ts < ?
+AND end_ts > ?
+ORDER BY begin_ts ASC
+LIMIT 1;
+`, taskID, tlID, endTs.UTC(), beginTs.UTC())
+
+	var overlappingID int
+	err := row.Scan(&overlappingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return fmt.Errorf("%w (ID: %d)", ErrTaskLogOverlaps, overlappingID)
 }
 
-func UpdateTaskActiveStatus(db *sql.DB, id int, active bool) error {
+// EditSavedTL updates a saved task log entry, replacing its tags with tags.
+// Unless allowOverlap is true, it returns ErrTaskLogOverlaps if the updated
+// entry would overlap with another saved entry for the same task.
+func EditSavedTL(db *sql.DB, tlID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, allowOverlap bool) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		return editSavedTLInTx(tx, tlID, -1, beginTs, endTs, comment, category, tags, allowOverlap)
+	})
+}
+
+// EditAndMoveSavedTL is like EditSavedTL, but also moves the entry to the task
+// with ID newTaskID, in the same transaction. Overlaps are checked against the
+// saved entries for the new task.
+func EditAndMoveSavedTL(db *sql.DB, tlID int, newTaskID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, allowOverlap bool) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		return editSavedTLInTx(tx, tlID, newTaskID, beginTs, endTs, comment, category, tags, allowOverlap)
+	})
+}
+
+// editSavedTLInTx updates a saved task log entry, and moves it to the task
+// with ID newTaskID, unless it's -1.
+func editSavedTLInTx(tx *sql.Tx, tlID int, newTaskID int, beginTs time.Time, endTs time.Time, comment, category *string, tags []string, allowOverlap bool) (int, error) {
+	var tl types.TaskLogEntry
+	row := tx.QueryRow(`
+SELECT id, task_id, begin_ts, end_ts, secs_spent, comment
+FROM task_log
+WHERE id=?;
+    `, tlID)
+
+	if row.Err() != nil {
+		return -1, fmt.Errorf("%w: %s", ErrCouldntGetTaskLogDetails, row.Err().Error())
+	}
+	err := row.Scan(&tl.ID,
+		&tl.TaskID,
+		&tl.BeginTS,
+		&tl.EndTS,
+		&tl.SecsSpent,
+		&tl.Comment,
+	)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %s", ErrCouldntGetTaskLogDetails, err.Error())
+	}
+
+	previousSecsSpent := tl.SecsSpent
+	taskID := tl.TaskID
+	targetTaskID := taskID
+	if newTaskID != -1 {
+		targetTaskID = newTaskID
+	}
+
+	if !allowOverlap {
+		if err := checkTLOverlapInTx(tx, tlID, targetTaskID, beginTs, endTs); err != nil {
+			return -1, err
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+UPDATE task_log
+SET begin_ts = ?,
+    end_ts = ?,
+    secs_spent = ?,
+	    comment = ?,
+	    category = ?,
+	    tags = ?,
+	    updated_at = ?
+WHERE id=?;
+`)
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	secsSpent := int(endTs.Sub(beginTs).Seconds())
+
+	now := time.Now().UTC()
+	res, err := stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, category, formatTLTags(tags), now, tlID)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	if previousSecsSpent != secsSpent {
+		tStmt, err := tx.Prepare(`
+UPDATE task
+SET secs_spent = secs_spent+?,
+    updated_at = ?
+WHERE id = ?;
+    `)
+		if err != nil {
+			return -1, err
+		}
+		defer tStmt.Close()
+
+		_, err = tStmt.Exec(secsSpent-previousSecsSpent, now, taskID)
+		if err != nil {
+			return -1, fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
+		}
+	}
+
+	if targetTaskID != taskID {
+		if err := moveTaskLogInTx(tx, tlID, taskID, targetTaskID, secsSpent, now); err != nil {
+			return -1, err
+		}
+	}
+
+	return int(lastID), nil
+}
+
+func FetchActiveTaskDetails(db *sql.DB) (types.ActiveTaskDetails, error) {
+	row := db.QueryRow(`
+SELECT t.id, t.summary, tl.id, tl.begin_ts, tl.comment
+FROM task_log tl left join task t on tl.task_id = t.id
+WHERE tl.active=true;
+`)
+
+	var activeTaskDetails types.ActiveTaskDetails
+	err := row.Scan(
+		&activeTaskDetails.TaskID,
+		&activeTaskDetails.TaskSummary,
+		&activeTaskDetails.CurrentLogID,
+		&activeTaskDetails.CurrentLogBeginTS,
+		&activeTaskDetails.CurrentLogComment,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		activeTaskDetails.TaskID = -1
+		return activeTaskDetails, nil
+	} else if err != nil {
+		return activeTaskDetails, err
+	}
+	activeTaskDetails.CurrentLogBeginTS = activeTaskDetails.CurrentLogBeginTS.Local()
+	return activeTaskDetails, nil
+}
+
+func FetchTaskByID(db *sql.DB, id int) (types.Task, error) {
+	task, err := fetchTaskByID(db, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task, ErrTaskNotFound
+	}
+
+	return task, err
+}
+
+// FetchMostRecentlyArchivedTask returns the inactive task that was updated
+// most recently, which is usually the one archived last. It returns nil if
+// there are no inactive tasks.
+func FetchMostRecentlyArchivedTask(db *sql.DB) (*types.Task, error) {
+	var task types.Task
+	row := db.QueryRow(`
+SELECT id, summary, secs_spent, active, created_at, updated_at, color
+FROM task
+WHERE active = false
+ORDER BY updated_at DESC, id DESC
+LIMIT 1;
+`)
+
+	err := row.Scan(&task.ID,
+		&task.Summary,
+		&task.SecsSpent,
+		&task.Active,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Color,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	task.CreatedAt = task.CreatedAt.Local()
+	task.UpdatedAt = task.UpdatedAt.Local()
+
+	return &task, nil
+}
+
+func FetchTLByID(db *sql.DB, id int) (types.TaskLogEntry, error) {
+	tl, err := fetchTLByID(db, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tl, ErrTaskLogNotFound
+	}
+
+	return tl, err
+}
+
+// FetchFirstOverlappingTL returns the saved entry for the task that begins
+// the earliest among the ones intersecting the range [beginTs, endTs). It
+// returns ErrTaskLogNotFound if there's no such entry.
+func FetchFirstOverlappingTL(db *sql.DB, taskID int, beginTs, endTs time.Time) (types.TaskLogEntry, error) {
+	var tl types.TaskLogEntry
+	row := db.QueryRow(`
+SELECT id, task_id, begin_ts, end_ts, secs_spent, comment
+FROM task_log
+WHERE task_id = ?
+AND active = false
+AND begin_ts < ?
+AND end_ts > ?
+ORDER BY begin_ts ASC
+LIMIT 1;
+`, taskID, endTs.UTC(), beginTs.UTC())
+
+	err := row.Scan(&tl.ID,
+		&tl.TaskID,
+		&tl.BeginTS,
+		&tl.EndTS,
+		&tl.SecsSpent,
+		&tl.Comment,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tl, ErrTaskLogNotFound
+	}
+	if err != nil {
+		return tl, err
+	}
+	tl.BeginTS = tl.BeginTS.Local()
+	tl.EndTS = tl.EndTS.Local()
+
+	return tl, nil
+}
+
+func InsertTask(db *sql.DB, summary string) (int, error) {
+	if err := checkTaskSummaryLength(summary); err != nil {
+		return -1, err
+	}
+
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		return insertTaskInTx(tx, summary, time.Now().UTC())
+	})
+}
+
+func insertTaskInTx(tx *sql.Tx, summary string, now time.Time) (int, error) {
+	syncID, err := newSyncID()
+	if err != nil {
+		return -1, fmt.Errorf("%w: %s", ErrCouldntGenerateSyncID, err.Error())
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT into task (summary, active, sync_id, created_at, updated_at)
+		VALUES (?, true, ?, ?, ?);
+`)
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(summary, syncID, now, now)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(lastID), nil
+}
+
+func UpdateTask(db *sql.DB, id int, summary string) error {
+	if err := checkTaskSummaryLength(summary); err != nil {
+		return err
+	}
+
 	stmt, err := db.Prepare(`
 UPDATE task
-SET active = ?,
+SET summary = ?,
     updated_at = ?
 WHERE id = ?
 `)
@@ -439,173 +929,1207 @@ WHERE id = ?
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(active, time.Now().UTC(), id)
+	_, err = stmt.Exec(summary, time.Now().UTC(), id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func UpdateTaskData(db *sql.DB, t *types.Task) error {
-	row := db.QueryRow(`
-SELECT secs_spent, updated_at
-FROM task
-WHERE id=?;
-    `, t.ID)
+func checkTaskSummaryLength(summary string) error {
+	if utf8.RuneCountInString(summary) > MaxTaskSummaryLength {
+		return fmt.Errorf("%w (it can have at most %d characters)", ErrTaskSummaryTooLong, MaxTaskSummaryLength)
+	}
+
+	return nil
+}
+
+// UpdateTaskCreatedAt changes when the task is considered to have been
+// created. It returns ErrTaskCreatedAtInFuture if createdAt is in the future.
+func UpdateTaskCreatedAt(db *sql.DB, taskID int, createdAt time.Time) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		return updateTaskCreatedAtInTx(tx, taskID, createdAt)
+	})
+}
+
+// UpdateTaskWithCreatedAt is like UpdateTask, but also changes when the task
+// is considered to have been created (see UpdateTaskCreatedAt), unless
+// createdAt is nil. Either both are changed or neither is.
+func UpdateTaskWithCreatedAt(db *sql.DB, id int, summary string, createdAt *time.Time) error {
+	if err := checkTaskSummaryLength(summary); err != nil {
+		return err
+	}
+
+	return runInTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+UPDATE task
+SET summary = ?,
+    updated_at = ?
+WHERE id = ?
+`, summary, time.Now().UTC(), id)
+		if err != nil {
+			return err
+		}
+
+		if createdAt == nil {
+			return nil
+		}
+
+		return updateTaskCreatedAtInTx(tx, id, *createdAt)
+	})
+}
+
+func updateTaskCreatedAtInTx(tx *sql.Tx, taskID int, createdAt time.Time) error {
+	now := time.Now()
+	if createdAt.After(now) {
+		return ErrTaskCreatedAtInFuture
+	}
+
+	_, err := tx.Exec(`
+UPDATE task
+SET created_at = ?,
+    updated_at = ?
+WHERE id = ?
+`, createdAt.UTC(), now.UTC(), taskID)
+	return err
+}
+
+func UpdateTaskActiveStatus(db *sql.DB, id int, active bool) error {
+	stmt, err := db.Prepare(`
+UPDATE task
+SET active = ?,
+    updated_at = ?
+WHERE id = ?
+`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(active, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetTaskColor stores a color (a hex or ANSI 256 color code) for a task. An
+// empty color clears the stored value.
+func SetTaskColor(db *sql.DB, taskID int, color string) error {
+	var value *string
+	if color != "" {
+		if !types.IsValidColor(color) {
+			return fmt.Errorf("%w: %q", ErrInvalidTaskColor, color)
+		}
+		value = &color
+	}
+
+	res, err := db.Exec(`
+UPDATE task
+SET color = ?,
+    updated_at = ?
+WHERE id = ?
+`, value, time.Now().UTC(), taskID)
+	if err != nil {
+		return err
+	}
+
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numRows == 0 {
+		return ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// SetTaskWeekResetDay sets the weekday on which the time tracked on a task
+// "this week" restarts. A nil day makes the task follow the regular week,
+// which starts on Monday.
+func SetTaskWeekResetDay(db *sql.DB, taskID int, day *time.Weekday) error {
+	var value *int
+	if day != nil {
+		dayValue := int(*day)
+		value = &dayValue
+	}
+
+	res, err := db.Exec(`
+UPDATE task
+SET week_reset_day = ?,
+    updated_at = ?
+WHERE id = ?
+`, value, time.Now().UTC(), taskID)
+	if err != nil {
+		return err
+	}
+
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numRows == 0 {
+		return ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// FetchTaskWeekTotal returns the time tracked on a task in saved entries that
+// ended since the start of its week, ie, since its week reset day (or Monday,
+// if it doesn't have one) on or before now.
+func FetchTaskWeekTotal(db *sql.DB, taskID int, now time.Time) (int, error) {
+	var resetDay sql.NullInt64
+	err := db.QueryRow(`
+SELECT week_reset_day
+FROM task
+WHERE id = ?;
+`, taskID).Scan(&resetDay)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrTaskNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	row := db.QueryRow(`
+SELECT COALESCE(SUM(secs_spent), 0)
+FROM task_log
+WHERE task_id = ?
+AND active = false
+AND end_ts >= ?
+AND end_ts <= ?;
+`, taskID, taskWeekStart(resetDay, now).UTC(), now.UTC())
+
+	var secsSpent int
+	err = row.Scan(&secsSpent)
+
+	return secsSpent, err
+}
+
+// FetchStatsForTaskWeeks is like FetchStatsBetweenTS, but only considers saved
+// entries that ended since the start of each task's own week (see
+// FetchTaskWeekTotal), up to now.
+func FetchStatsForTaskWeeks(db *sql.DB, now time.Time, limit int) ([]types.TaskReportEntry, error) {
+	var args []any
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		args = append(args, taskWeekStart(sql.NullInt64{Int64: int64(day), Valid: true}, now).UTC())
+	}
+	args = append(args, taskWeekStart(sql.NullInt64{}, now).UTC(), now.UTC(), limit)
+
+	rows, err := db.Query(`
+SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries, SUM(tl.secs_spent) AS secs_spent
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.active = false
+AND tl.end_ts >= CASE t.week_reset_day
+    WHEN 0 THEN ?
+    WHEN 1 THEN ?
+    WHEN 2 THEN ?
+    WHEN 3 THEN ?
+    WHEN 4 THEN ?
+    WHEN 5 THEN ?
+    WHEN 6 THEN ?
+    ELSE ?
+END
+AND tl.end_ts <= ?
+GROUP BY tl.task_id
+ORDER BY secs_spent DESC
+LIMIT ?;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskReportEntries(rows)
+}
+
+// taskWeekStart returns the start of the week now falls in, for a task with
+// resetDay as its week reset day. Tasks without one follow the regular week,
+// which starts on Monday.
+func taskWeekStart(resetDay sql.NullInt64, now time.Time) time.Time {
+	weekStart := time.Monday
+	if resetDay.Valid {
+		weekStart = time.Weekday(resetDay.Int64)
+	}
+
+	return types.StartOfWeek(now, weekStart)
+}
+
+// RecalculateTaskSecsSpent sets a task's secs_spent to the sum of secs_spent
+// across its saved task log entries, and returns the new value. This repairs
+// totals that have drifted from the task's log entries.
+func RecalculateTaskSecsSpent(db *sql.DB, taskID int) (int, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (int, error) {
+		var exists int
+		err := tx.QueryRow(`SELECT 1 FROM task WHERE id = ?`, taskID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, ErrTaskNotFound
+		}
+		if err != nil {
+			return -1, err
+		}
+
+		var secsSpent int
+		err = tx.QueryRow(`
+SELECT COALESCE(SUM(secs_spent), 0)
+FROM task_log
+WHERE task_id = ?
+AND active = false;
+`, taskID).Scan(&secsSpent)
+		if err != nil {
+			return -1, err
+		}
+
+		_, err = tx.Exec(`
+UPDATE task
+SET secs_spent = ?
+WHERE id = ?;
+`, secsSpent, taskID)
+		if err != nil {
+			return -1, err
+		}
+
+		return secsSpent, nil
+	})
+}
+
+// RecalculateAllTasksSecsSpent is the batch variant of
+// RecalculateTaskSecsSpent. It returns the number of tasks whose secs_spent
+// needed to be corrected.
+func RecalculateAllTasksSecsSpent(db *sql.DB) (int, error) {
+	res, err := db.Exec(`
+UPDATE task
+SET secs_spent = (
+    SELECT COALESCE(SUM(tl.secs_spent), 0)
+    FROM task_log tl
+    WHERE tl.task_id = task.id
+    AND tl.active = false
+)
+WHERE secs_spent != (
+    SELECT COALESCE(SUM(tl.secs_spent), 0)
+    FROM task_log tl
+    WHERE tl.task_id = task.id
+    AND tl.active = false
+);
+`)
+	if err != nil {
+		return -1, err
+	}
+
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(numRows), nil
+}
+
+func UpdateTaskData(db *sql.DB, t *types.Task) error {
+	row := db.QueryRow(`
+SELECT secs_spent, updated_at
+FROM task
+WHERE id=?;
+    `, t.ID)
+
+	err := row.Scan(
+		&t.SecsSpent,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func FetchTasks(db *sql.DB, active bool, limit int) ([]types.Task, error) {
+	return FetchTasksPaged(db, active, limit, 0)
+}
+
+// FetchTasksPaged is like FetchTasks, but skips the first offset tasks. Tasks
+// updated at the same time are ordered by ID, so that pages don't overlap.
+func FetchTasksPaged(db *sql.DB, active bool, limit, offset int) ([]types.Task, error) {
+	rows, err := db.Query(`
+SELECT id, summary, secs_spent, created_at, updated_at, active, color
+FROM task
+WHERE active=?
+ORDER by updated_at DESC, id DESC
+LIMIT ?
+OFFSET ?;
+    `, active, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTasks(rows)
+}
+
+func FetchTLEntries(db *sql.DB, desc bool, limit int) ([]types.TaskLogEntry, error) {
+	var order string
+	if desc {
+		order = "DESC"
+	} else {
+		order = "ASC"
+	}
+	query := fmt.Sprintf(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+ORDER by tl.end_ts %s
+LIMIT ?;
+`, order)
+
+	rows, err := db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskLogEntries(rows)
+}
+
+// FetchTLEntriesForTask returns saved task log entries for a single task,
+// ordered by end time.
+func FetchTLEntriesForTask(db *sql.DB, taskID int, desc bool, limit int) ([]types.TaskLogEntry, error) {
+	var order string
+	if desc {
+		order = "DESC"
+	} else {
+		order = "ASC"
+	}
+	query := fmt.Sprintf(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+AND tl.task_id=?
+ORDER by tl.end_ts %s
+LIMIT ?;
+`, order)
+
+	rows, err := db.Query(query, taskID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskLogEntries(rows)
+}
+
+// AllTLEntries yields every saved task log entry, oldest first, reading rows
+// from the database one at a time instead of collecting them upfront. Iteration
+// stops after the first error, which is yielded alongside a zero entry.
+func AllTLEntries(db *sql.DB) iter.Seq2[types.TaskLogEntry, error] {
+	return func(yield func(types.TaskLogEntry, error) bool) {
+		rows, err := db.Query(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+ORDER by tl.begin_ts ASC, tl.id ASC;
+`)
+		if err != nil {
+			yield(types.TaskLogEntry{}, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			entry, err := scanTaskLogEntry(rows)
+			if err != nil {
+				yield(types.TaskLogEntry{}, err)
+				return
+			}
+			if !yield(entry, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(types.TaskLogEntry{}, err)
+		}
+	}
+}
+
+// SearchTaskLogsByComment returns at most limit saved task log entries whose
+// comment contains query, ignoring case, most recent first.
+func SearchTaskLogsByComment(db *sql.DB, query string, limit int) ([]types.TaskLogEntry, error) {
+	rows, err := db.Query(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+AND instr(lower(tl.comment), lower(?)) > 0
+ORDER by tl.end_ts DESC
+LIMIT ?;
+`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskLogEntries(rows)
+}
+
+// FetchTLEntriesBetweenTS returns at most limit saved task log entries that
+// end between beginTs and endTs, and match filter. It also reports whether more
+// entries than limit matched, in which case the result is truncated.
+func FetchTLEntriesBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter TLFilter, limit int) ([]types.TaskLogEntry, bool, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	// one more row than asked for is fetched to know if the limit was reached
+	args = append(args, limit+1)
+
+	rows, err := db.Query(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+AND tl.end_ts >= ?
+AND tl.end_ts < ?
+`+filterSQL+`
+ORDER by tl.begin_ts ASC LIMIT ?;
+    `, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	entries, err := collectTaskLogEntries(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries, limitReached := trimToLimit(entries, limit)
+	return entries, limitReached, nil
+}
+
+// FetchStats returns the all-time stats for at most limit tasks with entries
+// matching filter, ordered by time spent.
+func FetchStats(db *sql.DB, filter TLFilter, limit int) ([]types.TaskReportEntry, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := filterArgs
+	args = append(args, limit)
+
+	rows, err := db.Query(`
+SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries, t.secs_spent
+from task_log tl
+LEFT JOIN task t on tl.task_id = t.id
+WHERE true
+`+filterSQL+`
+GROUP BY tl.task_id
+ORDER BY t.secs_spent DESC
+limit ?;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskReportEntries(rows)
+}
+
+// FetchStatsBetweenTS returns the stats for at most limit tasks by entries that
+// end between beginTs and endTs, and match filter, ordered by time spent.
+func FetchStatsBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter TLFilter, limit int) ([]types.TaskReportEntry, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+
+	rows, err := db.Query(`
+SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
+FROM task_log tl 
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.end_ts >= ? AND tl.end_ts < ?
+`+filterSQL+`
+GROUP BY tl.task_id
+ORDER BY secs_spent DESC
+LIMIT ?;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectTaskReportEntries(rows)
+}
+
+// FetchStatsGrouped returns the stats for each task in each period (as
+// determined by granularity) between beginTs and endTs, with periods
+// beginning in beginTs's location, considering entries that match filter. A
+// task log counts towards the period it ends in. Entries are ordered by period,
+// and then by time spent.
+func FetchStatsGrouped(db *sql.DB, beginTs, endTs time.Time, filter TLFilter, granularity types.Granularity) ([]types.GroupedStatsEntry, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+
+	rows, err := db.Query(`
+SELECT tl.task_id, t.summary, tl.end_ts, tl.secs_spent
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.active = false
+AND tl.end_ts >= ? AND tl.end_ts < ?
+`+filterSQL+`;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type bucketKey struct {
+		periodStart time.Time
+		taskID      int
+	}
+
+	loc := beginTs.Location()
+	var entries []types.GroupedStatsEntry
+	indexByBucket := make(map[bucketKey]int)
+	for rows.Next() {
+		var taskID, secsSpent int
+		var summary string
+		var endTS time.Time
+		if err := rows.Scan(&taskID, &summary, &endTS, &secsSpent); err != nil {
+			return nil, err
+		}
+
+		key := bucketKey{granularity.PeriodStart(endTS.In(loc)), taskID}
+		i, ok := indexByBucket[key]
+		if !ok {
+			i = len(entries)
+			indexByBucket[key] = i
+			entries = append(entries, types.GroupedStatsEntry{
+				PeriodStart: key.periodStart,
+				TaskReportEntry: types.TaskReportEntry{
+					TaskID:      taskID,
+					TaskSummary: summary,
+				},
+			})
+		}
+		entries[i].NumEntries++
+		entries[i].SecsSpent += secsSpent
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(entries, func(a, b types.GroupedStatsEntry) int {
+		if c := a.PeriodStart.Compare(b.PeriodStart); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.SecsSpent, a.SecsSpent); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.TaskID, b.TaskID)
+	})
+
+	return entries, nil
+}
+
+// FetchStatsByCategory returns the time spent on entries that end between
+// beginTs and endTs, and match filter, summed up for each task log category.
+// Entries without a category are summed up together, with a nil Category.
+// Categories are ordered by time spent.
+func FetchStatsByCategory(db *sql.DB, beginTs, endTs time.Time, filter TLFilter) ([]types.CategoryStatsEntry, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+
+	rows, err := db.Query(`
+SELECT tl.category, COUNT(tl.id) AS num_entries, SUM(tl.secs_spent) AS secs_spent
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.active = false
+AND tl.end_ts >= ? AND tl.end_ts < ?
+`+filterSQL+`
+GROUP BY tl.category
+ORDER BY secs_spent DESC, tl.category ASC;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []types.CategoryStatsEntry
+	for rows.Next() {
+		var entry types.CategoryStatsEntry
+		if err := rows.Scan(&entry.Category, &entry.NumEntries, &entry.SecsSpent); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// FetchReportBetweenTS returns the time spent on at most limit tasks by
+// entries that end between beginTs and endTs, and match filter. It also reports
+// whether more tasks than limit matched, in which case the result is truncated.
+func FetchReportBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter TLFilter, limit int) ([]types.TaskReportEntry, bool, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	args = append(args, limit+1)
+
+	rows, err := db.Query(`
+SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
+FROM task_log tl 
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.end_ts >= ? AND tl.end_ts < ?
+`+filterSQL+`
+GROUP BY tl.task_id
+ORDER BY t.updated_at ASC
+LIMIT ?;
+`, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	entries, err := collectTaskReportEntries(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries, limitReached := trimToLimit(entries, limit)
+	return entries, limitReached, nil
+}
+
+// trimToLimit caps entries at limit, and reports whether any were dropped.
+func trimToLimit[T any](entries []T, limit int) ([]T, bool) {
+	if len(entries) <= limit {
+		return entries, false
+	}
+
+	return entries[:limit], true
+}
+
+// FetchDailyTotalsBetweenTS returns the time tracked on each day between
+// beginTs and endTs, with days starting at midnight in beginTs's location. A
+// task log counts towards the day it ends on, and only entries that match
+// filter are considered. Days with no tracked time are included with a total
+// of zero.
+func FetchDailyTotalsBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter TLFilter) ([]types.DailyTotal, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+
+	rows, err := db.Query(`
+SELECT tl.end_ts, tl.secs_spent
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.active = false
+AND tl.end_ts >= ? AND tl.end_ts < ?
+`+filterSQL+`;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loc := beginTs.Location()
+	dayStart := func(ts time.Time) time.Time {
+		ts = ts.In(loc)
+		return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, loc)
+	}
+
+	var totals []types.DailyTotal
+	indexByDay := make(map[time.Time]int)
+	for day := dayStart(beginTs); day.Before(endTs); day = day.AddDate(0, 0, 1) {
+		indexByDay[day] = len(totals)
+		totals = append(totals, types.DailyTotal{Day: day})
+	}
+
+	for rows.Next() {
+		var endTS time.Time
+		var secsSpent int
+		if err := rows.Scan(&endTS, &secsSpent); err != nil {
+			return nil, err
+		}
+
+		if i, ok := indexByDay[dayStart(endTS)]; ok {
+			totals[i].SecsSpent += secsSpent
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// FetchDaysWithActivity returns the days on which any time was tracked, in
+// ascending order, with days starting at midnight in the local timezone. A
+// task log counts towards the day it ends on, and only entries that match
+// filter are considered.
+func FetchDaysWithActivity(db *sql.DB, filter TLFilter) ([]time.Time, error) {
+	filterSQL, filterArgs := filter.sql()
+
+	rows, err := db.Query(`
+SELECT tl.end_ts
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE tl.active = false
+AND tl.secs_spent > 0
+`+filterSQL+`;
+`, filterArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	seen := make(map[time.Time]struct{})
+	for rows.Next() {
+		var endTS time.Time
+		if err := rows.Scan(&endTS); err != nil {
+			return nil, err
+		}
+
+		endTS = endTS.Local()
+		day := time.Date(endTS.Year(), endTS.Month(), endTS.Day(), 0, 0, 0, 0, time.Local)
+		if _, ok := seen[day]; ok {
+			continue
+		}
+		seen[day] = struct{}{}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(days, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+
+	return days, nil
+}
+
+// TLFilter restricts the saved task log entries a query considers. Fields
+// left empty (or nil) don't restrict entries, except for TaskStatus, which
+// always applies.
+type TLFilter struct {
+	// TaskStatus only keeps entries for tasks with this status.
+	TaskStatus types.TaskStatus
+	// Tag only keeps entries for tasks carrying this tag.
+	Tag string
+	// LogTag only keeps entries tagged with this tag.
+	LogTag string
+	// TaskID only keeps entries for the task with this ID.
+	TaskID *int
+}
+
+// sql returns SQL conditions (each prefixed with AND) that restrict rows
+// joined as "tl" (task_log) and "t" (task) to the ones matching f, along with
+// the arguments the conditions need.
+func (f TLFilter) sql() (string, []any) {
+	filter, args := getTaskFilter(f.TaskStatus, f.Tag)
+
+	taskIDFilter, taskIDFilterArgs := getTaskIDFilter(f.TaskID)
+	filter += taskIDFilter
+	args = append(args, taskIDFilterArgs...)
+
+	logTagFilter, logTagFilterArgs := getLogTagFilter(f.LogTag)
+	filter += logTagFilter
+	args = append(args, logTagFilterArgs...)
+
+	return filter, args
+}
+
+// getTaskFilter returns SQL conditions (each prefixed with AND) that restrict
+// rows joined as "tl" (task_log) and "t" (task) to the given task status and
+// tag, along with the arguments the conditions need.
+func getTaskFilter(taskStatus types.TaskStatus, tag string) (string, []any) {
+	var filter string
+	var args []any
+
+	switch taskStatus {
+	case types.TaskStatusActive:
+		filter += "AND t.active is true\n"
+	case types.TaskStatusInactive:
+		filter += "AND t.active is false\n"
+	case types.TaskStatusTracking:
+		filter += "AND tl.task_id IN (SELECT task_id FROM task_log WHERE active is true)\n"
+	}
+
+	if tag != "" {
+		filter += "AND tl.task_id IN (SELECT task_id FROM task_tag WHERE tag = ?)\n"
+		args = append(args, tag)
+	}
+
+	return filter, args
+}
+
+// getLogTagFilter returns an SQL condition (prefixed with AND) that restricts
+// rows joined as "tl" (task_log) to entries tagged with logTag, along with the
+// arguments the condition needs. An empty logTag doesn't restrict rows.
+func getLogTagFilter(logTag string) (string, []any) {
+	if logTag == "" {
+		return "", nil
+	}
+
+	return "AND instr(',' || tl.tags || ',', ',' || ? || ',') > 0\n", []any{logTag}
+}
+
+// getTaskIDFilter returns an SQL condition (prefixed with AND) that restricts
+// rows joined as "tl" (task_log) to entries for the task with taskID, along
+// with the arguments the condition needs. A nil taskID doesn't restrict rows.
+func getTaskIDFilter(taskID *int) (string, []any) {
+	if taskID == nil {
+		return "", nil
+	}
+
+	return "AND tl.task_id = ?\n", []any{*taskID}
+}
+
+// SetTLTags replaces the tags on a task log entry. Tags are stored as a comma
+// separated list; passing no tags clears them.
+func SetTLTags(db *sql.DB, tlID int, tags []string) error {
+	res, err := db.Exec(`
+UPDATE task_log
+SET tags = ?,
+    updated_at = ?
+WHERE id = ?;
+`, formatTLTags(tags), time.Now().UTC(), tlID)
+	if err != nil {
+		return err
+	}
+
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numRows == 0 {
+		return ErrTaskLogNotFound
+	}
+
+	return nil
+}
+
+// formatTLTags returns the value stored in a task log entry's tags column for
+// tags, or nil if there are none left after dropping empty and repeated ones.
+func formatTLTags(tags []string) *string {
+	formatted := types.FormatLogTags(types.ParseLogTags(strings.Join(tags, ",")))
+	if formatted == "" {
+		return nil
+	}
+
+	return &formatted
+}
+
+// AddTaskTag tags a task. Adding a tag the task already carries is a no-op.
+func AddTaskTag(db *sql.DB, taskID int, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ErrEmptyTaskTag
+	}
+	if strings.Contains(tag, ",") {
+		return ErrTagHasComma
+	}
+
+	return runInTx(db, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRow(`SELECT 1 FROM task WHERE id = ?`, taskID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+INSERT OR IGNORE INTO task_tag (task_id, tag)
+VALUES (?, ?);
+`, taskID, tag)
+		if err != nil {
+			return err
+		}
+
+		return touchTaskInTx(tx, taskID)
+	})
+}
+
+// RemoveTaskTag removes a tag from a task. Removing a tag the task doesn't
+// carry is a no-op.
+func RemoveTaskTag(db *sql.DB, taskID int, tag string) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+DELETE FROM task_tag
+WHERE task_id = ?
+AND tag = ?;
+`, taskID, strings.TrimSpace(tag))
+		if err != nil {
+			return err
+		}
+
+		return touchTaskInTx(tx, taskID)
+	})
+}
+
+// touchTaskInTx marks a task as updated, so that changes to its tags are
+// picked up by sync.
+func touchTaskInTx(tx *sql.Tx, taskID int) error {
+	_, err := tx.Exec(`
+UPDATE task
+SET updated_at = ?
+WHERE id = ?;
+`, time.Now().UTC(), taskID)
+	return err
+}
+
+// TagChangeResult holds the number of tasks and task log entries affected by
+// renaming or deleting a tag.
+type TagChangeResult struct {
+	NumTasks    int
+	NumTaskLogs int
+}
+
+// RenameTag renames tag to newTag on all tasks and task log entries carrying
+// it. Entities already carrying newTag simply lose tag.
+func RenameTag(db *sql.DB, tag, newTag string) (TagChangeResult, error) {
+	newTag = strings.TrimSpace(newTag)
+	if newTag == "" {
+		return TagChangeResult{}, ErrEmptyTaskTag
+	}
+	if strings.Contains(newTag, ",") {
+		return TagChangeResult{}, ErrTagHasComma
+	}
+
+	return changeTag(db, tag, newTag)
+}
+
+// DeleteTag removes tag from all tasks and task log entries carrying it.
+func DeleteTag(db *sql.DB, tag string) (TagChangeResult, error) {
+	return changeTag(db, tag, "")
+}
+
+// changeTag replaces tag with newTag everywhere it's used, or removes it if
+// newTag is empty.
+func changeTag(db *sql.DB, tag, newTag string) (TagChangeResult, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return TagChangeResult{}, ErrEmptyTaskTag
+	}
+
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (TagChangeResult, error) {
+		var result TagChangeResult
+
+		err := tx.QueryRow(`
+SELECT COUNT(*)
+FROM task_tag
+WHERE tag = ?;
+`, tag).Scan(&result.NumTasks)
+		if err != nil {
+			return result, err
+		}
+
+		now := time.Now().UTC()
+		_, err = tx.Exec(`
+UPDATE task
+SET updated_at = ?
+WHERE id IN (SELECT task_id FROM task_tag WHERE tag = ?);
+`, now, tag)
+		if err != nil {
+			return result, err
+		}
+
+		if newTag != "" {
+			_, err = tx.Exec(`
+UPDATE OR IGNORE task_tag
+SET tag = ?
+WHERE tag = ?;
+`, newTag, tag)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		// rows left over here either carried newTag already, or are being deleted
+		_, err = tx.Exec(`
+DELETE FROM task_tag
+WHERE tag = ?;
+`, tag)
+		if err != nil {
+			return result, err
+		}
+
+		rows, err := tx.Query(`
+SELECT id, tags
+FROM task_log
+WHERE instr(',' || tags || ',', ',' || ? || ',') > 0;
+`, tag)
+		if err != nil {
+			return result, err
+		}
+		defer rows.Close()
+
+		type tlTagsUpdate struct {
+			id   int
+			tags *string
+		}
+		var updates []tlTagsUpdate
+		for rows.Next() {
+			var tlID int
+			var tags string
+			if err := rows.Scan(&tlID, &tags); err != nil {
+				return result, err
+			}
+
+			var kept []string
+			for _, t := range types.ParseLogTags(tags) {
+				switch {
+				case t != tag:
+					kept = append(kept, t)
+				case newTag != "":
+					kept = append(kept, newTag)
+				}
+			}
+
+			var value *string
+			if formatted := types.FormatLogTags(types.ParseLogTags(strings.Join(kept, ","))); formatted != "" {
+				value = &formatted
+			}
+			updates = append(updates, tlTagsUpdate{tlID, value})
+		}
+		if err := rows.Err(); err != nil {
+			return result, err
+		}
 
-	err := row.Scan(
-		&t.SecsSpent,
-		&t.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
+		for _, update := range updates {
+			_, err := tx.Exec(`
+UPDATE task_log
+SET tags = ?,
+    updated_at = ?
+WHERE id = ?;
+`, update.tags, now, update.id)
+			if err != nil {
+				return result, err
+			}
+		}
+		result.NumTaskLogs = len(updates)
+
+		return result, nil
+	})
 }
 
-func FetchTasks(db *sql.DB, active bool, limit int) ([]types.Task, error) {
+// FetchTagsForTask returns the tags a task carries, in alphabetical order.
+func FetchTagsForTask(db *sql.DB, taskID int) ([]string, error) {
 	rows, err := db.Query(`
-SELECT id, summary, secs_spent, created_at, updated_at, active
-FROM task
-WHERE active=?
-ORDER by updated_at DESC
-LIMIT ?;
-    `, active, limit)
+SELECT tag
+FROM task_tag
+WHERE task_id = ?
+ORDER BY tag ASC;
+`, taskID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	return collectTasks(rows)
-}
-
-func FetchTLEntries(db *sql.DB, desc bool, limit int) ([]types.TaskLogEntry, error) {
-	var order string
-	if desc {
-		order = "DESC"
-	} else {
-		order = "ASC"
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
 	}
-	query := fmt.Sprintf(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
-FROM task_log tl left join task t on tl.task_id=t.id
-WHERE tl.active=false
-ORDER by tl.end_ts %s
-LIMIT ?;
-`, order)
-
-	rows, err := db.Query(query, limit)
-	if err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	return collectTaskLogEntries(rows)
+	return tags, nil
 }
 
-func FetchTLEntriesBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]types.TaskLogEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "AND t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "AND t.active is false"
-	}
+// FetchTodayTotal returns the number of seconds tracked in saved task log
+// entries that ended on the same day as now.
+func FetchTodayTotal(db *sql.DB, now time.Time) (int, error) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
 
-	rows, err := db.Query(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
-FROM task_log tl left join task t on tl.task_id=t.id
-WHERE tl.active=false
-AND tl.end_ts >= ?
-AND tl.end_ts < ?
-`+tsFilter+`
-ORDER by tl.begin_ts ASC LIMIT ?;
-    `, beginTs.UTC(), endTs.UTC(), limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	row := db.QueryRow(`
+SELECT COALESCE(SUM(secs_spent), 0)
+FROM task_log
+WHERE active=false
+AND end_ts >= ?
+AND end_ts < ?;
+`, dayStart.UTC(), dayEnd.UTC())
 
-	return collectTaskLogEntries(rows)
+	var secsSpent int
+	err := row.Scan(&secsSpent)
+
+	return secsSpent, err
 }
 
-func FetchStats(db *sql.DB, taskStatus types.TaskStatus, limit int) ([]types.TaskReportEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "WHERE t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "WHERE t.active is false"
-	}
+// GetWeeklyGoal returns the number of seconds the user aims to track in a
+// week. Zero means no goal is set.
+func GetWeeklyGoal(db *sql.DB) (int, error) {
+	row := db.QueryRow(`
+SELECT weekly_goal_secs
+FROM settings
+WHERE id = 1;
+`)
 
-	rows, err := db.Query(`
-SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries, t.secs_spent
-from task_log tl
-LEFT JOIN task t on tl.task_id = t.id
-`+tsFilter+`
-GROUP BY tl.task_id
-ORDER BY t.secs_spent DESC
-limit ?;
-`, limit)
-	if err != nil {
-		return nil, err
+	var goalSecs int
+	err := row.Scan(&goalSecs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
 	}
-	defer rows.Close()
 
-	return collectTaskReportEntries(rows)
+	return goalSecs, err
 }
 
-func FetchStatsBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]types.TaskReportEntry, error) {
-	var activeFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		activeFilter = " AND t.active is true"
-	case types.TaskStatusInactive:
-		activeFilter = " AND t.active is false"
+// SetWeeklyGoal saves the number of seconds the user aims to track in a week.
+// Setting it to zero clears the goal.
+func SetWeeklyGoal(db *sql.DB, goalSecs int) error {
+	if goalSecs < 0 {
+		return ErrNegativeWeeklyGoal
 	}
 
-	rows, err := db.Query(`
-SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
-FROM task_log tl 
-LEFT JOIN task t ON tl.task_id = t.id
-WHERE tl.end_ts >= ? AND tl.end_ts < ?`+activeFilter+`
-GROUP BY tl.task_id
-ORDER BY secs_spent DESC
-LIMIT ?;
-`, beginTs.UTC(), endTs.UTC(), limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	_, err := db.Exec(`
+INSERT INTO settings (id, weekly_goal_secs)
+VALUES (1, ?)
+ON CONFLICT(id) DO UPDATE SET weekly_goal_secs = excluded.weekly_goal_secs;
+`, goalSecs)
 
-	return collectTaskReportEntries(rows)
+	return err
 }
 
-func FetchReportBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]types.TaskReportEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "AND t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "AND t.active is false"
-	}
+// GetLastPeriod returns the period last used with command (eg. "report"). An
+// empty string means none has been saved yet.
+func GetLastPeriod(db *sql.DB, command string) (string, error) {
+	row := db.QueryRow(`
+SELECT period
+FROM last_period
+WHERE command = ?;
+`, command)
 
-	rows, err := db.Query(`
-SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
-FROM task_log tl 
-LEFT JOIN task t ON tl.task_id = t.id
-WHERE tl.end_ts >= ? AND tl.end_ts < ?
-`+tsFilter+`
-GROUP BY tl.task_id
-ORDER BY t.updated_at ASC
-LIMIT ?;
-`, beginTs.UTC(), endTs.UTC(), limit)
-	if err != nil {
-		return nil, err
+	var period string
+	err := row.Scan(&period)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
 	}
-	defer rows.Close()
 
-	return collectTaskReportEntries(rows)
+	return period, err
+}
+
+// SetLastPeriod saves period as the one last used with command, replacing
+// the one saved earlier, if any.
+func SetLastPeriod(db *sql.DB, command, period string) error {
+	_, err := db.Exec(`
+INSERT INTO last_period (command, period)
+VALUES (?, ?)
+ON CONFLICT(command) DO UPDATE SET period = excluded.period;
+`, command, period)
+
+	return err
 }
 
 func DeleteTL(db *sql.DB, entry *types.TaskLogEntry) error {
@@ -649,88 +2173,241 @@ WHERE ID=?;
 	})
 }
 
-func MoveTaskLog(db *sql.DB, tlID int, oldTaskID int, newTaskID int, secsSpent int) error {
-	if oldTaskID == newTaskID {
-		return nil
+// DeleteTLsBetweenTS deletes the saved task log entries that ended between
+// beginTs and endTs (optionally only for the task with ID taskID), and takes
+// the time spent on them off their tasks. It returns the number of entries
+// deleted.
+func DeleteTLsBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskID *int) (int, error) {
+	filter := ""
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	if taskID != nil {
+		filter = "AND task_id = ?\n"
+		args = append(args, *taskID)
 	}
 
-	return runInTx(db, func(tx *sql.Tx) error {
-		// Update the task_log entry's task_id
-		now := time.Now().UTC()
-		updateTLStmt, err := tx.Prepare(`
-UPDATE task_log
-	SET task_id = ?,
-	    updated_at = ?
-WHERE id = ? AND task_id = ?;
-`)
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (int, error) {
+		rows, err := tx.Query(`
+SELECT task_id, SUM(secs_spent)
+FROM task_log
+WHERE active = false
+AND end_ts >= ?
+AND end_ts < ?
+`+filter+`GROUP BY task_id;
+`, args...)
 		if err != nil {
-			return err
+			return 0, err
 		}
-		defer updateTLStmt.Close()
 
-		result, err := updateTLStmt.Exec(newTaskID, now, tlID, oldTaskID)
-		if err != nil {
-			return err
+		secsSpentPerTask := make(map[int]int)
+		for rows.Next() {
+			var id, secsSpent int
+			if err := rows.Scan(&id, &secsSpent); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			secsSpentPerTask[id] = secsSpent
 		}
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			return err
+		if err := rows.Close(); err != nil {
+			return 0, err
 		}
-		if rowsAffected == 0 {
-			return ErrTaskLogNotFound
+		if err := rows.Err(); err != nil {
+			return 0, err
 		}
 
-		// Decrease secs_spent on old task (atomic conditional update)
-		oldTaskResult, err := tx.Exec(`
+		now := time.Now().UTC()
+		for id, secsSpent := range secsSpentPerTask {
+			res, err := tx.Exec(`
 UPDATE task
 SET secs_spent = secs_spent - ?,
     updated_at = ?
 WHERE id = ? AND secs_spent >= ?;
-	`, secsSpent, now, oldTaskID, secsSpent)
+`, secsSpent, now, id, secsSpent)
+			if err != nil {
+				return 0, err
+			}
+
+			numRows, err := res.RowsAffected()
+			if err != nil {
+				return 0, err
+			}
+			if numRows == 0 {
+				return 0, fmt.Errorf("%w (task ID: %d)", ErrNegativeSecsSpent, id)
+			}
+		}
+
+		res, err := tx.Exec(`
+DELETE FROM task_log
+WHERE active = false
+AND end_ts >= ?
+AND end_ts < ?
+`+filter+`;`, args...)
 		if err != nil {
-			return err
+			return 0, err
 		}
-		oldTaskRowsAffected, err := oldTaskResult.RowsAffected()
+
+		numDeleted, err := res.RowsAffected()
 		if err != nil {
-			return err
+			return 0, err
 		}
-		if oldTaskRowsAffected == 0 {
-			// Check if row exists to determine the error
-			var exists int
-			err = tx.QueryRow(`SELECT 1 FROM task WHERE id = ?`, oldTaskID).Scan(&exists)
-			if errors.Is(err, sql.ErrNoRows) {
-				return ErrTaskNotFound
-			}
-			return ErrNegativeSecsSpent
+
+		return int(numDeleted), nil
+	})
+}
+
+// DeleteAllTLsForTask deletes every saved task log entry for the task with ID
+// taskID, and sets the task's secs_spent to what's left on its remaining
+// entries. An active task log for the task is left untouched. It returns the
+// number of entries deleted.
+func DeleteAllTLsForTask(db *sql.DB, taskID int) (int, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (int, error) {
+		var exists int
+		err := tx.QueryRow(`SELECT 1 FROM task WHERE id = ?`, taskID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrTaskNotFound
+		}
+		if err != nil {
+			return 0, err
 		}
 
-		// Increase secs_spent on new task
-		newTaskStmt, err := tx.Prepare(`
-UPDATE task
-SET secs_spent = secs_spent + ?,
-    updated_at = ?
-WHERE id = ?;
-`)
+		res, err := tx.Exec(`
+DELETE FROM task_log
+WHERE task_id = ?
+AND active = false;
+`, taskID)
 		if err != nil {
-			return err
+			return 0, err
 		}
-		defer newTaskStmt.Close()
 
-		res, err := newTaskStmt.Exec(secsSpent, now, newTaskID)
+		numDeleted, err := res.RowsAffected()
 		if err != nil {
-			return err
+			return 0, err
 		}
-		newTaskRowsAffected, err := res.RowsAffected()
+
+		_, err = tx.Exec(`
+UPDATE task
+SET secs_spent = (
+    SELECT COALESCE(SUM(tl.secs_spent), 0)
+    FROM task_log tl
+    WHERE tl.task_id = task.id
+    AND tl.active = false
+),
+    updated_at = ?
+WHERE id = ?;
+`, time.Now().UTC(), taskID)
 		if err != nil {
-			return err
+			return 0, err
 		}
-		if newTaskRowsAffected == 0 {
-			return ErrTaskNotFound
+
+		return int(numDeleted), nil
+	})
+}
+
+func MoveTaskLog(db *sql.DB, tlID int, oldTaskID int, newTaskID int, secsSpent int) error {
+	if oldTaskID == newTaskID {
+		return nil
+	}
+
+	return runInTx(db, func(tx *sql.Tx) error {
+		return moveTaskLogInTx(tx, tlID, oldTaskID, newTaskID, secsSpent, time.Now().UTC())
+	})
+}
+
+// MoveTaskLogs moves several saved task log entries to the task with ID
+// newTaskID in a single transaction. If any of them can't be moved, none are.
+func MoveTaskLogs(db *sql.DB, entries []types.TaskLogEntry, newTaskID int) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		for _, entry := range entries {
+			if entry.TaskID == newTaskID {
+				continue
+			}
+
+			if err := moveTaskLogInTx(tx, entry.ID, entry.TaskID, newTaskID, entry.SecsSpent, now); err != nil {
+				return fmt.Errorf("%w (ID: %d)", err, entry.ID)
+			}
 		}
+
 		return nil
 	})
 }
 
+// moveTaskLogInTx moves a saved task log entry to another task, and moves the
+// time spent on it along with it.
+func moveTaskLogInTx(tx *sql.Tx, tlID int, oldTaskID int, newTaskID int, secsSpent int, now time.Time) error {
+	// Update the task_log entry's task_id
+	updateTLStmt, err := tx.Prepare(`
+UPDATE task_log
+	SET task_id = ?,
+	    updated_at = ?
+WHERE id = ? AND task_id = ?;
+`)
+	if err != nil {
+		return err
+	}
+	defer updateTLStmt.Close()
+
+	result, err := updateTLStmt.Exec(newTaskID, now, tlID, oldTaskID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTaskLogNotFound
+	}
+
+	// Decrease secs_spent on old task (atomic conditional update)
+	oldTaskResult, err := tx.Exec(`
+UPDATE task
+SET secs_spent = secs_spent - ?,
+    updated_at = ?
+WHERE id = ? AND secs_spent >= ?;
+	`, secsSpent, now, oldTaskID, secsSpent)
+	if err != nil {
+		return err
+	}
+	oldTaskRowsAffected, err := oldTaskResult.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if oldTaskRowsAffected == 0 {
+		// Check if row exists to determine the error
+		var exists int
+		err = tx.QueryRow(`SELECT 1 FROM task WHERE id = ?`, oldTaskID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		return ErrNegativeSecsSpent
+	}
+
+	// Increase secs_spent on new task
+	newTaskStmt, err := tx.Prepare(`
+UPDATE task
+SET secs_spent = secs_spent + ?,
+    updated_at = ?
+WHERE id = ?;
+`)
+	if err != nil {
+		return err
+	}
+	defer newTaskStmt.Close()
+
+	res, err := newTaskStmt.Exec(secsSpent, now, newTaskID)
+	if err != nil {
+		return err
+	}
+	newTaskRowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if newTaskRowsAffected == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
 func runInTxAndReturnID(db *sql.DB, fn func(tx *sql.Tx) (int, error)) (int, error) {
 	tx, err := db.Begin()
 	if err != nil {
@@ -792,7 +2469,7 @@ func runInTxAndReturnA[A any](db *sql.DB, fn func(tx *sql.Tx) (A, error)) (A, er
 func fetchTaskByID(db *sql.DB, id int) (types.Task, error) {
 	var task types.Task
 	row := db.QueryRow(`
-SELECT id, summary, secs_spent, active, created_at, updated_at
+SELECT id, summary, secs_spent, active, created_at, updated_at, color
 FROM task
 WHERE id=?;
     `, id)
@@ -806,6 +2483,7 @@ WHERE id=?;
 		&task.Active,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.Color,
 	)
 	if err != nil {
 		return task, err
@@ -818,10 +2496,12 @@ WHERE id=?;
 
 func fetchTLByID(db *sql.DB, id int) (types.TaskLogEntry, error) {
 	var tl types.TaskLogEntry
+	var tags *string
 	row := db.QueryRow(`
-SELECT id, task_id, begin_ts, end_ts, secs_spent, comment
+SELECT id, task_id, begin_ts, end_ts, secs_spent, comment, tags, category
 FROM task_log
-WHERE id=?;
+WHERE id=?
+AND active=false;
     `, id)
 
 	if row.Err() != nil {
@@ -833,10 +2513,15 @@ WHERE id=?;
 		&tl.EndTS,
 		&tl.SecsSpent,
 		&tl.Comment,
+		&tags,
+		&tl.Category,
 	)
 	if err != nil {
 		return tl, err
 	}
+	if tags != nil {
+		tl.Tags = types.ParseLogTags(*tags)
+	}
 	tl.BeginTS = tl.BeginTS.Local()
 	tl.EndTS = tl.EndTS.Local()
 
@@ -867,7 +2552,11 @@ WHERE id=?;
 	return tl, nil
 }
 
-func ArchiveStaleTasks(db *sql.DB, since time.Time) (int, error) {
+// ArchiveStaleTasks marks active tasks with no log entries since the given
+// time as inactive, and returns how many were archived. If
+// skipRecentlyCreated is set, tasks created after since are left alone, even
+// if they have no log entries at all.
+func ArchiveStaleTasks(db *sql.DB, since time.Time, skipRecentlyCreated bool) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
 		// Find active tasks with no log entries since the given time
 		// This includes tasks with no log entries at all, or whose latest log entry is older than "since"
@@ -877,6 +2566,7 @@ UPDATE task
 SET active = false,
     updated_at = ?
 WHERE active = true
+AND (? = false OR task.created_at < ?)
 AND NOT EXISTS (
     SELECT 1
     FROM task_log
@@ -889,7 +2579,7 @@ AND NOT EXISTS (
 		}
 		defer stmt.Close()
 
-		res, err := stmt.Exec(time.Now().UTC(), since.UTC())
+		res, err := stmt.Exec(time.Now().UTC(), skipRecentlyCreated, since.UTC(), since.UTC())
 		if err != nil {
 			return 0, err
 		}
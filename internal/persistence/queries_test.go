@@ -3,6 +3,7 @@ package persistence
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -53,6 +54,29 @@ func TestRepository(t *testing.T) {
 		assert.Zero(t, task.SecsSpent)
 	})
 
+	t.Run("TestInsertNewTLWithComment", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		beginTS := time.Now().Add(-time.Hour).Truncate(time.Second)
+		comment := testComment
+
+		// WHEN
+		tlID, err := InsertNewTLWithComment(testDB, taskID, beginTS, &comment)
+
+		// THEN
+		require.NoError(t, err, "failed to insert task log")
+		activeTaskDetails, err := FetchActiveTaskDetails(testDB)
+		require.NoError(t, err, "failed to fetch active task details")
+		assert.Equal(t, tlID, activeTaskDetails.CurrentLogID)
+		assert.True(t, beginTS.Equal(activeTaskDetails.CurrentLogBeginTS))
+		require.NotNil(t, activeTaskDetails.CurrentLogComment)
+		assert.Equal(t, comment, *activeTaskDetails.CurrentLogComment)
+	})
+
 	t.Run("EditActiveTL", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -64,7 +88,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		_, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		// WHEN
@@ -81,6 +105,7 @@ func TestRepository(t *testing.T) {
 
 		// THEN
 		assert.Equal(t, taskID, activeTaskDetails.TaskID)
+		assert.Equal(t, tlID, activeTaskDetails.CurrentLogID)
 		assert.True(t, updatedBeginTS.Equal(activeTaskDetails.CurrentLogBeginTS))
 		require.NotNil(t, activeTaskDetails.CurrentLogComment)
 		assert.Equal(t, comment, *activeTaskDetails.CurrentLogComment)
@@ -110,7 +135,7 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		comment := testComment
-		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, &comment)
+		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, &comment, nil, nil)
 
 		// THEN
 		require.NoError(t, err, "failed to update task log")
@@ -127,6 +152,220 @@ func TestRepository(t *testing.T) {
 		assert.Equal(t, numSecondsBefore+numSeconds, taskAfter.SecsSpent)
 	})
 
+	t.Run("TestFinishActiveTL saves category", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		endTS := time.Now()
+		beginTS := endTS.Add(-time.Hour)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		category := "meeting"
+		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, secsInOneHour, nil, &category, nil)
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		require.NotNil(t, taskLog.Category)
+		assert.Equal(t, category, *taskLog.Category)
+	})
+
+	t.Run("TestFinishActiveTL saves tags", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		endTS := time.Now()
+		beginTS := endTS.Add(-time.Hour)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, secsInOneHour, nil, nil, []string{"meeting", " review", "meeting"})
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, []string{"meeting", "review"}, taskLog.Tags)
+	})
+
+	t.Run("TestFinishActiveTLWithOptions rounds up at the halfway boundary", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		beginTS := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		endTS := beginTS.Add(22*time.Minute + 30*time.Second)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, insertErr, "failed to insert task log")
+
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		// WHEN
+		finished, err := FinishActiveTLWithOptions(testDB, tlID, taskID, beginTS, endTS, nil, nil, nil, FinishTLOptions{RoundTo: 15 * time.Minute})
+
+		// THEN
+		require.NoError(t, err, "failed to update task log")
+		assert.Equal(t, FinishedTL{tlID, 30 * 60}, finished)
+
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.Equal(t, 30*60, taskLog.SecsSpent)
+		assert.True(t, beginTS.Add(30*time.Minute).Equal(taskLog.EndTS))
+		assert.Equal(t, taskBefore.SecsSpent+30*60, taskAfter.SecsSpent)
+	})
+
+	t.Run("TestFinishActiveTLWithOptions doesn't round the end past the current time", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		endTS := time.Now().Truncate(time.Second)
+		beginTS := endTS.Add(-2 * time.Minute)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		finished, err := FinishActiveTLWithOptions(testDB, tlID, taskID, beginTS, endTS, nil, nil, nil, FinishTLOptions{RoundTo: 15 * time.Minute})
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+		assert.Equal(t, 2*60, finished.SecsSpent)
+
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.True(t, endTS.Equal(taskLog.EndTS))
+	})
+
+	t.Run("TestFinishActiveTLWithOptions merges into the task's earlier entry for the day", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		day := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		firstComment := "first"
+		firstTLID, err := InsertManualTL(testDB, taskID, day.Add(9*time.Hour), day.Add(10*time.Hour), &firstComment, nil, []string{"deep"}, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		beginTS := day.Add(14 * time.Hour)
+		endTS := beginTS.Add(90 * time.Minute)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		// WHEN
+		secondComment := "second"
+		finished, err := FinishActiveTLWithOptions(testDB, tlID, taskID, beginTS, endTS, &secondComment, nil, []string{"review", "deep"}, FinishTLOptions{MergeSameDay: true})
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+		// the merged entry spans 09:00 to 15:30, but the gap in between doesn't
+		// count as time spent
+		assert.Equal(t, FinishedTL{firstTLID, 90 * 60}, finished)
+
+		entries, _, err := FetchTLEntriesBetweenTS(testDB, day, day.AddDate(0, 0, 1), TLFilter{TaskStatus: types.TaskStatusAny}, 100)
+		require.NoError(t, err, "failed to fetch task log entries")
+		require.Len(t, entries, 1)
+		assert.Equal(t, firstTLID, entries[0].ID)
+		assert.Equal(t, 2*60*60+30*60, entries[0].SecsSpent)
+		assert.True(t, entries[0].BeginTS.Equal(day.Add(9*time.Hour)))
+		assert.True(t, entries[0].EndTS.Equal(endTS))
+		require.NotNil(t, entries[0].Comment)
+		assert.Equal(t, "first\nsecond", *entries[0].Comment)
+		assert.Equal(t, []string{"deep", "review"}, entries[0].Tags)
+
+		_, err = fetchTLByID(testDB, tlID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, taskBefore.SecsSpent+90*60, taskAfter.SecsSpent)
+	})
+
+	t.Run("TestFinishActiveTLWithOptions rounds the merged entry's range", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		day := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		firstTLID, err := InsertManualTL(testDB, taskID, day.Add(9*time.Hour), day.Add(10*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		beginTS := day.Add(10*time.Hour + 30*time.Minute)
+		endTS := beginTS.Add(20 * time.Minute)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		finished, err := FinishActiveTLWithOptions(testDB, tlID, taskID, beginTS, endTS, nil, nil, nil, FinishTLOptions{MergeSameDay: true, RoundTo: 15 * time.Minute})
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+		assert.Equal(t, FinishedTL{firstTLID, 15 * 60}, finished)
+
+		taskLog, err := fetchTLByID(testDB, firstTLID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, 5*15*60, taskLog.SecsSpent)
+		assert.True(t, day.Add(10*time.Hour+45*time.Minute).Equal(taskLog.EndTS))
+	})
+
+	t.Run("TestFinishActiveTLWithOptions doesn't merge entries from other days", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		day := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		_, err := InsertManualTL(testDB, taskID, day.Add(-2*time.Hour), day.Add(-time.Hour), nil, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		beginTS := day.Add(14 * time.Hour)
+		endTS := beginTS.Add(90 * time.Minute)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		finished, err := FinishActiveTLWithOptions(testDB, tlID, taskID, beginTS, endTS, nil, nil, nil, FinishTLOptions{MergeSameDay: true})
+
+		// THEN
+		require.NoError(t, err, "failed to finish task log")
+		assert.Equal(t, FinishedTL{tlID, 90 * 60}, finished)
+
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, 90*60, taskLog.SecsSpent)
+	})
+
 	t.Run("TestFinishActiveTL can save TL with empty comment", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -142,7 +381,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		// WHEN
-		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, nil)
+		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, nil, nil, nil)
 
 		// THEN
 		require.NoError(t, err, "failed to update task log")
@@ -173,7 +412,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to fetch task")
 
 		// WHEN
-		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now)
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now, FinishTLOptions{})
 
 		// THEN
 		require.NoError(t, err, "failed to quick switch active task")
@@ -221,7 +460,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to update active task log")
 
 		// WHEN
-		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now)
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now, FinishTLOptions{})
 
 		// THEN
 		require.NoError(t, err, "failed to quick switch active task")
@@ -246,6 +485,72 @@ func TestRepository(t *testing.T) {
 		require.Nil(t, activeTL.Comment)
 	})
 
+	t.Run("TestQuickSwitchActiveTL merges into the task's earlier entry for the day", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		secondTaskID := 2
+		day := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		firstTLID, err := InsertManualTL(testDB, taskID, day.Add(9*time.Hour), day.Add(10*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		beginTS := day.Add(11 * time.Hour)
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+		comment := testComment
+		require.NoError(t, EditActiveTL(testDB, beginTS, &comment))
+
+		switchTS := day.Add(12 * time.Hour)
+
+		// WHEN
+		_, err = QuickSwitchActiveTL(testDB, secondTaskID, switchTS, FinishTLOptions{MergeSameDay: true})
+
+		// THEN
+		require.NoError(t, err, "failed to quick switch active task")
+
+		_, err = fetchTLByID(testDB, tlID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		mergedTL, err := fetchTLByID(testDB, firstTLID)
+		require.NoError(t, err, "failed to fetch merged task log")
+		assert.Equal(t, 2*60*60, mergedTL.SecsSpent)
+		assert.True(t, switchTS.Equal(mergedTL.EndTS))
+		require.NotNil(t, mergedTL.Comment)
+		assert.Equal(t, comment, *mergedTL.Comment)
+	})
+
+	t.Run("TestQuickSwitchActiveTL rounds the finished task log", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedDB(t, testDB, getTestData(referenceTS))
+		taskID := 1
+		secondTaskID := 2
+		switchTS := time.Now().Add(-time.Hour).Truncate(time.Second)
+		beginTS := switchTS.Add(-(22*time.Minute + 30*time.Second))
+		tlID, err := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, switchTS, FinishTLOptions{RoundTo: 15 * time.Minute})
+
+		// THEN
+		require.NoError(t, err, "failed to quick switch active task")
+
+		finishedTL, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch last active task log")
+		assert.Equal(t, 30*60, finishedTL.SecsSpent)
+		assert.True(t, beginTS.Add(30*time.Minute).Equal(finishedTL.EndTS))
+
+		activeTL, err := fetchActiveTLByID(testDB, result.CurrentlyActiveTLID)
+		require.NoError(t, err, "failed to fetch active task log")
+		assert.True(t, switchTS.Equal(activeTL.BeginTS))
+	})
+
 	t.Run("TestQuickSwitchActiveTL returns error if no task is active", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -253,7 +558,7 @@ func TestRepository(t *testing.T) {
 		now := time.Now().Truncate(time.Second)
 
 		// WHEN
-		_, err := QuickSwitchActiveTL(testDB, 1, now)
+		_, err := QuickSwitchActiveTL(testDB, 1, now, FinishTLOptions{})
 
 		// THEN
 		require.ErrorIs(t, ErrNoTaskActive, err)
@@ -277,7 +582,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, nil, nil, false)
 
 		// THEN
 		require.NoError(t, err, "failed to insert task log")
@@ -294,7 +599,7 @@ func TestRepository(t *testing.T) {
 		assert.Equal(t, numSecondsBefore+numSeconds, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestInsertManualTL can insert TL with empty comment", func(t *testing.T) {
+	t.Run("TestInsertManualTL saves category", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
@@ -302,50 +607,56 @@ func TestRepository(t *testing.T) {
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 		taskID := 1
+		endTS := time.Now()
+		beginTS := endTS.Add(-time.Hour)
 
 		// WHEN
-		numSeconds := 60 * 90
-		endTS := time.Now()
-		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil)
+		category := "coding"
+		withCategoryID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil, &category, nil, true)
+		require.NoError(t, err, "failed to insert task log")
+		withoutCategoryID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil, nil, nil, true)
+		require.NoError(t, err, "failed to insert task log")
 
 		// THEN
-		require.NoError(t, err, "failed to insert task log")
+		withCategory, err := fetchTLByID(testDB, withCategoryID)
+		require.NoError(t, err, "failed to fetch task log")
+		require.NotNil(t, withCategory.Category)
+		assert.Equal(t, category, *withCategory.Category)
 
-		taskLog, err := fetchTLByID(testDB, tlID)
+		withoutCategory, err := fetchTLByID(testDB, withoutCategoryID)
 		require.NoError(t, err, "failed to fetch task log")
+		assert.Nil(t, withoutCategory.Category)
 
-		assert.Equal(t, numSeconds, taskLog.SecsSpent)
-		assert.Nil(t, taskLog.Comment)
+		entries, _, err := FetchTLEntriesBetweenTS(testDB, beginTS.Add(-time.Minute), endTS.Add(time.Minute), TLFilter{TaskStatus: types.TaskStatusAny}, 10)
+		require.NoError(t, err)
+		categories := make(map[int]*string)
+		for _, entry := range entries {
+			categories[entry.ID] = entry.Category
+		}
+		require.NotNil(t, categories[withCategoryID])
+		assert.Equal(t, category, *categories[withCategoryID])
+		assert.Nil(t, categories[withoutCategoryID])
 	})
 
-	t.Run("TestEditSavedTL works when new time spent is larger than the previous one", func(t *testing.T) {
+	t.Run("TestInsertManualTLRounded rounds down just below the halfway boundary", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now().Truncate(time.Second)
+		referenceTS := time.Now()
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 		taskID := 1
 
-		comment := testComment
-		numSeconds := 60 * 90
-		endTS := time.Now().Truncate(time.Second)
-		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
 		taskBefore, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task after tl insert")
+		require.NoError(t, err, "failed to fetch task")
 
 		// WHEN
-		numSecondsDelta := 60
-		updatedComment := testCommentUpdated
-		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta*2))
-		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment)
+		beginTS := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		endTS := beginTS.Add(22*time.Minute + 29*time.Second)
+		tlID, err := InsertManualTLRounded(testDB, taskID, beginTS, endTS, nil, nil, nil, false, 15*time.Minute)
 
 		// THEN
-		require.NoError(t, err, "failed to edit saved task log")
+		require.NoError(t, err, "failed to insert task log")
 
 		taskLog, err := fetchTLByID(testDB, tlID)
 		require.NoError(t, err, "failed to fetch task log")
@@ -353,40 +664,30 @@ func TestRepository(t *testing.T) {
 		taskAfter, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task")
 
-		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
-		assert.True(t, newEndTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", newEndTS, taskLog.EndTS)
-		assert.Equal(t, numSeconds+numSecondsDelta, taskLog.SecsSpent)
-		require.NotNil(t, taskLog.Comment)
-		assert.Equal(t, updatedComment, *taskLog.Comment)
-		assert.Equal(t, taskBefore.SecsSpent+numSecondsDelta, taskAfter.SecsSpent)
+		assert.Equal(t, 15*60, taskLog.SecsSpent)
+		assert.True(t, beginTS.Add(15*time.Minute).Equal(taskLog.EndTS))
+		assert.Equal(t, taskBefore.SecsSpent+15*60, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestEditSavedTL works when new time spent is smaller than the previous one", func(t *testing.T) {
+	t.Run("TestInsertManualTLRounded rounds up at the halfway boundary", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now().Truncate(time.Second)
+		referenceTS := time.Now()
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 		taskID := 1
 
-		comment := testComment
-		numSeconds := 60 * 90
-		endTS := time.Now().Truncate(time.Second)
-		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
 		taskBefore, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task after tl insert")
+		require.NoError(t, err, "failed to fetch task")
 
 		// WHEN
-		numSecondsDelta := 60
-		updatedComment := testCommentUpdated
-		newBeginTS := beginTS.Add(time.Second * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, endTS, &updatedComment)
+		beginTS := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		endTS := beginTS.Add(7*time.Minute + 30*time.Second)
+		tlID, err := InsertManualTLRounded(testDB, taskID, beginTS, endTS, nil, nil, nil, false, 15*time.Minute)
 
 		// THEN
-		require.NoError(t, err, "failed to edit saved task log")
+		require.NoError(t, err, "failed to insert task log")
 
 		taskLog, err := fetchTLByID(testDB, tlID)
 		require.NoError(t, err, "failed to fetch task log")
@@ -394,355 +695,632 @@ func TestRepository(t *testing.T) {
 		taskAfter, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task")
 
-		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
-		assert.True(t, endTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", endTS, taskLog.EndTS)
-		assert.Equal(t, numSeconds-numSecondsDelta, taskLog.SecsSpent)
-		require.NotNil(t, taskLog.Comment)
-		assert.Equal(t, updatedComment, *taskLog.Comment)
-		assert.Equal(t, taskBefore.SecsSpent-numSecondsDelta, taskAfter.SecsSpent)
+		assert.Equal(t, 15*60, taskLog.SecsSpent)
+		assert.True(t, beginTS.Add(15*time.Minute).Equal(taskLog.EndTS))
+		assert.Equal(t, taskBefore.SecsSpent+15*60, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestEditSavedTL works when time spent is unchanged", func(t *testing.T) {
+	t.Run("TestInsertManualTL can insert TL with empty comment", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now().Truncate(time.Second)
+		referenceTS := time.Now()
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 		taskID := 1
 
-		comment := testComment
+		// WHEN
 		numSeconds := 60 * 90
-		endTS := time.Now().Truncate(time.Second)
+		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
-		taskBefore, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task after tl insert")
-
-		// WHEN
-		numSecondsDelta := 60
-		updatedComment := testCommentUpdated
-		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
-		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to edit saved task log")
+		require.NoError(t, err, "failed to insert task log")
 
 		taskLog, err := fetchTLByID(testDB, tlID)
 		require.NoError(t, err, "failed to fetch task log")
 
-		taskAfter, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task")
-
-		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
-		assert.True(t, newEndTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", newEndTS, taskLog.EndTS)
 		assert.Equal(t, numSeconds, taskLog.SecsSpent)
-		require.NotNil(t, taskLog.Comment)
-		assert.Equal(t, updatedComment, *taskLog.Comment)
-		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
+		assert.Nil(t, taskLog.Comment)
 	})
 
-	t.Run("TestDeleteTaskLogEntry", func(t *testing.T) {
+	t.Run("TestInsertManualTL rejects entries overlapping with existing ones", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 		taskID := 1
-		tlID := 1
+		existing := seedData.taskLogs[0]
+
 		taskBefore, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task")
-		numSecondsBefore := taskBefore.SecsSpent
-		taskLog, err := fetchTLByID(testDB, tlID)
-		require.NoError(t, err, "failed to fetch task log")
 
 		// WHEN
-		err = DeleteTL(testDB, &taskLog)
+		beginTS := existing.BeginTS.Add(time.Hour)
+		endTS := existing.EndTS.Add(time.Hour)
+		_, err = InsertManualTL(testDB, taskID, beginTS, endTS, nil, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to insert task log")
+		require.ErrorIs(t, err, ErrTaskLogOverlaps)
 
 		taskAfter, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task")
-
-		assert.Equal(t, numSecondsBefore-taskLog.SecsSpent, taskAfter.SecsSpent)
+		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestFetchTLEntriesBetweenTS for all tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTL allows entries adjacent to existing ones", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
 		taskID := 1
-		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
+		first := seedData.taskLogs[0]
+		second := seedData.taskLogs[1]
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		_, errBetween := InsertManualTL(testDB, taskID, first.EndTS, second.BeginTS, nil, nil, nil, false)
+		_, errBefore := InsertManualTL(testDB, taskID, first.BeginTS.Add(-time.Hour), first.BeginTS, nil, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 3)
+		assert.NoError(t, errBetween)
+		assert.NoError(t, errBefore)
 	})
 
-	t.Run("TestFetchTLEntriesBetweenTS for active tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTL ignores entries for other tasks", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
-		err = UpdateTaskActiveStatus(testDB, 1, false)
-		require.NoError(t, err, "failed to make task inactive")
+		otherTaskTL := seedData.taskLogs[2]
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+		_, err := InsertManualTL(testDB, 1, otherTaskTL.EndTS.Add(-time.Hour), otherTaskTL.EndTS, nil, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		assert.NoError(t, err)
 	})
 
-	t.Run("TestFetchTLEntriesBetweenTS for inactive tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTL allows overlapping entries when asked to", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
-		err = UpdateTaskActiveStatus(testDB, 2, false)
-		require.NoError(t, err, "failed to make task inactive")
+		existing := seedData.taskLogs[0]
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		_, err := InsertManualTL(testDB, 1, existing.BeginTS, existing.EndTS, nil, nil, nil, true)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		assert.NoError(t, err)
 	})
 
-	t.Run("TestFetchStats for all tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTLs inserts many entries in one transaction", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now()
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 
-		taskID := 1
-		comment := "an extra task log"
-		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
+		taskOneBefore, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task")
+		taskTwoBefore, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err, "failed to fetch task")
+
+		numEntries := 200
+		numSeconds := 60 * 15
+		entries := make([]types.ManualTaskLog, 0, numEntries)
+		for i := range numEntries {
+			endTS := referenceTS.Add(time.Duration(-i) * time.Hour)
+			entries = append(entries, types.ManualTaskLog{
+				TaskID:  i%2 + 1,
+				BeginTS: endTS.Add(time.Second * -1 * time.Duration(numSeconds)),
+				EndTS:   endTS,
+			})
+		}
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusAny, 100)
+		ids, err := InsertManualTLs(testDB, entries, true)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 2)
+		require.NoError(t, err, "failed to insert task logs")
+		assert.Len(t, ids, numEntries)
 
-		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 3, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour+numSeconds, entries[0].SecsSpent)
+		taskLog, err := fetchTLByID(testDB, ids[numEntries-1])
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, numSeconds, taskLog.SecsSpent)
 
-		assert.Equal(t, 2, entries[1].TaskID)
-		assert.Equal(t, 1, entries[1].NumEntries)
-		assert.Equal(t, 4*secsInOneHour, entries[1].SecsSpent)
+		taskOneAfter, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task")
+		taskTwoAfter, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.Equal(t, taskOneBefore.SecsSpent+numEntries/2*numSeconds, taskOneAfter.SecsSpent)
+		assert.Equal(t, taskTwoBefore.SecsSpent+numEntries/2*numSeconds, taskTwoAfter.SecsSpent)
 	})
 
-	t.Run("TestFetchStats for active tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTLs saves nothing if a task doesn't exist", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now()
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
 
-		err = UpdateTaskActiveStatus(testDB, 1, false)
-		require.NoError(t, err, "failed to make task inactive")
+		taskBefore, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task")
+
+		entries := []types.ManualTaskLog{
+			{TaskID: 1, BeginTS: referenceTS.Add(-time.Hour), EndTS: referenceTS},
+			{TaskID: 99, BeginTS: referenceTS.Add(-time.Hour), EndTS: referenceTS},
+		}
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusActive, 100)
+		_, err = InsertManualTLs(testDB, entries, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		assert.ErrorIs(t, err, ErrTaskNotFound)
 
-		assert.Equal(t, 2, entries[0].TaskID)
-		assert.Equal(t, 1, entries[0].NumEntries)
-		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+		taskAfter, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
+
+		var numTLs int
+		err = testDB.QueryRow("SELECT COUNT(*) FROM task_log").Scan(&numTLs)
+		require.NoError(t, err, "failed to count task logs")
+		assert.Equal(t, len(seedData.taskLogs), numTLs)
 	})
 
-	t.Run("TestFetchStats for inactive tasks", func(t *testing.T) {
+	t.Run("TestInsertManualTLs rejects overlapping entries unless allowed", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
 		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-
-		err = UpdateTaskActiveStatus(testDB, 2, false)
-		require.NoError(t, err, "failed to make task inactive")
+		seedDB(t, testDB, getTestData(referenceTS))
+		day := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		entries := []types.ManualTaskLog{
+			{TaskID: 1, BeginTS: day.Add(9 * time.Hour), EndTS: day.Add(10 * time.Hour)},
+			{TaskID: 1, BeginTS: day.Add(9*time.Hour + 30*time.Minute), EndTS: day.Add(11 * time.Hour)},
+		}
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusInactive, 100)
+		_, err := InsertManualTLs(testDB, entries, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		require.ErrorIs(t, err, ErrTaskLogOverlaps)
+		assert.ErrorContains(t, err, "entry 2")
+		tlEntries, _, err := FetchTLEntriesBetweenTS(testDB, day, day.AddDate(0, 0, 1), TLFilter{TaskStatus: types.TaskStatusAny}, 10)
+		require.NoError(t, err)
+		assert.Empty(t, tlEntries)
 
-		assert.Equal(t, 2, entries[0].TaskID)
-		assert.Equal(t, 1, entries[0].NumEntries)
-		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+		// WHEN
+		ids, err := InsertManualTLs(testDB, entries, true)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, ids, 2)
 	})
 
-	t.Run("TestFetchStatsBetweenTS for all tasks", func(t *testing.T) {
+	t.Run("TestEditSavedTL works when new time spent is larger than the previous one", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
 		taskID := 1
+
+		comment := testComment
 		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		endTS := time.Now().Truncate(time.Second)
+		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, nil, nil, false)
 		require.NoError(t, err, "failed to insert task log")
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task after tl insert")
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		numSecondsDelta := 60
+		updatedComment := testCommentUpdated
+		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta*2))
+		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 2)
+		require.NoError(t, err, "failed to edit saved task log")
 
-		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 2, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
 
-		assert.Equal(t, 2, entries[1].TaskID)
-		assert.Equal(t, 1, entries[1].NumEntries)
-		assert.Equal(t, 4*secsInOneHour, entries[1].SecsSpent)
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
+		assert.True(t, newEndTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", newEndTS, taskLog.EndTS)
+		assert.Equal(t, numSeconds+numSecondsDelta, taskLog.SecsSpent)
+		require.NotNil(t, taskLog.Comment)
+		assert.Equal(t, updatedComment, *taskLog.Comment)
+		assert.Equal(t, taskBefore.SecsSpent+numSecondsDelta, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestFetchStatsBetweenTS for active tasks", func(t *testing.T) {
+	t.Run("TestEditSavedTL changes and clears category", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
 		taskID := 1
-		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		endTS := time.Now().Truncate(time.Second)
+		beginTS := endTS.Add(-time.Hour)
+		category := "coding"
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil, &category, nil, true)
 		require.NoError(t, err, "failed to insert task log")
-		err = UpdateTaskActiveStatus(testDB, 2, false)
-		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+		updatedCategory := "review"
+		_, err = EditSavedTL(testDB, tlID, beginTS, endTS, nil, &updatedCategory, nil, true)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		require.NoError(t, err, "failed to edit saved task log")
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		require.NotNil(t, taskLog.Category)
+		assert.Equal(t, updatedCategory, *taskLog.Category)
 
-		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 2, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+		// WHEN
+		_, err = EditSavedTL(testDB, tlID, beginTS, endTS, nil, nil, nil, true)
+
+		// THEN
+		require.NoError(t, err, "failed to edit saved task log")
+		taskLog, err = fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Nil(t, taskLog.Category)
 	})
 
-	t.Run("TestFetchStatsBetweenTS for inactive tasks", func(t *testing.T) {
+	t.Run("TestEditSavedTL works when new time spent is smaller than the previous one", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
 		taskID := 1
+
+		comment := testComment
 		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		endTS := time.Now().Truncate(time.Second)
+		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, nil, nil, false)
 		require.NoError(t, err, "failed to insert task log")
-		err = UpdateTaskActiveStatus(testDB, 1, false)
-		require.NoError(t, err, "failed to make task inactive")
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task after tl insert")
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		numSecondsDelta := 60
+		updatedComment := testCommentUpdated
+		newBeginTS := beginTS.Add(time.Second * time.Duration(numSecondsDelta))
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, endTS, &updatedComment, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-		require.Len(t, entries, 1)
+		require.NoError(t, err, "failed to edit saved task log")
 
-		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 2, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
+		assert.True(t, endTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", endTS, taskLog.EndTS)
+		assert.Equal(t, numSeconds-numSecondsDelta, taskLog.SecsSpent)
+		require.NotNil(t, taskLog.Comment)
+		assert.Equal(t, updatedComment, *taskLog.Comment)
+		assert.Equal(t, taskBefore.SecsSpent-numSecondsDelta, taskAfter.SecsSpent)
 	})
 
-	t.Run("TestFetchReportBetweenTS for all tasks", func(t *testing.T) {
+	t.Run("TestEditSavedTL works when time spent is unchanged", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		referenceTS := time.Now().Truncate(time.Second)
 		seedData := getTestData(referenceTS)
 		seedDB(t, testDB, seedData)
-
 		taskID := 1
+
+		comment := testComment
 		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		endTS := time.Now().Truncate(time.Second)
+		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, nil, nil, false)
 		require.NoError(t, err, "failed to insert task log")
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task after tl insert")
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		numSecondsDelta := 60
+		updatedComment := testCommentUpdated
+		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
+		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment, nil, nil, false)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-
-		require.Len(t, entries, 2)
-		assert.Equal(t, 2, entries[0].TaskID)
-		assert.Equal(t, 1, entries[0].NumEntries)
-		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+		require.NoError(t, err, "failed to edit saved task log")
 
-		assert.Equal(t, 1, entries[1].TaskID)
-		assert.Equal(t, 2, entries[1].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[1].SecsSpent)
-	})
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
 
-	t.Run("TestFetchReportBetweenTS for active tasks tasks", func(t *testing.T) {
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.True(t, newBeginTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", newBeginTS, taskLog.BeginTS)
+		assert.True(t, newEndTS.Equal(taskLog.EndTS), "new end ts is not correct; expected=%v, got=%v", newEndTS, taskLog.EndTS)
+		assert.Equal(t, numSeconds, taskLog.SecsSpent)
+		require.NotNil(t, taskLog.Comment)
+		assert.Equal(t, updatedComment, *taskLog.Comment)
+		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
+	})
+
+	t.Run("TestEditSavedTL rejects changes that make the entry overlap with another one", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		first := seedData.taskLogs[0]
+		second := seedData.taskLogs[1]
+
+		// WHEN
+		_, errOverlap := EditSavedTL(testDB, second.ID, first.EndTS.Add(-time.Minute), second.EndTS, nil, nil, nil, false)
+		_, errAdjacent := EditSavedTL(testDB, second.ID, first.EndTS, second.EndTS, nil, nil, nil, false)
+
+		// THEN
+		require.ErrorIs(t, errOverlap, ErrTaskLogOverlaps)
+		assert.NoError(t, errAdjacent)
+
+		taskLog, err := fetchTLByID(testDB, second.ID)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.True(t, first.EndTS.Equal(taskLog.BeginTS), "new begin ts is not correct; expected=%v, got=%v", first.EndTS, taskLog.BeginTS)
+	})
+
+	t.Run("TestDeleteTaskLogEntry", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		tlID := 1
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+		numSecondsBefore := taskBefore.SecsSpent
+		taskLog, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch task log")
+
+		// WHEN
+		err = DeleteTL(testDB, &taskLog)
+
+		// THEN
+		require.NoError(t, err, "failed to insert task log")
+
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		assert.Equal(t, numSecondsBefore-taskLog.SecsSpent, taskAfter.SecsSpent)
+	})
+
+	t.Run("TestDeleteTLsBetweenTS deletes entries in range and keeps secs_spent consistent", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		ca := referenceTS.Add(time.Hour * 24 * 7 * -1)
+
+		// WHEN
+		numDeleted, err := DeleteTLsBetweenTS(testDB, ca.Add(time.Hour*5), ca.Add(time.Hour*10), nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2, numDeleted)
+
+		task1, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2*secsInOneHour, task1.SecsSpent)
+		task2, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task2.SecsSpent)
+
+		_, err = fetchTLByID(testDB, 1)
+		assert.NoError(t, err)
+		_, err = fetchTLByID(testDB, 2)
+		assert.Error(t, err)
+		_, err = fetchTLByID(testDB, 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("TestDeleteTLsBetweenTS only deletes entries for the given task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		ca := referenceTS.Add(time.Hour * 24 * 7 * -1)
+		taskID := 1
+
+		// WHEN
+		numDeleted, err := DeleteTLsBetweenTS(testDB, ca, ca.Add(time.Hour*10), &taskID)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2, numDeleted)
+
+		task1, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task1.SecsSpent)
+		task2, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4*secsInOneHour, task2.SecsSpent)
+
+		numTLs := 0
+		err = testDB.QueryRow(`SELECT COUNT(*) FROM task_log;`).Scan(&numTLs)
+		require.NoError(t, err)
+		assert.Equal(t, 1, numTLs)
+	})
+
+	t.Run("TestDeleteTLsBetweenTS returns zero when nothing is in range", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+
+		// WHEN
+		numDeleted, err := DeleteTLsBetweenTS(testDB, referenceTS, referenceTS.Add(time.Hour), nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 0, numDeleted)
+
+		task1, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 5*secsInOneHour, task1.SecsSpent)
+	})
+
+	t.Run("TestDeleteAllTLsForTask deletes saved entries and resets secs_spent", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+
+		// WHEN
+		numDeleted, err := DeleteAllTLsForTask(testDB, 1)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2, numDeleted)
+
+		task1, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task1.SecsSpent)
+		task2, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4*secsInOneHour, task2.SecsSpent)
+
+		numTLs := 0
+		err = testDB.QueryRow(`SELECT COUNT(*) FROM task_log;`).Scan(&numTLs)
+		require.NoError(t, err)
+		assert.Equal(t, 1, numTLs)
+	})
+
+	t.Run("TestDeleteAllTLsForTask leaves the active task log untouched", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		activeTLID, err := InsertNewTL(testDB, 2, referenceTS.Add(-time.Hour))
+		require.NoError(t, err)
+
+		// WHEN
+		numDeleted, err := DeleteAllTLsForTask(testDB, 2)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 1, numDeleted)
+
+		task2, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, task2.SecsSpent)
+
+		activeTL, err := fetchActiveTLByID(testDB, activeTLID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, activeTL.TaskID)
+	})
+
+	t.Run("TestDeleteAllTLsForTask returns error when task not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		_, err := DeleteAllTLsForTask(testDB, 999)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
+
+	t.Run("TestAllTLEntries", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		var comments []string
+		for entry, err := range AllTLEntries(testDB) {
+			require.NoError(t, err, "failed to iterate over task logs")
+			comments = append(comments, *entry.Comment)
+		}
+
+		// THEN
+		assert.Equal(t, []string{"task 1 tl 1", "task 2 tl 1", "task 1 tl 2"}, comments)
+	})
+
+	t.Run("TestSearchTaskLogsByComment", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		entries, err := SearchTaskLogsByComment(testDB, "TASK 1", 50)
+
+		// THEN
+		require.NoError(t, err, "failed to search task logs")
+		require.Len(t, entries, 2)
+		assert.Equal(t, "task 1 tl 2", *entries[0].Comment)
+		assert.Equal(t, "task 1 tl 1", *entries[1].Comment)
+	})
+
+	t.Run("TestSearchTaskLogsByComment respects limit and skips missing matches", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		limited, limitedErr := SearchTaskLogsByComment(testDB, "tl 1", 1)
+		none, noneErr := SearchTaskLogsByComment(testDB, "absent", 50)
+
+		// THEN
+		require.NoError(t, limitedErr, "failed to search task logs")
+		require.NoError(t, noneErr, "failed to search task logs")
+		require.Len(t, limited, 1)
+		assert.Equal(t, "task 2 tl 1", *limited[0].Comment)
+		assert.Empty(t, none)
+	})
+
+	t.Run("TestFetchTLEntriesBetweenTS for all tasks", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
@@ -755,324 +1333,2065 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
 		require.NoError(t, err, "failed to insert task log")
 
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, _, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 3)
+	})
+
+	t.Run("TestFetchTLEntriesBetweenTS for active tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		err = UpdateTaskActiveStatus(testDB, 1, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
+		entries, _, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusActive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("TestFetchTLEntriesBetweenTS for inactive tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
 		err = UpdateTaskActiveStatus(testDB, 2, false)
 		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
+		entries, _, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusInactive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("TestFetchTLEntriesBetweenTS reports when the limit is reached", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+
+		// WHEN
+		cappedEntries, cappedLimitReached, cappedErr := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 2)
+		allEntries, allLimitReached, allErr := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 3)
+
+		// THEN
+		require.NoError(t, cappedErr, "failed to fetch report entries")
+		require.Len(t, cappedEntries, 2)
+		assert.True(t, cappedLimitReached)
+		assert.Equal(t, allEntries[:2], cappedEntries)
+
+		require.NoError(t, allErr, "failed to fetch report entries")
+		require.Len(t, allEntries, 3)
+		assert.False(t, allLimitReached)
+	})
+
+	t.Run("TestFetchStats for all tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		comment := "an extra task log"
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		entries, err := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusAny}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 2)
 
-		require.Len(t, entries, 1)
 		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 2, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+		assert.Equal(t, 3, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour+numSeconds, entries[0].SecsSpent)
+
+		assert.Equal(t, 2, entries[1].TaskID)
+		assert.Equal(t, 1, entries[1].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[1].SecsSpent)
+	})
+
+	t.Run("TestFetchStats for active tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		err = UpdateTaskActiveStatus(testDB, 1, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		entries, err := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusActive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+
+		assert.Equal(t, 2, entries[0].TaskID)
+		assert.Equal(t, 1, entries[0].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestFetchStats for inactive tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		err = UpdateTaskActiveStatus(testDB, 2, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		entries, err := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusInactive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+
+		assert.Equal(t, 2, entries[0].TaskID)
+		assert.Equal(t, 1, entries[0].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestFetchStatsBetweenTS for all tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+
+		assert.Equal(t, 2, entries[1].TaskID)
+		assert.Equal(t, 1, entries[1].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[1].SecsSpent)
+	})
+
+	t.Run("TestFetchStatsBetweenTS for active tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+		err = UpdateTaskActiveStatus(testDB, 2, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusActive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestFetchStatsBetweenTS for inactive tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+		err = UpdateTaskActiveStatus(testDB, 1, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusInactive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+		require.Len(t, entries, 1)
+
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestFetchReportBetweenTS for all tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+
+		require.Len(t, entries, 2)
+		assert.Equal(t, 2, entries[0].TaskID)
+		assert.Equal(t, 1, entries[0].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+
+		assert.Equal(t, 1, entries[1].TaskID)
+		assert.Equal(t, 2, entries[1].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[1].SecsSpent)
+	})
+
+	t.Run("TestFetchReportBetweenTS reports when the limit is reached", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+
+		// WHEN
+		cappedEntries, cappedLimitReached, cappedErr := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 1)
+		allEntries, allLimitReached, allErr := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny}, 2)
+
+		// THEN
+		require.NoError(t, cappedErr, "failed to fetch report entries")
+		require.Len(t, cappedEntries, 1)
+		assert.True(t, cappedLimitReached)
+		assert.Equal(t, 2, cappedEntries[0].TaskID)
+
+		require.NoError(t, allErr, "failed to fetch report entries")
+		require.Len(t, allEntries, 2)
+		assert.False(t, allLimitReached)
+	})
+
+	t.Run("TestFetchReportBetweenTS for active tasks tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		err = UpdateTaskActiveStatus(testDB, 2, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusActive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+
+		require.Len(t, entries, 1)
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestFetchReportBetweenTS for inactive tasks tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		taskID := 1
+		numSeconds := 60 * 90
+		tlEndTS := referenceTS.Add(time.Hour * 2)
+		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
+		comment := taskLogComment
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, nil, nil, false)
+		require.NoError(t, err, "failed to insert task log")
+
+		err = UpdateTaskActiveStatus(testDB, 1, false)
+		require.NoError(t, err, "failed to make task inactive")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		entries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusInactive}, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch report entries")
+
+		require.Len(t, entries, 1)
+		assert.Equal(t, 1, entries[0].TaskID)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+	})
+
+	t.Run("TestTrackingTaskStatus only returns the task being tracked", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		_, err = InsertNewTL(testDB, 2, referenceTS.Add(time.Minute*-30))
+		require.NoError(t, err, "failed to start tracking")
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		reportEntries, _, reportErr := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+		statsEntries, statsErr := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+		tlEntries, _, tlErr := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+
+		// THEN
+		require.NoError(t, reportErr, "failed to fetch report entries")
+		require.Len(t, reportEntries, 1)
+		assert.Equal(t, 2, reportEntries[0].TaskID)
+
+		require.NoError(t, statsErr, "failed to fetch stats")
+		require.Len(t, statsEntries, 1)
+		assert.Equal(t, 2, statsEntries[0].TaskID)
+
+		require.NoError(t, tlErr, "failed to fetch task log entries")
+		require.Len(t, tlEntries, 1)
+		assert.Equal(t, 2, tlEntries[0].TaskID)
+	})
+
+	t.Run("TestTrackingTaskStatus returns nothing when no task is being tracked", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		reportEntries, _, reportErr := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+		statsEntries, statsErr := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+		tlEntries, _, tlErr := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusTracking}, 100)
+
+		// THEN
+		require.NoError(t, reportErr, "failed to fetch report entries")
+		assert.Empty(t, reportEntries)
+		require.NoError(t, statsErr, "failed to fetch stats")
+		assert.Empty(t, statsEntries)
+		require.NoError(t, tlErr, "failed to fetch task log entries")
+		assert.Empty(t, tlEntries)
+	})
+
+	err = testDB.Close()
+	require.NoErrorf(t, err, "error closing DB: %v", err)
+}
+
+func TestExtendedRepository(t *testing.T) {
+	testDB, err := sql.Open("sqlite", ":memory:")
+	require.NoErrorf(t, err, "error opening DB: %v", err)
+
+	err = InitDB(testDB)
+	require.NoErrorf(t, err, "error initializing DB: %v", err)
+
+	err = UpgradeDB(testDB, 1)
+	require.NoErrorf(t, err, "error upgrading DB: %v", err)
+
+	t.Run("TestUpdateTask changes summary", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		newSummary := "updated task summary"
+
+		// WHEN
+		err := UpdateTask(testDB, taskID, newSummary)
+
+		// THEN
+		require.NoError(t, err, "failed to update task")
+
+		task, fetchErr := fetchTaskByID(testDB, taskID)
+		require.NoError(t, fetchErr, "failed to fetch task")
+
+		assert.Equal(t, newSummary, task.Summary)
+		assert.Equal(t, taskID, task.ID)
+	})
+
+	t.Run("TestInsertTask enforces the maximum summary length in characters", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		atLimit := strings.Repeat("a", MaxTaskSummaryLength)
+		overLimit := strings.Repeat("a", MaxTaskSummaryLength+1)
+		multibyte := strings.Repeat("ü", MaxTaskSummaryLength)
+
+		// WHEN
+		_, atLimitErr := InsertTask(testDB, atLimit)
+		_, overLimitErr := InsertTask(testDB, overLimit)
+		multibyteID, multibyteErr := InsertTask(testDB, multibyte)
+
+		// THEN
+		require.NoError(t, atLimitErr)
+		assert.ErrorIs(t, overLimitErr, ErrTaskSummaryTooLong)
+		require.NoError(t, multibyteErr)
+		task, err := fetchTaskByID(testDB, multibyteID)
+		require.NoError(t, err)
+		assert.Equal(t, multibyte, task.Summary)
+	})
+
+	t.Run("TestUpdateTask rejects a summary that's too long", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+
+		// WHEN
+		err = UpdateTask(testDB, taskID, strings.Repeat("ü", MaxTaskSummaryLength+1))
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskSummaryTooLong)
+		task, fetchErr := fetchTaskByID(testDB, taskID)
+		require.NoError(t, fetchErr)
+		assert.Equal(t, "a task", task.Summary)
+	})
+
+	t.Run("TestFetchTaskByID returns task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		task, err := FetchTaskByID(testDB, 2)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, 2, task.ID)
+		assert.Equal(t, "seeded task 2", task.Summary)
+	})
+
+	t.Run("TestFetchTaskByID returns error when task not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		_, err := FetchTaskByID(testDB, 999)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
+
+	t.Run("TestFetchTLByID returns saved task log", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		tl, err := FetchTLByID(testDB, 1)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, 1, tl.ID)
+		assert.Equal(t, 1, tl.TaskID)
+	})
+
+	t.Run("TestFetchTLByID returns error for active task log", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		tlID, err := InsertNewTL(testDB, 1, referenceTS)
+		require.NoError(t, err)
+
+		// WHEN
+		_, err = FetchTLByID(testDB, tlID)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskLogNotFound)
+	})
+
+	t.Run("TestFetchTasks returns active tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		tasks, err := FetchTasks(testDB, true, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch active tasks")
+		require.Len(t, tasks, 2)
+		for _, task := range tasks {
+			assert.True(t, task.Active, "expected only active tasks")
+		}
+	})
+
+	t.Run("TestFetchTasks returns inactive tasks", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		err := UpdateTaskActiveStatus(testDB, 1, false)
+		require.NoError(t, err, "failed to deactivate task")
+
+		// WHEN
+		tasks, err := FetchTasks(testDB, false, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch inactive tasks")
+		require.Len(t, tasks, 1)
+		assert.Equal(t, 1, tasks[0].ID)
+		assert.False(t, tasks[0].Active)
+	})
+
+	t.Run("TestFetchTasks respects limit", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		tasks, err := FetchTasks(testDB, true, 1)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch tasks with limit")
+		require.Len(t, tasks, 1)
+	})
+
+	t.Run("TestFetchTasksPaged slices tasks by offset", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+		for i := range 5 {
+			taskID, err := InsertTask(testDB, fmt.Sprintf("task %d", i+1))
+			require.NoError(t, err)
+			_, err = testDB.Exec(`UPDATE task SET updated_at = ? WHERE id = ?;`, referenceTS.Add(time.Duration(i)*time.Hour), taskID)
+			require.NoError(t, err)
+		}
+
+		// WHEN
+		firstPage, err := FetchTasksPaged(testDB, true, 2, 0)
+		require.NoError(t, err)
+		secondPage, err := FetchTasksPaged(testDB, true, 2, 2)
+		require.NoError(t, err)
+		lastPage, err := FetchTasksPaged(testDB, true, 2, 4)
+		require.NoError(t, err)
+		beyondLastPage, err := FetchTasksPaged(testDB, true, 2, 6)
+		require.NoError(t, err)
+
+		// THEN
+		taskIDs := func(tasks []types.Task) []int {
+			ids := make([]int, len(tasks))
+			for i, task := range tasks {
+				ids[i] = task.ID
+			}
+			return ids
+		}
+		assert.Equal(t, []int{5, 4}, taskIDs(firstPage))
+		assert.Equal(t, []int{3, 2}, taskIDs(secondPage))
+		assert.Equal(t, []int{1}, taskIDs(lastPage))
+		assert.Empty(t, beyondLastPage)
+	})
+
+	t.Run("TestFetchTasksPaged keeps a stable order across pages for tasks updated at the same time", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		updatedAt := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+		for i := range 5 {
+			_, err := InsertTask(testDB, fmt.Sprintf("task %d", i+1))
+			require.NoError(t, err)
+		}
+		_, err := testDB.Exec(`UPDATE task SET updated_at = ?;`, updatedAt)
+		require.NoError(t, err)
+
+		// WHEN
+		var paged []int
+		for offset := 0; offset < 5; offset += 2 {
+			tasks, err := FetchTasksPaged(testDB, true, 2, offset)
+			require.NoError(t, err)
+			for _, task := range tasks {
+				paged = append(paged, task.ID)
+			}
+		}
+		all, err := FetchTasks(testDB, true, 5)
+		require.NoError(t, err)
+
+		// THEN
+		allIDs := make([]int, len(all))
+		for i, task := range all {
+			allIDs[i] = task.ID
+		}
+		assert.Equal(t, []int{5, 4, 3, 2, 1}, paged)
+		assert.Equal(t, allIDs, paged)
+	})
+
+	t.Run("TestFetchTLEntries returns entries in descending order", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		entries, err := FetchTLEntries(testDB, true, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task log entries")
+		require.Len(t, entries, 3)
+		// Descending: latest end_ts first
+		assert.True(t, entries[0].EndTS.After(entries[1].EndTS) || entries[0].EndTS.Equal(entries[1].EndTS))
+	})
+
+	t.Run("TestFetchTLEntries returns entries in ascending order", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		entries, err := FetchTLEntries(testDB, false, 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task log entries")
+		require.Len(t, entries, 3)
+		// Ascending: earliest end_ts first
+		assert.True(t, entries[0].EndTS.Before(entries[1].EndTS) || entries[0].EndTS.Equal(entries[1].EndTS))
+	})
+
+	t.Run("TestFetchTLEntries respects limit", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		entries, err := FetchTLEntries(testDB, true, 2)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task log entries with limit")
+		require.Len(t, entries, 2)
+	})
+
+	t.Run("TestFetchTLEntriesForTask only returns entries for the task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN
+		entries, err := FetchTLEntriesForTask(testDB, 1, true, 50)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch task log entries for task")
+		require.Len(t, entries, 2)
+		assert.Equal(t, 2, entries[0].ID)
+		assert.Equal(t, 1, entries[1].ID)
+	})
+
+	t.Run("TestDeleteActiveTL removes the open log entry", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		beginTS := time.Now().Add(-time.Hour)
+		_, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		require.NoError(t, insertErr, "failed to insert active task log")
+
+		// verify the active TL exists before deletion
+		activeDetails, err := FetchActiveTaskDetails(testDB)
+		require.NoError(t, err, "failed to fetch active task details")
+		assert.Equal(t, taskID, activeDetails.TaskID)
+
+		// WHEN
+		err = DeleteActiveTL(testDB)
+
+		// THEN
+		require.NoError(t, err, "failed to delete active task log")
+
+		// verify no active TL remains
+		var count int
+		countErr := testDB.QueryRow("SELECT COUNT(*) FROM task_log WHERE active=true").Scan(&count)
+		require.NoError(t, countErr, "failed to count active task logs")
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("TestDeleteActiveTL succeeds when no active log exists", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN - no active TL
+
+		// WHEN
+		err := DeleteActiveTL(testDB)
+
+		// THEN
+		require.NoError(t, err, "should not error when no active TL exists")
+	})
+
+	t.Run("TestMoveTaskLog moves entry and updates secs_spent", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		tlID := 1 // belongs to task 1, secsSpent=2h
+		oldTaskID := 1
+		newTaskID := 2
+		secsToMove := 2 * secsInOneHour
+
+		taskOneBefore, err := fetchTaskByID(testDB, oldTaskID)
+		require.NoError(t, err, "failed to fetch old task")
+		taskTwoBefore, err := fetchTaskByID(testDB, newTaskID)
+		require.NoError(t, err, "failed to fetch new task")
+
+		// WHEN
+		err = MoveTaskLog(testDB, tlID, oldTaskID, newTaskID, secsToMove)
+
+		// THEN
+		require.NoError(t, err, "failed to move task log")
+
+		// verify task_log entry now belongs to newTaskID
+		tl, fetchErr := fetchTLByID(testDB, tlID)
+		require.NoError(t, fetchErr, "failed to fetch moved task log")
+		assert.Equal(t, newTaskID, tl.TaskID)
+
+		// verify secs_spent transferred between tasks
+		taskOneAfter, err := fetchTaskByID(testDB, oldTaskID)
+		require.NoError(t, err, "failed to fetch old task after move")
+		taskTwoAfter, err := fetchTaskByID(testDB, newTaskID)
+		require.NoError(t, err, "failed to fetch new task after move")
+
+		assert.Equal(t, taskOneBefore.SecsSpent-secsToMove, taskOneAfter.SecsSpent)
+		assert.Equal(t, taskTwoBefore.SecsSpent+secsToMove, taskTwoAfter.SecsSpent)
+	})
+
+	t.Run("TestMoveTaskLog same task is a no-op", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+
+		taskBefore, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task")
+
+		// WHEN
+		err = MoveTaskLog(testDB, 1, taskID, taskID, secsInOneHour)
+
+		// THEN
+		require.NoError(t, err, "same-task move should not error")
+
+		taskAfter, err := fetchTaskByID(testDB, taskID)
+		require.NoError(t, err, "failed to fetch task after no-op move")
+
+		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
+	})
+
+	t.Run("TestMoveTaskLog returns error when task log not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+
+		// WHEN - use a non-existent TL ID
+		err := MoveTaskLog(testDB, 9999, 1, 2, secsInOneHour)
+
+		// THEN
+		require.ErrorIs(t, err, ErrTaskLogNotFound)
+	})
+
+	t.Run("TestMoveTaskLogs moves nothing if an entry can't be moved", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now()
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		tl, err := fetchTLByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task log")
+		taskTwoBefore, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err, "failed to fetch task")
+		entries := []types.TaskLogEntry{
+			tl,
+			{ID: 9999, TaskID: 1, SecsSpent: secsInOneHour},
+		}
+
+		// WHEN
+		err = MoveTaskLogs(testDB, entries, 2)
+
+		// THEN
+		require.ErrorIs(t, err, ErrTaskLogNotFound)
+
+		tlAfter, err := fetchTLByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, 1, tlAfter.TaskID)
+		taskTwoAfter, err := fetchTaskByID(testDB, 2)
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, taskTwoBefore.SecsSpent, taskTwoAfter.SecsSpent)
+	})
+
+	t.Run("TestFetchFirstOverlappingTL returns the earliest overlapping entry for the task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2025, 8, 16, 9, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+		otherTaskID, err := InsertTask(testDB, "another task")
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, referenceTS.Add(-time.Hour), referenceTS, nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, otherTaskID, referenceTS.Add(30*time.Minute), referenceTS.Add(time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, referenceTS.Add(2*time.Hour), referenceTS.Add(3*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		nextID, err := InsertManualTL(testDB, taskID, referenceTS.Add(time.Hour), referenceTS.Add(90*time.Minute), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		// WHEN
+		got, err := FetchFirstOverlappingTL(testDB, taskID, referenceTS, referenceTS.Add(4*time.Hour))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, nextID, got.ID)
+		assert.Equal(t, referenceTS.Add(time.Hour), got.BeginTS)
+	})
+
+	t.Run("TestFetchFirstOverlappingTL returns ErrTaskLogNotFound when nothing overlaps", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2025, 8, 16, 9, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, referenceTS.Add(time.Hour), referenceTS.Add(2*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		// WHEN
+		_, err = FetchFirstOverlappingTL(testDB, taskID, referenceTS, referenceTS.Add(time.Hour))
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskLogNotFound)
+	})
+
+	t.Run("TestFetchTodayTotal only counts saved entries that ended today", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		now := time.Date(2025, 8, 16, 18, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-4*time.Hour), now.Add(-2*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-90*time.Minute), now.Add(-time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.AddDate(0, 0, -1), now.AddDate(0, 0, -1).Add(time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertNewTL(testDB, taskID, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+
+		// WHEN
+		got, err := FetchTodayTotal(testDB, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 150*60, got)
+	})
+
+	t.Run("TestFetchTodayTotal counts entries by the day they ended on", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		midnight := time.Date(2025, 8, 16, 0, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, midnight.Add(-2*time.Hour), midnight.Add(-time.Minute), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, midnight.Add(-30*time.Minute), midnight.Add(45*time.Minute), nil, nil, nil, true)
+		require.NoError(t, err)
+
+		// WHEN
+		yesterday, err := FetchTodayTotal(testDB, midnight.Add(-time.Second))
+		require.NoError(t, err)
+		today, err := FetchTodayTotal(testDB, midnight.Add(time.Hour))
+		require.NoError(t, err)
+
+		// THEN
+		assert.Equal(t, 119*60, yesterday)
+		assert.Equal(t, 75*60, today)
+	})
+
+	t.Run("TestFetchTodayTotal returns zero when nothing was tracked", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// WHEN
+		got, err := FetchTodayTotal(testDB, time.Now())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 0, got)
+	})
+
+	t.Run("TestFetchTaskWeekTotal counts entries since the task's week reset day", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		now := time.Date(2024, time.June, 29, 12, 0, 0, 0, time.Local) // a Saturday
+		taskID, err := InsertTask(testDB, "weekly quota")
+		require.NoError(t, err)
+		wednesday := time.Date(2024, time.June, 26, 10, 0, 0, 0, time.Local)
+		_, err = InsertManualTL(testDB, taskID, wednesday, wednesday.Add(2*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		friday := time.Date(2024, time.June, 28, 10, 0, 0, 0, time.Local)
+		_, err = InsertManualTL(testDB, taskID, friday, friday.Add(time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		thursday := time.Thursday
+		require.NoError(t, SetTaskWeekResetDay(testDB, taskID, &thursday))
+
+		// WHEN
+		got, err := FetchTaskWeekTotal(testDB, taskID, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, secsInOneHour, got)
+	})
+
+	t.Run("TestFetchTaskWeekTotal starts the week on Monday without a reset day", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		now := time.Date(2024, time.June, 29, 12, 0, 0, 0, time.Local) // a Saturday
+		taskID, err := InsertTask(testDB, "regular task")
+		require.NoError(t, err)
+		lastSunday := time.Date(2024, time.June, 23, 10, 0, 0, 0, time.Local)
+		_, err = InsertManualTL(testDB, taskID, lastSunday, lastSunday.Add(time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		wednesday := time.Date(2024, time.June, 26, 10, 0, 0, 0, time.Local)
+		_, err = InsertManualTL(testDB, taskID, wednesday, wednesday.Add(2*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+
+		// WHEN
+		got, err := FetchTaskWeekTotal(testDB, taskID, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2*secsInOneHour, got)
+	})
+
+	t.Run("TestFetchStatsForTaskWeeks uses each task's week reset day", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		now := time.Date(2024, time.June, 29, 12, 0, 0, 0, time.Local) // a Saturday
+		quotaTaskID, err := InsertTask(testDB, "weekly quota")
+		require.NoError(t, err)
+		regularTaskID, err := InsertTask(testDB, "regular task")
+		require.NoError(t, err)
+		wednesday := time.Date(2024, time.June, 26, 10, 0, 0, 0, time.Local)
+		friday := time.Date(2024, time.June, 28, 10, 0, 0, 0, time.Local)
+		for _, taskID := range []int{quotaTaskID, regularTaskID} {
+			_, err = InsertManualTL(testDB, taskID, wednesday, wednesday.Add(2*time.Hour), nil, nil, nil, false)
+			require.NoError(t, err)
+			_, err = InsertManualTL(testDB, taskID, friday, friday.Add(time.Hour), nil, nil, nil, false)
+			require.NoError(t, err)
+		}
+		thursday := time.Thursday
+		require.NoError(t, SetTaskWeekResetDay(testDB, quotaTaskID, &thursday))
+
+		// WHEN
+		got, err := FetchStatsForTaskWeeks(testDB, now, 10)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []types.TaskReportEntry{
+			{TaskID: regularTaskID, TaskSummary: "regular task", NumEntries: 2, SecsSpent: 3 * secsInOneHour},
+			{TaskID: quotaTaskID, TaskSummary: "weekly quota", NumEntries: 1, SecsSpent: secsInOneHour},
+		}, got)
+	})
+
+	t.Run("TestSetTaskWeekResetDay fails for an unknown task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		monday := time.Monday
+
+		// WHEN
+		err := SetTaskWeekResetDay(testDB, 42, &monday)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
+
+	t.Run("TestGetWeeklyGoal returns zero when no goal is set", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// WHEN
+		got, err := GetWeeklyGoal(testDB)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 0, got)
+	})
+
+	t.Run("TestSetWeeklyGoal saves and overwrites the goal", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		require.NoError(t, SetWeeklyGoal(testDB, 40*3600))
+
+		// WHEN
+		err := SetWeeklyGoal(testDB, 30*3600)
+
+		// THEN
+		require.NoError(t, err)
+		got, err := GetWeeklyGoal(testDB)
+		require.NoError(t, err)
+		assert.Equal(t, 30*3600, got)
+	})
+
+	t.Run("TestSetWeeklyGoal rejects a negative goal", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// WHEN
+		err := SetWeeklyGoal(testDB, -1)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrNegativeWeeklyGoal)
+	})
+
+	t.Run("TestGetLastPeriod returns an empty period when none is saved", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// WHEN
+		got, err := GetLastPeriod(testDB, "report")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("TestSetLastPeriod saves and overwrites the period for each command", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		require.NoError(t, SetLastPeriod(testDB, "report", "week"))
+		require.NoError(t, SetLastPeriod(testDB, "stats", "this-month"))
+
+		// WHEN
+		err := SetLastPeriod(testDB, "report", "yest")
+
+		// THEN
+		require.NoError(t, err)
+		got, err := GetLastPeriod(testDB, "report")
+		require.NoError(t, err)
+		assert.Equal(t, "yest", got)
+
+		got, err = GetLastPeriod(testDB, "stats")
+		require.NoError(t, err)
+		assert.Equal(t, "this-month", got)
+
+		got, err = GetLastPeriod(testDB, "log")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("TestFetchRecentCommentsForTask returns distinct comments, most recent first", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		now := time.Date(2025, 8, 16, 18, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "a task")
+		require.NoError(t, err)
+		otherTaskID, err := InsertTask(testDB, "another task")
+		require.NoError(t, err)
+		standup, review, empty := "standup", "code review", ""
+		_, err = InsertManualTL(testDB, taskID, now.Add(-5*time.Hour), now.Add(-4*time.Hour), &standup, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), &review, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), &standup, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), &empty, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, taskID, now.Add(-1*time.Hour), now, nil, nil, nil, false)
+		require.NoError(t, err)
+		otherComment := "other task's comment"
+		_, err = InsertManualTL(testDB, otherTaskID, now.Add(-1*time.Hour), now, &otherComment, nil, nil, false)
+		require.NoError(t, err)
+
+		// WHEN
+		got, err := FetchRecentCommentsForTask(testDB, taskID, 10)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"standup", "code review"}, got)
+	})
+
+	t.Run("TestSetTaskColor stores a color for a task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := SetTaskColor(testDB, 1, "#ff8800")
+
+		// THEN
+		require.NoError(t, err)
+		task, err := FetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		require.NotNil(t, task.Color)
+		assert.Equal(t, "#ff8800", *task.Color)
+
+		tasks, err := FetchTasks(testDB, true, 10)
+		require.NoError(t, err)
+		for _, tsk := range tasks {
+			if tsk.ID == 1 {
+				require.NotNil(t, tsk.Color)
+				assert.Equal(t, "#ff8800", *tsk.Color)
+			} else {
+				assert.Nil(t, tsk.Color)
+			}
+		}
+	})
+
+	t.Run("TestSetTaskColor clears the color when given an empty one", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+		require.NoError(t, SetTaskColor(testDB, 1, "208"))
+
+		// WHEN
+		err := SetTaskColor(testDB, 1, "")
+
+		// THEN
+		require.NoError(t, err)
+		task, err := FetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Nil(t, task.Color)
+	})
+
+	t.Run("TestSetTaskColor rejects invalid colors", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := SetTaskColor(testDB, 1, "orange")
+
+		// THEN
+		assert.ErrorIs(t, err, ErrInvalidTaskColor)
+
+		task, err := FetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Nil(t, task.Color)
+	})
+
+	t.Run("TestSetTaskColor returns error when task not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := SetTaskColor(testDB, 999, "#ff8800")
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
+
+	t.Run("TestAddTaskTag adds tags to a task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		require.NoError(t, AddTaskTag(testDB, 1, "internal"))
+		require.NoError(t, AddTaskTag(testDB, 1, " acme "))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 2, "other"))
+
+		// THEN
+		tags, err := FetchTagsForTask(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme", "internal"}, tags)
+	})
+
+	t.Run("TestAddTaskTag fails for an empty tag", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := AddTaskTag(testDB, 1, "  ")
+
+		// THEN
+		assert.ErrorIs(t, err, ErrEmptyTaskTag)
+	})
+
+	t.Run("TestAddTaskTag fails for a tag with a comma", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := AddTaskTag(testDB, 1, "acme, internal")
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTagHasComma)
+	})
+
+	t.Run("TestAddTaskTag returns error when task not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+
+		// WHEN
+		err := AddTaskTag(testDB, 999, "acme")
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
+
+	t.Run("TestRemoveTaskTag removes a tag from a task", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		seedDB(t, testDB, getTestData(time.Now()))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 1, "internal"))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+
+		// WHEN
+		err := RemoveTaskTag(testDB, 1, "acme")
+
+		// THEN
+		require.NoError(t, err)
+		tags, err := FetchTagsForTask(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"internal"}, tags)
+
+		otherTags, err := FetchTagsForTask(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme"}, otherTags)
+	})
+
+	t.Run("TestFetchReportBetweenTS only includes tasks carrying the tag", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 1, "internal"))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+
+		// WHEN
+		internalEntries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "internal"}, 100)
+		require.NoError(t, err)
+		acmeEntries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme"}, 100)
+		require.NoError(t, err)
+		unknownEntries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "unknown"}, 100)
+		require.NoError(t, err)
+
+		// THEN
+		require.Len(t, internalEntries, 1)
+		assert.Equal(t, 1, internalEntries[0].TaskID)
+		assert.Equal(t, 2, internalEntries[0].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, internalEntries[0].SecsSpent)
+
+		require.Len(t, acmeEntries, 2)
+		assert.Empty(t, unknownEntries)
+	})
+
+	t.Run("TestFetchReportBetweenTS combines the tag with the task status", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+		require.NoError(t, UpdateTaskActiveStatus(testDB, 2, false))
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+
+		// WHEN
+		entries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusInactive, Tag: "acme"}, 100)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, 2, entries[0].TaskID)
+	})
+
+	t.Run("TestSetTLTags round trips task log tags", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+
+		// WHEN
+		err := SetTLTags(testDB, 1, []string{"meeting", " review", "meeting"})
+
+		// THEN
+		require.NoError(t, err)
+		tl, err := FetchTLByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"meeting", "review"}, tl.Tags)
+
+		entries, err := FetchTLEntriesForTask(testDB, 1, false, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, []string{"meeting", "review"}, entries[0].Tags)
+		assert.Nil(t, entries[1].Tags)
+
+		require.NoError(t, SetTLTags(testDB, 1, nil))
+		tl, err = FetchTLByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Nil(t, tl.Tags)
+	})
+
+	t.Run("TestSetTLTags returns error when task log not found", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		// WHEN
+		err := SetTLTags(testDB, 999, []string{"meeting"})
+
+		// THEN
+		assert.ErrorIs(t, err, ErrTaskLogNotFound)
+	})
+
+	t.Run("TestRenameTag renames all occurrences of a tag", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme-corp"))
+		require.NoError(t, AddTaskTag(testDB, 2, "internal"))
+		require.NoError(t, SetTLTags(testDB, 1, []string{"acme", "meeting"}))
+		require.NoError(t, SetTLTags(testDB, 2, []string{"acme-corp", "acme"}))
+		require.NoError(t, SetTLTags(testDB, 3, []string{"acme2"}))
+
+		// WHEN
+		result, err := RenameTag(testDB, "acme", "acme-corp")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, TagChangeResult{NumTasks: 2, NumTaskLogs: 2}, result)
+
+		task1Tags, err := FetchTagsForTask(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme-corp"}, task1Tags)
+		task2Tags, err := FetchTagsForTask(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme-corp", "internal"}, task2Tags)
+
+		tl1, err := FetchTLByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme-corp", "meeting"}, tl1.Tags)
+		tl2, err := FetchTLByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme-corp"}, tl2.Tags)
+		tl3, err := FetchTLByID(testDB, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme2"}, tl3.Tags)
+	})
+
+	t.Run("TestRenameTag rejects invalid new tags", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		// WHEN
+		_, emptyErr := RenameTag(testDB, "acme", " ")
+		_, commaErr := RenameTag(testDB, "acme", "acme,corp")
+
+		// THEN
+		assert.ErrorIs(t, emptyErr, ErrEmptyTaskTag)
+		assert.ErrorIs(t, commaErr, ErrTagHasComma)
+	})
+
+	t.Run("TestDeleteTag removes a tag from all entities without touching others", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, AddTaskTag(testDB, 1, "acme"))
+		require.NoError(t, AddTaskTag(testDB, 1, "internal"))
+		require.NoError(t, AddTaskTag(testDB, 2, "internal"))
+		require.NoError(t, SetTLTags(testDB, 1, []string{"acme"}))
+		require.NoError(t, SetTLTags(testDB, 2, []string{"meeting", "acme"}))
+		require.NoError(t, SetTLTags(testDB, 3, []string{"meeting"}))
+
+		// WHEN
+		result, err := DeleteTag(testDB, "acme")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, TagChangeResult{NumTasks: 1, NumTaskLogs: 2}, result)
+
+		task1Tags, err := FetchTagsForTask(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"internal"}, task1Tags)
+		task2Tags, err := FetchTagsForTask(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"internal"}, task2Tags)
+
+		tl1, err := FetchTLByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Nil(t, tl1.Tags)
+		tl2, err := FetchTLByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"meeting"}, tl2.Tags)
+		tl3, err := FetchTLByID(testDB, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"meeting"}, tl3.Tags)
+	})
+
+	t.Run("TestFetchReportBetweenTS only includes entries carrying the log tag", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, SetTLTags(testDB, 1, []string{"review", "meeting"}))
+		require.NoError(t, SetTLTags(testDB, 3, []string{"meetings"}))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+
+		// WHEN
+		meetingEntries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, LogTag: "meeting"}, 100)
+		require.NoError(t, err)
+		acmeMeetingEntries, _, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme", LogTag: "meeting"}, 100)
+		require.NoError(t, err)
+		tlEntries, _, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, LogTag: "meetings"}, 100)
+		require.NoError(t, err)
+
+		// THEN
+		require.Len(t, meetingEntries, 1)
+		assert.Equal(t, 1, meetingEntries[0].TaskID)
+		assert.Equal(t, 1, meetingEntries[0].NumEntries)
+		assert.Equal(t, 2*secsInOneHour, meetingEntries[0].SecsSpent)
+
+		assert.Empty(t, acmeMeetingEntries)
+
+		require.Len(t, tlEntries, 1)
+		assert.Equal(t, 3, tlEntries[0].ID)
+		assert.Equal(t, []string{"meetings"}, tlEntries[0].Tags)
+	})
+
+	t.Run("TestFetchTLEntriesBetweenTS and TestFetchStats only include tasks carrying the tag", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		require.NoError(t, AddTaskTag(testDB, 2, "acme"))
+		beginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+
+		// WHEN
+		tlEntries, _, err := FetchTLEntriesBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme"}, 100)
+		require.NoError(t, err)
+		statsEntries, err := FetchStatsBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme"}, 100)
+		require.NoError(t, err)
+		allTimeStatsEntries, err := FetchStats(testDB, TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme"}, 100)
+		require.NoError(t, err)
+
+		// THEN
+		require.Len(t, tlEntries, 1)
+		assert.Equal(t, 2, tlEntries[0].TaskID)
+		require.Len(t, statsEntries, 1)
+		assert.Equal(t, 2, statsEntries[0].TaskID)
+		require.Len(t, allTimeStatsEntries, 1)
+		assert.Equal(t, 2, allTimeStatsEntries[0].TaskID)
+	})
+
+	t.Run("fetching report, stats, and task log entries for a task only includes its entries", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		seedDB(t, testDB, getTestData(referenceTS))
+		beginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
+		taskID := 1
+		unknownTaskID := 999
+
+		// WHEN
+		reportEntries, _, err := FetchReportBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &taskID}, 100)
+		require.NoError(t, err)
+		statsEntries, err := FetchStatsBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &taskID}, 100)
+		require.NoError(t, err)
+		tlEntries, _, err := FetchTLEntriesBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &taskID}, 100)
+		require.NoError(t, err)
+		unknownTaskEntries, _, err := FetchReportBetweenTS(testDB, beginTS, referenceTS, TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &unknownTaskID}, 100)
+		require.NoError(t, err)
+
+		// THEN
+		require.Len(t, reportEntries, 1)
+		assert.Equal(t, taskID, reportEntries[0].TaskID)
+		require.Len(t, statsEntries, 1)
+		assert.Equal(t, taskID, statsEntries[0].TaskID)
+		assert.Equal(t, reportEntries[0].SecsSpent, statsEntries[0].SecsSpent)
+		require.NotEmpty(t, tlEntries)
+		for _, entry := range tlEntries {
+			assert.Equal(t, taskID, entry.TaskID)
+		}
+		assert.Len(t, tlEntries, reportEntries[0].NumEntries)
+		assert.Empty(t, unknownTaskEntries)
 	})
 
-	t.Run("TestFetchReportBetweenTS for inactive tasks tasks", func(t *testing.T) {
+	t.Run("TestFetchDailyTotalsBetweenTS sums time per day", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-
-		taskID := 1
-		numSeconds := 60 * 90
-		tlEndTS := referenceTS.Add(time.Hour * 2)
-		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
-		require.NoError(t, err, "failed to insert task log")
-
-		err = UpdateTaskActiveStatus(testDB, 1, false)
-		require.NoError(t, err, "failed to make task inactive")
+		day1 := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.Local)
+		task1ID, err := InsertTask(testDB, "task 1")
+		require.NoError(t, err)
+		task2ID, err := InsertTask(testDB, "task 2")
+		require.NoError(t, err)
+		for _, tl := range []struct {
+			taskID     int
+			begin, end time.Time
+		}{
+			{task1ID, day1.Add(9 * time.Hour), day1.Add(11 * time.Hour)},
+			{task2ID, day1.Add(13 * time.Hour), day1.Add(14 * time.Hour)},
+			// day 2 has no entries; this one ends on day 3, so it counts there
+			{task1ID, day1.Add(47 * time.Hour), day1.Add(49 * time.Hour)},
+			{task2ID, day1.Add(24*3*time.Hour + 10*time.Hour), day1.Add(24*3*time.Hour + 10*time.Hour + 30*time.Minute)},
+		} {
+			_, err := InsertManualTL(testDB, tl.taskID, tl.begin, tl.end, nil, nil, nil, false)
+			require.NoError(t, err)
+		}
 
 		// WHEN
-		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		totals, err := FetchDailyTotalsBetweenTS(testDB, day1, day1.AddDate(0, 0, 5), TLFilter{TaskStatus: types.TaskStatusAny})
 
 		// THEN
-		require.NoError(t, err, "failed to fetch report entries")
-
-		require.Len(t, entries, 1)
-		assert.Equal(t, 1, entries[0].TaskID)
-		assert.Equal(t, 2, entries[0].NumEntries)
-		assert.Equal(t, 5*secsInOneHour, entries[0].SecsSpent)
+		require.NoError(t, err)
+		require.Len(t, totals, 5)
+		expectedSecs := []int{3 * secsInOneHour, 0, 2 * secsInOneHour, secsInOneHour / 2, 0}
+		for i, total := range totals {
+			assert.True(t, day1.AddDate(0, 0, i).Equal(total.Day), "unexpected day at index %d", i)
+			assert.Equal(t, expectedSecs[i], total.SecsSpent, "unexpected total at index %d", i)
+		}
 	})
 
-	err = testDB.Close()
-	require.NoErrorf(t, err, "error closing DB: %v", err)
-}
+	t.Run("TestFetchDailyTotalsBetweenTS only buckets entries for tasks matching the status", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
 
-func TestExtendedRepository(t *testing.T) {
-	testDB, err := sql.Open("sqlite", ":memory:")
-	require.NoErrorf(t, err, "error opening DB: %v", err)
+		// GIVEN
+		day1 := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.Local)
+		activeTaskID, err := InsertTask(testDB, "active task")
+		require.NoError(t, err)
+		inactiveTaskID, err := InsertTask(testDB, "inactive task")
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, activeTaskID, day1.Add(9*time.Hour), day1.Add(10*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, inactiveTaskID, day1.Add(11*time.Hour), day1.Add(13*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		_, err = InsertManualTL(testDB, inactiveTaskID, day1.Add(33*time.Hour), day1.Add(34*time.Hour), nil, nil, nil, false)
+		require.NoError(t, err)
+		require.NoError(t, UpdateTaskActiveStatus(testDB, inactiveTaskID, false))
 
-	err = InitDB(testDB)
-	require.NoErrorf(t, err, "error initializing DB: %v", err)
+		// WHEN
+		activeTotals, activeErr := FetchDailyTotalsBetweenTS(testDB, day1, day1.AddDate(0, 0, 2), TLFilter{TaskStatus: types.TaskStatusActive})
+		inactiveTotals, inactiveErr := FetchDailyTotalsBetweenTS(testDB, day1, day1.AddDate(0, 0, 2), TLFilter{TaskStatus: types.TaskStatusInactive})
 
-	err = UpgradeDB(testDB, 1)
-	require.NoErrorf(t, err, "error upgrading DB: %v", err)
+		// THEN
+		require.NoError(t, activeErr)
+		require.Len(t, activeTotals, 2)
+		assert.Equal(t, secsInOneHour, activeTotals[0].SecsSpent)
+		assert.Equal(t, 0, activeTotals[1].SecsSpent)
+
+		require.NoError(t, inactiveErr)
+		require.Len(t, inactiveTotals, 2)
+		assert.Equal(t, 2*secsInOneHour, inactiveTotals[0].SecsSpent)
+		assert.Equal(t, secsInOneHour, inactiveTotals[1].SecsSpent)
+	})
 
-	t.Run("TestUpdateTask changes summary", func(t *testing.T) {
+	t.Run("TestFetchStatsGrouped buckets entries by week", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-		taskID := 1
-		newSummary := "updated task summary"
+		// 2024/09/01 is a Sunday, so the range spans two weeks
+		sunday := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.Local)
+		monday := sunday.AddDate(0, 0, 1)
+		task1ID, err := InsertTask(testDB, "task 1")
+		require.NoError(t, err)
+		task2ID, err := InsertTask(testDB, "task 2")
+		require.NoError(t, err)
+		for _, tl := range []struct {
+			taskID     int
+			begin, end time.Time
+		}{
+			{task1ID, sunday.Add(9 * time.Hour), sunday.Add(10 * time.Hour)},
+			// begins on Sunday, but ends on Monday, so it counts towards the second week
+			{task1ID, sunday.Add(23 * time.Hour), monday.Add(time.Hour)},
+			{task1ID, monday.Add(9 * time.Hour), monday.Add(12 * time.Hour)},
+			{task2ID, monday.Add(13 * time.Hour), monday.Add(14 * time.Hour)},
+		} {
+			_, err := InsertManualTL(testDB, tl.taskID, tl.begin, tl.end, nil, nil, nil, false)
+			require.NoError(t, err)
+		}
 
 		// WHEN
-		err := UpdateTask(testDB, taskID, newSummary)
+		entries, err := FetchStatsGrouped(testDB, sunday, sunday.AddDate(0, 0, 3), TLFilter{TaskStatus: types.TaskStatusAny}, types.GranularityWeek)
 
 		// THEN
-		require.NoError(t, err, "failed to update task")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
 
-		task, fetchErr := fetchTaskByID(testDB, taskID)
-		require.NoError(t, fetchErr, "failed to fetch task")
+		assert.True(t, sunday.AddDate(0, 0, -6).Equal(entries[0].PeriodStart))
+		assert.Equal(t, task1ID, entries[0].TaskID)
+		assert.Equal(t, 1, entries[0].NumEntries)
+		assert.Equal(t, secsInOneHour, entries[0].SecsSpent)
 
-		assert.Equal(t, newSummary, task.Summary)
-		assert.Equal(t, taskID, task.ID)
+		assert.True(t, monday.Equal(entries[1].PeriodStart))
+		assert.Equal(t, task1ID, entries[1].TaskID)
+		assert.Equal(t, 2, entries[1].NumEntries)
+		assert.Equal(t, 5*secsInOneHour, entries[1].SecsSpent)
+
+		assert.True(t, monday.Equal(entries[2].PeriodStart))
+		assert.Equal(t, task2ID, entries[2].TaskID)
+		assert.Equal(t, "task 2", entries[2].TaskSummary)
+		assert.Equal(t, secsInOneHour, entries[2].SecsSpent)
 	})
 
-	t.Run("TestFetchTasks returns active tasks", func(t *testing.T) {
+	t.Run("TestFetchStatsByCategory sums up entries for each category", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		day := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.Local)
+		task1ID, err := InsertTask(testDB, "task 1")
+		require.NoError(t, err)
+		task2ID, err := InsertTask(testDB, "task 2")
+		require.NoError(t, err)
+		coding := "coding"
+		meeting := "meeting"
+		for _, tl := range []struct {
+			taskID     int
+			begin, end time.Time
+			category   *string
+		}{
+			{task1ID, day.Add(9 * time.Hour), day.Add(12 * time.Hour), &coding},
+			{task2ID, day.Add(13 * time.Hour), day.Add(14 * time.Hour), &coding},
+			{task1ID, day.Add(14 * time.Hour), day.Add(16 * time.Hour), &meeting},
+			{task2ID, day.Add(16 * time.Hour), day.Add(17 * time.Hour), nil},
+			{task2ID, day.Add(17 * time.Hour), day.Add(17*time.Hour + 30*time.Minute), nil},
+			// outside the range
+			{task1ID, day.AddDate(0, 0, 1).Add(9 * time.Hour), day.AddDate(0, 0, 1).Add(10 * time.Hour), &meeting},
+		} {
+			_, err := InsertManualTL(testDB, tl.taskID, tl.begin, tl.end, nil, tl.category, nil, false)
+			require.NoError(t, err)
+		}
 
 		// WHEN
-		tasks, err := FetchTasks(testDB, true, 100)
+		entries, err := FetchStatsByCategory(testDB, day, day.AddDate(0, 0, 1), TLFilter{TaskStatus: types.TaskStatusAny})
 
 		// THEN
-		require.NoError(t, err, "failed to fetch active tasks")
-		require.Len(t, tasks, 2)
-		for _, task := range tasks {
-			assert.True(t, task.Active, "expected only active tasks")
-		}
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		require.NotNil(t, entries[0].Category)
+		assert.Equal(t, coding, *entries[0].Category)
+		assert.Equal(t, 2, entries[0].NumEntries)
+		assert.Equal(t, 4*secsInOneHour, entries[0].SecsSpent)
+
+		require.NotNil(t, entries[1].Category)
+		assert.Equal(t, meeting, *entries[1].Category)
+		assert.Equal(t, 1, entries[1].NumEntries)
+		assert.Equal(t, 2*secsInOneHour, entries[1].SecsSpent)
+
+		assert.Nil(t, entries[2].Category)
+		assert.Equal(t, 2, entries[2].NumEntries)
+		assert.Equal(t, secsInOneHour+secsInOneHour/2, entries[2].SecsSpent)
+
+		// WHEN
+		entries, err = FetchStatsByCategory(testDB, day, day.AddDate(0, 0, 1), TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &task2ID})
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Nil(t, entries[0].Category)
+		require.NotNil(t, entries[1].Category)
+		assert.Equal(t, coding, *entries[1].Category)
 	})
 
-	t.Run("TestFetchTasks returns inactive tasks", func(t *testing.T) {
+	t.Run("TestFetchStatsGrouped buckets entries by month", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-
-		err := UpdateTaskActiveStatus(testDB, 1, false)
-		require.NoError(t, err, "failed to deactivate task")
+		septemberStart := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.Local)
+		octoberStart := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "task 1")
+		require.NoError(t, err)
+		for _, tl := range []struct {
+			begin, end time.Time
+		}{
+			{septemberStart.Add(9 * time.Hour), septemberStart.Add(11 * time.Hour)},
+			// begins on the last day of September, but ends in October
+			{octoberStart.Add(-time.Hour), octoberStart.Add(time.Hour)},
+			{octoberStart.Add(9 * time.Hour), octoberStart.Add(10 * time.Hour)},
+		} {
+			_, err := InsertManualTL(testDB, taskID, tl.begin, tl.end, nil, nil, nil, false)
+			require.NoError(t, err)
+		}
 
 		// WHEN
-		tasks, err := FetchTasks(testDB, false, 100)
+		entries, err := FetchStatsGrouped(testDB, septemberStart, octoberStart.AddDate(0, 1, 0), TLFilter{TaskStatus: types.TaskStatusAny}, types.GranularityMonth)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch inactive tasks")
-		require.Len(t, tasks, 1)
-		assert.Equal(t, 1, tasks[0].ID)
-		assert.False(t, tasks[0].Active)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		assert.True(t, septemberStart.Equal(entries[0].PeriodStart))
+		assert.Equal(t, 1, entries[0].NumEntries)
+		assert.Equal(t, 2*secsInOneHour, entries[0].SecsSpent)
+
+		assert.True(t, octoberStart.Equal(entries[1].PeriodStart))
+		assert.Equal(t, 2, entries[1].NumEntries)
+		assert.Equal(t, 3*secsInOneHour, entries[1].SecsSpent)
 	})
 
-	t.Run("TestFetchTasks respects limit", func(t *testing.T) {
+	t.Run("TestRecalculateTaskSecsSpent repairs a desynced task", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		seedDB(t, testDB, getTestData(time.Now()))
+		_, err := testDB.Exec(`UPDATE task SET secs_spent = 1 WHERE id IN (1, 2)`)
+		require.NoError(t, err)
 
 		// WHEN
-		tasks, err := FetchTasks(testDB, true, 1)
+		secsSpent, err := RecalculateTaskSecsSpent(testDB, 1)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch tasks with limit")
-		require.Len(t, tasks, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 5*secsInOneHour, secsSpent)
+		task, err := FetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 5*secsInOneHour, task.SecsSpent)
+
+		otherTask, err := FetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, otherTask.SecsSpent)
 	})
 
-	t.Run("TestFetchTLEntries returns entries in descending order", func(t *testing.T) {
+	t.Run("TestRecalculateTaskSecsSpent ignores the active task log", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		seedDB(t, testDB, getTestData(time.Now()))
+		_, err := InsertNewTL(testDB, 2, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		_, err = testDB.Exec(`UPDATE task SET secs_spent = 0 WHERE id = 2`)
+		require.NoError(t, err)
 
 		// WHEN
-		entries, err := FetchTLEntries(testDB, true, 100)
+		secsSpent, err := RecalculateTaskSecsSpent(testDB, 2)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch task log entries")
-		require.Len(t, entries, 3)
-		// Descending: latest end_ts first
-		assert.True(t, entries[0].EndTS.After(entries[1].EndTS) || entries[0].EndTS.Equal(entries[1].EndTS))
+		require.NoError(t, err)
+		assert.Equal(t, 4*secsInOneHour, secsSpent)
 	})
 
-	t.Run("TestFetchTLEntries returns entries in ascending order", func(t *testing.T) {
+	t.Run("TestRecalculateTaskSecsSpent returns error when task not found", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		seedDB(t, testDB, getTestData(time.Now()))
 
 		// WHEN
-		entries, err := FetchTLEntries(testDB, false, 100)
+		_, err := RecalculateTaskSecsSpent(testDB, 999)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch task log entries")
-		require.Len(t, entries, 3)
-		// Ascending: earliest end_ts first
-		assert.True(t, entries[0].EndTS.Before(entries[1].EndTS) || entries[0].EndTS.Equal(entries[1].EndTS))
+		assert.ErrorIs(t, err, ErrTaskNotFound)
 	})
 
-	t.Run("TestFetchTLEntries respects limit", func(t *testing.T) {
+	t.Run("TestRecalculateAllTasksSecsSpent repairs only desynced tasks", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		seedDB(t, testDB, getTestData(time.Now()))
+		_, err := testDB.Exec(`UPDATE task SET secs_spent = 7 WHERE id = 2`)
+		require.NoError(t, err)
 
 		// WHEN
-		entries, err := FetchTLEntries(testDB, true, 2)
+		numRepaired, err := RecalculateAllTasksSecsSpent(testDB)
 
 		// THEN
-		require.NoError(t, err, "failed to fetch task log entries with limit")
-		require.Len(t, entries, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, numRepaired)
+		task1, err := FetchTaskByID(testDB, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 5*secsInOneHour, task1.SecsSpent)
+		task2, err := FetchTaskByID(testDB, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4*secsInOneHour, task2.SecsSpent)
 	})
 
-	t.Run("TestDeleteActiveTL removes the open log entry", func(t *testing.T) {
+	err = testDB.Close()
+	require.NoErrorf(t, err, "error closing extended repository DB: %v", err)
+}
+
+func TestUpdateTaskCreatedAt(t *testing.T) {
+	testDB, err := sql.Open("sqlite", ":memory:")
+	require.NoErrorf(t, err, "error opening DB: %v", err)
+
+	err = InitDB(testDB)
+	require.NoErrorf(t, err, "error initializing DB: %v", err)
+
+	err = UpgradeDB(testDB, 1)
+	require.NoErrorf(t, err, "error upgrading DB: %v", err)
+
+	t.Run("created_at round-trips in UTC", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-		taskID := 1
-		beginTS := time.Now().Add(-time.Hour)
-		_, insertErr := InsertNewTL(testDB, taskID, beginTS)
-		require.NoError(t, insertErr, "failed to insert active task log")
-
-		// verify the active TL exists before deletion
-		activeDetails, err := FetchActiveTaskDetails(testDB)
-		require.NoError(t, err, "failed to fetch active task details")
-		assert.Equal(t, taskID, activeDetails.TaskID)
+		taskID, err := InsertTask(testDB, "migrated task")
+		require.NoError(t, err)
+		createdAt := time.Date(2023, 3, 15, 9, 30, 0, 0, time.FixedZone("UTC+5:30", 5*60*60+30*60))
 
 		// WHEN
-		err = DeleteActiveTL(testDB)
+		err = UpdateTaskCreatedAt(testDB, taskID, createdAt)
 
 		// THEN
-		require.NoError(t, err, "failed to delete active task log")
+		require.NoError(t, err)
 
-		// verify no active TL remains
-		var count int
-		countErr := testDB.QueryRow("SELECT COUNT(*) FROM task_log WHERE active=true").Scan(&count)
-		require.NoError(t, countErr, "failed to count active task logs")
-		assert.Equal(t, 0, count)
+		var stored time.Time
+		err = testDB.QueryRow("SELECT created_at FROM task WHERE id = ?", taskID).Scan(&stored)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 3, 15, 4, 0, 0, 0, time.UTC), stored.UTC())
+
+		task, err := FetchTaskByID(testDB, taskID)
+		require.NoError(t, err)
+		assert.True(t, createdAt.Equal(task.CreatedAt))
+		assert.Equal(t, time.Local, task.CreatedAt.Location())
+
+		tasks, err := FetchTasks(testDB, true, 10)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.True(t, createdAt.Equal(tasks[0].CreatedAt))
+		assert.Equal(t, time.Local, tasks[0].CreatedAt.Location())
 	})
 
-	t.Run("TestDeleteActiveTL succeeds when no active log exists", func(t *testing.T) {
+	t.Run("updating the summary and created_at together", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
-		// GIVEN - no active TL
+		// GIVEN
+		taskID, err := InsertTask(testDB, "old summary")
+		require.NoError(t, err)
+		createdAt := time.Date(2023, 3, 15, 9, 30, 0, 0, time.Local)
 
 		// WHEN
-		err := DeleteActiveTL(testDB)
+		err = UpdateTaskWithCreatedAt(testDB, taskID, "new summary", &createdAt)
 
 		// THEN
-		require.NoError(t, err, "should not error when no active TL exists")
+		require.NoError(t, err)
+		task, err := FetchTaskByID(testDB, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, "new summary", task.Summary)
+		assert.True(t, createdAt.Equal(task.CreatedAt))
 	})
 
-	t.Run("TestMoveTaskLog moves entry and updates secs_spent", func(t *testing.T) {
+	t.Run("a created_at in the future changes neither", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-
-		tlID := 1 // belongs to task 1, secsSpent=2h
-		oldTaskID := 1
-		newTaskID := 2
-		secsToMove := 2 * secsInOneHour
-
-		taskOneBefore, err := fetchTaskByID(testDB, oldTaskID)
-		require.NoError(t, err, "failed to fetch old task")
-		taskTwoBefore, err := fetchTaskByID(testDB, newTaskID)
-		require.NoError(t, err, "failed to fetch new task")
+		taskID, err := InsertTask(testDB, "old summary")
+		require.NoError(t, err)
+		taskBefore, err := FetchTaskByID(testDB, taskID)
+		require.NoError(t, err)
+		createdAt := time.Now().Add(time.Hour)
 
 		// WHEN
-		err = MoveTaskLog(testDB, tlID, oldTaskID, newTaskID, secsToMove)
+		err = UpdateTaskWithCreatedAt(testDB, taskID, "new summary", &createdAt)
 
 		// THEN
-		require.NoError(t, err, "failed to move task log")
+		require.ErrorIs(t, err, ErrTaskCreatedAtInFuture)
+		task, err := FetchTaskByID(testDB, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, "old summary", task.Summary)
+		assert.True(t, taskBefore.CreatedAt.Equal(task.CreatedAt))
+	})
+}
 
-		// verify task_log entry now belongs to newTaskID
-		tl, fetchErr := fetchTLByID(testDB, tlID)
-		require.NoError(t, fetchErr, "failed to fetch moved task log")
-		assert.Equal(t, newTaskID, tl.TaskID)
+func TestFetchMostRecentlyArchivedTask(t *testing.T) {
+	testDB, err := sql.Open("sqlite", ":memory:")
+	require.NoErrorf(t, err, "error opening DB: %v", err)
 
-		// verify secs_spent transferred between tasks
-		taskOneAfter, err := fetchTaskByID(testDB, oldTaskID)
-		require.NoError(t, err, "failed to fetch old task after move")
-		taskTwoAfter, err := fetchTaskByID(testDB, newTaskID)
-		require.NoError(t, err, "failed to fetch new task after move")
+	err = InitDB(testDB)
+	require.NoErrorf(t, err, "error initializing DB: %v", err)
 
-		assert.Equal(t, taskOneBefore.SecsSpent-secsToMove, taskOneAfter.SecsSpent)
-		assert.Equal(t, taskTwoBefore.SecsSpent+secsToMove, taskTwoAfter.SecsSpent)
-	})
+	err = UpgradeDB(testDB, 1)
+	require.NoErrorf(t, err, "error upgrading DB: %v", err)
 
-	t.Run("TestMoveTaskLog same task is a no-op", func(t *testing.T) {
+	t.Run("returns nil when there are no inactive tasks", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
-		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
-		taskID := 1
-
-		taskBefore, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task")
+		_, err := InsertTask(testDB, "active task")
+		require.NoError(t, err)
 
 		// WHEN
-		err = MoveTaskLog(testDB, 1, taskID, taskID, secsInOneHour)
+		task, err := FetchMostRecentlyArchivedTask(testDB)
 
 		// THEN
-		require.NoError(t, err, "same-task move should not error")
-
-		taskAfter, err := fetchTaskByID(testDB, taskID)
-		require.NoError(t, err, "failed to fetch task after no-op move")
-
-		assert.Equal(t, taskBefore.SecsSpent, taskAfter.SecsSpent)
+		require.NoError(t, err)
+		assert.Nil(t, task)
 	})
 
-	t.Run("TestMoveTaskLog returns error when task log not found", func(t *testing.T) {
+	t.Run("returns the inactive task updated last", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
 		// GIVEN
 		referenceTS := time.Now()
-		seedData := getTestData(referenceTS)
-		seedDB(t, testDB, seedData)
+		var ids []int
+		for _, summary := range []string{"archived earlier", "archived last", "still active"} {
+			id, err := InsertTask(testDB, summary)
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+		_, err := testDB.Exec("UPDATE task SET active = false, updated_at = ? WHERE id = ?", referenceTS.Add(-2*time.Hour).UTC(), ids[0])
+		require.NoError(t, err)
+		_, err = testDB.Exec("UPDATE task SET active = false, updated_at = ? WHERE id = ?", referenceTS.Add(-time.Hour).UTC(), ids[1])
+		require.NoError(t, err)
 
-		// WHEN - use a non-existent TL ID
-		err := MoveTaskLog(testDB, 9999, 1, 2, secsInOneHour)
+		// WHEN
+		task, err := FetchMostRecentlyArchivedTask(testDB)
 
 		// THEN
-		require.ErrorIs(t, err, ErrTaskLogNotFound)
+		require.NoError(t, err)
+		require.NotNil(t, task)
+		assert.Equal(t, ids[1], task.ID)
+		assert.Equal(t, "archived last", task.Summary)
+		assert.False(t, task.Active)
 	})
-
-	err = testDB.Close()
-	require.NoErrorf(t, err, "error closing extended repository DB: %v", err)
 }
 
 func TestArchiveStaleTasks(t *testing.T) {
@@ -1097,13 +3416,13 @@ func TestArchiveStaleTasks(t *testing.T) {
 		recentLogEndTS := referenceTS.Add(time.Hour * -2)
 		recentLogBeginTS := recentLogEndTS.Add(time.Hour * -1)
 		recentComment := "recent log entry"
-		_, err = InsertManualTL(testDB, 1, recentLogBeginTS, recentLogEndTS, &recentComment)
+		_, err = InsertManualTL(testDB, 1, recentLogBeginTS, recentLogEndTS, &recentComment, nil, nil, false)
 		require.NoError(t, err, "failed to insert recent task log")
 
 		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
 
 		// WHEN
-		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo)
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, false)
 
 		// THEN
 		require.NoError(t, err, "failed to archive stale tasks")
@@ -1131,7 +3450,7 @@ func TestArchiveStaleTasks(t *testing.T) {
 		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
 
 		// WHEN
-		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo)
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, false)
 
 		// THEN
 		require.NoError(t, err, "failed to archive stale tasks")
@@ -1143,6 +3462,37 @@ func TestArchiveStaleTasks(t *testing.T) {
 		assert.False(t, task.Active, "task with no logs should be archived")
 	})
 
+	t.Run("TestArchiveStaleTasks skips recently created tasks with no log entries if asked to", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN - a new task, and one created before the cutoff, both with no log entries
+		referenceTS := time.Now()
+		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
+
+		newTaskID, err := InsertTask(testDB, "new task with no logs")
+		require.NoError(t, err, "failed to insert task")
+
+		oldTaskID, err := InsertTask(testDB, "old task with no logs")
+		require.NoError(t, err, "failed to insert task")
+		_, err = testDB.Exec("UPDATE task SET created_at = ? WHERE id = ?", referenceTS.AddDate(0, 0, -21).UTC(), oldTaskID)
+		require.NoError(t, err, "failed to backdate task")
+
+		// WHEN
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, true)
+
+		// THEN
+		require.NoError(t, err, "failed to archive stale tasks")
+		assert.Equal(t, 1, archivedCount, "expected only the old task to be archived")
+
+		newTask, err := fetchTaskByID(testDB, newTaskID)
+		require.NoError(t, err, "failed to fetch new task")
+		assert.True(t, newTask.Active, "recently created task should be exempt")
+
+		oldTask, err := fetchTaskByID(testDB, oldTaskID)
+		require.NoError(t, err, "failed to fetch old task")
+		assert.False(t, oldTask.Active, "task created before the cutoff should be archived")
+	})
+
 	t.Run("TestArchiveStaleTasks does not archive tasks with recent log entries", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -1154,7 +3504,7 @@ func TestArchiveStaleTasks(t *testing.T) {
 		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
 
 		// WHEN
-		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo)
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, false)
 
 		// THEN
 		require.NoError(t, err, "failed to archive stale tasks")
@@ -1185,7 +3535,7 @@ func TestArchiveStaleTasks(t *testing.T) {
 		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
 
 		// WHEN
-		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo)
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, false)
 
 		// THEN
 		require.NoError(t, err, "failed to archive stale tasks")
@@ -1216,7 +3566,7 @@ func TestArchiveStaleTasks(t *testing.T) {
 		twoWeeksAgo := referenceTS.AddDate(0, 0, -14)
 
 		// WHEN
-		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo)
+		archivedCount, err := ArchiveStaleTasks(testDB, twoWeeksAgo, false)
 
 		// THEN
 		require.NoError(t, err, "failed to archive stale tasks")
@@ -1236,13 +3586,19 @@ func cleanupDB(t *testing.T, testDB *sql.DB) {
 	t.Helper()
 
 	var err error
-	for _, tbl := range []string{"task_log", "task"} {
+	for _, tbl := range []string{"task_tag", "task_log", "task"} {
 		_, err = testDB.Exec(fmt.Sprintf("DELETE FROM %s", tbl))
 		require.NoErrorf(t, err, "failed to clean up table %q: %v", tbl, err)
 
 		_, err := testDB.Exec("DELETE FROM sqlite_sequence WHERE name=?;", tbl)
 		require.NoErrorf(t, err, "failed to reset auto increment for table %q: %v", tbl, err)
 	}
+
+	_, err = testDB.Exec("UPDATE settings SET weekly_goal_secs = 0;")
+	require.NoErrorf(t, err, "failed to reset settings: %v", err)
+
+	_, err = testDB.Exec("DELETE FROM last_period;")
+	require.NoErrorf(t, err, "failed to reset last periods: %v", err)
 }
 
 type testData struct {
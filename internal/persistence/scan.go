@@ -17,6 +17,7 @@ func scanTask(row *sql.Rows) (types.Task, error) {
 		&entry.CreatedAt,
 		&entry.UpdatedAt,
 		&entry.Active,
+		&entry.Color,
 	)
 	if err != nil {
 		return types.Task{}, err
@@ -30,6 +31,7 @@ func scanTask(row *sql.Rows) (types.Task, error) {
 // It also converts time fields to local timezone.
 func scanTaskLogEntry(row *sql.Rows) (types.TaskLogEntry, error) {
 	var entry types.TaskLogEntry
+	var tags *string
 	err := row.Scan(
 		&entry.ID,
 		&entry.TaskID,
@@ -38,10 +40,15 @@ func scanTaskLogEntry(row *sql.Rows) (types.TaskLogEntry, error) {
 		&entry.EndTS,
 		&entry.SecsSpent,
 		&entry.Comment,
+		&tags,
+		&entry.Category,
 	)
 	if err != nil {
 		return types.TaskLogEntry{}, err
 	}
+	if tags != nil {
+		entry.Tags = types.ParseLogTags(*tags)
+	}
 	entry.BeginTS = entry.BeginTS.Local()
 	entry.EndTS = entry.EndTS.Local()
 	return entry, nil
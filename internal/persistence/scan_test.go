@@ -29,7 +29,7 @@ func TestScanTask(t *testing.T) {
 	seedDB(t, db, getTestData(referenceTS))
 
 	rows, err := db.Query(`
-SELECT id, summary, secs_spent, created_at, updated_at, active
+SELECT id, summary, secs_spent, created_at, updated_at, active, color
 FROM task
 WHERE id = 1`)
 	require.NoError(t, err)
@@ -44,6 +44,7 @@ WHERE id = 1`)
 	assert.Equal(t, "seeded task 1", entry.Summary)
 	assert.Equal(t, 5*secsInOneHour, entry.SecsSpent)
 	assert.True(t, entry.Active)
+	assert.Nil(t, entry.Color)
 	createdAt := referenceTS.UTC().Add(time.Hour * 24 * 7 * -1)
 	updatedAt := createdAt.Add(time.Hour * 9)
 	// Timezone conversion: Local() must be applied
@@ -61,7 +62,7 @@ func TestScanTaskLogEntry(t *testing.T) {
 	seedDB(t, db, getTestData(referenceTS))
 
 	rows, err := db.Query(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.id = 1`)
@@ -101,7 +102,7 @@ func TestScanTaskLogEntry_NilComment(t *testing.T) {
 	require.NoError(t, err)
 
 	rows, err := db.Query(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.id = 1`)
@@ -113,6 +114,8 @@ WHERE tl.id = 1`)
 	entry, err := scanTaskLogEntry(rows)
 	require.NoError(t, err)
 	assert.Nil(t, entry.Comment)
+	assert.Nil(t, entry.Tags)
+	assert.Nil(t, entry.Category)
 }
 
 // TestScanTaskReportEntry verifies that scanTaskReportEntry correctly reads an
@@ -153,7 +156,7 @@ func TestCollectTasks(t *testing.T) {
 	seedDB(t, db, getTestData(referenceTS))
 
 	rows, err := db.Query(`
-SELECT id, summary, secs_spent, created_at, updated_at, active
+SELECT id, summary, secs_spent, created_at, updated_at, active, color
 FROM task
 ORDER BY id ASC`)
 	require.NoError(t, err)
@@ -192,7 +195,7 @@ func TestCollectTaskLogEntries(t *testing.T) {
 	seedDB(t, db, getTestData(referenceTS))
 
 	rows, err := db.Query(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.active = false
@@ -216,7 +219,7 @@ func TestCollectTaskLogEntries_Empty(t *testing.T) {
 	defer db.Close()
 
 	rows, err := db.Query(`
-SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment, tl.tags, tl.category
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.active = false`)
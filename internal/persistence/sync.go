@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +16,10 @@ import (
 
 var ErrConflictingActiveSyncTaskLog = errors.New("sync would create multiple active task logs")
 
+// syncTagSeparator joins a task's tags when fetching them alongside the task;
+// unlike a comma, it can't end up in a tag typed in by the user.
+const syncTagSeparator = "\x1f"
+
 type sqlScanner interface {
 	Scan(dest ...any) error
 }
@@ -30,10 +36,12 @@ func newSyncID() (string, error) {
 
 func FetchSyncTasks(db *sql.DB) ([]types.SyncTaskRecord, error) {
 	rows, err := db.Query(`
-SELECT id, sync_id, summary, secs_spent, active, created_at, updated_at
+SELECT id, sync_id, summary, secs_spent, active, color, week_reset_day,
+	   (SELECT group_concat(tag, ?) FROM task_tag WHERE task_id = task.id),
+	   created_at, updated_at
 FROM task
 ORDER BY updated_at ASC, id ASC;
-	`)
+	`, syncTagSeparator)
 	if err != nil {
 		return nil, err
 	}
@@ -57,10 +65,12 @@ ORDER BY updated_at ASC, id ASC;
 
 func FetchSyncTaskByID(db *sql.DB, id int) (types.SyncTaskRecord, error) {
 	row := db.QueryRow(`
-SELECT id, sync_id, summary, secs_spent, active, created_at, updated_at
+SELECT id, sync_id, summary, secs_spent, active, color, week_reset_day,
+	   (SELECT group_concat(tag, ?) FROM task_tag WHERE task_id = task.id),
+	   created_at, updated_at
 FROM task
 WHERE id = ?;
-	`, id)
+	`, syncTagSeparator, id)
 
 	return scanSyncTaskRecord(row)
 }
@@ -68,7 +78,8 @@ WHERE id = ?;
 func FetchSyncTaskLogs(db *sql.DB) ([]types.SyncTaskLogRecord, error) {
 	rows, err := db.Query(`
 SELECT tl.id, tl.sync_id, tl.task_id, t.sync_id, tl.begin_ts, tl.end_ts,
-	   tl.secs_spent, tl.comment, tl.active, tl.created_at, tl.updated_at
+	   tl.secs_spent, tl.comment, tl.category, tl.tags, tl.active, tl.created_at,
+	   tl.updated_at
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 ORDER BY tl.updated_at ASC, tl.id ASC;
@@ -97,7 +108,8 @@ ORDER BY tl.updated_at ASC, tl.id ASC;
 func FetchSyncTaskLogByID(db *sql.DB, id int) (types.SyncTaskLogRecord, error) {
 	row := db.QueryRow(`
 SELECT tl.id, tl.sync_id, tl.task_id, t.sync_id, tl.begin_ts, tl.end_ts,
-	   tl.secs_spent, tl.comment, tl.active, tl.created_at, tl.updated_at
+	   tl.secs_spent, tl.comment, tl.category, tl.tags, tl.active, tl.created_at,
+	   tl.updated_at
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.id = ?;
@@ -135,11 +147,20 @@ SET secs_spent = COALESCE((
 func applySyncTask(tx *sql.Tx, incoming types.SyncTaskRecord) error {
 	current, err := fetchSyncTaskBySyncID(tx, incoming.SyncID)
 	if errors.Is(err, sql.ErrNoRows) {
-		_, execErr := tx.Exec(`
-INSERT INTO task (sync_id, summary, secs_spent, active, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?);
-		`, incoming.SyncID, incoming.Summary, incoming.SecsSpent, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC())
-		return execErr
+		res, execErr := tx.Exec(`
+INSERT INTO task (sync_id, summary, secs_spent, active, color, week_reset_day, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+		`, incoming.SyncID, incoming.Summary, incoming.SecsSpent, incoming.Active, incoming.Color, incoming.WeekResetDay, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC())
+		if execErr != nil {
+			return execErr
+		}
+
+		localID, execErr := res.LastInsertId()
+		if execErr != nil {
+			return execErr
+		}
+
+		return replaceSyncTaskTags(tx, int(localID), incoming.Tags)
 	}
 	if err != nil {
 		return err
@@ -151,10 +172,37 @@ VALUES (?, ?, ?, ?, ?, ?);
 
 	_, err = tx.Exec(`
 UPDATE task
-SET summary = ?, secs_spent = ?, active = ?, created_at = ?, updated_at = ?
+SET summary = ?, secs_spent = ?, active = ?, color = ?, week_reset_day = ?, created_at = ?, updated_at = ?
 WHERE sync_id = ?;
-	`, incoming.Summary, incoming.SecsSpent, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC(), incoming.SyncID)
-	return err
+	`, incoming.Summary, incoming.SecsSpent, incoming.Active, incoming.Color, incoming.WeekResetDay, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC(), incoming.SyncID)
+	if err != nil {
+		return err
+	}
+
+	return replaceSyncTaskTags(tx, current.LocalID, incoming.Tags)
+}
+
+// replaceSyncTaskTags makes tags the only tags the task carries.
+func replaceSyncTaskTags(tx *sql.Tx, taskID int, tags []string) error {
+	_, err := tx.Exec(`
+DELETE FROM task_tag
+WHERE task_id = ?;
+	`, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err := tx.Exec(`
+INSERT OR IGNORE INTO task_tag (task_id, tag)
+VALUES (?, ?);
+		`, taskID, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func applySyncTaskLog(tx *sql.Tx, incoming types.SyncTaskLogRecord) error {
@@ -170,9 +218,9 @@ func applySyncTaskLog(tx *sql.Tx, incoming types.SyncTaskLogRecord) error {
 		}
 
 		_, execErr := tx.Exec(`
-INSERT INTO task_log (sync_id, task_id, begin_ts, end_ts, secs_spent, comment, active, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
-		`, incoming.SyncID, taskLocalID, incoming.BeginTS.UTC(), nullableTime(incoming.EndTS), incoming.SecsSpent, incoming.Comment, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC())
+INSERT INTO task_log (sync_id, task_id, begin_ts, end_ts, secs_spent, comment, category, tags, active, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+		`, incoming.SyncID, taskLocalID, incoming.BeginTS.UTC(), nullableTime(incoming.EndTS), incoming.SecsSpent, incoming.Comment, incoming.Category, incoming.Tags, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC())
 		return execErr
 	}
 	if err != nil {
@@ -189,18 +237,20 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 	_, err = tx.Exec(`
 UPDATE task_log
-SET task_id = ?, begin_ts = ?, end_ts = ?, secs_spent = ?, comment = ?, active = ?, created_at = ?, updated_at = ?
+SET task_id = ?, begin_ts = ?, end_ts = ?, secs_spent = ?, comment = ?, category = ?, tags = ?, active = ?, created_at = ?, updated_at = ?
 WHERE sync_id = ?;
-	`, taskLocalID, incoming.BeginTS.UTC(), nullableTime(incoming.EndTS), incoming.SecsSpent, incoming.Comment, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC(), incoming.SyncID)
+	`, taskLocalID, incoming.BeginTS.UTC(), nullableTime(incoming.EndTS), incoming.SecsSpent, incoming.Comment, incoming.Category, incoming.Tags, incoming.Active, incoming.CreatedAt.UTC(), incoming.UpdatedAt.UTC(), incoming.SyncID)
 	return err
 }
 
 func fetchSyncTaskBySyncID(tx *sql.Tx, syncID string) (types.SyncTaskRecord, error) {
 	row := tx.QueryRow(`
-SELECT id, sync_id, summary, secs_spent, active, created_at, updated_at
+SELECT id, sync_id, summary, secs_spent, active, color, week_reset_day,
+	   (SELECT group_concat(tag, ?) FROM task_tag WHERE task_id = task.id),
+	   created_at, updated_at
 FROM task
 WHERE sync_id = ?;
-	`, syncID)
+	`, syncTagSeparator, syncID)
 
 	return scanSyncTaskRecord(row)
 }
@@ -223,7 +273,8 @@ WHERE sync_id = ?;
 func fetchSyncTaskLogBySyncID(tx *sql.Tx, syncID string) (types.SyncTaskLogRecord, error) {
 	row := tx.QueryRow(`
 SELECT tl.id, tl.sync_id, tl.task_id, t.sync_id, tl.begin_ts, tl.end_ts,
-	   tl.secs_spent, tl.comment, tl.active, tl.created_at, tl.updated_at
+	   tl.secs_spent, tl.comment, tl.category, tl.tags, tl.active, tl.created_at,
+	   tl.updated_at
 FROM task_log tl
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.sync_id = ?;
@@ -279,17 +330,28 @@ func shouldReplace(currentUpdatedAt time.Time, incomingUpdatedAt time.Time, curr
 }
 
 func taskConflictKey(record types.SyncTaskRecord) string {
-	return fmt.Sprintf("%s|%t|%d|%s", record.Summary, record.Active, record.SecsSpent, record.CreatedAt.UTC().Format(time.RFC3339Nano))
+	return fmt.Sprintf(
+		"%s|%t|%d|%s|%s|%s|%s",
+		record.Summary,
+		record.Active,
+		record.SecsSpent,
+		normalizeStringPtr(record.Color),
+		formatIntPtr(record.WeekResetDay),
+		strings.Join(record.Tags, syncTagSeparator),
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+	)
 }
 
 func taskLogConflictKey(record types.SyncTaskLogRecord) string {
 	return fmt.Sprintf(
-		"%s|%s|%s|%d|%s|%t|%s|%s",
+		"%s|%s|%s|%d|%s|%s|%s|%t|%s|%s",
 		record.TaskSyncID,
 		record.BeginTS.UTC().Format(time.RFC3339Nano),
 		formatTimePtr(record.EndTS),
 		record.SecsSpent,
 		normalizeStringPtr(record.Comment),
+		normalizeStringPtr(record.Category),
+		normalizeStringPtr(record.Tags),
 		record.Active,
 		record.CreatedAt.UTC().Format(time.RFC3339Nano),
 		record.SyncID,
@@ -303,6 +365,13 @@ func normalizeStringPtr(value *string) string {
 	return strings.TrimSpace(*value)
 }
 
+func formatIntPtr(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
 func formatTimePtr(value *time.Time) string {
 	if value == nil {
 		return ""
@@ -319,17 +388,35 @@ func nullableTime(value *time.Time) any {
 
 func scanSyncTaskRecord(scanner sqlScanner) (types.SyncTaskRecord, error) {
 	var record types.SyncTaskRecord
+	var weekResetDay sql.NullInt64
+	var tags sql.NullString
 	err := scanner.Scan(
 		&record.LocalID,
 		&record.SyncID,
 		&record.Summary,
 		&record.SecsSpent,
 		&record.Active,
+		&record.Color,
+		&weekResetDay,
+		&tags,
 		&record.CreatedAt,
 		&record.UpdatedAt,
 	)
+	if err != nil {
+		return record, err
+	}
 
-	return record, err
+	if weekResetDay.Valid {
+		day := int(weekResetDay.Int64)
+		record.WeekResetDay = &day
+	}
+
+	if tags.Valid {
+		record.Tags = strings.Split(tags.String, syncTagSeparator)
+		sort.Strings(record.Tags)
+	}
+
+	return record, nil
 }
 
 func scanSyncTaskLogRecord(scanner sqlScanner) (types.SyncTaskLogRecord, error) {
@@ -344,6 +431,8 @@ func scanSyncTaskLogRecord(scanner sqlScanner) (types.SyncTaskLogRecord, error)
 		&endTS,
 		&record.SecsSpent,
 		&record.Comment,
+		&record.Category,
+		&record.Tags,
 		&record.Active,
 		&record.CreatedAt,
 		&record.UpdatedAt,
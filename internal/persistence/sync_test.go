@@ -50,7 +50,7 @@ func TestFetchSyncTaskLogByIDPreservesStableIdentityAcrossEdits(t *testing.T) {
 	beginTS := time.Date(2026, time.February, 1, 10, 0, 0, 0, time.UTC)
 	endTS := beginTS.Add(2 * time.Hour)
 	beforeInsert := time.Now().UTC()
-	taskLogID, err := InsertManualTL(db, taskID, beginTS, endTS, &comment)
+	taskLogID, err := InsertManualTL(db, taskID, beginTS, endTS, &comment, nil, nil, false)
 	require.NoError(t, err)
 	afterInsert := time.Now().UTC()
 
@@ -77,7 +77,7 @@ func TestFetchSyncTaskLogByIDPreservesStableIdentityAcrossEdits(t *testing.T) {
 	require.NoError(t, err)
 
 	editedComment := "edited"
-	_, err = EditSavedTL(db, taskLogID, beginTS.Add(-30*time.Minute), endTS, &editedComment)
+	_, err = EditSavedTL(db, taskLogID, beginTS.Add(-30*time.Minute), endTS, &editedComment, nil, nil, false)
 	require.NoError(t, err)
 
 	editedRecord, err := FetchSyncTaskLogByID(db, taskLogID)
@@ -150,3 +150,58 @@ func TestApplySyncBundleBreaksTimestampTiesDeterministically(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "zzz", updatedTask.Summary)
 }
+
+func TestApplySyncBundleCarriesTaskAndTaskLogAttributes(t *testing.T) {
+	source := newTestDB(t)
+	defer source.Close()
+
+	taskID, err := InsertTask(source, "sync task")
+	require.NoError(t, err)
+	require.NoError(t, SetTaskColor(source, taskID, "#ff0000"))
+	day := time.Wednesday
+	require.NoError(t, SetTaskWeekResetDay(source, taskID, &day))
+	require.NoError(t, AddTaskTag(source, taskID, "work"))
+	require.NoError(t, AddTaskTag(source, taskID, "client: a"))
+
+	beginTS := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	category := "meetings"
+	_, err = InsertManualTL(source, taskID, beginTS, beginTS.Add(time.Hour), nil, &category, []string{"standup", "daily"}, false)
+	require.NoError(t, err)
+
+	tasks, err := FetchSyncTasks(source)
+	require.NoError(t, err)
+	taskLogs, err := FetchSyncTaskLogs(source)
+	require.NoError(t, err)
+
+	target := newTestDB(t)
+	defer target.Close()
+
+	require.NoError(t, ApplySyncBundle(target, tasks, taskLogs))
+
+	syncedTasks, err := FetchSyncTasks(target)
+	require.NoError(t, err)
+	require.Len(t, syncedTasks, 1)
+	require.NotNil(t, syncedTasks[0].Color)
+	assert.Equal(t, "#ff0000", *syncedTasks[0].Color)
+	require.NotNil(t, syncedTasks[0].WeekResetDay)
+	assert.Equal(t, int(time.Wednesday), *syncedTasks[0].WeekResetDay)
+	assert.Equal(t, []string{"client: a", "work"}, syncedTasks[0].Tags)
+
+	syncedTaskLogs, err := FetchSyncTaskLogs(target)
+	require.NoError(t, err)
+	require.Len(t, syncedTaskLogs, 1)
+	require.NotNil(t, syncedTaskLogs[0].Category)
+	assert.Equal(t, "meetings", *syncedTaskLogs[0].Category)
+	require.NotNil(t, syncedTaskLogs[0].Tags)
+	assert.Equal(t, *taskLogs[0].Tags, *syncedTaskLogs[0].Tags)
+
+	require.NoError(t, RemoveTaskTag(source, taskID, "work"))
+	tasks, err = FetchSyncTasks(source)
+	require.NoError(t, err)
+	require.NoError(t, ApplySyncBundle(target, tasks, nil))
+
+	syncedTasks, err = FetchSyncTasks(target)
+	require.NoError(t, err)
+	require.Len(t, syncedTasks, 1)
+	assert.Equal(t, []string{"client: a"}, syncedTasks[0].Tags)
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hexCodeRegex = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// IsValidColor reports whether s is a valid hex color code (eg, "#ff0000" or
+// "#f00") or an ANSI 256 color code (eg, "208").
+func IsValidColor(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	if strings.HasPrefix(s, "#") {
+		return hexCodeRegex.MatchString(s)
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return false
+	}
+
+	if i < 0 || i > 255 {
+		return false
+	}
+
+	return true
+}
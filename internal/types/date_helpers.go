@@ -22,6 +22,7 @@ var (
 	errEndDateIsNotAfterStartDate = errors.New("end date is not after start date")
 	errTimePeriodNotValid         = errors.New("time period is not valid")
 	errTimePeriodTooLarge         = errors.New("time period is too large")
+	ErrWeekdayInvalid             = errors.New("weekday is invalid")
 )
 
 func parseDateRange(rangeStr string, now time.Time) (DateRange, error) {
@@ -94,13 +95,33 @@ func GetDateRangeFromPeriod(period string, now time.Time, fullWeek bool, maxDays
 		}
 		end = start.AddDate(0, 0, numDays)
 
-	case "this-month":
+	case "month", "this-month":
 		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 		end = start.AddDate(0, 1, 0)
 		// Get the last day of the current month (0th day of next month = last day of current month)
 		lastDayOfMonth := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
 		numDays = lastDayOfMonth
 
+	case "last-month":
+		start = time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(0, 1, 0)
+		numDays = time.Date(now.Year(), now.Month(), 0, 0, 0, 0, 0, now.Location()).Day()
+
+	case "this-quarter":
+		start = startOfQuarter(now)
+		end = start.AddDate(0, 3, 0)
+		numDays = numDaysBetween(start, end)
+
+	case "last-quarter":
+		end = startOfQuarter(now)
+		start = end.AddDate(0, -3, 0)
+		numDays = numDaysBetween(start, end)
+
+	case "this-year":
+		start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(1, 0, 0)
+		numDays = numDaysBetween(start, end)
+
 	default:
 		var err error
 
@@ -128,6 +149,48 @@ func GetDateRangeFromPeriod(period string, now time.Time, fullWeek bool, maxDays
 		}
 	}
 
+	if maxDaysAllowed != nil && numDays > *maxDaysAllowed {
+		return DateRange{}, fmt.Errorf("%w: maximum number of days allowed (both inclusive): %d", errTimePeriodTooLarge, *maxDaysAllowed)
+	}
+
+	return DateRange{
+		Start:   start,
+		End:     end,
+		NumDays: numDays,
+	}, nil
+}
+
+// GetDateRangeFromSinceUntil returns the range from since up to until, each of
+// which can be a date (eg. "2024/06/08") or a date and a time (eg.
+// "2024/06/08 14:30"). A date on its own is inclusive for until, ie. the range
+// runs till the end of that day. An empty until means now.
+func GetDateRangeFromSinceUntil(since, until string, now time.Time, maxDaysAllowed *int) (DateRange, error) {
+	start, _, err := parseDateOrTime(since)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("%w: %s", errStartDateIncorrect, err.Error())
+	}
+
+	end := now
+	if strings.TrimSpace(until) != "" {
+		var dateOnly bool
+		end, dateOnly, err = parseDateOrTime(until)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("%w: %s", errEndDateIncorrect, err.Error())
+		}
+		if dateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+	}
+
+	if !end.After(start) {
+		return DateRange{}, errEndDateIsNotAfterStartDate
+	}
+
+	numDays := numDaysBetween(start, end.Add(-time.Nanosecond)) + 1
+	if maxDaysAllowed != nil && numDays > *maxDaysAllowed {
+		return DateRange{}, fmt.Errorf("%w: maximum number of days allowed (both inclusive): %d", errTimePeriodTooLarge, *maxDaysAllowed)
+	}
+
 	return DateRange{
 		Start:   start,
 		End:     end,
@@ -135,6 +198,37 @@ func GetDateRangeFromPeriod(period string, now time.Time, fullWeek bool, maxDays
 	}, nil
 }
 
+// parseDateOrTime parses value as either a date and a time, or just a date in
+// which case dateOnly is true, in the local timezone.
+func parseDateOrTime(value string) (ts time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+	ts, err = time.ParseInLocation(timeFormat, value, time.Local)
+	if err == nil {
+		return ts, false, nil
+	}
+
+	ts, err = time.ParseInLocation(dateFormat, value, time.Local)
+	if err != nil {
+		return ts, false, fmt.Errorf("expected format: %s or %s, got %q", dateFormat, timeFormat, value)
+	}
+
+	return ts, true, nil
+}
+
+// startOfQuarter returns the beginning of the calendar quarter ts falls in.
+func startOfQuarter(ts time.Time) time.Time {
+	firstMonth := time.Month((int(ts.Month())-1)/3*3 + 1)
+	return time.Date(ts.Year(), firstMonth, 1, 0, 0, 0, 0, ts.Location())
+}
+
+// numDaysBetween returns the number of calendar days from start to end,
+// unaffected by DST transitions in between.
+func numDaysBetween(start, end time.Time) int {
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(endDate.Sub(startDate).Hours() / 24)
+}
+
 func GetShiftedTime(ts time.Time, direction TimeShiftDirection, duration TimeShiftDuration) time.Time {
 	var d time.Duration
 
@@ -147,12 +241,47 @@ func GetShiftedTime(ts time.Time, direction TimeShiftDirection, duration TimeShi
 		d = time.Hour
 	case ShiftDay:
 		d = time.Hour * 24
+	case ShiftWeek:
+		d = time.Hour * 24 * 7
+	}
+
+	return ShiftTimeBy(ts, direction, d)
+}
+
+// RoundTLEnd moves endTs so that the time between beginTs and endTs is a
+// multiple of increment, rounding to the nearest one (halves round up). An
+// entry is never rounded down below a single increment. endTs is never moved
+// past latest (usually the current time); it's rounded down instead then, or
+// left as is if that would go below a single increment. A non-positive
+// increment leaves endTs as is.
+func RoundTLEnd(beginTs, endTs time.Time, increment time.Duration, latest time.Time) time.Time {
+	if increment <= 0 {
+		return endTs
+	}
+
+	d := endTs.Sub(beginTs).Round(increment)
+	if d < increment {
+		d = increment
+	}
+
+	rounded := beginTs.Add(d)
+	if !rounded.After(latest) || !rounded.After(endTs) {
+		return rounded
+	}
+
+	if down := endTs.Sub(beginTs).Truncate(increment); down >= increment {
+		return beginTs.Add(down)
 	}
 
+	return endTs
+}
+
+// ShiftTimeBy moves ts by step in the given direction.
+func ShiftTimeBy(ts time.Time, direction TimeShiftDirection, step time.Duration) time.Time {
 	if direction == ShiftBackward {
-		d = -1 * d
+		step = -1 * step
 	}
-	return ts.Add(d)
+	return ts.Add(step)
 }
 
 type tsRelative uint8
@@ -191,3 +320,52 @@ func getTSRelative(ts time.Time, reference time.Time) tsRelative {
 	}
 	return tsFromBeforeThisWeek
 }
+
+// StartOfWeek returns the beginning of the most recent day (which can be the
+// day of now itself) that falls on weekStart.
+func StartOfWeek(now time.Time, weekStart time.Weekday) time.Time {
+	offset := (7 + now.Weekday() - weekStart) % 7
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	return startOfDay.AddDate(0, 0, -int(offset))
+}
+
+// ParseWeekday parses the name of a weekday, either in full (eg. "monday") or
+// its first three letters (eg. "mon"), ignoring case.
+func ParseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, nil
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("%w: %q", ErrWeekdayInvalid, value)
+}
+
+// LongestStreak returns the length of the longest run of consecutive days in
+// days, along with the day the run begins on; the earliest run wins a tie.
+// days must be sorted in ascending order, and each must be the start of a day.
+func LongestStreak(days []time.Time) (int, time.Time) {
+	var longest, current int
+	var longestStart, currentStart time.Time
+	for i, day := range days {
+		switch {
+		case i > 0 && day.Equal(days[i-1]):
+			continue
+		case i > 0 && day.Equal(days[i-1].AddDate(0, 0, 1)):
+			current++
+		default:
+			current = 1
+			currentStart = day
+		}
+
+		if current > longest {
+			longest = current
+			longestStart = currentStart
+		}
+	}
+
+	return longest, longestStart
+}
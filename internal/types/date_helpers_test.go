@@ -207,6 +207,38 @@ func TestGetDateRangeFromPeriod(t *testing.T) {
 			expectedEndStr:   "2024/06/01 00:00",
 			expectedNumDays:  31,
 		},
+		{
+			name:             "month",
+			period:           "month",
+			now:              now,
+			expectedStartStr: "2024/06/01 00:00",
+			expectedEndStr:   "2024/07/01 00:00",
+			expectedNumDays:  30,
+		},
+		{
+			name:             "last-month",
+			period:           "last-month",
+			now:              now,
+			expectedStartStr: "2024/05/01 00:00",
+			expectedEndStr:   "2024/06/01 00:00",
+			expectedNumDays:  31,
+		},
+		{
+			name:             "last-month at end of month",
+			period:           "last-month",
+			now:              nowME,
+			expectedStartStr: "2024/04/01 00:00",
+			expectedEndStr:   "2024/05/01 00:00",
+			expectedNumDays:  30,
+		},
+		{
+			name:             "last-month in january",
+			period:           "last-month",
+			now:              time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2023/12/01 00:00",
+			expectedEndStr:   "2024/01/01 00:00",
+			expectedNumDays:  31,
+		},
 		{
 			name:             "a date",
 			period:           "2024/06/20",
@@ -239,6 +271,20 @@ func TestGetDateRangeFromPeriod(t *testing.T) {
 			maxDaysAllowed: &maxDaysAllowed,
 			expectedErr:    errTimePeriodTooLarge,
 		},
+		{
+			name:           "month too large",
+			period:         "month",
+			now:            now,
+			maxDaysAllowed: &maxDaysAllowed,
+			expectedErr:    errTimePeriodTooLarge,
+		},
+		{
+			name:           "last-month too large",
+			period:         "last-month",
+			now:            now,
+			maxDaysAllowed: &maxDaysAllowed,
+			expectedErr:    errTimePeriodTooLarge,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -326,3 +372,354 @@ func TestGetDateRangeFromPeriod_DST(t *testing.T) {
 	assert.Equal(t, "2024/04/01 00:00", endStr)
 	assert.Equal(t, 31, got.NumDays) // March has 31 days, DST-safe calculation should return this
 }
+
+func TestGetDateRangeFromPeriodQuartersAndYears(t *testing.T) {
+	testCases := []struct {
+		name             string
+		period           string
+		now              time.Time
+		expectedStartStr string
+		expectedEndStr   string
+		expectedNumDays  int
+	}{
+		{
+			name:             "this-quarter in the first quarter of a leap year",
+			period:           "this-quarter",
+			now:              time.Date(2024, 2, 29, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2024/01/01 00:00",
+			expectedEndStr:   "2024/04/01 00:00",
+			expectedNumDays:  91,
+		},
+		{
+			name:             "this-quarter on the first day of a quarter",
+			period:           "this-quarter",
+			now:              time.Date(2024, 7, 1, 0, 0, 0, 0, time.Local),
+			expectedStartStr: "2024/07/01 00:00",
+			expectedEndStr:   "2024/10/01 00:00",
+			expectedNumDays:  92,
+		},
+		{
+			name:             "this-quarter on the last day of a quarter",
+			period:           "this-quarter",
+			now:              time.Date(2024, 12, 31, 23, 59, 0, 0, time.Local),
+			expectedStartStr: "2024/10/01 00:00",
+			expectedEndStr:   "2025/01/01 00:00",
+			expectedNumDays:  92,
+		},
+		{
+			name:             "last-quarter",
+			period:           "last-quarter",
+			now:              time.Date(2024, 8, 15, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2024/04/01 00:00",
+			expectedEndStr:   "2024/07/01 00:00",
+			expectedNumDays:  91,
+		},
+		{
+			name:             "last-quarter in the first quarter",
+			period:           "last-quarter",
+			now:              time.Date(2025, 2, 10, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2024/10/01 00:00",
+			expectedEndStr:   "2025/01/01 00:00",
+			expectedNumDays:  92,
+		},
+		{
+			name:             "this-year",
+			period:           "this-year",
+			now:              time.Date(2025, 8, 15, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2025/01/01 00:00",
+			expectedEndStr:   "2026/01/01 00:00",
+			expectedNumDays:  365,
+		},
+		{
+			name:             "this-year in a leap year",
+			period:           "this-year",
+			now:              time.Date(2024, 12, 31, 10, 0, 0, 0, time.Local),
+			expectedStartStr: "2024/01/01 00:00",
+			expectedEndStr:   "2025/01/01 00:00",
+			expectedNumDays:  366,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDateRangeFromPeriod(tt.period, tt.now, false, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStartStr, got.Start.Format(timeFormat))
+			assert.Equal(t, tt.expectedEndStr, got.End.Format(timeFormat))
+			assert.Equal(t, tt.expectedNumDays, got.NumDays)
+		})
+	}
+}
+
+func TestGetDateRangeFromPeriodQuarter_DST(t *testing.T) {
+	locNY, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("skipping test: tzdata unavailable or timezone 'America/New_York' not found: %s", err)
+	}
+
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, locNY) // DST starts on 2024/03/10 in America/New_York
+
+	got, err := GetDateRangeFromPeriod("this-quarter", now, false, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024/01/01 00:00", got.Start.Format(timeFormat))
+	assert.Equal(t, "2024/04/01 00:00", got.End.Format(timeFormat))
+	assert.Equal(t, 91, got.NumDays)
+}
+
+func TestStartOfWeek(t *testing.T) {
+	now := time.Date(2024, 6, 29, 12, 0, 0, 0, time.Local) // a Saturday
+	testCases := []struct {
+		name      string
+		weekStart time.Weekday
+		expected  string
+	}{
+		{
+			name:      "week starting on monday",
+			weekStart: time.Monday,
+			expected:  "2024/06/24 00:00",
+		},
+		{
+			name:      "week starting on thursday",
+			weekStart: time.Thursday,
+			expected:  "2024/06/27 00:00",
+		},
+		{
+			name:      "week starting on the same day",
+			weekStart: time.Saturday,
+			expected:  "2024/06/29 00:00",
+		},
+		{
+			name:      "week starting on the next day",
+			weekStart: time.Sunday,
+			expected:  "2024/06/23 00:00",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StartOfWeek(now, tt.weekStart)
+			assert.Equal(t, tt.expected, got.Format(timeFormat))
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Weekday
+	}{
+		{value: "monday", expected: time.Monday},
+		{value: "Thu", expected: time.Thursday},
+		{value: " SUNDAY ", expected: time.Sunday},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseWeekday(tt.value)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("invalid weekday", func(t *testing.T) {
+		_, err := ParseWeekday("someday")
+
+		assert.ErrorIs(t, err, ErrWeekdayInvalid)
+	})
+}
+
+func TestRoundTLEnd(t *testing.T) {
+	begin := time.Date(2024, 6, 29, 9, 0, 0, 0, time.Local)
+	latest := begin.Add(24 * time.Hour)
+	testCases := []struct {
+		name      string
+		duration  time.Duration
+		increment time.Duration
+		latest    time.Time
+		expected  time.Duration
+	}{
+		{
+			name:      "rounds down below the halfway point",
+			duration:  22*time.Minute + 29*time.Second,
+			increment: 15 * time.Minute,
+			latest:    latest,
+			expected:  15 * time.Minute,
+		},
+		{
+			name:      "rounds up at the halfway point",
+			duration:  22*time.Minute + 30*time.Second,
+			increment: 15 * time.Minute,
+			latest:    latest,
+			expected:  30 * time.Minute,
+		},
+		{
+			name:      "never rounds below a single increment",
+			duration:  2 * time.Minute,
+			increment: 15 * time.Minute,
+			latest:    latest,
+			expected:  15 * time.Minute,
+		},
+		{
+			name:      "doesn't round without an increment",
+			duration:  22 * time.Minute,
+			increment: 0,
+			latest:    latest,
+			expected:  22 * time.Minute,
+		},
+		{
+			name:      "rounds down instead of moving past latest",
+			duration:  22*time.Minute + 30*time.Second,
+			increment: 15 * time.Minute,
+			latest:    begin.Add(25 * time.Minute),
+			expected:  15 * time.Minute,
+		},
+		{
+			name:      "doesn't round if it can only move past latest",
+			duration:  2 * time.Minute,
+			increment: 15 * time.Minute,
+			latest:    begin.Add(2 * time.Minute),
+			expected:  2 * time.Minute,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RoundTLEnd(begin, begin.Add(tt.duration), tt.increment, tt.latest)
+			assert.Equal(t, tt.expected, got.Sub(begin))
+		})
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 0, 0, 0, 0, time.Local)
+	}
+
+	testCases := []struct {
+		name           string
+		days           []time.Time
+		expectedLength int
+		expectedStart  time.Time
+	}{
+		{
+			name:           "no days",
+			days:           nil,
+			expectedLength: 0,
+		},
+		{
+			name:           "single day",
+			days:           []time.Time{day(4)},
+			expectedLength: 1,
+			expectedStart:  day(4),
+		},
+		{
+			name:           "run after a gap",
+			days:           []time.Time{day(1), day(2), day(5), day(6), day(7), day(9)},
+			expectedLength: 3,
+			expectedStart:  day(5),
+		},
+		{
+			name:           "tie goes to the earliest run",
+			days:           []time.Time{day(1), day(2), day(4), day(5)},
+			expectedLength: 2,
+			expectedStart:  day(1),
+		},
+		{
+			name:           "run across a month boundary",
+			days:           []time.Time{time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local), day(1), day(2)},
+			expectedLength: 3,
+			expectedStart:  time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:           "duplicate days",
+			days:           []time.Time{day(1), day(1), day(2)},
+			expectedLength: 2,
+			expectedStart:  day(1),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLength, gotStart := LongestStreak(tt.days)
+
+			assert.Equal(t, tt.expectedLength, gotLength)
+			assert.True(t, tt.expectedStart.Equal(gotStart))
+		})
+	}
+}
+
+func TestGetDateRangeFromSinceUntil(t *testing.T) {
+	now := time.Date(2024, 6, 20, 15, 30, 0, 0, time.Local)
+	maxDays := 7
+
+	testCases := []struct {
+		name            string
+		since           string
+		until           string
+		expectedStart   string
+		expectedEnd     string
+		expectedNumDays int
+	}{
+		{
+			name:            "since a date until now",
+			since:           "2024/06/18",
+			expectedStart:   "2024/06/18 00:00",
+			expectedEnd:     "2024/06/20 15:30",
+			expectedNumDays: 3,
+		},
+		{
+			name:            "until a date includes that day",
+			since:           "2024/06/10",
+			until:           "2024/06/12",
+			expectedStart:   "2024/06/10 00:00",
+			expectedEnd:     "2024/06/13 00:00",
+			expectedNumDays: 3,
+		},
+		{
+			name:            "times within a single day",
+			since:           "2024/06/10 09:00",
+			until:           "2024/06/10 17:45",
+			expectedStart:   "2024/06/10 09:00",
+			expectedEnd:     "2024/06/10 17:45",
+			expectedNumDays: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDateRangeFromSinceUntil(tt.since, tt.until, now, &maxDays)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, got.Start.Format(timeFormat))
+			assert.Equal(t, tt.expectedEnd, got.End.Format(timeFormat))
+			assert.Equal(t, tt.expectedNumDays, got.NumDays)
+		})
+	}
+
+	t.Run("until not after since", func(t *testing.T) {
+		_, err := GetDateRangeFromSinceUntil("2024/06/10 09:00", "2024/06/10 09:00", now, nil)
+
+		assert.ErrorIs(t, err, errEndDateIsNotAfterStartDate)
+	})
+
+	t.Run("span larger than allowed", func(t *testing.T) {
+		_, err := GetDateRangeFromSinceUntil("2024/06/01", "2024/06/08", now, &maxDays)
+
+		assert.ErrorIs(t, err, errTimePeriodTooLarge)
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		_, err := GetDateRangeFromSinceUntil("last week", "", now, nil)
+
+		assert.ErrorIs(t, err, errStartDateIncorrect)
+	})
+
+	t.Run("invalid until", func(t *testing.T) {
+		_, err := GetDateRangeFromSinceUntil("2024/06/01", "tomorrow", now, nil)
+
+		assert.ErrorIs(t, err, errEndDateIncorrect)
+	})
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -11,6 +12,7 @@ var (
 	errEndTimeIsEmpty         = errors.New("end time is empty")
 	errBeginTimeIsInvalid     = errors.New("begin time is invalid")
 	errEndTimeIsInvalid       = errors.New("end time is invalid")
+	ErrTimeIsInvalid          = errors.New("time is invalid")
 	errEndTimeBeforeBeginTime = errors.New("end time is before begin time")
 	ErrDurationNotLongEnough  = errors.New("end time needs to be at least a minute after begin time")
 )
@@ -43,6 +45,15 @@ func ParseTaskLogTimes(beginStr, endStr string) (time.Time, time.Time, error) {
 	return beginTS, endTS, nil
 }
 
+func ParseTaskLogTime(value string) (time.Time, error) {
+	ts, err := time.ParseInLocation(timeFormat, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return ts, fmt.Errorf("%w: %q (expected format: %s)", ErrTimeIsInvalid, value, timeFormat)
+	}
+
+	return ts, nil
+}
+
 func IsTaskLogDurationValid(begin, end time.Time) error {
 	if end.Before(begin) {
 		return errEndTimeBeforeBeginTime
@@ -115,3 +115,29 @@ func TestParseTaskLogTimes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTaskLogTime(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{name: "valid time", value: "2025/08/08 00:40"},
+		{name: "valid time with surrounding whitespace", value: " 2025/08/08 00:40 "},
+		{name: "empty time", value: "", err: ErrTimeIsInvalid},
+		{name: "invalid format", value: "2025-08-08 00:40", err: ErrTimeIsInvalid},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := ParseTaskLogTime(tt.value)
+
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "2025/08/08 00:40", ts.Format(timeFormat))
+			}
+		})
+	}
+}
@@ -4,15 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dhth/hours/internal/utils"
 	"github.com/dustin/go-humanize"
 )
 
 const emptyCommentIndicator = "∅"
 
-var ErrIncorrectTaskStatusProvided = errors.New("incorrect task status provided")
+var (
+	ErrIncorrectTaskStatusProvided = errors.New("incorrect task status provided")
+	ErrIncorrectGranularity        = errors.New("incorrect granularity provided")
+)
 
 type Task struct {
 	ID             int
@@ -22,6 +27,7 @@ type Task struct {
 	TrackingActive bool
 	SecsSpent      int
 	Active         bool
+	Color          *string
 	ListTitle      string
 	ListDesc       string
 }
@@ -34,10 +40,31 @@ type TaskLogEntry struct {
 	EndTS       time.Time
 	SecsSpent   int
 	Comment     *string
+	Tags        []string
+	Category    *string
 	ListTitle   string
 	ListDesc    string
 }
 
+// ManualTaskLog holds the details needed to save a finished task log entry
+// that wasn't tracked live.
+type ManualTaskLog struct {
+	TaskID   int
+	BeginTS  time.Time
+	EndTS    time.Time
+	Comment  *string
+	Category *string
+}
+
+// ImportedTaskLog holds the details needed to save a finished task log entry
+// coming from another tool, where tasks are only known by their summary.
+type ImportedTaskLog struct {
+	TaskSummary string
+	BeginTS     time.Time
+	EndTS       time.Time
+	Comment     *string
+}
+
 type ActiveTaskLogEntry struct {
 	ID          int
 	TaskID      int
@@ -49,10 +76,17 @@ type ActiveTaskLogEntry struct {
 type ActiveTaskDetails struct {
 	TaskID            int
 	TaskSummary       string
+	CurrentLogID      int
 	CurrentLogBeginTS time.Time
 	CurrentLogComment *string
 }
 
+// DailyTotal holds the time tracked on a single day.
+type DailyTotal struct {
+	Day       time.Time
+	SecsSpent int
+}
+
 type TaskReportEntry struct {
 	TaskID      int
 	TaskSummary string
@@ -60,17 +94,35 @@ type TaskReportEntry struct {
 	SecsSpent   int
 }
 
+// GroupedStatsEntry is a task's stats for a single period (eg. a week), which
+// begins at PeriodStart.
+type GroupedStatsEntry struct {
+	PeriodStart time.Time
+	TaskReportEntry
+}
+
+// CategoryStatsEntry is the time tracked on task log entries with a category.
+// A nil Category stands for entries without one.
+type CategoryStatsEntry struct {
+	Category   *string
+	NumEntries int
+	SecsSpent  int
+}
+
 // SyncTaskRecord is the shared persistence projection for syncing task rows.
 // It keeps the local integer key for local joins while exposing the durable
 // sync identifier and canonical timestamps used by future sync code.
 type SyncTaskRecord struct {
-	LocalID   int
-	SyncID    string
-	Summary   string
-	SecsSpent int
-	Active    bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	LocalID      int
+	SyncID       string
+	Summary      string
+	SecsSpent    int
+	Active       bool
+	Color        *string
+	WeekResetDay *int
+	Tags         []string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // SyncTaskLogRecord is the shared persistence projection for syncing task_log
@@ -85,6 +137,8 @@ type SyncTaskLogRecord struct {
 	EndTS       *time.Time
 	SecsSpent   int
 	Comment     *string
+	Category    *string
+	Tags        *string
 	Active      bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -114,14 +168,19 @@ func (t *Task) UpdateListTitle() {
 		trackingIndicator = "⏲ "
 	}
 
-	t.ListTitle = trackingIndicator + t.Summary
+	title := trackingIndicator + t.Summary
+	if t.Color != nil {
+		title = lipgloss.NewStyle().Foreground(lipgloss.Color(*t.Color)).Render(title)
+	}
+
+	t.ListTitle = title
 }
 
-func (t *Task) UpdateListDesc(timeProvider TimeProvider) {
+func (t *Task) UpdateListDesc(timeProvider TimeProvider, durationFmt DurationFormat) {
 	var timeSpent string
 
 	if t.SecsSpent != 0 {
-		timeSpent = "worked on for " + HumanizeDuration(t.SecsSpent)
+		timeSpent = "worked on for " + durationFmt.Format(t.SecsSpent)
 	} else {
 		timeSpent = "no time spent"
 	}
@@ -134,8 +193,8 @@ func (tl *TaskLogEntry) UpdateListTitle() {
 	tl.ListTitle = utils.TrimWithMoreLinesIndicator(tl.GetComment(), 60)
 }
 
-func (tl *TaskLogEntry) UpdateListDesc(timeProvider TimeProvider) {
-	timeSpentStr := HumanizeDuration(tl.SecsSpent)
+func (tl *TaskLogEntry) UpdateListDesc(timeProvider TimeProvider, durationFmt DurationFormat) {
+	timeSpentStr := durationFmt.Format(tl.SecsSpent)
 
 	var timeStr string
 	var durationMsg string
@@ -143,15 +202,17 @@ func (tl *TaskLogEntry) UpdateListDesc(timeProvider TimeProvider) {
 	now := timeProvider.Now()
 	endTSRelative := getTSRelative(tl.EndTS, now)
 
+	clockTimes := fmt.Sprintf("%s  ...  %s", tl.BeginTS.Format(timeOnlyFormat), tl.EndTS.Format(timeOnlyFormat))
+
 	switch endTSRelative {
 	case tsFromToday:
-		durationMsg = fmt.Sprintf("%s  ...  %s", tl.BeginTS.Format(timeOnlyFormat), tl.EndTS.Format(timeOnlyFormat))
+		durationMsg = clockTimes
 	case tsFromYesterday:
-		durationMsg = "Yesterday"
+		durationMsg = fmt.Sprintf("Yesterday  %s", clockTimes)
 	case tsFromThisWeek:
-		durationMsg = tl.EndTS.Format(dayFormat)
+		durationMsg = fmt.Sprintf("%s  %s", tl.EndTS.Format(dayFormat), clockTimes)
 	default:
-		durationMsg = humanize.RelTime(tl.EndTS, now, "ago", "from now")
+		durationMsg = fmt.Sprintf("%s  %s", humanize.RelTime(tl.EndTS, now, "ago", "from now"), clockTimes)
 	}
 
 	timeStr = fmt.Sprintf("%s (%s)",
@@ -169,6 +230,28 @@ func (tl *TaskLogEntry) GetComment() string {
 	return *tl.Comment
 }
 
+// ParseLogTags parses a comma separated list of task log tags. Tags are
+// trimmed, and empty or repeated ones are dropped.
+func ParseLogTags(value string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// FormatLogTags is the inverse of ParseLogTags.
+func FormatLogTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
 func (t Task) Title() string {
 	return t.ListTitle
 }
@@ -213,6 +296,40 @@ func HumanizeDuration(durationInSecs int) string {
 	return fmt.Sprintf("%dh %dm", int(duration.Hours()), modMins)
 }
 
+// DurationFormat determines how durations are shown in list descriptions.
+type DurationFormat uint8
+
+const (
+	DurationFormatFull    DurationFormat = iota // eg, "1h 30m"
+	DurationFormatCompact                       // eg, "1h30"
+)
+
+func (f DurationFormat) Format(durationInSecs int) string {
+	if f == DurationFormatCompact {
+		return CompactDuration(durationInSecs)
+	}
+
+	return HumanizeDuration(durationInSecs)
+}
+
+// CompactDuration is like HumanizeDuration, but leaves out the minutes unit
+// (and the space before it) for durations of an hour or more.
+func CompactDuration(durationInSecs int) string {
+	duration := time.Duration(durationInSecs) * time.Second
+
+	if duration.Minutes() < 60 {
+		return HumanizeDuration(durationInSecs)
+	}
+
+	modMins := int(math.Mod(duration.Minutes(), 60))
+
+	if modMins == 0 {
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	}
+
+	return fmt.Sprintf("%dh%02d", int(duration.Hours()), modMins)
+}
+
 type TimeShiftDirection uint8
 
 const (
@@ -227,6 +344,7 @@ const (
 	ShiftFiveMinutes
 	ShiftHour
 	ShiftDay
+	ShiftWeek
 )
 
 type TaskStatus uint8
@@ -235,12 +353,15 @@ const (
 	TSValueActive   = "active"
 	TSValueInactive = "inactive"
 	TSValueAny      = "any"
+	TSValueTracking = "tracking"
 )
 
 const (
 	TaskStatusActive TaskStatus = iota
 	TaskStatusInactive
 	TaskStatusAny
+	// TaskStatusTracking matches the task that's being tracked right now, if any
+	TaskStatusTracking
 )
 
 func ParseTaskStatus(value string) (TaskStatus, error) {
@@ -251,12 +372,81 @@ func ParseTaskStatus(value string) (TaskStatus, error) {
 		return TaskStatusInactive, nil
 	case TSValueAny:
 		return TaskStatusAny, nil
+	case TSValueTracking:
+		return TaskStatusTracking, nil
 	default:
 		return TaskStatusAny, ErrIncorrectTaskStatusProvided
 	}
 }
 
-var ValidTaskStatusValues = []string{TSValueActive, TSValueInactive, TSValueAny}
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskStatusActive:
+		return TSValueActive
+	case TaskStatusInactive:
+		return TSValueInactive
+	case TaskStatusTracking:
+		return TSValueTracking
+	default:
+		return TSValueAny
+	}
+}
+
+var ValidTaskStatusValues = []string{TSValueActive, TSValueInactive, TSValueAny, TSValueTracking}
+
+// Granularity is the length of the periods stats are grouped into.
+type Granularity uint8
+
+const (
+	GranularityValueDay   = "day"
+	GranularityValueWeek  = "week"
+	GranularityValueMonth = "month"
+)
+
+const (
+	GranularityDay Granularity = iota
+	GranularityWeek
+	GranularityMonth
+)
+
+func ParseGranularity(value string) (Granularity, error) {
+	switch value {
+	case GranularityValueDay:
+		return GranularityDay, nil
+	case GranularityValueWeek:
+		return GranularityWeek, nil
+	case GranularityValueMonth:
+		return GranularityMonth, nil
+	default:
+		return GranularityDay, fmt.Errorf("%w: %q", ErrIncorrectGranularity, value)
+	}
+}
+
+func (g Granularity) String() string {
+	switch g {
+	case GranularityWeek:
+		return GranularityValueWeek
+	case GranularityMonth:
+		return GranularityValueMonth
+	default:
+		return GranularityValueDay
+	}
+}
+
+// PeriodStart returns the beginning of the period ts falls in, in ts's
+// location. Weeks begin on Monday.
+func (g Granularity) PeriodStart(ts time.Time) time.Time {
+	switch g {
+	case GranularityWeek:
+		return StartOfWeek(ts, time.Monday)
+	case GranularityMonth:
+		return time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, ts.Location())
+	default:
+		return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location())
+	}
+}
+
+var ValidGranularityValues = []string{GranularityValueDay, GranularityValueWeek, GranularityValueMonth}
 
 type DateRange struct {
 	Start   time.Time
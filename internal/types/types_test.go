@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +30,11 @@ func TestParseTaskStatus(t *testing.T) {
 			input:    TSValueAny,
 			expected: TaskStatusAny,
 		},
+		{
+			name:     "tracking",
+			input:    TSValueTracking,
+			expected: TaskStatusTracking,
+		},
 		{
 			name:        "unknown value returns error",
 			input:       "unknown",
@@ -55,6 +61,45 @@ func TestParseTaskStatus(t *testing.T) {
 	}
 }
 
+func TestGranularityPeriodStart(t *testing.T) {
+	// 2024/09/05 is a Thursday
+	ts := time.Date(2024, time.September, 5, 14, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		granularity Granularity
+		expected    time.Time
+	}{
+		{
+			name:        "day",
+			granularity: GranularityDay,
+			expected:    time.Date(2024, time.September, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "week",
+			granularity: GranularityWeek,
+			expected:    time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "month",
+			granularity: GranularityMonth,
+			expected:    time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseGranularity(tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.granularity, parsed)
+			assert.Equal(t, tt.expected, tt.granularity.PeriodStart(ts))
+		})
+	}
+
+	_, err := ParseGranularity("year")
+	assert.ErrorIs(t, err, ErrIncorrectGranularity)
+}
+
 func TestHumanizeDuration(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -105,3 +150,134 @@ func TestHumanizeDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestCompactDuration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    int
+		expected string
+	}{
+		{
+			name:     "30 seconds",
+			input:    30,
+			expected: "30s",
+		},
+		{
+			name:     "1805 seconds",
+			input:    1805,
+			expected: "30m",
+		},
+		{
+			name:     "3605 seconds",
+			input:    3605,
+			expected: "1h",
+		},
+		{
+			name:     "3900 seconds",
+			input:    3900,
+			expected: "1h05",
+		},
+		{
+			name:     "5400 seconds",
+			input:    5400,
+			expected: "1h30",
+		},
+		{
+			name:     "87000 seconds",
+			input:    87000,
+			expected: "24h10",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompactDuration(tt.input)
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseLogTags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "empty input",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:     "single tag",
+			input:    "meeting",
+			expected: []string{"meeting"},
+		},
+		{
+			name:     "tags are trimmed",
+			input:    " meeting ,  review",
+			expected: []string{"meeting", "review"},
+		},
+		{
+			name:     "empty and repeated tags are dropped",
+			input:    "meeting,,review, meeting,",
+			expected: []string{"meeting", "review"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLogTags(tt.input)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestTaskLogEntryListDescIncludesClockTimes(t *testing.T) {
+	now := time.Date(2024, time.September, 5, 18, 0, 0, 0, time.Local) // a Thursday
+	timeProvider := TestTimeProvider{FixedTime: now}
+
+	testCases := []struct {
+		name     string
+		beginTS  time.Time
+		expected string
+	}{
+		{
+			name:     "today",
+			beginTS:  time.Date(2024, time.September, 5, 9, 0, 0, 0, time.Local),
+			expected: "09:00  ...  11:30",
+		},
+		{
+			name:     "yesterday",
+			beginTS:  time.Date(2024, time.September, 4, 9, 0, 0, 0, time.Local),
+			expected: "Yesterday  09:00  ...  11:30",
+		},
+		{
+			name:     "this week",
+			beginTS:  time.Date(2024, time.September, 2, 9, 0, 0, 0, time.Local),
+			expected: "Monday  09:00  ...  11:30",
+		},
+		{
+			name:     "before this week",
+			beginTS:  time.Date(2024, time.August, 20, 9, 0, 0, 0, time.Local),
+			expected: "2 weeks ago  09:00  ...  11:30",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := TaskLogEntry{
+				TaskSummary: "write docs",
+				BeginTS:     tt.beginTS,
+				EndTS:       tt.beginTS.Add(150 * time.Minute),
+				SecsSpent:   150 * 60,
+			}
+
+			entry.UpdateListDesc(timeProvider, DurationFormatFull)
+
+			assert.Contains(t, entry.ListDesc, tt.expected+" ")
+			assert.Contains(t, entry.ListDesc, "(2h 30m)")
+		})
+	}
+}
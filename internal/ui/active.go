@@ -2,6 +2,7 @@ package ui
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -14,21 +15,25 @@ import (
 const (
 	ActiveTaskPlaceholder     = "{{task}}"
 	ActiveTaskTimePlaceholder = "{{time}}"
+	ActiveTaskWeekPlaceholder = "{{week}}"
 	activeSecsThreshold       = 60
 	activeSecsThresholdStr    = "<1m"
 )
 
-func ShowActiveTask(db *sql.DB, writer io.Writer, template string) error {
+// ShowActiveTask writes the task being tracked to writer using template, and
+// returns whether a task is being tracked. Nothing is written otherwise.
+func ShowActiveTask(db *sql.DB, writer io.Writer, template string) (bool, error) {
 	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if activeTaskDetails.TaskID == -1 {
-		return nil
+		return false, nil
 	}
 
-	timeSpent := time.Since(activeTaskDetails.CurrentLogBeginTS).Seconds()
+	now := time.Now()
+	timeSpent := now.Sub(activeTaskDetails.CurrentLogBeginTS).Seconds()
 	var timeSpentStr string
 	if timeSpent <= activeSecsThreshold {
 		timeSpentStr = activeSecsThresholdStr
@@ -38,6 +43,47 @@ func ShowActiveTask(db *sql.DB, writer io.Writer, template string) error {
 
 	activeStr := strings.Replace(template, ActiveTaskPlaceholder, activeTaskDetails.TaskSummary, 1)
 	activeStr = strings.Replace(activeStr, ActiveTaskTimePlaceholder, timeSpentStr, 1)
+
+	if strings.Contains(activeStr, ActiveTaskWeekPlaceholder) {
+		// the week's total includes the time spent so far on the active log
+		weekSecs, err := pers.FetchTaskWeekTotal(db, activeTaskDetails.TaskID, now)
+		if err != nil {
+			return false, err
+		}
+		activeStr = strings.Replace(activeStr, ActiveTaskWeekPlaceholder, types.HumanizeDuration(weekSecs+int(timeSpent)), 1)
+	}
+
 	fmt.Fprint(writer, activeStr)
-	return nil
+	return true, nil
+}
+
+type activeTaskJSON struct {
+	Active  bool   `json:"active"`
+	Task    string `json:"task,omitempty"`
+	Seconds *int   `json:"seconds,omitempty"`
+}
+
+// ShowActiveTaskJSON is like ShowActiveTask, but writes a JSON object instead,
+// which only holds "active": false when no task is being tracked.
+func ShowActiveTaskJSON(db *sql.DB, writer io.Writer) (bool, error) {
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return false, err
+	}
+
+	output := activeTaskJSON{}
+	if activeTaskDetails.TaskID != -1 {
+		secondsSpent := int(time.Since(activeTaskDetails.CurrentLogBeginTS).Seconds())
+		output = activeTaskJSON{
+			Active:  true,
+			Task:    activeTaskDetails.TaskSummary,
+			Seconds: &secondsSpent,
+		}
+	}
+
+	if err := json.NewEncoder(writer).Encode(output); err != nil {
+		return false, err
+	}
+
+	return output.Active, nil
 }
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+const calendarCellWidth = 3
+
+// calendarShades holds the glyphs used for a day's cell, from no time tracked
+// to the most time tracked on a single day in the range.
+var calendarShades = []string{"·", "░", "▒", "▓", "█"}
+
+// RenderCalendar writes a week by week calendar of dateRange to writer, where
+// each day's cell is shaded according to the time tracked on it relative to
+// the busiest day in the range.
+func RenderCalendar(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	dateRange types.DateRange,
+	filter pers.TLFilter,
+) error {
+	calendar, err := getCalendar(db, style, dateRange, filter.TaskStatus, filter.Tag, filter.TaskID, plain, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	fmt.Fprint(writer, calendar)
+	return nil
+}
+
+func getCalendar(db *sql.DB,
+	style Style,
+	dateRange types.DateRange,
+	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	plain bool,
+	now time.Time,
+) (string, error) {
+	// days that haven't started yet are left out, rather than shown as empty
+	end := startedDaysEnd(dateRange.End, now)
+
+	totals, err := pers.FetchDailyTotalsBetweenTS(db, dateRange.Start, end, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID})
+	if err != nil {
+		return "", err
+	}
+
+	rs := style.getReportStyles(plain)
+	shadeStyle := rs.footerStyle
+	if !plain {
+		shadeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(style.theme.ActiveTasks))
+	}
+
+	headerValues := []string{"Week", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun", "TimeSpent"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+
+	if len(totals) == 0 {
+		return renderRecordsTable(rs, headers, nil, [][]string{make([]string, len(headerValues))})
+	}
+
+	var maxSecs int
+	for _, total := range totals {
+		maxSecs = max(maxSecs, total.SecsSpent)
+	}
+
+	var data [][]string
+	var row []string
+	var weekSecs int
+	for i, total := range totals {
+		weekdayIndex := int((7 + total.Day.Weekday() - time.Monday) % 7)
+		if row == nil {
+			weekStart := total.Day.AddDate(0, 0, -weekdayIndex)
+			row = make([]string, len(headerValues))
+			row[0] = weekStart.Format(dateFormat)
+		}
+
+		row[weekdayIndex+1] = shadeStyle.Render(calendarCell(total.SecsSpent, maxSecs))
+		weekSecs += total.SecsSpent
+
+		if weekdayIndex == 6 || i == len(totals)-1 {
+			if weekSecs > 0 {
+				row[len(row)-1] = types.HumanizeDuration(weekSecs)
+			}
+			data = append(data, row)
+			row = nil
+			weekSecs = 0
+		}
+	}
+
+	table, err := renderRecordsTable(rs, headers, nil, data)
+	if err != nil {
+		return "", err
+	}
+
+	legend := make([]string, len(calendarShades))
+	for i, shade := range calendarShades {
+		legend[i] = shadeStyle.Render(shade)
+	}
+
+	return fmt.Sprintf("%s\nless %s more\n", table, strings.Join(legend, " ")), nil
+}
+
+// calendarCell returns the cell for a day with secs tracked on it, where
+// maxSecs is the most time tracked on a single day in the calendar.
+func calendarCell(secs, maxSecs int) string {
+	level := 0
+	if secs > 0 && maxSecs > 0 {
+		// any tracked time gets at least the lightest shade
+		level = 1 + (secs*(len(calendarShades)-2))/maxSecs
+	}
+
+	return strings.Repeat(calendarShades[level], calendarCellWidth)
+}
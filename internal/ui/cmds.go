@@ -9,6 +9,7 @@ import (
 	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/session"
 	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/ui/theme"
 	_ "modernc.org/sqlite" // sqlite driver
 )
 
@@ -17,6 +18,9 @@ func toggleTracking(db *sql.DB,
 	beginTs time.Time,
 	endTs time.Time,
 	comment *string,
+	category *string,
+	tags []string,
+	opts pers.FinishTLOptions,
 ) tea.Cmd {
 	return func() tea.Msg {
 		row := db.QueryRow(`
@@ -48,19 +52,34 @@ LIMIT 1
 			return trackingToggledMsg{taskID: taskID}
 
 		default:
-			secsSpent := int(endTs.Sub(beginTs).Seconds())
-			err := pers.FinishActiveTL(db, activeTaskLogID, activeTaskID, beginTs, endTs, secsSpent, comment)
+			overlapsWith, err := fetchOverlappingTL(db, activeTaskID, beginTs, endTs)
 			if err != nil {
 				return trackingToggledMsg{err: err}
 			}
-			return trackingToggledMsg{taskID: taskID, finished: true, secsSpent: secsSpent}
+
+			finished, err := pers.FinishActiveTLWithOptions(db, activeTaskLogID, activeTaskID, beginTs, endTs, comment, category, tags, opts)
+			if err != nil {
+				return trackingToggledMsg{err: err}
+			}
+			return trackingToggledMsg{taskID: taskID, finished: true, secsSpent: finished.SecsSpent, overlapsWith: overlapsWith}
 		}
 	}
 }
 
-func quickSwitchActiveIssue(db *sql.DB, taskID int, ts time.Time) tea.Cmd {
+func startTrackingWithComment(db *sql.DB, taskID int, beginTS time.Time, comment *string) tea.Cmd {
 	return func() tea.Msg {
-		result, err := pers.QuickSwitchActiveTL(db, taskID, ts)
+		_, err := pers.InsertNewTLWithComment(db, taskID, beginTS, comment)
+		if err != nil {
+			return trackingToggledMsg{err: err}
+		}
+
+		return trackingToggledMsg{taskID: taskID, comment: comment}
+	}
+}
+
+func quickSwitchActiveIssue(db *sql.DB, taskID int, ts time.Time, opts pers.FinishTLOptions) tea.Cmd {
+	return func() tea.Msg {
+		result, err := pers.QuickSwitchActiveTL(db, taskID, ts, opts)
 		return activeTLSwitchedMsg{
 			lastActiveTaskID:      result.LastActiveTaskID,
 			currentlyActiveTaskID: taskID,
@@ -71,6 +90,23 @@ func quickSwitchActiveIssue(db *sql.DB, taskID int, ts time.Time) tea.Cmd {
 	}
 }
 
+func fetchRecentComments(db *sql.DB, taskID int, forward bool) tea.Cmd {
+	return func() tea.Msg {
+		comments, err := pers.FetchRecentCommentsForTask(db, taskID, recentCommentsLimit)
+		return recentCommentsFetchedMsg{taskID, comments, forward, err}
+	}
+}
+
+func fetchFirstOverlappingTL(db *sql.DB, taskID int, beginTS, endTS time.Time) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := pers.FetchFirstOverlappingTL(db, taskID, beginTS, endTS)
+		if errors.Is(err, pers.ErrTaskLogNotFound) {
+			return overlappingTLFetchedMsg{beginTS: beginTS}
+		}
+		return overlappingTLFetchedMsg{beginTS, entry, err == nil, err}
+	}
+}
+
 func updateActiveTL(db *sql.DB, beginTS time.Time, comment *string) tea.Cmd {
 	return func() tea.Msg {
 		err := pers.EditActiveTL(db, beginTS, comment)
@@ -78,17 +114,50 @@ func updateActiveTL(db *sql.DB, beginTS time.Time, comment *string) tea.Cmd {
 	}
 }
 
-func insertManualTL(db *sql.DB, taskID int, beginTS time.Time, endTS time.Time, comment *string) tea.Cmd {
+// insertManualTL saves a finished task log entry. It's saved even if it
+// overlaps with a saved entry for the task; the first such entry is reported
+// back so that the user can be warned about it.
+func insertManualTL(db *sql.DB, taskID int, beginTS time.Time, endTS time.Time, comment, category *string, tags []string, roundTo time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		_, err := pers.InsertManualTL(db, taskID, beginTS, endTS, comment)
-		return manualTLInsertedMsg{taskID, err}
+		overlapsWith, err := fetchOverlappingTL(db, taskID, beginTS, endTS)
+		if err != nil {
+			return manualTLInsertedMsg{taskID: taskID, err: err}
+		}
+
+		_, err = pers.InsertManualTLRounded(db, taskID, beginTS, endTS, comment, category, tags, true, roundTo)
+		return manualTLInsertedMsg{taskID: taskID, overlapsWith: overlapsWith, err: err}
 	}
 }
 
-func editSavedTL(db *sql.DB, tlID, taskID int, beginTS time.Time, endTS time.Time, comment *string) tea.Cmd {
+// fetchOverlappingTL returns the first saved entry for the task that
+// intersects [beginTS, endTS), or nil if there's none.
+func fetchOverlappingTL(db *sql.DB, taskID int, beginTS, endTS time.Time) (*types.TaskLogEntry, error) {
+	entry, err := pers.FetchFirstOverlappingTL(db, taskID, beginTS, endTS)
+	if errors.Is(err, pers.ErrTaskLogNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// duplicateTL saves a copy of entry that spans beginTS to endTS, and asks for
+// the copy to be focused once the task log list is refetched.
+func duplicateTL(db *sql.DB, entry types.TaskLogEntry, beginTS, endTS time.Time) tea.Cmd {
 	return func() tea.Msg {
-		_, err := pers.EditSavedTL(db, tlID, beginTS, endTS, comment)
-		return savedTLEditedMsg{tlID, taskID, err}
+		tlID, err := pers.InsertManualTL(db, entry.TaskID, beginTS, endTS, entry.Comment, entry.Category, entry.Tags, false)
+		if err != nil {
+			return manualTLInsertedMsg{taskID: entry.TaskID, err: err}
+		}
+		return manualTLInsertedMsg{taskID: entry.TaskID, tlIDToFocusOn: &tlID, err: err}
+	}
+}
+
+func editSavedTL(db *sql.DB, prev types.TaskLogEntry, beginTS time.Time, endTS time.Time, comment, category *string, tags []string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := pers.EditSavedTL(db, prev.ID, beginTS, endTS, comment, category, tags, false)
+		return savedTLEditedMsg{prev.ID, prev.TaskID, prev, err}
 	}
 }
 
@@ -109,6 +178,15 @@ func fetchActiveTask(db *sql.DB) tea.Cmd {
 	}
 }
 
+// fetchTrackedTask fetches the task being tracked, for when it isn't on the
+// page of active tasks being shown.
+func fetchTrackedTask(db *sql.DB, taskID int) tea.Cmd {
+	return func() tea.Msg {
+		task, err := pers.FetchTaskByID(db, taskID)
+		return trackedTaskFetchedMsg{task, err}
+	}
+}
+
 func updateTaskRep(db *sql.DB, t *types.Task) tea.Cmd {
 	return func() tea.Msg {
 		err := pers.UpdateTaskData(db, t)
@@ -130,6 +208,30 @@ func fetchTLS(db *sql.DB, tlIDToFocusOn *int) tea.Cmd {
 	}
 }
 
+func fetchTLSForTask(db *sql.DB, taskID int, tlIDToFocusOn *int) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := pers.FetchTLEntriesForTask(db, taskID, true, 50)
+		return tLsFetchedMsg{
+			entries:       entries,
+			tlIDToFocusOn: tlIDToFocusOn,
+			filterTaskID:  &taskID,
+			err:           err,
+		}
+	}
+}
+
+func fetchTLSMatchingComment(db *sql.DB, query string, tlIDToFocusOn *int) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := pers.SearchTaskLogsByComment(db, query, 50)
+		return tLsFetchedMsg{
+			entries:       entries,
+			tlIDToFocusOn: tlIDToFocusOn,
+			commentQuery:  query,
+			err:           err,
+		}
+	}
+}
+
 func deleteTL(db *sql.DB, entry *types.TaskLogEntry) tea.Cmd {
 	return func() tea.Msg {
 		err := pers.DeleteTL(db, entry)
@@ -140,6 +242,17 @@ func deleteTL(db *sql.DB, entry *types.TaskLogEntry) tea.Cmd {
 	}
 }
 
+func deleteAllTLsForTask(db *sql.DB, taskID int) tea.Cmd {
+	return func() tea.Msg {
+		numDeleted, err := pers.DeleteAllTLsForTask(db, taskID)
+		return taskTLsDeletedMsg{
+			taskID:     taskID,
+			numDeleted: numDeleted,
+			err:        err,
+		}
+	}
+}
+
 func deleteActiveTL(db *sql.DB) tea.Cmd {
 	return func() tea.Msg {
 		err := pers.DeleteActiveTL(db)
@@ -154,10 +267,12 @@ func createTask(db *sql.DB, summary string) tea.Cmd {
 	}
 }
 
-func updateTask(db *sql.DB, task *types.Task, summary string) tea.Cmd {
+// updateTask updates the task's summary, as well as when it was created if
+// createdAt is not nil.
+func updateTask(db *sql.DB, task *types.Task, summary string, createdAt *time.Time) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.UpdateTask(db, task.ID, summary)
-		return taskUpdatedMsg{task, summary, err}
+		err := pers.UpdateTaskWithCreatedAt(db, task.ID, summary, createdAt)
+		return taskUpdatedMsg{task, summary, createdAt, err}
 	}
 }
 
@@ -168,10 +283,22 @@ func updateTaskActiveStatus(db *sql.DB, task *types.Task, active bool) tea.Cmd {
 	}
 }
 
-func fetchTasks(db *sql.DB, active bool) tea.Cmd {
+// fetchTasks fetches the page-th (0 based) page of active or inactive tasks.
+func fetchTasks(db *sql.DB, active bool, page int) tea.Cmd {
 	return func() tea.Msg {
-		tasks, err := pers.FetchTasks(db, active, 50)
-		return tasksFetchedMsg{tasks, active, err}
+		// one more task than fits on a page is fetched to find out if there's a
+		// next page
+		tasks, err := pers.FetchTasksPaged(db, active, taskPageSize+1, page*taskPageSize)
+		if err != nil {
+			return tasksFetchedMsg{active: active, page: page, err: err}
+		}
+
+		hasNextPage := len(tasks) > taskPageSize
+		if hasNextPage {
+			tasks = tasks[:taskPageSize]
+		}
+
+		return tasksFetchedMsg{tasks, active, page, hasNextPage, nil}
 	}
 }
 
@@ -201,27 +328,41 @@ func waitForSessionEvent(monitor session.Monitor) tea.Cmd {
 	}
 }
 
+// getRecordsData fetches the records for dateRange. A nil dateRange fetches
+// all time stats, regardless of analyticsType.
 func getRecordsData(
 	analyticsType recordsKind,
 	db *sql.DB,
 	style Style,
-	dateRange types.DateRange,
+	dateRange *types.DateRange,
 	taskStatus types.TaskStatus,
+	tag string,
+	logTag string,
+	taskID *int,
+	limit int,
 	plain bool,
 ) tea.Cmd {
 	return func() tea.Msg {
 		var data string
 		var err error
 
+		if dateRange == nil {
+			data, err = getStats(db, style, nil, taskStatus, tag, taskID, limit, plain)
+			return recordsDataFetchedMsg{
+				report: data,
+				err:    err,
+			}
+		}
+
 		switch analyticsType {
 		case reportRecords:
-			data, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain, fetchTLEntriesForDay)
+			data, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, tag, logTag, taskID, limit, plain, fetchTLEntriesForDay)
 		case reportAggRecords:
-			data, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain, fetchReportEntriesForDay)
+			data, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, tag, logTag, taskID, limit, plain, fetchReportEntriesForDay)
 		case reportLogs:
-			data, err = getTaskLog(db, style, dateRange.Start, dateRange.End, taskStatus, 20, plain)
+			data, err = getTaskLog(db, style, dateRange.Start, dateRange.End, taskStatus, tag, taskID, limit, plain)
 		case reportStats:
-			data, err = getStats(db, style, &dateRange, taskStatus, plain)
+			data, err = getStats(db, style, dateRange, taskStatus, tag, taskID, limit, plain)
 		}
 
 		return recordsDataFetchedMsg{
@@ -232,16 +373,120 @@ func getRecordsData(
 	}
 }
 
+func fetchWeeklyTotals(db *sql.DB, style Style, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		// each task's week starts on its own week reset day, like the "this
+		// week" total shown for the active task
+		entries, err := pers.FetchStatsForTaskWeeks(db, now, statsLogEntriesLimit)
+		if err != nil {
+			return weeklyTotalsFetchedMsg{err: err}
+		}
+
+		totals, err := renderStatsTable(style, entries, statsLogEntriesLimit, false, true)
+		return weeklyTotalsFetchedMsg{totals, err}
+	}
+}
+
+func fetchTodayTotal(db *sql.DB, now time.Time, checkDailyMax bool) tea.Cmd {
+	return func() tea.Msg {
+		secsSpent, err := pers.FetchTodayTotal(db, now)
+		if err != nil {
+			return todayTotalFetchedMsg{checkDailyMax: checkDailyMax, err: err}
+		}
+
+		weeklyGoalSecs, err := pers.GetWeeklyGoal(db)
+		if err != nil {
+			return todayTotalFetchedMsg{secsSpent, 0, 0, checkDailyMax, err}
+		}
+
+		// the week's total is needed even without a goal, as it can be shown
+		// in the task list's title
+		weekSecsSpent, err := fetchWeekTotal(db, now)
+		return todayTotalFetchedMsg{secsSpent, weekSecsSpent, weeklyGoalSecs, checkDailyMax, err}
+	}
+}
+
+func fetchWeekTotal(db *sql.DB, now time.Time) (int, error) {
+	dateRange, err := types.GetDateRangeFromPeriod(types.TimePeriodWeek, now, false, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, pers.TLFilter{TaskStatus: types.TaskStatusAny}, statsLogEntriesLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	var secsSpent int
+	for _, entry := range entries {
+		secsSpent += entry.SecsSpent
+	}
+
+	return secsSpent, nil
+}
+
 func moveTaskLog(db *sql.DB, tlID int, oldTaskID int, newTaskID int, secsSpent int) tea.Cmd {
 	return func() tea.Msg {
 		err := pers.MoveTaskLog(db, tlID, oldTaskID, newTaskID, secsSpent)
-		return taskLogMovedMsg{tlID, oldTaskID, newTaskID, err}
+		return taskLogMovedMsg{tlID, oldTaskID, newTaskID, secsSpent, err}
+	}
+}
+
+// moveTaskLogs moves all of entries to the task with ID newTaskID, or none of
+// them if any can't be moved.
+func moveTaskLogs(db *sql.DB, entries []types.TaskLogEntry, newTaskID int) tea.Cmd {
+	return func() tea.Msg {
+		err := pers.MoveTaskLogs(db, entries, newTaskID)
+		if err != nil {
+			return taskLogsMovedMsg{0, newTaskID, err}
+		}
+		return taskLogsMovedMsg{len(entries), newTaskID, nil}
+	}
+}
+
+// undoTLAction reverses action. Restored entries are allowed to overlap with
+// others, since they were saved that way before.
+func undoTLAction(db *sql.DB, action undoableAction) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		entry := action.entry
+		switch action.kind {
+		case undoDeleteTL:
+			_, err = pers.InsertManualTL(db, entry.TaskID, entry.BeginTS, entry.EndTS, entry.Comment, entry.Category, entry.Tags, true)
+		case undoMoveTL:
+			err = pers.MoveTaskLog(db, entry.ID, action.newTaskID, entry.TaskID, entry.SecsSpent)
+		case undoEditTL:
+			_, err = pers.EditSavedTL(db, entry.ID, entry.BeginTS, entry.EndTS, entry.Comment, entry.Category, entry.Tags, true)
+		}
+		return tLActionUndoneMsg{action, err}
+	}
+}
+
+func reloadTheme(themeName, themesDir string) tea.Cmd {
+	return func() tea.Msg {
+		thm, err := theme.Get(themeName, themesDir)
+		if err != nil {
+			return themeReloadedMsg{err: err}
+		}
+		return themeReloadedMsg{style: NewStyle(thm)}
 	}
 }
 
 func archiveStaleTasks(db *sql.DB, since time.Time) tea.Cmd {
 	return func() tea.Msg {
-		count, err := pers.ArchiveStaleTasks(db, since)
+		count, err := pers.ArchiveStaleTasks(db, since, true)
 		return staleTasksArchivedMsg{count, err}
 	}
 }
+
+func restoreLastArchivedTask(db *sql.DB) tea.Cmd {
+	return func() tea.Msg {
+		task, err := pers.FetchMostRecentlyArchivedTask(db)
+		if err != nil || task == nil {
+			return lastArchivedTaskRestoredMsg{nil, err}
+		}
+
+		err = pers.UpdateTaskActiveStatus(db, task.ID, true)
+		return lastArchivedTaskRestoredMsg{task, err}
+	}
+}
@@ -110,14 +110,46 @@ var (
 	}
 )
 
+// GenerationEstimate describes the data GenerateData creates. The number of
+// task log entries for each task is picked at random, hence the range.
+type GenerationEstimate struct {
+	NumTasks  int
+	MinNumTLs int
+	MaxNumTLs int
+}
+
+// EstimateGeneratedData returns what GenerateData would create for numDays and
+// numTasks, without touching the database.
+func EstimateGeneratedData(numDays, numTasks uint8) GenerationEstimate {
+	minPerTask, maxPerTask := numTLsPerTaskRange(numDays)
+
+	return GenerationEstimate{
+		NumTasks:  int(numTasks),
+		MinNumTLs: int(numTasks) * minPerTask,
+		MaxNumTLs: int(numTasks) * maxPerTask,
+	}
+}
+
+// numTLsPerTaskRange returns the least and the most task log entries
+// GenerateData creates for a single task.
+func numTLsPerTaskRange(numDays uint8) (int, int) {
+	half := int(numDays / 2)
+	if half == 0 {
+		return 0, 0
+	}
+
+	return half, 2*half - 1
+}
+
 func GenerateData(db *sql.DB, numDays, numTasks uint8) error {
+	minNumLogs, maxNumLogs := numTLsPerTaskRange(numDays)
 	for i := range numTasks {
 		summary := tasks[rand.Intn(len(tasks))]
 		_, err := pers.InsertTask(db, summary)
 		if err != nil {
 			return err
 		}
-		numLogs := int(numDays/2) + rand.Intn(int(numDays/2))
+		numLogs := minNumLogs + rand.Intn(maxNumLogs-minNumLogs+1)
 		for range numLogs {
 			beginTs := randomTimestamp(int(numDays))
 			numMinutes := 30 + rand.Intn(60)
@@ -131,7 +163,7 @@ func GenerateData(db *sql.DB, numDays, numTasks uint8) error {
 				comment = &commentStr
 			}
 
-			_, err = pers.InsertManualTL(db, int(i+1), beginTs, endTs, comment)
+			_, err = pers.InsertManualTL(db, int(i+1), beginTs, endTs, comment, nil, nil, true)
 			if err != nil {
 				return err
 			}
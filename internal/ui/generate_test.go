@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateGeneratedData(t *testing.T) {
+	testCases := []struct {
+		name     string
+		numDays  uint8
+		numTasks uint8
+		expected GenerationEstimate
+	}{
+		{
+			name:     "even number of days",
+			numDays:  10,
+			numTasks: 3,
+			expected: GenerationEstimate{NumTasks: 3, MinNumTLs: 15, MaxNumTLs: 27},
+		},
+		{
+			name:     "odd number of days",
+			numDays:  7,
+			numTasks: 2,
+			expected: GenerationEstimate{NumTasks: 2, MinNumTLs: 6, MaxNumTLs: 10},
+		},
+		{
+			name:     "a single day",
+			numDays:  1,
+			numTasks: 4,
+			expected: GenerationEstimate{NumTasks: 4},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateGeneratedData(tt.numDays, tt.numTasks)
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGenerateDataStaysWithinEstimate(t *testing.T) {
+	for _, numDays := range []uint8{1, 7, 10} {
+		// GIVEN
+		db := setupTestDB(t)
+		estimate := EstimateGeneratedData(numDays, 3)
+
+		// WHEN
+		err := GenerateData(db, numDays, 3)
+
+		// THEN
+		require.NoError(t, err)
+		var numTasks, numTLs int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM task").Scan(&numTasks))
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM task_log").Scan(&numTLs))
+		assert.Equal(t, estimate.NumTasks, numTasks)
+		assert.GreaterOrEqual(t, numTLs, estimate.MinNumTLs)
+		assert.LessOrEqual(t, numTLs, estimate.MaxNumTLs)
+		db.Close()
+	}
+}
@@ -7,10 +7,14 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	c "github.com/dhth/hours/internal/common"
 	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/ui/theme"
+	"github.com/dhth/hours/internal/utils"
 )
 
 // commentPtrFromInput returns a pointer to the trimmed textarea value, or nil if it is empty.
@@ -22,10 +26,19 @@ func commentPtrFromInput(input textarea.Model) *string {
 	return &v
 }
 
+func categoryPtrFromInput(input textinput.Model) *string {
+	v := strings.TrimSpace(input.Value())
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
 const (
-	genericErrorMsg               = "Something went wrong"
-	removeFilterMsg               = "Remove filter first"
-	beginTsCannotBeInTheFutureMsg = "Begin timestamp cannot be in the future"
+	genericErrorMsg                 = "Something went wrong"
+	removeFilterMsg                 = "Remove filter first"
+	beginTsCannotBeInTheFutureMsg   = "Begin timestamp cannot be in the future"
+	createdAtCannotBeInTheFutureMsg = "Creation timestamp cannot be in the future"
 )
 
 var suggestReloadingMsg = fmt.Sprintf("Something went wrong, please restart hours; let %s know about this error via %s.", c.Author, c.RepoIssuesURL)
@@ -67,9 +80,9 @@ func (m *Model) handleRequestToGoBackOrQuit() bool {
 		} else {
 			m.activeView = taskLogView
 		}
-	case helpView:
+	case helpView, weeklyTotalsView:
 		m.activeView = m.lastView
-	case moveTaskLogView:
+	case moveTaskLogView, filterTaskLogView:
 		m.activeView = taskLogView
 		m.targetTasksList.ResetFilter()
 	}
@@ -77,22 +90,97 @@ func (m *Model) handleRequestToGoBackOrQuit() bool {
 	return shouldQuit
 }
 
+// listForView returns the list shown in view, if any.
+func (m *Model) listForView(view stateView) *list.Model {
+	switch view {
+	case taskListView:
+		return &m.activeTasksList
+	case taskLogView:
+		return &m.taskLogList
+	case inactiveTaskListView:
+		return &m.inactiveTasksList
+	}
+	return nil
+}
+
+// switchToListView makes view the active one. The selection of the list being
+// left is remembered, and the one view's list had when it was last left is
+// restored, so that refreshes in the meantime don't send it back to the top.
+func (m *Model) switchToListView(view stateView) {
+	if l := m.listForView(m.activeView); l != nil {
+		m.listSelections[m.activeView] = l.Index()
+	}
+
+	m.activeView = view
+
+	l := m.listForView(view)
+	if l == nil || l.FilterState() != list.Unfiltered {
+		return
+	}
+
+	index, ok := m.listSelections[view]
+	if !ok || len(l.Items()) == 0 {
+		return
+	}
+
+	l.Select(min(index, len(l.Items())-1))
+}
+
 func (m *Model) getCmdToReloadData() tea.Cmd {
 	var cmd tea.Cmd
 	switch m.activeView {
 	case taskListView:
-		cmd = fetchTasks(m.db, true)
+		cmd = fetchTasks(m.db, true, m.activeTasksPage)
 	case taskLogView:
-		cmd = fetchTLS(m.db, nil)
+		cmd = m.getCmdToRefreshTLS(nil)
 		m.taskLogList.ResetSelected()
 	case inactiveTaskListView:
-		cmd = fetchTasks(m.db, false)
+		cmd = fetchTasks(m.db, false, m.inactiveTasksPage)
 		m.inactiveTasksList.ResetSelected()
 	}
 
 	return cmd
 }
 
+func (m *Model) getCmdToReloadTheme() tea.Cmd {
+	if !strings.HasPrefix(m.themeName, theme.CustomThemePrefix) {
+		m.message = errMsg("Only custom themes can be reloaded")
+		return nil
+	}
+
+	return reloadTheme(m.themeName, m.themesDir)
+}
+
+// applyStyle switches to a new style, including for the lists and the help
+// view, which hold on to the colors they were set up with.
+func (m *Model) applyStyle(style Style) {
+	m.style = style
+
+	titleFG := lipgloss.Color(style.theme.TitleForeground)
+	for _, l := range []struct {
+		list  *list.Model
+		color lipgloss.Color
+	}{
+		{&m.activeTasksList, lipgloss.Color(style.theme.ActiveTasks)},
+		{&m.taskLogList, lipgloss.Color(style.theme.TaskLogList)},
+		{&m.inactiveTasksList, lipgloss.Color(style.theme.InactiveTasks)},
+		{&m.targetTasksList, lipgloss.Color(style.theme.ActiveTasks)},
+	} {
+		l.list.SetDelegate(newItemDelegate(style.listItemTitleColor, style.listItemDescColor, l.color))
+		l.list.Styles.Title = l.list.Styles.Title.
+			Foreground(titleFG).
+			Background(l.color)
+	}
+	m.taskLogList.SetDelegate(newTaskLogDelegate(
+		newItemDelegate(style.listItemTitleColor, style.listItemDescColor, lipgloss.Color(style.theme.TaskLogList)),
+		m.selectedTLIDs,
+	))
+
+	if m.helpVPReady {
+		m.helpVP.SetContent(getHelpText(style))
+	}
+}
+
 func (m *Model) handleRequestToScrollVPUp() {
 	switch m.activeView {
 	case helpView:
@@ -127,6 +215,58 @@ func (m *Model) handleRequestToScrollVPDown() {
 	}
 }
 
+// handleRequestToToggleDurationFormat switches durations in all list
+// descriptions between the full and compact formats.
+func (m *Model) handleRequestToToggleDurationFormat() {
+	switch m.activeView {
+	case taskListView, taskLogView, inactiveTaskListView:
+	default:
+		return
+	}
+
+	if m.durationFormat == types.DurationFormatFull {
+		m.durationFormat = types.DurationFormatCompact
+		m.message = infoMsg("Showing compact durations")
+	} else {
+		m.durationFormat = types.DurationFormatFull
+		m.message = infoMsg("Showing full durations")
+	}
+
+	for _, l := range []*list.Model{&m.activeTasksList, &m.inactiveTasksList} {
+		for _, item := range l.Items() {
+			if task, ok := item.(*types.Task); ok {
+				task.UpdateListDesc(m.timeProvider, m.durationFormat)
+			}
+		}
+	}
+
+	for i, item := range m.taskLogList.Items() {
+		if entry, ok := item.(types.TaskLogEntry); ok {
+			entry.UpdateListDesc(m.timeProvider, m.durationFormat)
+			m.taskLogList.SetItem(i, entry)
+		}
+	}
+
+	m.updateActiveTasksListTitle()
+}
+
+// handleRequestToToggleActiveTLElapsed switches the active task log in the
+// footer between showing when it began and how long it has been running.
+func (m *Model) handleRequestToToggleActiveTLElapsed() {
+	switch m.activeView {
+	case taskListView, taskLogView, inactiveTaskListView:
+	default:
+		return
+	}
+
+	m.showActiveTLElapsed = !m.showActiveTLElapsed
+	if m.showActiveTLElapsed {
+		m.message = infoMsg("Showing time elapsed on the active task")
+	} else {
+		m.message = infoMsg("Showing when the active task began")
+	}
+}
+
 func (m *Model) handleWindowResizing(msg tea.WindowSizeMsg) {
 	w, h := m.style.list.GetFrameSize()
 
@@ -185,50 +325,135 @@ func (m *Model) handleTasksFetchedMsg(msg tasksFetchedMsg) tea.Cmd {
 		return nil
 	}
 
+	// the last page can end up empty, eg. after deactivating its only task
+	if len(msg.tasks) == 0 && msg.page > 0 {
+		return fetchTasks(m.db, msg.active, msg.page-1)
+	}
+
 	var cmd tea.Cmd
 	switch msg.active {
 	case true:
+		m.activeTasksPage = msg.page
+		m.activeTasksHasNextPage = msg.hasNextPage
+		trackedTask, trackedTaskKnown := m.taskMap[m.activeTaskID]
 		m.taskMap = make(map[int]*types.Task)
 		m.taskIndexMap = make(map[int]int)
 		tasks := make([]list.Item, len(msg.tasks))
 		for i, task := range msg.tasks {
 			task.UpdateListTitle()
-			task.UpdateListDesc(m.timeProvider)
+			task.UpdateListDesc(m.timeProvider, m.durationFormat)
 			tasks[i] = &task
 			m.taskMap[task.ID] = &task
 			m.taskIndexMap[task.ID] = i
 		}
+		// the tracked task is needed to stop tracking it, and for the footer,
+		// even when it's on another page
+		if _, ok := m.taskMap[m.activeTaskID]; !ok && m.trackingActive && trackedTaskKnown {
+			m.taskMap[m.activeTaskID] = trackedTask
+		}
 		m.activeTasksList.SetItems(tasks)
-		m.activeTasksList.Title = "Tasks"
+		m.sortActiveTasks()
+		m.updateActiveTasksListTitle()
 		m.tasksFetched = true
-		cmd = fetchActiveTask(m.db)
+		cmd = tea.Batch(fetchActiveTask(m.db), m.getCmdToFetchTodayTotal(false))
 
 	case false:
+		m.inactiveTasksPage = msg.page
+		m.inactiveTasksHasNextPage = msg.hasNextPage
 		inactiveTasks := make([]list.Item, len(msg.tasks))
 		for i, inactiveTask := range msg.tasks {
 			inactiveTask.UpdateListTitle()
-			inactiveTask.UpdateListDesc(m.timeProvider)
+			inactiveTask.UpdateListDesc(m.timeProvider, m.durationFormat)
 			inactiveTasks[i] = &inactiveTask
 		}
 		m.inactiveTasksList.SetItems(inactiveTasks)
+		m.inactiveTasksList.Title = pagedListTitle("Inactive Tasks", msg.page, msg.hasNextPage)
 	}
 
 	return cmd
 }
 
+// updateActiveTasksListTitle sets the title of the active tasks list, which
+// includes the time tracked this week if requested.
+func (m *Model) updateActiveTasksListTitle() {
+	title := pagedListTitle("Tasks", m.activeTasksPage, m.activeTasksHasNextPage)
+	if m.showWeekTotalInTitle {
+		title = fmt.Sprintf("%s — %s this week", title, m.durationFormat.Format(m.weekTotalSecs))
+	}
+
+	m.activeTasksList.Title = title
+}
+
+// handleRequestToToggleWeekTotalInTitle shows (or hides) the time tracked this
+// week in the active tasks list's title.
+func (m *Model) handleRequestToToggleWeekTotalInTitle() {
+	if m.activeView != taskListView {
+		return
+	}
+
+	m.showWeekTotalInTitle = !m.showWeekTotalInTitle
+	m.updateActiveTasksListTitle()
+}
+
+// pagedListTitle returns title, along with the (1 based) page number when
+// tasks don't fit on a single page.
+func pagedListTitle(title string, page int, hasNextPage bool) string {
+	if page == 0 && !hasNextPage {
+		return title
+	}
+
+	return fmt.Sprintf("%s (page %d)", title, page+1)
+}
+
+// getCmdToChangeTasksPage returns a command to fetch the next (or previous)
+// page of tasks in the current task list view.
+func (m *Model) getCmdToChangeTasksPage(next bool) tea.Cmd {
+	var active bool
+	var page int
+	var hasNextPage bool
+	switch m.activeView {
+	case taskListView:
+		active, page, hasNextPage = true, m.activeTasksPage, m.activeTasksHasNextPage
+	case inactiveTaskListView:
+		active, page, hasNextPage = false, m.inactiveTasksPage, m.inactiveTasksHasNextPage
+	default:
+		return nil
+	}
+
+	switch {
+	case next && !hasNextPage:
+		m.message = errMsg("Already on the last page of tasks")
+		return nil
+	case !next && page == 0:
+		m.message = errMsg("Already on the first page of tasks")
+		return nil
+	case next:
+		page++
+	default:
+		page--
+	}
+
+	return fetchTasks(m.db, active, page)
+}
+
 func (m *Model) handleManualTLInsertedMsg(msg manualTLInsertedMsg) []tea.Cmd {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
 		return nil
 	}
 
+	if msg.overlapsWith != nil {
+		m.message = warnMsg(overlapWarning(*msg.overlapsWith))
+	}
+
 	task, ok := m.taskMap[msg.taskID]
 
 	var cmds []tea.Cmd
 	if ok {
 		cmds = append(cmds, updateTaskRep(m.db, task))
 	}
-	cmds = append(cmds, fetchTLS(m.db, nil))
+	cmds = append(cmds, m.getCmdToRefreshTLS(msg.tlIDToFocusOn))
+	cmds = append(cmds, m.getCmdToFetchTodayTotal(true))
 	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 		cmds = append(cmds, syncCmd)
 	}
@@ -236,19 +461,51 @@ func (m *Model) handleManualTLInsertedMsg(msg manualTLInsertedMsg) []tea.Cmd {
 	return cmds
 }
 
+// getCmdToFetchTodayTotal returns a command to fetch the time tracked today,
+// and this week's progress towards the weekly goal, which are shown in the
+// footer. If checkDailyMax is set, the user is also
+// warned if the total exceeds the configured daily max.
+func (m *Model) getCmdToFetchTodayTotal(checkDailyMax bool) tea.Cmd {
+	return fetchTodayTotal(m.db, m.timeProvider.Now(), checkDailyMax)
+}
+
+func (m *Model) handleTodayTotalFetchedMsg(msg todayTotalFetchedMsg) {
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error fetching today's total: %s", msg.err))
+		return
+	}
+
+	m.todayTotalSecs = msg.secsSpent
+	m.weekTotalSecs = msg.weekSecsSpent
+	m.weeklyGoalSecs = msg.weeklyGoalSecs
+	m.updateActiveTasksListTitle()
+
+	if !msg.checkDailyMax || m.dailyMax <= 0 || time.Duration(msg.secsSpent)*time.Second <= m.dailyMax {
+		return
+	}
+
+	m.message = errMsg(fmt.Sprintf("Heads up: you've tracked %s today, which is more than your daily max of %s",
+		types.HumanizeDuration(msg.secsSpent),
+		types.HumanizeDuration(int(m.dailyMax.Seconds())),
+	))
+}
+
 func (m *Model) handleSavedTLEditedMsg(msg savedTLEditedMsg) []tea.Cmd {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
 		return nil
 	}
 
+	m.lastUndoable = &undoableAction{kind: undoEditTL, entry: msg.prev}
+
 	task, ok := m.taskMap[msg.taskID]
 
 	var cmds []tea.Cmd
 	if ok {
 		cmds = append(cmds, updateTaskRep(m.db, task))
 	}
-	cmds = append(cmds, fetchTLS(m.db, &msg.tlID))
+	cmds = append(cmds, m.getCmdToRefreshTLS(&msg.tlID))
+	cmds = append(cmds, m.getCmdToFetchTodayTotal(false))
 	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 		cmds = append(cmds, syncCmd)
 	}
@@ -256,18 +513,87 @@ func (m *Model) handleSavedTLEditedMsg(msg savedTLEditedMsg) []tea.Cmd {
 	return cmds
 }
 
+// overlapWarning tells the user that a task log entry was saved even though it
+// overlaps with entry.
+func overlapWarning(entry types.TaskLogEntry) string {
+	return fmt.Sprintf("Heads up: saved, but this overlaps with a saved entry for the task (%s - %s)",
+		entry.BeginTS.Format(timeFormat),
+		entry.EndTS.Format(timeFormat),
+	)
+}
+
+func (m *Model) handleOverlappingTLFetchedMsg(msg overlappingTLFetchedMsg) {
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error looking up overlapping entries: %s", msg.err))
+		return
+	}
+
+	if m.activeView != finishActiveTLView {
+		return
+	}
+
+	if !msg.found {
+		m.message = infoMsg("End time doesn't overlap with any saved entry for this task")
+		return
+	}
+
+	if !msg.entry.BeginTS.After(msg.beginTS) {
+		m.message = errMsg(fmt.Sprintf("A saved entry (%s - %s) overlaps with the begin time; end time can't be adjusted to avoid it",
+			msg.entry.BeginTS.Format(timeFormat),
+			msg.entry.EndTS.Format(timeOnlyFormat),
+		))
+		return
+	}
+
+	m.tLInputs[entryEndTS].SetValue(msg.entry.BeginTS.Format(timeFormat))
+	m.message = infoMsg("Moved end time back to the begin of the next saved entry")
+}
+
+func (m *Model) handleRecentCommentsFetchedMsg(msg recentCommentsFetchedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error fetching recent comments: %s", msg.err))
+		return nil
+	}
+
+	if !m.trackingActive || msg.taskID != m.activeTaskID {
+		return nil
+	}
+
+	if len(msg.comments) == 0 {
+		m.message = errMsg("No previous comments for this task")
+		return nil
+	}
+
+	comment := nextRecentComment(msg.comments, m.activeTLComment, msg.forward)
+	m.message = infoMsg(fmt.Sprintf("Comment: %s", comment))
+	return updateActiveTL(m.db, m.activeTLBeginTS, &comment)
+}
+
 func (m *Model) handleTLSFetchedMsg(msg tLsFetchedMsg) {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
 		return
 	}
 
+	m.taskLogFilterTaskID = -1
+	m.taskLogCommentQuery = msg.commentQuery
+	m.taskLogList.Title = taskLogListTitle
+	if msg.commentQuery != "" {
+		m.taskLogList.Title = fmt.Sprintf("Task Logs matching %q", utils.Trim(msg.commentQuery, 30))
+	}
+	if msg.filterTaskID != nil {
+		m.taskLogFilterTaskID = *msg.filterTaskID
+		if task, ok := m.taskMap[*msg.filterTaskID]; ok {
+			m.taskLogList.Title = fmt.Sprintf("Task Logs: %s (last 50)", utils.Trim(task.Summary, 40))
+		}
+	}
+
 	items := make([]list.Item, len(msg.entries))
 	var indexToFocusOn *int
 	var indexToFocusOnFound bool
 	for i, e := range msg.entries {
 		e.UpdateListTitle()
-		e.UpdateListDesc(m.timeProvider)
+		e.UpdateListDesc(m.timeProvider, m.durationFormat)
 		items[i] = e
 		if !indexToFocusOnFound && msg.tlIDToFocusOn != nil && e.ID == *msg.tlIDToFocusOn {
 			indexToFocusOn = &i
@@ -278,6 +604,7 @@ func (m *Model) handleTLSFetchedMsg(msg tLsFetchedMsg) {
 
 	if indexToFocusOn != nil {
 		m.taskLogList.Select(*indexToFocusOn)
+		m.listSelections[taskLogView] = *indexToFocusOn
 	} else {
 		m.taskLogList.Select(0)
 	}
@@ -302,22 +629,48 @@ func (m *Model) handleActiveTaskFetchedMsg(msg activeTaskFetchedMsg) tea.Cmd {
 	m.activeTLBeginTS = msg.activeTask.CurrentLogBeginTS
 	m.activeTLComment = msg.activeTask.CurrentLogComment
 
+	m.trackingActive = true
+
 	activeTask, ok := m.taskMap[m.activeTaskID]
-	if ok {
-		activeTask.TrackingActive = true
-		activeTask.UpdateListTitle()
+	if !ok {
+		return tea.Batch(fetchTrackedTask(m.db, m.activeTaskID), m.scheduleBackgroundSyncCmd())
+	}
 
-		// go to tracked item on startup
-		activeIndex, aOk := m.taskIndexMap[msg.activeTask.TaskID]
-		if aOk {
-			m.activeTasksList.Select(activeIndex)
-		}
+	activeTask.TrackingActive = true
+	activeTask.UpdateListTitle()
+
+	// go to tracked item on startup
+	activeIndex, aOk := m.taskIndexMap[msg.activeTask.TaskID]
+	if aOk {
+		m.activeTasksList.Select(activeIndex)
 	}
-	m.trackingActive = true
 
 	return m.scheduleBackgroundSyncCmd()
 }
 
+// handleTrackedTaskFetchedMsg keeps track of the task being tracked when it
+// isn't on the page of active tasks being shown.
+func (m *Model) handleTrackedTaskFetchedMsg(msg trackedTaskFetchedMsg) {
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error fetching the task being tracked: %s", msg.err))
+		return
+	}
+
+	if !m.trackingActive || msg.task.ID != m.activeTaskID {
+		return
+	}
+
+	if _, ok := m.taskMap[msg.task.ID]; ok {
+		return
+	}
+
+	task := msg.task
+	task.TrackingActive = true
+	task.UpdateListTitle()
+	task.UpdateListDesc(m.timeProvider, m.durationFormat)
+	m.taskMap[task.ID] = &task
+}
+
 func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
@@ -331,6 +684,7 @@ func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 	}
 
 	m.changesLocked = false
+	m.idleSince = time.Time{}
 	autoStopped := msg.finished && m.autoStopTaskID == msg.taskID
 	if msg.finished {
 		m.autoStopTaskID = -1
@@ -353,12 +707,16 @@ func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 			m.autoResumeAt = time.Time{}
 		}
 		m.lastTrackingChange = trackingFinished
+		if msg.overlapsWith != nil {
+			m.message = warnMsg(overlapWarning(*msg.overlapsWith))
+		}
 		task.TrackingActive = false
 		m.activeTLComment = nil
 		m.trackingActive = false
 		m.activeTaskID = -1
 		cmds = append(cmds, updateTaskRep(m.db, task))
-		cmds = append(cmds, fetchTLS(m.db, nil))
+		cmds = append(cmds, m.getCmdToRefreshTLS(nil))
+		cmds = append(cmds, m.getCmdToFetchTodayTotal(true))
 		if autoStopped && !m.sessionLocked {
 			if resumeCmd := m.getCmdToResumeAutoStoppedTaskAt(time.Time{}); resumeCmd != nil {
 				cmds = append(cmds, resumeCmd)
@@ -375,6 +733,7 @@ func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 		task.TrackingActive = true
 		m.trackingActive = true
 		m.activeTaskID = msg.taskID
+		m.activeTLComment = msg.comment
 		if m.autoResumeNoticePending {
 			m.message = infoMsg(autoResumeNoticeMsg(m.autoResumePauseDuration))
 			m.autoResumeNoticePending = false
@@ -424,11 +783,12 @@ func (m *Model) handleActiveTLSwitchedMsg(msg activeTLSwitchedMsg) []tea.Cmd {
 	currentlyActiveTask.UpdateListTitle()
 
 	m.activeTLComment = nil
+	m.idleSince = time.Time{}
 	m.activeTaskID = msg.currentlyActiveTaskID
 	m.activeTLBeginTS = msg.ts
 
 	var cmds []tea.Cmd
-	cmds = append(cmds, fetchTLS(m.db, nil))
+	cmds = append(cmds, m.getCmdToRefreshTLS(nil))
 	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 		cmds = append(cmds, syncCmd)
 	}
@@ -445,12 +805,99 @@ func (m *Model) handleTLDeleted(msg tLDeletedMsg) []tea.Cmd {
 		return nil
 	}
 
+	m.lastUndoable = &undoableAction{kind: undoDeleteTL, entry: *msg.entry}
+
 	var cmds []tea.Cmd
 	task, ok := m.taskMap[msg.entry.TaskID]
 	if ok {
 		cmds = append(cmds, updateTaskRep(m.db, task))
 	}
-	cmds = append(cmds, fetchTLS(m.db, nil))
+	cmds = append(cmds, m.getCmdToRefreshTLS(nil))
+	cmds = append(cmds, m.getCmdToFetchTodayTotal(false))
+
+	return cmds
+}
+
+func (m *Model) handleTaskTLsDeletedMsg(msg taskTLsDeletedMsg) []tea.Cmd {
+	if msg.err != nil {
+		m.message = errMsg("Error deleting task log entries: " + msg.err.Error())
+		return nil
+	}
+
+	m.message = infoMsg(fmt.Sprintf("Deleted %d task log entries", msg.numDeleted))
+
+	var cmds []tea.Cmd
+	if task, ok := m.taskMap[msg.taskID]; ok {
+		cmds = append(cmds, updateTaskRep(m.db, task))
+	}
+	cmds = append(cmds, m.getCmdToRefreshTLS(nil))
+	cmds = append(cmds, m.getCmdToFetchTodayTotal(false))
+	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
+		cmds = append(cmds, syncCmd)
+	}
+
+	return cmds
+}
+
+func (m *Model) handleTaskLogsMovedMsg(msg taskLogsMovedMsg) []tea.Cmd {
+	m.activeView = taskLogView
+	m.targetTasksList.ResetFilter()
+	m.moveTLs = nil
+	clear(m.selectedTLIDs)
+
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error moving task logs: %s", msg.err))
+	} else {
+		m.message = infoMsg(fmt.Sprintf("Moved %d task log entries", msg.numMoved))
+	}
+	if msg.numMoved == 0 {
+		return nil
+	}
+
+	cmds := []tea.Cmd{
+		m.getCmdToRefreshTLS(nil),
+		fetchTasks(m.db, true, m.activeTasksPage),
+	}
+	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
+		cmds = append(cmds, syncCmd)
+	}
+
+	return cmds
+}
+
+func (m *Model) getCmdToUndoLastTLAction() tea.Cmd {
+	if m.lastUndoable == nil {
+		m.message = errMsg("Nothing to undo")
+		return nil
+	}
+
+	return undoTLAction(m.db, *m.lastUndoable)
+}
+
+func (m *Model) handleTLActionUndoneMsg(msg tLActionUndoneMsg) []tea.Cmd {
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Error undoing last action: %s", msg.err))
+		return nil
+	}
+
+	m.lastUndoable = nil
+	switch msg.action.kind {
+	case undoDeleteTL:
+		m.message = infoMsg("Restored deleted task log entry")
+	case undoMoveTL:
+		m.message = infoMsg("Moved task log entry back")
+	case undoEditTL:
+		m.message = infoMsg("Reverted changes to task log entry")
+	}
+
+	cmds := []tea.Cmd{
+		m.getCmdToRefreshTLS(nil),
+		fetchTasks(m.db, true, m.activeTasksPage),
+		fetchTasks(m.db, false, m.inactiveTasksPage),
+	}
+	if syncCmd := m.requestSyncCmd(); syncCmd != nil {
+		cmds = append(cmds, syncCmd)
+	}
 
 	return cmds
 }
@@ -472,6 +919,7 @@ func (m *Model) handleActiveTLDeletedMsg(msg activeTaskLogDeletedMsg) {
 	m.lastTrackingChange = trackingFinished
 	m.trackingActive = false
 	m.activeTLComment = nil
+	m.idleSince = time.Time{}
 	m.activeTaskID = -1
 	m.autoStopTaskID = -1
 	m.autoResumeTaskID = -1
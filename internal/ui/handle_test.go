@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,6 +91,63 @@ func TestHandleCopyTaskSummary(t *testing.T) {
 	}
 }
 
+func TestHandleCopyTaskLogComment(t *testing.T) {
+	testCases := []struct {
+		name            string
+		setupModel      func() Model
+		expectedMsg     string
+		expectedMsgKind userMsgKind
+	}{
+		{
+			name: "success",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				entry := createTestTaskLogEntry(1, 1, "Test task summary", m.timeProvider)
+				m.taskLogList.SetItems([]list.Item{*entry})
+				m.taskLogList.Select(0)
+				return m
+			},
+			expectedMsg:     "Copied to clipboard",
+			expectedMsgKind: userMsgInfo,
+		},
+		{
+			name: "entry without comment",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				entry := createTestTaskLogEntry(1, 1, "Test task summary", m.timeProvider)
+				entry.Comment = nil
+				m.taskLogList.SetItems([]list.Item{*entry})
+				m.taskLogList.Select(0)
+				return m
+			},
+			expectedMsg:     "No comment to copy",
+			expectedMsgKind: userMsgErr,
+		},
+		{
+			name: "no entry selected",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				return m
+			},
+			expectedMsg:     "No task log entry selected",
+			expectedMsgKind: userMsgErr,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.setupModel()
+			m.handleCopyTaskLogComment()
+
+			assert.Equal(t, tt.expectedMsg, m.message.value)
+			assert.Equal(t, tt.expectedMsgKind, m.message.kind)
+		})
+	}
+}
+
 // T-082: handle.go async message handler tests
 
 func TestHandleTasksFetchedMsg(t *testing.T) {
@@ -114,7 +172,7 @@ func TestHandleTasksFetchedMsg(t *testing.T) {
 
 		cmd := m.handleTasksFetchedMsg(msg)
 
-		// returns fetchActiveTask cmd
+		// returns cmds to fetch the active task and today's total
 		require.NotNil(t, cmd)
 		assert.True(t, m.tasksFetched)
 		assert.Len(t, m.taskMap, 2)
@@ -125,6 +183,54 @@ func TestHandleTasksFetchedMsg(t *testing.T) {
 		assert.Len(t, m.activeTasksList.Items(), 2)
 	})
 
+	t.Run("page is shown in the list title when tasks don't fit on one page", func(t *testing.T) {
+		m := createTestModel()
+		tasks := []types.Task{
+			{ID: 1, Summary: "task one", Active: true, UpdatedAt: referenceTime},
+		}
+		msg := tasksFetchedMsg{tasks: tasks, active: true, page: 1, hasNextPage: true}
+
+		m.handleTasksFetchedMsg(msg)
+
+		assert.Equal(t, 1, m.activeTasksPage)
+		assert.True(t, m.activeTasksHasNextPage)
+		assert.Equal(t, "Tasks (page 2)", m.activeTasksList.Title)
+	})
+
+	t.Run("week's total is shown in the list title when requested", func(t *testing.T) {
+		m := createTestModel()
+		m.weekTotalSecs = 18 * 60 * 60
+		tasks := []types.Task{
+			{ID: 1, Summary: "task one", Active: true, UpdatedAt: referenceTime},
+		}
+		msg := tasksFetchedMsg{tasks: tasks, active: true}
+
+		m.handleTasksFetchedMsg(msg)
+		assert.Equal(t, "Tasks", m.activeTasksList.Title)
+
+		m.handleRequestToToggleWeekTotalInTitle()
+		assert.Equal(t, "Tasks — 18h this week", m.activeTasksList.Title)
+
+		m.weekTotalSecs = 19 * 60 * 60
+		m.handleTasksFetchedMsg(msg)
+		assert.Equal(t, "Tasks — 19h this week", m.activeTasksList.Title)
+
+		m.handleRequestToToggleWeekTotalInTitle()
+		assert.Equal(t, "Tasks", m.activeTasksList.Title)
+	})
+
+	t.Run("empty page falls back to the previous one", func(t *testing.T) {
+		m := createTestModel()
+		m.activeTasksPage = 2
+		msg := tasksFetchedMsg{active: true, page: 2}
+
+		cmd := m.handleTasksFetchedMsg(msg)
+
+		require.NotNil(t, cmd)
+		assert.Equal(t, 2, m.activeTasksPage)
+		assert.False(t, m.tasksFetched)
+	})
+
 	t.Run("inactive tasks populate inactiveTasksList", func(t *testing.T) {
 		m := createTestModel()
 		tasks := []types.Task{
@@ -160,8 +266,8 @@ func TestHandleManualTLInsertedMsg(t *testing.T) {
 
 		cmds := m.handleManualTLInsertedMsg(msg)
 
-		// updateTaskRep + fetchTLS = 2 cmds
-		require.Len(t, cmds, 2)
+		// updateTaskRep + fetchTLS + fetchTodayTotal = 3 cmds
+		require.Len(t, cmds, 3)
 	})
 
 	t.Run("success with unknown task returns only fetchTLS cmd", func(t *testing.T) {
@@ -171,7 +277,31 @@ func TestHandleManualTLInsertedMsg(t *testing.T) {
 
 		cmds := m.handleManualTLInsertedMsg(msg)
 
-		require.Len(t, cmds, 1)
+		require.Len(t, cmds, 2)
+	})
+
+	t.Run("overlapping entry is saved with a warning", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		m := createTestModel()
+		m.db = db
+		taskID := int(insertTestTask(t, db, "task", true))
+		m.taskMap[taskID] = createTestTask(taskID, "task", true, false, m.timeProvider)
+		day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+		insertTestTaskLog(t, db, int64(taskID), day.Add(9*time.Hour), day.Add(10*time.Hour), "existing")
+
+		// WHEN
+		msg := insertManualTL(db, taskID, day.Add(9*time.Hour+30*time.Minute), day.Add(11*time.Hour), nil, nil, nil, 0)()
+		cmds := m.handleManualTLInsertedMsg(msg.(manualTLInsertedMsg))
+
+		// THEN
+		require.Len(t, cmds, 3)
+		assert.Equal(t, userMsgWarn, m.message.kind)
+		assert.Contains(t, m.message.value, "overlaps with a saved entry")
+		entries, _, err := persistence.FetchTLEntriesBetweenTS(db, day, day.AddDate(0, 0, 1), persistence.TLFilter{TaskStatus: types.TaskStatusAny}, 10)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
 	})
 }
 
@@ -195,7 +325,7 @@ func TestHandleSavedTLEditedMsg(t *testing.T) {
 
 		cmds := m.handleSavedTLEditedMsg(msg)
 
-		require.Len(t, cmds, 2)
+		require.Len(t, cmds, 3)
 	})
 
 	t.Run("success with unknown task returns only fetchTLS cmd", func(t *testing.T) {
@@ -204,7 +334,7 @@ func TestHandleSavedTLEditedMsg(t *testing.T) {
 
 		cmds := m.handleSavedTLEditedMsg(msg)
 
-		require.Len(t, cmds, 1)
+		require.Len(t, cmds, 2)
 	})
 }
 
@@ -322,6 +452,33 @@ func TestHandleTrackingToggledMsg(t *testing.T) {
 		assert.Equal(t, userMsgErr, m.message.kind)
 	})
 
+	t.Run("finishing a log that overlaps with a saved one warns", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		m := createTestModel()
+		m.db = db
+		taskID := int(insertTestTask(t, db, "task", true))
+		task := createTestTask(taskID, "task", true, true, m.timeProvider)
+		m.taskMap[taskID] = task
+		m.trackingActive = true
+		m.activeTaskID = taskID
+		day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+		insertTestTaskLog(t, db, int64(taskID), day.Add(9*time.Hour), day.Add(10*time.Hour), "existing")
+		_, err := persistence.InsertNewTL(db, taskID, day.Add(9*time.Hour+30*time.Minute))
+		require.NoError(t, err)
+
+		// WHEN
+		msg := toggleTracking(db, taskID, day.Add(9*time.Hour+30*time.Minute), day.Add(11*time.Hour), nil, nil, nil, persistence.FinishTLOptions{})()
+		m.handleTrackingToggledMsg(msg.(trackingToggledMsg))
+
+		// THEN
+		assert.Equal(t, userMsgWarn, m.message.kind)
+		assert.Contains(t, m.message.value, "overlaps with a saved entry")
+		assert.False(t, m.trackingActive)
+		assert.Equal(t, trackingFinished, m.lastTrackingChange)
+	})
+
 	t.Run("finished=true clears tracking state", func(t *testing.T) {
 		m := createTestModel()
 		task := createTestTask(1, "task", true, true, m.timeProvider)
@@ -338,8 +495,8 @@ func TestHandleTrackingToggledMsg(t *testing.T) {
 		assert.False(t, task.TrackingActive)
 		assert.False(t, m.changesLocked)
 		assert.Equal(t, -1, m.autoResumeTaskID)
-		// updateTaskRep + fetchTLS = 2 cmds
-		require.Len(t, cmds, 2)
+		// updateTaskRep + fetchTLS + fetchTodayTotal = 3 cmds
+		require.Len(t, cmds, 3)
 	})
 
 	t.Run("auto-stopped task while locked becomes resume candidate", func(t *testing.T) {
@@ -357,7 +514,7 @@ func TestHandleTrackingToggledMsg(t *testing.T) {
 		assert.Equal(t, -1, m.activeTaskID)
 		assert.Equal(t, -1, m.autoStopTaskID)
 		assert.Equal(t, 1, m.autoResumeTaskID)
-		require.Len(t, cmds, 2)
+		require.Len(t, cmds, 3)
 	})
 
 	t.Run("auto-stopped task after unlock resumes immediately", func(t *testing.T) {
@@ -380,7 +537,7 @@ func TestHandleTrackingToggledMsg(t *testing.T) {
 		assert.True(t, m.autoResumeNoticePending)
 		assert.Equal(t, 20*time.Minute, m.autoResumePauseDuration)
 		assert.Empty(t, m.message.value)
-		require.Len(t, cmds, 3)
+		require.Len(t, cmds, 4)
 	})
 
 	t.Run("finished=false sets tracking started", func(t *testing.T) {
@@ -501,7 +658,7 @@ func TestHandleTLDeleted(t *testing.T) {
 
 		cmds := m.handleTLDeleted(msg)
 
-		require.Len(t, cmds, 2)
+		require.Len(t, cmds, 3)
 	})
 
 	t.Run("success with unknown task returns only fetchTLS cmd", func(t *testing.T) {
@@ -511,7 +668,7 @@ func TestHandleTLDeleted(t *testing.T) {
 
 		cmds := m.handleTLDeleted(msg)
 
-		require.Len(t, cmds, 1)
+		require.Len(t, cmds, 2)
 	})
 }
 
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+)
+
+// HeaderMeta holds the details shown in the header that can be prepended to
+// the output of the log, report, and stats commands, so that saved output
+// describes how it was generated.
+type HeaderMeta struct {
+	Command     string
+	GeneratedAt time.Time
+}
+
+func renderHeaderMeta(style Style, meta HeaderMeta, dateRange *types.DateRange, taskStatus types.TaskStatus, plain bool) string {
+	var rangeStr string
+	if dateRange == nil {
+		rangeStr = "all"
+	} else {
+		rangeStr = fmt.Sprintf("%s...%s",
+			dateRange.Start.Format(dateFormat),
+			dateRange.End.AddDate(0, 0, -1).Format(dateFormat),
+		)
+	}
+
+	header := strings.Join([]string{
+		fmt.Sprintf("command: %s", meta.Command),
+		fmt.Sprintf("range: %s", rangeStr),
+		fmt.Sprintf("task status: %s", taskStatus),
+		fmt.Sprintf("generated at: %s", meta.GeneratedAt.Format(timeFormat)),
+	}, " | ")
+
+	if !plain {
+		header = style.recordsDateRange.Render(header)
+	}
+
+	return header + "\n\n"
+}
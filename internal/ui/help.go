@@ -42,6 +42,8 @@ func getHelpText(style Style) string {
   <shift+tab>                             Go to previous view/form entry
   q/<esc>                                 Go back or quit
   <ctrl+c>                                Quit immediately
+  :                                       Open the command palette to run actions by name
+                                              (eg. "start", "switch-to <task>")
   ?                                       Show help view
 `),
 		style.helpPrimary.Render("General List Controls"),
@@ -51,26 +53,50 @@ func getHelpText(style Style) string {
   h<Left>                                 Go to previous page
   l<Right>                                Go to next page
   <ctrl+r>                                Refresh list
+  <ctrl+l>                                Reload the custom theme in use from its file
+  z                                       Toggle compact durations
+  e                                       Toggle between when the active task began and
+                                              the time elapsed on it
+  <ctrl+z>                                Undo the last task log deletion, move, or edit
+  i                                       Trim the active task log back to when you went
+                                              idle (needs --idle-threshold)
 `),
 		style.helpPrimary.Render("Task List View"),
 		style.helpSecondary.Render(`
   a                                       Add a task
   u                                       Update task details
+  D                                       Clone task (creates a new task with the same
+                                              summary)
   c                                       Copy task summary to clipboard
   s                                       Start/stop recording time on a task; stopping
                                               will open up the "Task Log Entry View"
   S                                       Quick switch recording; will save a task log
                                               entry for the currently active task, and
                                               start recording time for another
+  C                                       Start recording time on a task with a comment
   f                                       Quickly finish the currently active task log,
 								  without opening the task log entry view
   <ctrl+s>                                Edit the currently active task log/Add a new
                                               manual task log entry
   <ctrl+x>                                Discard currently active recording
+  ]/[                                     Cycle the active task log's comment through
+                                              the task's recent comments
+  K/J                                     Move the active task log's begin time back/
+                                              forward by a minute
   <ctrl+t>                                Go to currently tracked item
   A                                       Archive all tasks with no log entries in the
                                               last 2 weeks
+  R                                       Restore the most recently archived task
+  w                                       Show time tracked on each task this week
+  W                                       Toggle showing the time tracked this week in
+                                              the task list's title
+  o                                       Cycle task order between most recent update,
+                                              most time spent, and summary
   <ctrl+d>                                Deactivate task
+  X                                       Delete all saved task log entries for a task
+                                              (asks for confirmation)
+  n/p                                     Go to next/previous page of tasks (shown 50
+                                              at a time)
 `),
 		style.helpPrimary.Render("Task Logs List View"),
 		style.helpSecondary.Render(`
@@ -79,8 +105,18 @@ func getHelpText(style Style) string {
 
   d                                       Show task log details
   <ctrl+s>/u                              Update task log entry
-  <ctrl+d>                                Delete task log entry
-  m                                       Move task log entry to another task
+  <ctrl+d>                                Delete task log entry (asks for confirmation)
+  m                                       Move task log entry (or the selected entries)
+                                              to another task
+  <space>                                 Select/unselect task log entry, to move
+                                              several entries at once
+  c                                       Copy task log comment to clipboard
+  D                                       Duplicate task log entry, ending it now
+  t                                       Show only the entries of a selected task;
+                                              press again to show all entries
+  T                                       Go to the first entry from today
+  /                                       Show only the entries whose comment contains
+                                              some text; <esc> shows all entries again
 `),
 		style.helpPrimary.Render("Task Log Details View"),
 		style.helpSecondary.Render(`
@@ -91,16 +127,27 @@ func getHelpText(style Style) string {
 		style.helpSecondary.Render(`
   c                                       Copy task summary to clipboard
   <ctrl+d>                                Activate task
+  n/p                                     Go to next/previous page of tasks (shown 50
+                                              at a time)
 `),
 		style.helpPrimary.Render("Task Log Entry View"),
 		style.helpSecondary.Render(`
   enter/<ctrl+s>                          Save entered details for the task log
   k                                       Move timestamp backwards by one minute
   j                                       Move timestamp forwards by one minute
-  K                                       Move timestamp backwards by five minutes
-  J                                       Move timestamp forwards by five minutes
+  K                                       Move timestamp backwards by five minutes (or
+                                              by --shift-step)
+  J                                       Move timestamp forwards by five minutes (or
+                                              by --shift-step)
+  p                                       Move timestamp backwards by an hour
+  n                                       Move timestamp forwards by an hour
   h                                       Move timestamp backwards by a day
   l                                       Move timestamp forwards by a day
+  W                                       Move timestamp backwards by a week
+  w                                       Move timestamp forwards by a week
+  <ctrl+l>                                When finishing the active task log, move the
+                                              end time back to the begin of the next
+                                              saved entry for the task, if they overlap
 `),
 	)
 }
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	idleCheckInterval = time.Minute
+	trimIdleTimeKey   = "i"
+)
+
+func scheduleIdleCheckCmd(idleThreshold time.Duration) tea.Cmd {
+	if idleThreshold <= 0 {
+		return nil
+	}
+
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return idleCheckTickMsg{}
+	})
+}
+
+// recordInteraction notes that the user pressed key. Any pending offer to trim
+// the active task log is withdrawn, as the user is no longer idle, unless key
+// is the one accepting it.
+func (m *Model) recordInteraction(key tea.KeyMsg) {
+	m.lastInteractionAt = m.timeProvider.Now()
+	if key.String() != trimIdleTimeKey {
+		m.idleSince = time.Time{}
+	}
+}
+
+// handleIdleCheckTickMsg offers to trim the active task log if there hasn't
+// been any interaction with the TUI for longer than the idle threshold since
+// it began.
+func (m *Model) handleIdleCheckTickMsg() tea.Cmd {
+	if m.trackingActive && m.idleSince.IsZero() {
+		idleSince := m.lastInteractionAt
+		if idleSince.Before(m.activeTLBeginTS) {
+			idleSince = m.activeTLBeginTS
+		}
+
+		if m.timeProvider.Now().Sub(idleSince) >= m.idleThreshold {
+			m.idleSince = idleSince
+			m.message = infoMsg(fmt.Sprintf("You've been idle since %s; press i to trim the active task log back to then",
+				idleSince.Format(timeFormat)))
+		}
+	}
+
+	return scheduleIdleCheckCmd(m.idleThreshold)
+}
+
+// getCmdToTrimActiveTLToIdleStart finishes the active task log at the point
+// where the user was last seen interacting with the TUI.
+func (m *Model) getCmdToTrimActiveTLToIdleStart() tea.Cmd {
+	if !m.trackingActive || m.idleSince.IsZero() {
+		m.message = errMsg("No idle time to trim")
+		return nil
+	}
+
+	endTS := m.idleSince.Truncate(time.Second)
+	err := types.IsTaskLogDurationValid(m.activeTLBeginTS, endTS)
+	if errors.Is(err, types.ErrDurationNotLongEnough) {
+		m.message = infoMsg("Task log would be too short to save after trimming; press <ctrl+x> if you want to discard it")
+		return nil
+	}
+	if err != nil {
+		m.message = errMsg(fmt.Sprintf("Error: %s", err.Error()))
+		return nil
+	}
+
+	m.idleSince = time.Time{}
+	m.changesLocked = true
+	m.activeTLEndTS = endTS
+
+	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, m.activeTLComment, nil, nil, m.finishTLOptions())
+}
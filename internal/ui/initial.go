@@ -7,10 +7,13 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/session"
 	"github.com/dhth/hours/internal/types"
 )
 
+const taskLogListTitle = "Task Logs (last 50)"
+
 // setupList applies the shared defaults to a list model: title, status-bar item
 // name, quit-keybinding, help, title style, and page-navigation keybindings.
 // filteringEnabled controls whether the list supports filtering.
@@ -32,6 +35,8 @@ func setupList(l *list.Model, title, singularItem, pluralItem string, bgColor li
 
 const (
 	tlCommentLengthLimit = 3000
+	tlTagsLengthLimit    = 200
+	tlCategoryLimit      = 50
 	textInputWidth       = 80
 )
 
@@ -55,7 +60,7 @@ func InitialModel(db *sql.DB,
 	var inactiveTaskItems []list.Item
 	var tasklogListItems []list.Item
 
-	tLInputs := make([]textinput.Model, 2)
+	tLInputs := make([]textinput.Model, 4)
 	tLInputs[entryBeginTS] = textinput.New()
 	tLInputs[entryBeginTS].Placeholder = "09:30"
 	tLInputs[entryBeginTS].CharLimit = len(timeFormat)
@@ -66,6 +71,16 @@ func InitialModel(db *sql.DB,
 	tLInputs[entryEndTS].CharLimit = len(timeFormat)
 	tLInputs[entryEndTS].Width = 30
 
+	tLInputs[entryTags] = textinput.New()
+	tLInputs[entryTags].Placeholder = "meeting, review"
+	tLInputs[entryTags].CharLimit = tlTagsLengthLimit
+	tLInputs[entryTags].Width = 60
+
+	tLInputs[entryCategory] = textinput.New()
+	tLInputs[entryCategory].Placeholder = "coding"
+	tLInputs[entryCategory].CharLimit = tlCategoryLimit
+	tLInputs[entryCategory].Width = 30
+
 	tLCommentInput := textarea.New()
 	tLCommentInput.Placeholder = `Task log comment goes here.
 
@@ -76,13 +91,32 @@ This can be used to record details about your work on this task.`
 	tLCommentInput.ShowLineNumbers = false
 	tLCommentInput.Prompt = "  ┃ "
 
-	taskInputs := make([]textinput.Model, 1)
+	taskInputs := make([]textinput.Model, 2)
 	taskInputs[summaryField] = textinput.New()
 	taskInputs[summaryField].Placeholder = "task summary goes here"
 	taskInputs[summaryField].Focus()
-	taskInputs[summaryField].CharLimit = 100
+	// the summary's length is checked on submit instead, which lets the user
+	// know what the limit is
 	taskInputs[summaryField].Width = textInputWidth
 
+	taskInputs[createdAtField] = textinput.New()
+	taskInputs[createdAtField].Placeholder = "2024/06/08 09:30"
+	taskInputs[createdAtField].CharLimit = len(timeFormat)
+	taskInputs[createdAtField].Width = 30
+
+	commandPaletteInput := textinput.New()
+	commandPaletteInput.Prompt = ": "
+	commandPaletteInput.Placeholder = "start, stop, switch-to <task>, ..."
+	commandPaletteInput.CharLimit = 120
+	commandPaletteInput.Width = 50
+
+	tLSearchInput := textinput.New()
+	tLSearchInput.Prompt = "/ "
+	tLSearchInput.Placeholder = "text in comment"
+	tLSearchInput.CharLimit = 120
+	tLSearchInput.Width = 50
+
+	selectedTLIDs := make(map[int]struct{})
 	m := Model{
 		db:             db,
 		sessionMonitor: sessionMonitor,
@@ -98,19 +132,30 @@ This can be used to record details about your work on this task.`
 				style.listItemDescColor,
 				lipgloss.Color(style.theme.InactiveTasks),
 			), listWidth, 0),
-		taskMap:      make(map[int]*types.Task),
-		taskIndexMap: make(map[int]int),
+		taskMap:        make(map[int]*types.Task),
+		taskIndexMap:   make(map[int]int),
+		selectedTLIDs:  selectedTLIDs,
+		listSelections: make(map[stateView]int),
 		taskLogList: list.New(tasklogListItems,
-			newItemDelegate(style.listItemTitleColor,
-				style.listItemDescColor,
-				lipgloss.Color(style.theme.TaskLogList),
+			newTaskLogDelegate(
+				newItemDelegate(style.listItemTitleColor,
+					style.listItemDescColor,
+					lipgloss.Color(style.theme.TaskLogList),
+				),
+				selectedTLIDs,
 			), listWidth, 0),
 		showHelpIndicator:           true,
 		tLInputs:                    tLInputs,
 		tLCommentInput:              tLCommentInput,
 		taskInputs:                  taskInputs,
+		commandPaletteInput:         commandPaletteInput,
+		tLSearchInput:               tLSearchInput,
 		autoStopTaskID:              -1,
 		autoResumeTaskID:            -1,
+		taskLogFilterTaskID:         -1,
+		shiftStep:                   defaultShiftStep,
+		maxSummaryLength:            pers.MaxTaskSummaryLength,
+		lastInteractionAt:           timeProvider.Now(),
 		debug:                       debug,
 		logFramesCfg:                logFramesCfg,
 		syncConfig:                  syncConfig,
@@ -118,7 +163,7 @@ This can be used to record details about your work on this task.`
 	}
 	titleFG := lipgloss.Color(style.theme.TitleForeground)
 	setupList(&m.activeTasksList, "Tasks", "task", "tasks", lipgloss.Color(style.theme.ActiveTasks), titleFG, true)
-	setupList(&m.taskLogList, "Task Logs (last 50)", "entry", "entries", lipgloss.Color(style.theme.TaskLogList), titleFG, false)
+	setupList(&m.taskLogList, taskLogListTitle, "entry", "entries", lipgloss.Color(style.theme.TaskLogList), titleFG, false)
 	setupList(&m.inactiveTasksList, "Inactive Tasks", "task", "tasks", lipgloss.Color(style.theme.InactiveTasks), titleFG, true)
 
 	m.targetTasksList = list.New([]list.Item{},
@@ -139,6 +184,10 @@ func initialRecordsModel(
 	dateRange types.DateRange,
 	period string,
 	taskStatus types.TaskStatus,
+	tag string,
+	logTag string,
+	taskID *int,
+	limit int,
 	plain bool,
 	initialData string,
 ) recordsModel {
@@ -150,6 +199,10 @@ func initialRecordsModel(
 		dateRange:    dateRange,
 		period:       period,
 		taskStatus:   taskStatus,
+		tag:          tag,
+		logTag:       logTag,
+		taskID:       taskID,
+		limit:        limit,
 		plain:        plain,
 		report:       initialData,
 	}
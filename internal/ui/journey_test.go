@@ -2,6 +2,7 @@ package ui
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -90,7 +91,7 @@ func (h *journeyTestHarness) insertTask(summary string, active bool) int {
 
 // insertTaskLog creates a completed (non-active) task log entry using persistence layer
 func (h *journeyTestHarness) insertTaskLog(taskID int, beginTS, endTS time.Time, comment string) int {
-	tlogID, err := persistence.InsertManualTL(h.db, taskID, beginTS, endTS, &comment)
+	tlogID, err := persistence.InsertManualTL(h.db, taskID, beginTS, endTS, &comment, nil, nil, false)
 	require.NoError(h.t, err)
 
 	return tlogID
@@ -286,7 +287,7 @@ func (h *journeyTestHarness) refreshTaskList() {
 	listItems := make([]list.Item, len(tasks))
 	for i := range tasks {
 		tasks[i].UpdateListTitle()
-		tasks[i].UpdateListDesc(h.timeProvider)
+		tasks[i].UpdateListDesc(h.timeProvider, h.model.durationFormat)
 		listItems[i] = &tasks[i]
 		h.model.taskMap[tasks[i].ID] = &tasks[i]
 		h.model.taskIndexMap[tasks[i].ID] = i
@@ -302,7 +303,7 @@ func (h *journeyTestHarness) refreshInactiveTaskList() {
 	listItems := make([]list.Item, len(tasks))
 	for i := range tasks {
 		tasks[i].UpdateListTitle()
-		tasks[i].UpdateListDesc(h.timeProvider)
+		tasks[i].UpdateListDesc(h.timeProvider, h.model.durationFormat)
 		listItems[i] = &tasks[i]
 	}
 	h.model.inactiveTasksList.SetItems(listItems)
@@ -316,7 +317,7 @@ func (h *journeyTestHarness) refreshTaskLogList() {
 	listItems := make([]list.Item, len(entries))
 	for i := range entries {
 		entries[i].UpdateListTitle()
-		entries[i].UpdateListDesc(h.timeProvider)
+		entries[i].UpdateListDesc(h.timeProvider, h.model.durationFormat)
 		listItems[i] = entries[i]
 	}
 	h.model.taskLogList.SetItems(listItems)
@@ -406,6 +407,41 @@ func (h *journeyTestHarness) moveTaskLogToTaskByID(targetTaskID int) {
 	h.model = newModel.(Model)
 }
 
+// filterTaskLogByTaskID filters the task log list to the entries of the task identified by task ID
+func (h *journeyTestHarness) filterTaskLogByTaskID(taskID int) {
+	// Enter filter view (this directly changes the view, no command returned)
+	cmd := h.model.handleRequestToFilterTaskLogByTask()
+	require.Nil(h.t, cmd, "handleRequestToFilterTaskLogByTask should not return a command on success")
+	require.Equal(h.t, filterTaskLogView, h.model.activeView)
+
+	// Find the task by ID in the targetTasksList
+	targetItems := h.model.targetTasksList.Items()
+	targetIndex := -1
+	for i := range targetItems {
+		task, ok := targetItems[i].(*types.Task)
+		if ok && task.ID == taskID {
+			targetIndex = i
+			break
+		}
+	}
+	require.NotEqual(h.t, -1, targetIndex, "task with ID %d should be found in targetTasksList", taskID)
+
+	// Select task
+	h.model.targetTasksList.Select(targetIndex)
+
+	// Submit the selection (this returns the command)
+	cmd = h.model.handleTaskLogFilterSelection()
+	require.NotNil(h.t, cmd, "handleTaskLogFilterSelection should return a command")
+
+	// Execute command
+	msg := cmd()
+	require.NotNil(h.t, msg)
+
+	// Update model
+	newModel, _ := h.model.Update(msg)
+	h.model = newModel.(Model)
+}
+
 // deactivateTask deactivates the currently selected active task
 func (h *journeyTestHarness) deactivateTask() {
 	cmd := h.model.getCmdToDeactivateTask()
@@ -485,8 +521,6 @@ func (h *journeyTestHarness) assertTrackingState(expectedActive bool, expectedTa
 }
 
 // assertMessage asserts the current user message
-//
-//nolint:unused // Future test helper
 func (h *journeyTestHarness) assertMessage(expected string) {
 	assert.Equal(h.t, expected, h.model.message.value)
 }
@@ -883,3 +917,618 @@ func (h *journeyTestHarness) getActiveTaskIDAtCurrentSelection() int {
 	}
 	return task.ID
 }
+
+func TestJourneyWeeklyTotalsOverlay(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	taskID := h.insertTask("Weekly task", true)
+	h.insertTaskLog(taskID, h.timeProvider.Now().Add(-3*time.Hour), h.timeProvider.Now().Add(-1*time.Hour), "this week")
+	h.insertTaskLog(taskID, h.timeProvider.Now().AddDate(0, 0, -14), h.timeProvider.Now().AddDate(0, 0, -14).Add(time.Hour), "two weeks ago")
+	h.refreshTaskList()
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	require.Len(t, cmds, 1)
+	newModel, _ := h.model.Update(cmds[0]())
+	h.model = newModel.(Model)
+
+	// THEN
+	h.assertView(weeklyTotalsView)
+	view := stripANSI(h.model.View())
+	assert.Contains(t, view, "Weekly task")
+	assert.Contains(t, view, "2h")
+	assert.NotContains(t, view, "3h")
+
+	// WHEN
+	newModel, _ = h.model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	h.model = newModel.(Model)
+
+	// THEN
+	h.assertView(taskListView)
+}
+
+func TestJourneyDailyMaxWarning(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dailyMax    time.Duration
+		expectedMsg string
+	}{
+		{
+			name:        "warns when today's total exceeds the daily max",
+			dailyMax:    4 * time.Hour,
+			expectedMsg: "Heads up: you've tracked 4h 30m today, which is more than your daily max of 4h",
+		},
+		{
+			name:     "doesn't warn when today's total is within the daily max",
+			dailyMax: 5 * time.Hour,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			h := newJourneyTestHarness(t)
+			defer h.cleanup()
+			h.model.dailyMax = tt.dailyMax
+
+			now := h.timeProvider.Now()
+			taskID := h.insertTask("Long day", true)
+			h.insertTaskLog(taskID, now.Add(-8*time.Hour), now.Add(-5*time.Hour), "morning")
+			h.refreshTaskList()
+
+			// WHEN
+			newTLID := h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-90*time.Minute), "late morning")
+			require.NotZero(t, newTLID)
+			cmds := h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID})
+			for _, cmd := range cmds {
+				if msg, ok := cmd().(todayTotalFetchedMsg); ok {
+					newModel, _ := h.model.Update(msg)
+					h.model = newModel.(Model)
+				}
+			}
+
+			// THEN
+			assert.Equal(t, tt.expectedMsg, h.model.message.value)
+		})
+	}
+}
+
+func TestJourneyTodayTotalIsRefreshedAfterChanges(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	applyTodayTotalCmds := func(cmds []tea.Cmd) {
+		for _, cmd := range cmds {
+			if msg, ok := cmd().(todayTotalFetchedMsg); ok {
+				newModel, _ := h.model.Update(msg)
+				h.model = newModel.(Model)
+			}
+		}
+	}
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Long day", true)
+	h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "morning")
+	h.refreshTaskList()
+
+	// WHEN
+	tlID := h.insertTaskLog(taskID, now.Add(-90*time.Minute), now.Add(-time.Hour), "standup")
+	applyTodayTotalCmds(h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID}))
+
+	// THEN
+	assert.Equal(t, 90*60, h.model.todayTotalSecs)
+	assert.Contains(t, h.model.View(), "today: 1h 30m")
+
+	// WHEN
+	entry, err := h.getTaskLogByID(tlID)
+	require.NoError(t, err)
+	require.NoError(t, persistence.DeleteTL(h.db, entry))
+	applyTodayTotalCmds(h.model.handleTLDeleted(tLDeletedMsg{entry: entry}))
+
+	// THEN
+	assert.Equal(t, 60*60, h.model.todayTotalSecs)
+}
+
+func TestJourneyWeeklyGoalProgressIsShownInFooter(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	require.NoError(t, persistence.SetWeeklyGoal(h.db, 2*60*60))
+
+	applyTodayTotalCmds := func(cmds []tea.Cmd) {
+		for _, cmd := range cmds {
+			if msg, ok := cmd().(todayTotalFetchedMsg); ok {
+				newModel, _ := h.model.Update(msg)
+				h.model = newModel.(Model)
+			}
+		}
+	}
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Long day", true)
+	h.refreshTaskList()
+
+	// WHEN
+	h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "morning")
+	applyTodayTotalCmds(h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID}))
+
+	// THEN
+	assert.Equal(t, 60*60, h.model.weekTotalSecs)
+	assert.Equal(t, 2*60*60, h.model.weeklyGoalSecs)
+	assert.Contains(t, h.model.View(), "1h / 2h this week")
+
+	// WHEN
+	h.insertTaskLog(taskID, now.Add(-90*time.Minute), now.Add(-15*time.Minute), "standup")
+	applyTodayTotalCmds(h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID}))
+
+	// THEN
+	assert.Equal(t, 135*60, h.model.weekTotalSecs)
+	assert.Contains(t, h.model.View(), "2h 15m / 2h this week")
+}
+
+func TestJourneyWeekTotalInTaskListTitleIsUpdatedAfterTrackingChanges(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Long day", true)
+	h.refreshTaskList()
+	h.model.handleRequestToToggleWeekTotalInTitle()
+
+	// WHEN
+	h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "morning")
+	for _, cmd := range h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID}) {
+		if msg, ok := cmd().(todayTotalFetchedMsg); ok {
+			newModel, _ := h.model.Update(msg)
+			h.model = newModel.(Model)
+		}
+	}
+
+	// THEN
+	assert.Zero(t, h.model.weeklyGoalSecs)
+	assert.Equal(t, "Tasks — 1h this week", h.model.activeTasksList.Title)
+}
+
+func TestJourneyWeeklyGoalProgressIsHiddenWithoutAGoal(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Long day", true)
+	h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "morning")
+	h.refreshTaskList()
+
+	// WHEN
+	for _, cmd := range h.model.handleManualTLInsertedMsg(manualTLInsertedMsg{taskID: taskID}) {
+		if msg, ok := cmd().(todayTotalFetchedMsg); ok {
+			newModel, _ := h.model.Update(msg)
+			h.model = newModel.(Model)
+		}
+	}
+
+	// THEN
+	assert.Zero(t, h.model.weeklyGoalSecs)
+	assert.NotContains(t, h.model.View(), "this week")
+}
+
+func TestJourneyToggleActiveTLElapsed(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	taskID := h.insertTask("Task to Track", true)
+	task := createTestTask(taskID, "Task to Track", true, false, h.timeProvider)
+	h.model.taskMap[taskID] = task
+	h.model.taskIndexMap[taskID] = 0
+	h.model.activeTasksList.SetItems([]list.Item{task})
+	h.model.activeTasksList.Select(0)
+	h.model.tasksFetched = true
+	h.startTracking()
+	beginTS := h.model.activeTLBeginTS
+	h.model.timeProvider = types.TestTimeProvider{FixedTime: beginTS.Add(83 * time.Minute)}
+	require.Contains(t, h.model.View(), fmt.Sprintf("(since %s)", beginTS.Format(timeOnlyFormat)))
+
+	// WHEN
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+
+	// THEN
+	assert.True(t, h.model.showActiveTLElapsed)
+	assert.Contains(t, h.model.View(), "(1h 23m elapsed)")
+
+	// WHEN
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+
+	// THEN
+	assert.False(t, h.model.showActiveTLElapsed)
+	assert.Contains(t, h.model.View(), fmt.Sprintf("(since %s)", beginTS.Format(timeOnlyFormat)))
+}
+
+func TestJourneyPageThroughTasks(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	for i := range taskPageSize + 5 {
+		h.insertTask(fmt.Sprintf("task %d", i+1), true)
+	}
+	applyCmd := func(cmd tea.Cmd) {
+		require.NotNil(t, cmd)
+		newModel, _ := h.model.Update(cmd())
+		h.model = newModel.(Model)
+	}
+	applyCmd(fetchTasks(h.db, true, 0))
+	require.Len(t, h.model.activeTasksList.Items(), taskPageSize)
+	assert.Equal(t, "Tasks (page 1)", h.model.activeTasksList.Title)
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	require.Len(t, cmds, 1)
+	applyCmd(cmds[0])
+
+	// THEN
+	assert.Len(t, h.model.activeTasksList.Items(), 5)
+	assert.Equal(t, 1, h.model.activeTasksPage)
+	assert.Equal(t, "Tasks (page 2)", h.model.activeTasksList.Title)
+
+	// WHEN
+	cmds = h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+	// THEN
+	assert.Empty(t, cmds)
+	assert.Equal(t, "Already on the last page of tasks", h.model.message.value)
+
+	// WHEN
+	cmds = h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	require.Len(t, cmds, 1)
+	applyCmd(cmds[0])
+
+	// THEN
+	assert.Len(t, h.model.activeTasksList.Items(), taskPageSize)
+	assert.Equal(t, 0, h.model.activeTasksPage)
+}
+
+func TestJourneyStopTrackingTaskOnAnotherPage(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	for i := range taskPageSize + 5 {
+		h.insertTask(fmt.Sprintf("task %d", i+1), true)
+	}
+	applyCmd := func(cmd tea.Cmd) {
+		require.NotNil(t, cmd)
+		newModel, _ := h.model.Update(cmd())
+		h.model = newModel.(Model)
+	}
+	applyCmd(fetchTasks(h.db, true, 0))
+	h.selectTask(0)
+	taskID := h.getActiveTaskIDAtCurrentSelection()
+	h.startTracking()
+	h.assertTrackingState(true, taskID)
+
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	require.Len(t, cmds, 1)
+	applyCmd(cmds[0])
+	require.NotContains(t, h.model.taskIndexMap, taskID)
+
+	// WHEN
+	h.stopTracking()
+	h.finishTracking(h.timeProvider.Now().Add(time.Hour), "done")
+
+	// THEN
+	h.assertTrackingState(false, -1)
+	h.assertDBTaskLogCount(1)
+}
+
+func TestJourneyTrackedTaskOnAnotherPageIsFetchedOnStartup(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	for i := range taskPageSize + 5 {
+		h.insertTask(fmt.Sprintf("task %d", i+1), true)
+	}
+	tasks, err := persistence.FetchTasksPaged(h.db, true, 1, taskPageSize+1)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	trackedTaskID := tasks[0].ID
+	_, err = persistence.InsertNewTL(h.db, trackedTaskID, h.timeProvider.Now())
+	require.NoError(t, err)
+
+	applyCmd := func(cmd tea.Cmd) {
+		require.NotNil(t, cmd)
+		newModel, _ := h.model.Update(cmd())
+		h.model = newModel.(Model)
+	}
+	applyCmd(fetchTasks(h.db, true, 0))
+	require.NotContains(t, h.model.taskMap, trackedTaskID)
+
+	// WHEN
+	applyCmd(fetchActiveTask(h.db))
+	applyCmd(fetchTrackedTask(h.db, trackedTaskID))
+
+	// THEN
+	h.assertTrackingState(true, trackedTaskID)
+	require.Contains(t, h.model.taskMap, trackedTaskID)
+	assert.True(t, h.model.taskMap[trackedTaskID].TrackingActive)
+	assert.NotContains(t, h.model.taskIndexMap, trackedTaskID)
+}
+
+func TestJourneyFilterTaskLogByTask(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	task1ID := h.insertTask("Task 1", true)
+	task2ID := h.insertTask("Task 2", true)
+	h.insertTaskLog(task1ID, now.Add(-5*time.Hour), now.Add(-4*time.Hour), "task 1 first")
+	h.insertTaskLog(task2ID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "task 2 only")
+	h.insertTaskLog(task1ID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "task 1 second")
+	h.refreshTaskList()
+	h.goToTaskLogView()
+	h.refreshTaskLogList()
+	require.Len(t, h.model.taskLogList.Items(), 3)
+
+	// WHEN
+	h.filterTaskLogByTaskID(task1ID)
+
+	// THEN
+	h.assertView(taskLogView)
+	require.Len(t, h.model.taskLogList.Items(), 2)
+	for _, item := range h.model.taskLogList.Items() {
+		entry, ok := item.(types.TaskLogEntry)
+		require.True(t, ok)
+		assert.Equal(t, task1ID, entry.TaskID)
+	}
+	assert.Equal(t, "Task Logs: Task 1 (last 50)", h.model.taskLogList.Title)
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.Len(t, cmds, 1)
+	newModel, _ := h.model.Update(cmds[0]())
+	h.model = newModel.(Model)
+
+	// THEN
+	require.Len(t, h.model.taskLogList.Items(), 3)
+	assert.Equal(t, taskLogListTitle, h.model.taskLogList.Title)
+}
+
+func TestJourneyCycleActiveTLComment(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Meetings", true)
+	h.insertTaskLog(taskID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "standup")
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "code review")
+	h.refreshTaskList()
+	h.selectTask(0)
+	h.startTracking()
+	h.assertTrackingState(true, taskID)
+
+	cycle := func(key rune) {
+		cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		require.Len(t, cmds, 1)
+		newModel, updateCmd := h.model.Update(cmds[0]())
+		h.model = newModel.(Model)
+		require.NotNil(t, updateCmd)
+		newModel, _ = h.model.Update(updateCmd())
+		h.model = newModel.(Model)
+	}
+
+	activeComment := func() string {
+		details, err := persistence.FetchActiveTaskDetails(h.db)
+		require.NoError(t, err)
+		require.NotNil(t, details.CurrentLogComment)
+		return *details.CurrentLogComment
+	}
+
+	// WHEN + THEN
+	cycle(']')
+	assert.Equal(t, "code review", activeComment())
+	assert.Equal(t, "code review", *h.model.activeTLComment)
+
+	cycle(']')
+	assert.Equal(t, "standup", activeComment())
+
+	cycle(']')
+	assert.Equal(t, "code review", activeComment())
+
+	cycle('[')
+	assert.Equal(t, "standup", activeComment())
+	assert.Equal(t, "standup", *h.model.activeTLComment)
+}
+
+func TestJourneyToggleCompactDurations(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-3*time.Hour), now.Add(-90*time.Minute), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+
+	taskDesc := func() string {
+		task, ok := h.model.activeTasksList.Items()[0].(*types.Task)
+		require.True(t, ok)
+		return task.Description()
+	}
+	taskLogDesc := func() string {
+		entry, ok := h.model.taskLogList.Items()[0].(types.TaskLogEntry)
+		require.True(t, ok)
+		return entry.Description()
+	}
+	toggle := func() {
+		h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	}
+
+	assert.Contains(t, taskDesc(), "worked on for 1h 30m")
+	assert.Contains(t, taskLogDesc(), "(1h 30m)")
+
+	// WHEN
+	toggle()
+
+	// THEN
+	assert.Equal(t, types.DurationFormatCompact, h.model.durationFormat)
+	assert.Contains(t, taskDesc(), "worked on for 1h30")
+	assert.NotContains(t, taskDesc(), "1h 30m")
+	assert.Contains(t, taskLogDesc(), "(1h30)")
+
+	// WHEN
+	toggle()
+
+	// THEN
+	assert.Equal(t, types.DurationFormatFull, h.model.durationFormat)
+	assert.Contains(t, taskDesc(), "worked on for 1h 30m")
+	assert.Contains(t, taskLogDesc(), "(1h 30m)")
+}
+
+func TestJourneyStartTrackingWithComment(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	taskID := h.insertTask("Write docs", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+
+	// WHEN
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	h.assertView(startTrackingView)
+	h.model.tLCommentInput.SetValue("outline the intro")
+
+	_, cmds := h.model.handleFormKeys(tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.Len(t, cmds, 1)
+	msg := cmds[0]()
+	require.NotNil(t, msg)
+	newModel, _ := h.model.Update(msg)
+	h.model = newModel.(Model)
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertTrackingState(true, taskID)
+	require.NotNil(t, h.model.activeTLComment)
+	assert.Equal(t, "outline the intro", *h.model.activeTLComment)
+
+	var comment sql.NullString
+	err := h.db.QueryRow("SELECT comment FROM task_log WHERE task_id = ? AND active = 1", taskID).Scan(&comment)
+	require.NoError(t, err)
+	assert.True(t, comment.Valid)
+	assert.Equal(t, "outline the intro", comment.String)
+}
+
+func TestJourneyDuplicateTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Standup", true)
+	h.insertTaskLog(taskID, now.Add(-26*time.Hour), now.Add(-25*time.Hour), "planning")
+	h.insertTaskLog(taskID, now.Add(-24*time.Hour), now.Add(-24*time.Hour+15*time.Minute), "daily standup")
+	h.refreshTaskList()
+	h.goToTaskLogView()
+	h.refreshTaskLogList()
+	h.selectTaskLog(0)
+
+	// WHEN
+	cmd := h.model.getCmdToDuplicateTL()
+	require.NotNil(t, cmd)
+	insertedMsg, ok := cmd().(manualTLInsertedMsg)
+	require.True(t, ok)
+	require.NoError(t, insertedMsg.err)
+	for _, cmd := range h.model.handleManualTLInsertedMsg(insertedMsg) {
+		if msg, ok := cmd().(tLsFetchedMsg); ok {
+			newModel, _ := h.model.Update(msg)
+			h.model = newModel.(Model)
+		}
+	}
+
+	// THEN
+	h.assertDBTaskLogCount(3)
+	require.Len(t, h.model.taskLogList.Items(), 3)
+	entry, ok := h.model.selectedTaskLogEntry()
+	require.True(t, ok)
+	require.NotNil(t, insertedMsg.tlIDToFocusOn)
+	assert.Equal(t, *insertedMsg.tlIDToFocusOn, entry.ID)
+	assert.Equal(t, 0, h.model.taskLogList.Index())
+	require.NotNil(t, entry.Comment)
+	assert.Equal(t, "daily standup", *entry.Comment)
+	assert.Equal(t, 15*60, entry.SecsSpent)
+	assert.True(t, entry.EndTS.Equal(now.Truncate(time.Second)))
+}
+
+func TestJourneyNudgeActiveTLBeginTS(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	taskID := h.insertTask("Write docs", true)
+	task := createTestTask(taskID, "Write docs", true, false, h.timeProvider)
+	h.model.taskMap[taskID] = task
+	h.model.taskIndexMap[taskID] = 0
+	h.model.activeTasksList.SetItems([]list.Item{task})
+	h.model.activeTasksList.Select(0)
+	h.model.tasksFetched = true
+	h.startTracking()
+	h.assertTrackingState(true, taskID)
+	beginTS := h.model.activeTLBeginTS
+
+	nudge := func(key rune) {
+		cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		require.Len(t, cmds, 1)
+		newModel, _ := h.model.Update(cmds[0]())
+		h.model = newModel.(Model)
+	}
+
+	activeBeginTS := func() time.Time {
+		details, err := persistence.FetchActiveTaskDetails(h.db)
+		require.NoError(t, err)
+		return details.CurrentLogBeginTS
+	}
+
+	// WHEN + THEN
+	nudge('K')
+	nudge('K')
+	assert.True(t, beginTS.Add(-2*time.Minute).Equal(activeBeginTS()))
+	assert.True(t, beginTS.Add(-2*time.Minute).Equal(h.model.activeTLBeginTS))
+	assert.Contains(t, h.model.View(), fmt.Sprintf("(since %s)", beginTS.Add(-2*time.Minute).Format(timeOnlyFormat)))
+
+	nudge('J')
+	assert.True(t, beginTS.Add(-time.Minute).Equal(activeBeginTS()))
+	assert.True(t, beginTS.Add(-time.Minute).Equal(h.model.activeTLBeginTS))
+}
+
+func TestJourneyNudgeActiveTLBeginTSIsGuarded(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	h.insertTask("Write docs", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+
+	// THEN
+	assert.Empty(t, cmds)
+	h.assertMessage("Nothing is being tracked right now")
+
+	// WHEN
+	h.startTracking()
+	cmds = h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+
+	// THEN
+	assert.Empty(t, cmds)
+	h.assertMessage(beginTsCannotBeInTheFutureMsg)
+}
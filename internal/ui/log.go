@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,39 +18,56 @@ import (
 const (
 	logTimeCharsBudget     = 6
 	interactiveLogDayLimit = 1
-	logLimit               = 10000
+	// DefaultLogLimit is the default for the maximum number of entries shown
+	// in a task log.
+	DefaultLogLimit = 10000
 )
 
 var errCouldntGenerateLogs = errors.New("couldn't generate logs")
 
+// LogOptions holds the options for rendering a task log.
+type LogOptions struct {
+	RecordsOptions
+	DateRange types.DateRange
+	// Timesheet renders each entry as a line of plain text instead of a table.
+	Timesheet bool
+}
+
 func RenderTaskLog(db *sql.DB,
 	style Style,
 	writer io.Writer,
-	plain bool,
-	dateRange types.DateRange,
-	period string,
-	taskStatus types.TaskStatus,
-	interactive bool,
+	opts LogOptions,
 ) error {
-	if interactive && dateRange.NumDays > interactiveLogDayLimit {
+	if opts.Interactive && opts.DateRange.NumDays > interactiveLogDayLimit {
 		return fmt.Errorf("%w (limited to %d day); use non-interactive mode to see logs for a larger time period", errInteractiveModeNotApplicable, interactiveLogDayLimit)
 	}
 
-	log, err := getTaskLog(db, style, dateRange.Start, dateRange.End, taskStatus, logLimit, plain)
+	f := opts.Filter
+	var log string
+	var err error
+	if opts.Timesheet {
+		log, err = getTaskLogTimesheet(db, style, opts.DateRange.Start, opts.DateRange.End, f.TaskStatus, f.Tag, f.TaskID, opts.Limit)
+	} else {
+		log, err = getTaskLog(db, style, opts.DateRange.Start, opts.DateRange.End, f.TaskStatus, f.Tag, f.TaskID, opts.Limit, opts.Plain)
+	}
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateLogs, err.Error())
 	}
 
-	if interactive {
+	if opts.Interactive {
 		p := tea.NewProgram(initialRecordsModel(
 			reportLogs,
 			db,
 			style,
 			types.RealTimeProvider{},
-			dateRange,
-			period,
-			taskStatus,
-			plain,
+			opts.DateRange,
+			opts.Period,
+			f.TaskStatus,
+			f.Tag,
+			"",
+			f.TaskID,
+			opts.Limit,
+			opts.Plain,
 			log,
 		))
 		_, err := p.Run()
@@ -57,6 +75,9 @@ func RenderTaskLog(db *sql.DB,
 			return err
 		}
 	} else {
+		if opts.HeaderMeta != nil {
+			fmt.Fprint(writer, renderHeaderMeta(style, *opts.HeaderMeta, &opts.DateRange, f.TaskStatus, opts.Plain))
+		}
 		fmt.Fprint(writer, log)
 	}
 	return nil
@@ -67,11 +88,13 @@ func getTaskLog(db *sql.DB,
 	start,
 	end time.Time,
 	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
 	limit int,
 	plain bool) (string,
 	error,
 ) {
-	entries, err := pers.FetchTLEntriesBetweenTS(db, start, end, taskStatus, limit)
+	entries, limitReached, err := pers.FetchTLEntriesBetweenTS(db, start, end, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID}, limit)
 	if err != nil {
 		return "", err
 	}
@@ -131,5 +154,72 @@ func getTaskLog(db *sql.DB,
 		headers[i] = rs.headerStyle.Render(h)
 	}
 
-	return renderRecordsTable(rs, headers, nil, data)
+	table, err := renderRecordsTable(rs, headers, nil, data)
+	if err != nil {
+		return "", err
+	}
+
+	if limitReached {
+		table += renderTruncationNotice(rs)
+	}
+
+	return table, nil
+}
+
+// getTaskLogTimesheet renders task log entries as plain text lines, eg.
+// "09:00–10:30 (1h30m) Task: comment", in the order they began. Entries are
+// grouped under a line with their date when they span more than one day.
+func getTaskLogTimesheet(db *sql.DB,
+	style Style,
+	start,
+	end time.Time,
+	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	limit int,
+) (string, error) {
+	entries, limitReached, err := pers.FetchTLEntriesBetweenTS(db, start, end, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID}, limit)
+	if err != nil {
+		return "", err
+	}
+
+	multiDay := len(entries) > 0 &&
+		entries[0].BeginTS.Format(dateFormat) != entries[len(entries)-1].BeginTS.Format(dateFormat)
+
+	var sb strings.Builder
+	var currentDay string
+	for _, entry := range entries {
+		day := entry.BeginTS.Format(dateFormat)
+		if multiDay && day != currentDay {
+			if currentDay != "" {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(day + "\n")
+			currentDay = day
+		}
+		sb.WriteString(renderTimesheetLine(entry) + "\n")
+	}
+
+	if limitReached {
+		sb.WriteString(renderTruncationNotice(style.getReportStyles(true)))
+	}
+
+	return sb.String(), nil
+}
+
+func renderTimesheetLine(entry types.TaskLogEntry) string {
+	line := fmt.Sprintf("%s–%s (%s) %s",
+		entry.BeginTS.Format(timeOnlyFormat),
+		entry.EndTS.Format(timeOnlyFormat),
+		strings.ReplaceAll(types.HumanizeDuration(entry.SecsSpent), " ", ""),
+		entry.TaskSummary,
+	)
+
+	if entry.Comment != nil {
+		if comment := strings.Join(strings.Fields(*entry.Comment), " "); comment != "" {
+			line += ": " + comment
+		}
+	}
+
+	return line
 }
@@ -29,10 +29,17 @@ const (
 	inactiveTaskListView                        // List of inactive tasks
 	editActiveTLView                            // Form to edit currently active task log (ie, begin TS)
 	finishActiveTLView                          // Form to finish active task log
+	startTrackingView                           // Form to start tracking a task with a comment
 	manualTasklogEntryView                      // Form to manually create a new task log entry
 	editSavedTLView                             // Form to edit an existing task log
+	confirmDeleteTLView                         // Prompt to confirm deleting a task log
+	confirmDeleteTaskTLsView                    // Prompt to confirm deleting all of a task's logs
 	taskInputView                               // Form to create or edit task details
 	moveTaskLogView                             // View to select target task for moving log entry
+	filterTaskLogView                           // View to select task to filter log entries by
+	weeklyTotalsView                            // Overlay showing time tracked on each task this week
+	commandPaletteView                          // Overlay to run actions by name
+	searchTaskLogsView                          // Overlay to search task logs by comment
 	helpView                                    // Help documentation view
 	insufficientDimensionsView                  // Error view when terminal is too small
 )
@@ -48,6 +55,7 @@ type taskInputField uint
 
 const (
 	summaryField taskInputField = iota
+	createdAtField
 )
 
 type tLTrackingFormField uint
@@ -55,11 +63,37 @@ type tLTrackingFormField uint
 const (
 	entryBeginTS tLTrackingFormField = iota
 	entryEndTS
+	entryTags
+	entryCategory
 	entryComment
 )
 
 type tasklogSaveType uint
 
+type taskSortOrder uint
+
+const (
+	taskSortByRecency taskSortOrder = iota
+	taskSortByTimeSpent
+	taskSortAlphabetically
+)
+
+type undoKind uint
+
+const (
+	undoDeleteTL undoKind = iota
+	undoMoveTL
+	undoEditTL
+)
+
+// undoableAction records the last destructive action on a saved task log,
+// along with the data needed to reverse it.
+type undoableAction struct {
+	kind      undoKind
+	entry     types.TaskLogEntry // the task log as it was before the action
+	newTaskID int                // the task the log was moved to (for undoMoveTL)
+}
+
 type recordsKind uint
 
 const (
@@ -79,12 +113,16 @@ const (
 	timeOnlyFormat       = "15:04"
 	dateFormat           = "2006/01/02"
 	userMsgDefaultFrames = 3
+	recentCommentsLimit  = 10
+	taskPageSize         = 50
+	defaultShiftStep     = 5 * time.Minute
 )
 
 type userMsgKind uint
 
 const (
 	userMsgInfo userMsgKind = iota
+	userMsgWarn
 	userMsgErr
 )
 
@@ -103,15 +141,22 @@ type Model struct {
 	activeView                     stateView
 	lastView                       stateView
 	lastViewBeforeInsufficientDims stateView
+	listSelections                 map[stateView]int
 	db                             *sql.DB
 	sessionMonitor                 session.Monitor
 	style                          Style
 	timeProvider                   types.TimeProvider
 	activeTasksList                list.Model
 	inactiveTasksList              list.Model
+	activeTasksPage                int
+	activeTasksHasNextPage         bool
+	inactiveTasksPage              int
+	inactiveTasksHasNextPage       bool
 	taskMap                        map[int]*types.Task
 	taskIndexMap                   map[int]int
 	activeTLBeginTS                time.Time
+	showActiveTLElapsed            bool
+	showWeekTotalInTitle           bool
 	activeTLEndTS                  time.Time
 	activeTLComment                *string
 	tasksFetched                   bool
@@ -156,6 +201,34 @@ type Model struct {
 	moveTLID                       int
 	moveOldTaskID                  int
 	moveSecsSpent                  int
+	moveTLs                        []types.TaskLogEntry
+	selectedTLIDs                  map[int]struct{}
+	taskLogFilterTaskID            int
+	taskLogCommentQuery            string
+	durationFormat                 types.DurationFormat
+	taskSortOrder                  taskSortOrder
+	lastUndoable                   *undoableAction
+	weeklyTotals                   string
+	dailyMax                       time.Duration
+	todayTotalSecs                 int
+	weekTotalSecs                  int
+	weeklyGoalSecs                 int
+	idleThreshold                  time.Duration
+	shiftStep                      time.Duration
+	maxSummaryLength               int
+	mergeSameDay                   bool
+	roundTo                        time.Duration
+	themeName                      string
+	themesDir                      string
+	lastInteractionAt              time.Time
+	idleSince                      time.Time
+	pomodoro                       PomodoroConfig
+	pomodoroPromptedFor            time.Time
+	trackingReminder               time.Duration
+	trackingRemindedFor            time.Time
+	trackingRemindersShown         int
+	commandPaletteInput            textinput.Model
+	tLSearchInput                  textinput.Model
 }
 
 func (m *Model) blurTLTrackingInputs() {
@@ -168,11 +241,14 @@ func (m *Model) blurTLTrackingInputs() {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		hideHelp(time.Minute*1),
-		fetchTasks(m.db, true),
+		fetchTasks(m.db, true, m.activeTasksPage),
 		fetchTLS(m.db, nil),
-		fetchTasks(m.db, false),
+		fetchTasks(m.db, false, m.inactiveTasksPage),
 		waitForSessionEvent(m.sessionMonitor),
 		m.startupSyncStatusCmd(),
+		scheduleIdleCheckCmd(m.idleThreshold),
+		schedulePomodoroCheckCmd(m.pomodoro),
+		scheduleTrackingReminderCheckCmd(m.trackingReminder),
 	)
 }
 
@@ -185,6 +261,11 @@ type recordsModel struct {
 	period       string
 	plain        bool
 	taskStatus   types.TaskStatus
+	tag          string
+	logTag       string
+	taskID       *int
+	limit        int
+	allTime      bool
 	report       string
 	quitting     bool
 	busy         bool
@@ -203,6 +284,14 @@ func infoMsg(msg string) userMsg {
 	}
 }
 
+func warnMsg(msg string) userMsg {
+	return userMsg{
+		value:      msg,
+		kind:       userMsgWarn,
+		framesLeft: userMsgDefaultFrames,
+	}
+}
+
 func errMsg(msg string) userMsg {
 	return userMsg{
 		value:      msg,
@@ -9,6 +9,12 @@ import (
 
 type hideHelpMsg struct{}
 
+type idleCheckTickMsg struct{}
+
+type pomodoroCheckTickMsg struct{}
+
+type trackingReminderTickMsg struct{}
+
 type sessionMonitorStoppedMsg struct{}
 
 type sessionStateChangedMsg struct {
@@ -27,10 +33,12 @@ type startupSyncStatusMsg struct {
 }
 
 type trackingToggledMsg struct {
-	taskID    int
-	finished  bool
-	secsSpent int
-	err       error
+	taskID       int
+	finished     bool
+	secsSpent    int
+	comment      *string
+	overlapsWith *types.TaskLogEntry
+	err          error
 }
 
 type activeTLSwitchedMsg struct {
@@ -47,16 +55,33 @@ type taskRepUpdatedMsg struct {
 }
 
 type manualTLInsertedMsg struct {
-	taskID int
-	err    error
+	taskID        int
+	tlIDToFocusOn *int
+	overlapsWith  *types.TaskLogEntry
+	err           error
 }
 
 type savedTLEditedMsg struct {
 	tlID   int
 	taskID int
+	prev   types.TaskLogEntry
 	err    error
 }
 
+type recentCommentsFetchedMsg struct {
+	taskID   int
+	comments []string
+	forward  bool
+	err      error
+}
+
+type overlappingTLFetchedMsg struct {
+	beginTS time.Time
+	entry   types.TaskLogEntry
+	found   bool
+	err     error
+}
+
 type activeTLUpdatedMsg struct {
 	beginTS time.Time
 	comment *string
@@ -73,9 +98,16 @@ type activeTaskFetchedMsg struct {
 	err        error
 }
 
+type trackedTaskFetchedMsg struct {
+	task types.Task
+	err  error
+}
+
 type tLsFetchedMsg struct {
 	entries       []types.TaskLogEntry
 	tlIDToFocusOn *int
+	filterTaskID  *int
+	commentQuery  string
 	err           error
 }
 
@@ -84,9 +116,10 @@ type taskCreatedMsg struct {
 }
 
 type taskUpdatedMsg struct {
-	tsk     *types.Task
-	summary string
-	err     error
+	tsk       *types.Task
+	summary   string
+	createdAt *time.Time
+	err       error
 }
 
 type taskActiveStatusUpdatedMsg struct {
@@ -100,26 +133,71 @@ type tLDeletedMsg struct {
 	err   error
 }
 
+type taskTLsDeletedMsg struct {
+	taskID     int
+	numDeleted int
+	err        error
+}
+
 type taskLogMovedMsg struct {
 	tlID      int
 	oldTaskID int
 	newTaskID int
+	secsSpent int
 	err       error
 }
 
-type tasksFetchedMsg struct {
-	tasks  []types.Task
-	active bool
+type taskLogsMovedMsg struct {
+	numMoved  int
+	newTaskID int
+	err       error
+}
+
+type tLActionUndoneMsg struct {
+	action undoableAction
 	err    error
 }
 
+type tasksFetchedMsg struct {
+	tasks       []types.Task
+	active      bool
+	page        int
+	hasNextPage bool
+	err         error
+}
+
+type themeReloadedMsg struct {
+	style Style
+	err   error
+}
+
 type staleTasksArchivedMsg struct {
 	count int
 	err   error
 }
 
+// lastArchivedTaskRestoredMsg reports the task that was reactivated; task is
+// nil if there was no archived task to restore.
+type lastArchivedTaskRestoredMsg struct {
+	task *types.Task
+	err  error
+}
+
+type weeklyTotalsFetchedMsg struct {
+	totals string
+	err    error
+}
+
+type todayTotalFetchedMsg struct {
+	secsSpent      int
+	weekSecsSpent  int
+	weeklyGoalSecs int
+	checkDailyMax  bool
+	err            error
+}
+
 type recordsDataFetchedMsg struct {
-	dateRange types.DateRange
+	dateRange *types.DateRange
 	report    string
 	err       error
 }
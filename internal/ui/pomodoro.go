@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dhth/hours/internal/types"
+)
+
+const pomodoroCheckInterval = 15 * time.Second
+
+// PomodoroConfig controls pomodoro mode, in which a break is suggested once
+// the active task log has been running for Work. It's off if Work is zero.
+type PomodoroConfig struct {
+	Work     time.Duration
+	Break    time.Duration
+	AutoStop bool
+}
+
+func (c PomodoroConfig) enabled() bool {
+	return c.Work > 0
+}
+
+func schedulePomodoroCheckCmd(cfg PomodoroConfig) tea.Cmd {
+	if !cfg.enabled() {
+		return nil
+	}
+
+	return tea.Tick(pomodoroCheckInterval, func(time.Time) tea.Msg {
+		return pomodoroCheckTickMsg{}
+	})
+}
+
+// handlePomodoroCheckTickMsg suggests taking a break once the active task log
+// has been running for the pomodoro work length, finishing it as well if
+// auto-stop is on. The break is only suggested once per task log.
+func (m *Model) handlePomodoroCheckTickMsg() []tea.Cmd {
+	cmds := []tea.Cmd{schedulePomodoroCheckCmd(m.pomodoro)}
+
+	if !m.trackingActive || m.pomodoroPromptedFor.Equal(m.activeTLBeginTS) {
+		return cmds
+	}
+
+	if m.timeProvider.Now().Sub(m.activeTLBeginTS) < m.pomodoro.Work {
+		return cmds
+	}
+
+	m.pomodoroPromptedFor = m.activeTLBeginTS
+	workStr := types.HumanizeDuration(int(m.pomodoro.Work.Seconds()))
+	breakStr := types.HumanizeDuration(int(m.pomodoro.Break.Seconds()))
+
+	if m.pomodoro.AutoStop {
+		if finishCmd := m.getCmdToFinishActiveTL(); finishCmd != nil {
+			m.message = infoMsg(fmt.Sprintf("You've worked for %s; stopped tracking, time for a %s break", workStr, breakStr))
+			return append(cmds, finishCmd)
+		}
+		return cmds
+	}
+
+	m.message = infoMsg(fmt.Sprintf("You've worked for %s; time for a %s break", workStr, breakStr))
+	return cmds
+}
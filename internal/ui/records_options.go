@@ -0,0 +1,24 @@
+package ui
+
+import (
+	pers "github.com/dhth/hours/internal/persistence"
+)
+
+// RecordsOptions holds what rendering a report, a task log, and stats have in
+// common.
+type RecordsOptions struct {
+	// Plain renders output without colors.
+	Plain bool
+	// Period is the period the date range was resolved from, as provided by
+	// the user; it's shown in interactive mode.
+	Period string
+	// Filter restricts the task log entries considered.
+	Filter pers.TLFilter
+	// Limit caps the number of rows shown.
+	Limit int
+	// Interactive shows the output in a view that allows moving between
+	// periods.
+	Interactive bool
+	// HeaderMeta, if set, is prepended to non-interactive output.
+	HeaderMeta *HeaderMeta
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dhth/hours/internal/types"
+)
+
+const trackingReminderCheckInterval = 15 * time.Second
+
+func scheduleTrackingReminderCheckCmd(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+
+	return tea.Tick(trackingReminderCheckInterval, func(time.Time) tea.Msg {
+		return trackingReminderTickMsg{}
+	})
+}
+
+// handleTrackingReminderTickMsg lets the user know how long the active task
+// log has been running each time it crosses a multiple of the reminder
+// interval (eg. every whole hour).
+func (m *Model) handleTrackingReminderTickMsg() tea.Cmd {
+	if m.trackingActive {
+		if !m.trackingRemindedFor.Equal(m.activeTLBeginTS) {
+			m.trackingRemindedFor = m.activeTLBeginTS
+			m.trackingRemindersShown = 0
+		}
+
+		elapsed := m.timeProvider.Now().Sub(m.activeTLBeginTS)
+		numIntervals := int(elapsed / m.trackingReminder)
+		if numIntervals > m.trackingRemindersShown {
+			m.trackingRemindersShown = numIntervals
+			elapsedStr := types.HumanizeDuration(int((time.Duration(numIntervals) * m.trackingReminder).Seconds()))
+			m.message = infoMsg(fmt.Sprintf("Tracking for %s", elapsedStr))
+		}
+	}
+
+	return scheduleTrackingReminderCheckCmd(m.trackingReminder)
+}
@@ -3,6 +3,10 @@ package ui
 import (
 	"bytes"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -56,7 +60,7 @@ func TestGetTaskLogEmpty(t *testing.T) {
 	end := start.AddDate(0, 0, 1)
 
 	// WHEN
-	result, err := getTaskLog(db, style, start, end, types.TaskStatusActive, 100, true)
+	result, err := getTaskLog(db, style, start, end, types.TaskStatusActive, "", nil, 100, true)
 
 	// THEN
 	require.NoError(t, err)
@@ -80,7 +84,7 @@ func TestGetTaskLogWithEntries(t *testing.T) {
 	queryEnd := queryStart.AddDate(0, 0, 1)
 
 	// WHEN - plain mode
-	result, err := getTaskLog(db, style, queryStart, queryEnd, types.TaskStatusAny, 100, true)
+	result, err := getTaskLog(db, style, queryStart, queryEnd, types.TaskStatusAny, "", nil, 100, true)
 
 	// THEN
 	require.NoError(t, err)
@@ -89,6 +93,38 @@ func TestGetTaskLogWithEntries(t *testing.T) {
 	assert.Contains(t, result, "2h")
 }
 
+func TestGetTaskLogTruncatedAtLimit(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Test Task", true)
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		entryStart := start.Add(time.Duration(i) * time.Hour)
+		insertTestTaskLog(t, db, taskID, entryStart, entryStart.Add(30*time.Minute), fmt.Sprintf("Entry %d", i+1))
+	}
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	queryEnd := queryStart.AddDate(0, 0, 1)
+
+	// WHEN
+	truncated, truncatedErr := getTaskLog(db, style, queryStart, queryEnd, types.TaskStatusAny, "", nil, 2, true)
+	complete, completeErr := getTaskLog(db, style, queryStart, queryEnd, types.TaskStatusAny, "", nil, 3, true)
+
+	// THEN
+	require.NoError(t, truncatedErr)
+	assert.Contains(t, truncated, "Entry 1")
+	assert.Contains(t, truncated, "Entry 2")
+	assert.NotContains(t, truncated, "Entry 3")
+	assert.Contains(t, truncated, recordsTruncatedMsg)
+
+	require.NoError(t, completeErr)
+	assert.Contains(t, complete, "Entry 3")
+	assert.NotContains(t, complete, recordsTruncatedMsg)
+}
+
 func TestRenderTaskLogInteractiveDayLimitExceeded(t *testing.T) {
 	// GIVEN
 	db := setupTestDB(t)
@@ -104,7 +140,16 @@ func TestRenderTaskLogInteractiveDayLimitExceeded(t *testing.T) {
 	}
 
 	// WHEN - interactive mode with multi-day range
-	err := RenderTaskLog(db, style, &buf, true, dateRange, "2d", types.TaskStatusAny, true)
+	err := RenderTaskLog(db, style, &buf, LogOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:       true,
+			Period:      "2d",
+			Filter:      persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+			Limit:       DefaultLogLimit,
+			Interactive: true,
+		},
+		DateRange: dateRange,
+	})
 
 	// THEN - should return error about interactive mode limit
 	require.Error(t, err)
@@ -132,13 +177,76 @@ func TestRenderTaskLogNonInteractiveMultiDayAllowed(t *testing.T) {
 	}
 
 	// WHEN - non-interactive mode with multi-day range
-	err := RenderTaskLog(db, style, &buf, true, dateRange, "2d", types.TaskStatusAny, false)
+	err := RenderTaskLog(db, style, &buf, LogOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "2d",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+			Limit:  DefaultLogLimit,
+		},
+		DateRange: dateRange,
+	})
 
 	// THEN - should succeed
 	require.NoError(t, err)
 	assert.Contains(t, buf.String(), "Day 1 work")
 }
 
+func TestGetTaskLogTimesheet(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	writingID := insertTestTask(t, db, "Writing", true)
+	reviewID := insertTestTask(t, db, "Review", true)
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	insertTestTaskLog(t, db, reviewID, day.Add(11*time.Hour), day.Add(11*time.Hour+45*time.Minute), "PR #12\nand #13")
+	insertTestTaskLog(t, db, writingID, day.Add(9*time.Hour), day.Add(10*time.Hour+30*time.Minute), "draft intro")
+	_, err := db.Exec(
+		"INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, active) VALUES (?, ?, ?, ?, ?, ?)",
+		writingID, day.Add(14*time.Hour), day.Add(16*time.Hour), 2*60*60, nil, false,
+	)
+	require.NoError(t, err)
+
+	// WHEN
+	result, err := getTaskLogTimesheet(db, style, day, day.AddDate(0, 0, 1), types.TaskStatusAny, "", nil, DefaultLogLimit)
+
+	// THEN
+	require.NoError(t, err)
+	expected := `09:00–10:30 (1h30m) Writing: draft intro
+11:00–11:45 (45m) Review: PR #12 and #13
+14:00–16:00 (2h) Writing
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestGetTaskLogTimesheetGroupsEntriesByDay(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Writing", true)
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	nextDay := day.AddDate(0, 0, 1)
+	insertTestTaskLog(t, db, taskID, nextDay.Add(9*time.Hour), nextDay.Add(10*time.Hour), "second day")
+	insertTestTaskLog(t, db, taskID, day.Add(9*time.Hour), day.Add(10*time.Hour), "first day")
+
+	// WHEN
+	result, err := getTaskLogTimesheet(db, style, day, day.AddDate(0, 0, 2), types.TaskStatusAny, "", nil, DefaultLogLimit)
+
+	// THEN
+	require.NoError(t, err)
+	expected := `2025/01/01
+09:00–10:00 (1h) Writing: first day
+
+2025/01/02
+09:00–10:00 (1h) Writing: second day
+`
+	assert.Equal(t, expected, result)
+}
+
 // T-031: Test RenderReport / renderReportGrid
 
 func TestGetReportNoEntries(t *testing.T) {
@@ -149,7 +257,7 @@ func TestGetReportNoEntries(t *testing.T) {
 	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	// WHEN
-	result, err := renderReportGrid(db, style, start, 1, types.TaskStatusAny, true, fetchTLEntriesForDay)
+	result, err := renderReportGrid(db, style, start, 1, types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, fetchTLEntriesForDay)
 
 	// THEN
 	require.NoError(t, err)
@@ -180,7 +288,7 @@ func TestGetReportMultiDayEntries(t *testing.T) {
 	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	// WHEN
-	result, err := renderReportGrid(db, style, queryStart, 2, types.TaskStatusAny, true, fetchTLEntriesForDay)
+	result, err := renderReportGrid(db, style, queryStart, 2, types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, fetchTLEntriesForDay)
 
 	// THEN - report shows task summaries and time spent (not comments)
 	require.NoError(t, err)
@@ -191,6 +299,69 @@ func TestGetReportMultiDayEntries(t *testing.T) {
 	assert.Contains(t, result, "2025/01/02")
 }
 
+func TestGetReportShowsGrandTotal(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Multi-day Task", true)
+	otherTaskID := insertTestTask(t, db, "Other Task", true)
+
+	day1Start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day1Start, day1Start.Add(2*time.Hour), "Day 1 work")
+	insertTestTaskLog(t, db, otherTaskID, day1Start.Add(3*time.Hour), day1Start.Add(3*time.Hour+30*time.Minute), "Day 1 other work")
+
+	day2Start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day2Start, day2Start.Add(3*time.Hour), "Day 2 work")
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	result, err := renderReportGrid(db, style, queryStart, 2, types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, fetchTLEntriesForDay)
+	singleDayResult, singleDayErr := renderReportGrid(db, style, queryStart, 1, types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, fetchTLEntriesForDay)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "2h 30m")
+	assert.Contains(t, result, "\nTotal: 5h 30m\n")
+
+	require.NoError(t, singleDayErr)
+	assert.NotContains(t, singleDayResult, "Total:")
+}
+
+func TestGetReportTruncatedAtLimit(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		taskID := insertTestTask(t, db, fmt.Sprintf("Task %d", i+1), true)
+		entryStart := start.Add(time.Duration(i) * time.Hour)
+		insertTestTaskLog(t, db, taskID, entryStart, entryStart.Add(30*time.Minute), "")
+	}
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	result, err := renderReportGrid(db, style, queryStart, 1, types.TaskStatusAny, "", "", nil, 2, true, fetchTLEntriesForDay)
+	markdown, markdownErr := renderReportMarkdown(db, queryStart, 1, types.TaskStatusAny, "", "", nil, 2, fetchTLEntriesForDay)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "Task 1")
+	assert.Contains(t, result, "Task 2")
+	assert.NotContains(t, result, "Task 3")
+	assert.Contains(t, result, "1h")
+	assert.Contains(t, result, recordsTruncatedMsg)
+
+	require.NoError(t, markdownErr)
+	assert.NotContains(t, markdown, "Task 3")
+	assert.Contains(t, markdown, recordsTruncatedMsg)
+}
+
 func TestGetReportAggEntries(t *testing.T) {
 	// GIVEN
 	db := setupTestDB(t)
@@ -212,7 +383,7 @@ func TestGetReportAggEntries(t *testing.T) {
 	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	// WHEN
-	result, err := renderReportGrid(db, style, queryStart, 1, types.TaskStatusAny, true, fetchReportEntriesForDay)
+	result, err := renderReportGrid(db, style, queryStart, 1, types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, fetchReportEntriesForDay)
 
 	// THEN - aggregate report should combine entries
 	require.NoError(t, err)
@@ -221,6 +392,116 @@ func TestGetReportAggEntries(t *testing.T) {
 	assert.Contains(t, result, "3h")
 }
 
+func TestRenderReportMarkdown(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	taskID := insertTestTask(t, db, "Write docs | review", true)
+	otherTaskID := insertTestTask(t, db, "Standup", true)
+
+	day1Start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day1Start, day1Start.Add(2*time.Hour), "Day 1 work")
+	insertTestTaskLog(t, db, otherTaskID, day1Start.Add(3*time.Hour), day1Start.Add(3*time.Hour+30*time.Minute), "Standup")
+
+	day2Start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day2Start, day2Start.Add(3*time.Hour), "Day 2 work")
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	result, err := renderReportMarkdown(db, queryStart, 2, types.TaskStatusAny, "", "", nil, DefaultReportLimit, fetchTLEntriesForDay)
+
+	// THEN
+	require.NoError(t, err)
+	expected := `| 2025/01/01 | 2025/01/02 |
+| --- | --- |
+| Write docs \| review (2h) | Write docs \| review (3h) |
+| Standup (30m) |  |
+| **2h 30m** | **3h** |
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestRenderReportCSV(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	taskID := insertTestTask(t, db, "Write docs; review", true)
+	otherTaskID := insertTestTask(t, db, "Standup", true)
+
+	day1Start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day1Start, day1Start.Add(time.Hour), "Day 1 work")
+	insertTestTaskLog(t, db, otherTaskID, day1Start.Add(2*time.Hour), day1Start.Add(2*time.Hour+30*time.Minute), "Standup")
+	insertTestTaskLog(t, db, taskID, day1Start.Add(3*time.Hour), day1Start.Add(4*time.Hour), "More day 1 work")
+
+	day2Start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day2Start, day2Start.Add(3*time.Hour), "Day 2 work")
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	result, err := renderReportCSV(db, queryStart, 2, types.TaskStatusAny, "", "", nil, DefaultReportLimit, fetchTLEntriesForDay, ';')
+
+	// THEN
+	require.NoError(t, err)
+	expected := `date;task;secs_spent
+2025/01/01;"Write docs; review";7200
+2025/01/01;Standup;1800
+2025/01/02;"Write docs; review";10800
+total;;19800
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestRenderReportCSVKeepsTasksWithTheSameSummaryApart(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	taskID := insertTestTask(t, db, "Review", true)
+	otherTaskID := insertTestTask(t, db, "Review", true)
+
+	dayStart := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, dayStart, dayStart.Add(time.Hour), "Review PR")
+	insertTestTaskLog(t, db, otherTaskID, dayStart.Add(2*time.Hour), dayStart.Add(2*time.Hour+30*time.Minute), "Review doc")
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	result, err := renderReportCSV(db, queryStart, 1, types.TaskStatusAny, "", "", nil, DefaultReportLimit, fetchReportEntriesForDay, ',')
+
+	// THEN
+	require.NoError(t, err)
+	expected := `date,task,secs_spent
+2025/01/01,Review,3600
+2025/01/01,Review,1800
+total,,5400
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestRenderReportCSVFailsWhenEntriesAreTruncated(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	taskID := insertTestTask(t, db, "Write docs", true)
+
+	dayStart := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, dayStart, dayStart.Add(time.Hour), "Draft")
+	insertTestTaskLog(t, db, taskID, dayStart.Add(2*time.Hour), dayStart.Add(3*time.Hour), "Polish")
+
+	queryStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// WHEN
+	_, err := renderReportCSV(db, queryStart, 1, types.TaskStatusAny, "", "", nil, 1, fetchTLEntriesForDay, ',')
+
+	// THEN
+	assert.ErrorIs(t, err, errReportCSVTruncated)
+}
+
 func TestRenderReportInteractiveNonAgg(t *testing.T) {
 	// GIVEN
 	db := setupTestDB(t)
@@ -235,12 +516,287 @@ func TestRenderReportInteractiveNonAgg(t *testing.T) {
 	}
 
 	// WHEN - non-interactive (interactive would require TUI)
-	err := RenderReport(db, style, &buf, true, dateRange, "1d", types.TaskStatusAny, false, false)
+	err := RenderReport(db, style, &buf, ReportOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "1d",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+			Limit:  DefaultReportLimit,
+		},
+		DateRange: dateRange,
+		DayFilter: ReportAllDays,
+		Format:    ReportFormatTable,
+	})
 
 	// THEN
 	assert.NoError(t, err)
 }
 
+func TestRenderReportWithHeaderMeta(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+	var buf bytes.Buffer
+
+	dateRange := types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+		NumDays: 3,
+	}
+	headerMeta := HeaderMeta{
+		Command:     "hours report 2025/01/01...2025/01/03 --task-status=active",
+		GeneratedAt: time.Date(2025, 1, 4, 9, 30, 0, 0, time.UTC),
+	}
+
+	// WHEN
+	err := RenderReport(db, style, &buf, ReportOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:      true,
+			Period:     "2025/01/01...2025/01/03",
+			Filter:     persistence.TLFilter{TaskStatus: types.TaskStatusActive},
+			Limit:      DefaultReportLimit,
+			HeaderMeta: &headerMeta,
+		},
+		DateRange: dateRange,
+		DayFilter: ReportAllDays,
+		Format:    ReportFormatTable,
+	})
+
+	// THEN
+	require.NoError(t, err)
+	header, _, found := strings.Cut(buf.String(), "\n")
+	require.True(t, found)
+	assert.Equal(t, "command: hours report 2025/01/01...2025/01/03 --task-status=active | range: 2025/01/01...2025/01/03 | task status: active | generated at: 2025/01/04 09:30", header)
+}
+
+func TestRenderReportWithTag(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+	var buf bytes.Buffer
+
+	taggedTaskID := insertTestTask(t, db, "client work", true)
+	otherTaskID := insertTestTask(t, db, "internal work", true)
+	require.NoError(t, persistence.AddTaskTag(db, int(taggedTaskID), "acme"))
+	day := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taggedTaskID, day, day.Add(time.Hour), "tagged")
+	insertTestTaskLog(t, db, otherTaskID, day.Add(2*time.Hour), day.Add(3*time.Hour), "untagged")
+
+	dateRange := types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		NumDays: 1,
+	}
+
+	// WHEN
+	err := RenderReport(db, style, &buf, ReportOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "2025/01/01",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny, Tag: "acme"},
+			Limit:  DefaultReportLimit,
+		},
+		DateRange: dateRange,
+		DayFilter: ReportAllDays,
+		Agg:       true,
+		Format:    ReportFormatTable,
+	})
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "client work")
+	assert.NotContains(t, buf.String(), "internal work")
+}
+
+func TestRenderReportForTask(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "client work", true)
+	otherTaskID := insertTestTask(t, db, "internal work", true)
+	day := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day, day.Add(time.Hour), "client")
+	insertTestTaskLog(t, db, otherTaskID, day.Add(2*time.Hour), day.Add(3*time.Hour), "internal")
+
+	dateRange := types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		NumDays: 1,
+	}
+	filterTaskID := int(taskID)
+	taskWithoutLogsID := int(insertTestTask(t, db, "no work", true))
+
+	// WHEN
+	var buf, emptyBuf bytes.Buffer
+	err := RenderReport(db, style, &buf, ReportOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "2025/01/01",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &filterTaskID},
+			Limit:  DefaultReportLimit,
+		},
+		DateRange: dateRange,
+		DayFilter: ReportAllDays,
+		Format:    ReportFormatTable,
+	})
+	require.NoError(t, err)
+	emptyErr := RenderReport(db, style, &emptyBuf, ReportOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "2025/01/01",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny, TaskID: &taskWithoutLogsID},
+			Limit:  DefaultReportLimit,
+		},
+		DateRange: dateRange,
+		DayFilter: ReportAllDays,
+		Format:    ReportFormatTable,
+	})
+
+	// THEN
+	assert.Contains(t, buf.String(), "client work")
+	assert.NotContains(t, buf.String(), "internal work")
+
+	require.NoError(t, emptyErr)
+	assert.NotContains(t, emptyBuf.String(), "work")
+}
+
+func TestRenderReportWithDayFilter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dayFilter   ReportDayFilter
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:        "first day only",
+			dayFilter:   ReportFirstDayOnly,
+			expected:    []string{"day one"},
+			notExpected: []string{"day two", "day three"},
+		},
+		{
+			name:        "last day only",
+			dayFilter:   ReportLastDayOnly,
+			expected:    []string{"day three"},
+			notExpected: []string{"day one", "day two"},
+		},
+		{
+			name:     "all days",
+			expected: []string{"day one", "day two", "day three"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			db := setupTestDB(t)
+			defer db.Close()
+			style := getTestStyle()
+			var buf bytes.Buffer
+
+			start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i, summary := range []string{"day one", "day two", "day three"} {
+				taskID := insertTestTask(t, db, summary, true)
+				begin := start.AddDate(0, 0, i).Add(9 * time.Hour)
+				insertTestTaskLog(t, db, taskID, begin, begin.Add(time.Hour), summary)
+			}
+
+			dateRange := types.DateRange{
+				Start:   start,
+				End:     start.AddDate(0, 0, 3),
+				NumDays: 3,
+			}
+
+			// WHEN
+			err := RenderReport(db, style, &buf, ReportOptions{
+				RecordsOptions: RecordsOptions{
+					Plain:  true,
+					Period: "2025/01/01...2025/01/03",
+					Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+					Limit:  DefaultReportLimit,
+				},
+				DateRange: dateRange,
+				DayFilter: tt.dayFilter,
+				Format:    ReportFormatTable,
+			})
+
+			// THEN
+			require.NoError(t, err)
+			result := buf.String()
+			for _, header := range []string{"2025/01/01", "2025/01/02", "2025/01/03"} {
+				assert.Contains(t, result, header)
+			}
+			for _, summary := range tt.expected {
+				assert.Contains(t, result, summary)
+			}
+			for _, summary := range tt.notExpected {
+				assert.NotContains(t, result, summary)
+			}
+		})
+	}
+}
+
+func TestRenderReportExcludesActiveTaskLog(t *testing.T) {
+	testCases := []struct {
+		name  string
+		agg   bool
+		plain bool
+	}{
+		{name: "plain"},
+		{name: "plain aggregated", agg: true, plain: true},
+		{name: "styled", plain: false},
+		{name: "styled aggregated", agg: true, plain: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			db := setupTestDB(t)
+			defer db.Close()
+			style := getTestStyle()
+			var buf bytes.Buffer
+
+			savedTaskID := insertTestTask(t, db, "finished work", true)
+			activeTaskID := insertTestTask(t, db, "ongoing work", true)
+			day := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+			insertTestTaskLog(t, db, savedTaskID, day, day.Add(time.Hour), "saved")
+			_, err := db.Exec(
+				"INSERT INTO task_log (task_id, begin_ts, secs_spent, comment, active) VALUES (?, ?, ?, ?, ?)",
+				activeTaskID, day.Add(2*time.Hour), 0, "in progress", true,
+			)
+			require.NoError(t, err)
+
+			dateRange := types.DateRange{
+				Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+				NumDays: 1,
+			}
+
+			// WHEN
+			err = RenderReport(db, style, &buf, ReportOptions{
+				RecordsOptions: RecordsOptions{
+					Plain:  tt.plain,
+					Period: "2025/01/01",
+					Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+					Limit:  DefaultReportLimit,
+				},
+				DateRange: dateRange,
+				DayFilter: ReportAllDays,
+				Agg:       tt.agg,
+				Format:    ReportFormatTable,
+			})
+
+			// THEN
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), "finished work")
+			assert.NotContains(t, buf.String(), "ongoing work")
+		})
+	}
+}
+
 // T-032: Test RenderStats / getStats / ShowActiveTask
 
 func TestGetStatsAllModeEmpty(t *testing.T) {
@@ -250,7 +806,7 @@ func TestGetStatsAllModeEmpty(t *testing.T) {
 	style := getTestStyle()
 
 	// WHEN - all mode (nil dateRange)
-	result, err := getStats(db, style, nil, types.TaskStatusAny, true)
+	result, err := getStats(db, style, nil, types.TaskStatusAny, "", nil, DefaultStatsLimit, true)
 
 	// THEN
 	require.NoError(t, err)
@@ -279,7 +835,7 @@ func TestGetStatsWithRangeAndEntries(t *testing.T) {
 	}
 
 	// WHEN
-	result, err := getStats(db, style, dateRange, types.TaskStatusAny, true)
+	result, err := getStats(db, style, dateRange, types.TaskStatusAny, "", nil, DefaultStatsLimit, true)
 
 	// THEN
 	require.NoError(t, err)
@@ -289,6 +845,114 @@ func TestGetStatsWithRangeAndEntries(t *testing.T) {
 	assert.Contains(t, result, "Total")
 }
 
+func TestGetStatsOnlyShowsUpToLimitTasks(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, summary := range []string{"Task A", "Task B", "Task C"} {
+		taskID := insertTestTask(t, db, summary, true)
+		begin := start.Add(time.Duration(i) * 2 * time.Hour)
+		insertTestTaskLog(t, db, taskID, begin, begin.Add(time.Duration(i+1)*time.Hour), "Work")
+	}
+
+	dateRange := &types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		NumDays: 1,
+	}
+
+	// WHEN
+	result, err := getStats(db, style, dateRange, types.TaskStatusAny, "", nil, 2, true)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "Task C")
+	assert.Contains(t, result, "Task B")
+	assert.NotContains(t, result, "Task A")
+	assert.Contains(t, result, "+1 more")
+	assert.Contains(t, result, "50.0%")
+	assert.Regexp(t, `Total\s+\|\s+3\s+\|\s+6h\s+\|\s+100.0%`, result)
+}
+
+func TestGetStatsShowsEachTasksShareOfTotal(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, summary := range []string{"Task A", "Task B", "Task C"} {
+		taskID := insertTestTask(t, db, summary, true)
+		begin := start.Add(time.Duration(i) * 2 * time.Hour)
+		insertTestTaskLog(t, db, taskID, begin, begin.Add(time.Hour), "Work")
+	}
+
+	dateRange := &types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		NumDays: 1,
+	}
+
+	// WHEN
+	result, err := getStats(db, style, dateRange, types.TaskStatusAny, "", nil, DefaultStatsLimit, true)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "%")
+	matches := regexp.MustCompile(`(\d+\.\d)%`).FindAllStringSubmatch(result, -1)
+	require.Len(t, matches, 4) // one per task, and the total
+	var sum float64
+	for _, match := range matches[:3] {
+		assert.Equal(t, "33.3", match[1])
+		var share float64
+		_, err := fmt.Sscanf(match[1], "%f", &share)
+		require.NoError(t, err)
+		sum += share
+	}
+	assert.InDelta(t, 100, sum, 0.5)
+	assert.Equal(t, "100.0", matches[3][1])
+}
+
+func TestRenderStatsTableWithoutTimeTracked(t *testing.T) {
+	// GIVEN
+	style := getTestStyle()
+	entries := []types.TaskReportEntry{
+		{TaskID: 1, TaskSummary: "Idle Task", NumEntries: 1, SecsSpent: 0},
+	}
+
+	// WHEN
+	result, err := renderStatsTable(style, entries, DefaultStatsLimit, true, false)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "0.0%")
+	assert.NotContains(t, result, "NaN")
+}
+
+func TestRenderCategoryStatsTableShowsUncategorizedBucket(t *testing.T) {
+	// GIVEN
+	style := getTestStyle()
+	coding := "coding"
+	entries := []types.CategoryStatsEntry{
+		{Category: &coding, NumEntries: 2, SecsSpent: 3 * 3600},
+		{Category: nil, NumEntries: 1, SecsSpent: 3600},
+	}
+
+	// WHEN
+	result, err := renderCategoryStatsTable(style, entries, true)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, result, "coding")
+	assert.Contains(t, result, "uncategorized")
+	assert.Contains(t, result, "75.0")
+	assert.Contains(t, result, "25.0")
+	assert.Contains(t, result, "Total")
+}
+
 func TestRenderStatsInteractiveConstraint(t *testing.T) {
 	// GIVEN
 	db := setupTestDB(t)
@@ -297,7 +961,16 @@ func TestRenderStatsInteractiveConstraint(t *testing.T) {
 	var buf bytes.Buffer
 
 	// WHEN - interactive mode without date range (period=all)
-	err := RenderStats(db, style, &buf, true, nil, "all", types.TaskStatusAny, true)
+	err := RenderStats(db, style, &buf, StatsOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:       true,
+			Period:      "all",
+			Filter:      persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+			Limit:       DefaultStatsLimit,
+			Interactive: true,
+		},
+		DateRange: nil,
+	})
 
 	// THEN - should return error
 	require.Error(t, err)
@@ -318,13 +991,335 @@ func TestRenderStatsNonInteractiveAllAllowed(t *testing.T) {
 	insertTestTaskLog(t, db, taskID, start, end, "Work")
 
 	// WHEN - non-interactive mode with period=all
-	err := RenderStats(db, style, &buf, true, nil, "all", types.TaskStatusAny, false)
+	err := RenderStats(db, style, &buf, StatsOptions{
+		RecordsOptions: RecordsOptions{
+			Plain:  true,
+			Period: "all",
+			Filter: persistence.TLFilter{TaskStatus: types.TaskStatusAny},
+			Limit:  DefaultStatsLimit,
+		},
+		DateRange: nil,
+	})
 
 	// THEN - should succeed
 	require.NoError(t, err)
 	assert.Contains(t, buf.String(), "All Mode Task")
 }
 
+func TestRenderStatsJSON(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	activeTaskID := insertTestTask(t, db, "Active Task", true)
+	inactiveTaskID := insertTestTask(t, db, "Inactive Task", false)
+	day1 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, inactiveTaskID, day1, day1.Add(time.Hour), "Inactive work")
+	insertTestTaskLog(t, db, activeTaskID, day1.Add(2*time.Hour), day1.Add(4*time.Hour), "Work")
+	insertTestTaskLog(t, db, activeTaskID, day2, day2.Add(30*time.Minute), "Next day work")
+	_, err := persistence.RecalculateAllTasksSecsSpent(db)
+	require.NoError(t, err)
+
+	dateRange := &types.DateRange{
+		Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		NumDays: 1,
+	}
+
+	decode := func(t *testing.T, output []byte) []statsJSONEntry {
+		t.Helper()
+		var entries []statsJSONEntry
+		require.NoError(t, json.Unmarshal(output, &entries))
+		return entries
+	}
+
+	t.Run("all time", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStatsJSON(db, &buf, nil, persistence.TLFilter{TaskStatus: types.TaskStatusAny}, DefaultStatsLimit)
+
+		// THEN
+		require.NoError(t, err)
+		entries := decode(t, buf.Bytes())
+		require.Len(t, entries, 2)
+		assert.Equal(t, statsJSONEntry{TaskID: int(activeTaskID), Summary: "Active Task", NumEntries: 2, SecsSpent: 9000}, entries[0])
+		assert.Equal(t, statsJSONEntry{TaskID: int(inactiveTaskID), Summary: "Inactive Task", NumEntries: 1, SecsSpent: 3600}, entries[1])
+		assert.Contains(t, buf.String(), `"taskId"`)
+		assert.Contains(t, buf.String(), `"numEntries"`)
+		assert.Contains(t, buf.String(), `"secsSpent"`)
+	})
+
+	t.Run("respects period and task status", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStatsJSON(db, &buf, dateRange, persistence.TLFilter{TaskStatus: types.TaskStatusActive}, DefaultStatsLimit)
+
+		// THEN
+		require.NoError(t, err)
+		entries := decode(t, buf.Bytes())
+		require.Len(t, entries, 1)
+		assert.Equal(t, statsJSONEntry{TaskID: int(activeTaskID), Summary: "Active Task", NumEntries: 1, SecsSpent: 7200}, entries[0])
+	})
+
+	t.Run("outputs an empty array when there are no entries", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// WHEN
+		err := RenderStatsJSON(db, &buf, dateRange, persistence.TLFilter{TaskStatus: types.TaskStatusInactive, Tag: "acme"}, DefaultStatsLimit)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
+
+func TestGetStatsExtremes(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Stats Task", true)
+	day1 := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day1.Add(9*time.Hour), day1.Add(11*time.Hour), "Work")
+	// nothing tracked on 2025/01/07
+	day3 := day1.AddDate(0, 0, 2)
+	insertTestTaskLog(t, db, taskID, day3.Add(9*time.Hour), day3.Add(14*time.Hour), "Work")
+	day4 := day1.AddDate(0, 0, 3)
+	insertTestTaskLog(t, db, taskID, day4.Add(9*time.Hour), day4.Add(10*time.Hour), "Work")
+
+	dateRange := types.DateRange{
+		Start:   day1,
+		End:     day1.AddDate(0, 0, 4),
+		NumDays: 4,
+	}
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	lineWith := func(result, label string) string {
+		for _, line := range strings.Split(result, "\n") {
+			if strings.Contains(line, label) {
+				return line
+			}
+		}
+		return ""
+	}
+
+	t.Run("skips days with no time tracked", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsExtremes(db, style, dateRange, types.TaskStatusAny, "", nil, false, true, now)
+
+		// THEN
+		require.NoError(t, err)
+		busiest := lineWith(result, "Busiest")
+		assert.Contains(t, busiest, "2025/01/08 (Wed)")
+		assert.Contains(t, busiest, "5h")
+		quietest := lineWith(result, "Quietest")
+		assert.Contains(t, quietest, "2025/01/09 (Thu)")
+		assert.Contains(t, quietest, "1h")
+	})
+
+	t.Run("considers days with no time tracked if asked to", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsExtremes(db, style, dateRange, types.TaskStatusAny, "", nil, true, true, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, lineWith(result, "Busiest"), "2025/01/08 (Wed)")
+		quietest := lineWith(result, "Quietest")
+		assert.Contains(t, quietest, "2025/01/07 (Tue)")
+		assert.Contains(t, quietest, "0s")
+	})
+
+	t.Run("ignores days in the future", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsExtremes(db, style, dateRange, types.TaskStatusAny, "", nil, true, true, day1.Add(12*time.Hour))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, lineWith(result, "Busiest"), "2025/01/06 (Mon)")
+		assert.Contains(t, lineWith(result, "Quietest"), "2025/01/06 (Mon)")
+	})
+}
+
+func TestGetStatsSparkline(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Sparkline Task", true)
+	day1 := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, day1.Add(9*time.Hour), day1.Add(11*time.Hour), "Work")
+	// nothing tracked on 2025/01/07
+	day3 := day1.AddDate(0, 0, 2)
+	insertTestTaskLog(t, db, taskID, day3.Add(9*time.Hour), day3.Add(15*time.Hour), "Work")
+
+	dateRange := types.DateRange{
+		Start:   day1,
+		End:     day1.AddDate(0, 0, 7),
+		NumDays: 7,
+	}
+
+	glyphs := func(result string) string {
+		var b strings.Builder
+		for _, r := range result {
+			if strings.ContainsRune(string(sparklineGlyphs), r) {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+
+	t.Run("has a glyph for each day", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsSparkline(db, style, dateRange, types.TaskStatusAny, "", nil, true, time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "▄▁█▁▁▁▁", glyphs(result))
+		assert.Contains(t, result, "2025/01/06")
+		assert.Contains(t, result, "2025/01/12")
+	})
+
+	t.Run("leaves out days that haven't started yet", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsSparkline(db, style, dateRange, types.TaskStatusAny, "", nil, true, day3.Add(18*time.Hour))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "▄▁█", glyphs(result))
+		assert.Contains(t, result, "2025/01/08")
+	})
+}
+
+func TestGetStatsStreak(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Streak Task", true)
+	day1 := time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local)
+	for _, offset := range []int{0, 1, 3, 4, 5} {
+		day := day1.AddDate(0, 0, offset)
+		insertTestTaskLog(t, db, taskID, day.Add(9*time.Hour), day.Add(10*time.Hour), "Work")
+	}
+
+	t.Run("all time", func(t *testing.T) {
+		// WHEN
+		result, err := getStatsStreak(db, style, nil, types.TaskStatusAny, "", nil, true)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "Longest streak: 3 days (2025/01/09 - 2025/01/11)")
+	})
+
+	t.Run("limited to date range", func(t *testing.T) {
+		dateRange := &types.DateRange{
+			Start:   day1,
+			End:     day1.AddDate(0, 0, 4),
+			NumDays: 4,
+		}
+
+		// WHEN
+		result, err := getStatsStreak(db, style, dateRange, types.TaskStatusAny, "", nil, true)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, result, "Longest streak: 2 days (2025/01/06 - 2025/01/07)")
+	})
+
+	t.Run("empty without activity", func(t *testing.T) {
+		dateRange := &types.DateRange{
+			Start:   day1.AddDate(0, 1, 0),
+			End:     day1.AddDate(0, 1, 7),
+			NumDays: 7,
+		}
+
+		// WHEN
+		result, err := getStatsStreak(db, style, dateRange, types.TaskStatusAny, "", nil, true)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestGetCalendar(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	style := getTestStyle()
+
+	taskID := insertTestTask(t, db, "Calendar Task", true)
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	insertTestTaskLog(t, db, taskID, monday.Add(9*time.Hour), monday.Add(13*time.Hour), "Work")
+	nextTuesday := monday.AddDate(0, 0, 8)
+	insertTestTaskLog(t, db, taskID, nextTuesday.Add(9*time.Hour), nextTuesday.Add(10*time.Hour), "Work")
+
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	dayCell := regexp.MustCompile(`[·░▒▓█]{3}`)
+
+	t.Run("two weeks starting on a monday", func(t *testing.T) {
+		dateRange := types.DateRange{
+			Start:   monday,
+			End:     monday.AddDate(0, 0, 14),
+			NumDays: 14,
+		}
+
+		// WHEN
+		result, err := getCalendar(db, style, dateRange, types.TaskStatusAny, "", nil, true, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, dayCell.FindAllString(result, -1), 14)
+		assert.Equal(t, 1, strings.Count(result, "███"))
+		assert.Equal(t, 1, strings.Count(result, "░░░"))
+		assert.Contains(t, result, "2025/01/06")
+		assert.Contains(t, result, "2025/01/13")
+		assert.NotContains(t, result, "2025/01/20")
+	})
+
+	t.Run("two weeks starting mid week", func(t *testing.T) {
+		wednesday := monday.AddDate(0, 0, 2)
+		dateRange := types.DateRange{
+			Start:   wednesday,
+			End:     wednesday.AddDate(0, 0, 14),
+			NumDays: 14,
+		}
+
+		// WHEN
+		result, err := getCalendar(db, style, dateRange, types.TaskStatusAny, "", nil, true, now)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, dayCell.FindAllString(result, -1), 14)
+		assert.Contains(t, result, "2025/01/06")
+		assert.Contains(t, result, "2025/01/13")
+		assert.Contains(t, result, "2025/01/20")
+	})
+
+	t.Run("leaves out days that haven't started yet", func(t *testing.T) {
+		dateRange := types.DateRange{
+			Start:   monday,
+			End:     monday.AddDate(0, 0, 14),
+			NumDays: 14,
+		}
+		tuesday := monday.AddDate(0, 0, 1).Add(15 * time.Hour)
+
+		// WHEN
+		result, err := getCalendar(db, style, dateRange, types.TaskStatusAny, "", nil, true, tuesday)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, dayCell.FindAllString(result, -1), 2)
+		assert.NotContains(t, result, "2025/01/13")
+	})
+}
+
 func TestShowActiveTaskNoActiveTask(t *testing.T) {
 	// GIVEN
 	db := setupTestDB(t)
@@ -332,10 +1327,11 @@ func TestShowActiveTaskNoActiveTask(t *testing.T) {
 	var buf bytes.Buffer
 
 	// WHEN - no active task in database
-	err := ShowActiveTask(db, &buf, "{{task}} - {{time}}")
+	active, err := ShowActiveTask(db, &buf, "{{task}} - {{time}}")
 
 	// THEN
 	require.NoError(t, err)
+	assert.False(t, active)
 	assert.Empty(t, buf.String())
 }
 
@@ -358,16 +1354,50 @@ func TestShowActiveTaskWithActiveTask(t *testing.T) {
 
 	// WHEN - call ShowActiveTask with template
 	template := "Currently working on: {{task}} ({{time}})"
-	err = ShowActiveTask(db, &buf, template)
+	active, err := ShowActiveTask(db, &buf, template)
 
 	// THEN - output should contain substituted task name and time
 	require.NoError(t, err)
+	assert.True(t, active)
 	output := buf.String()
 	assert.Contains(t, output, "Active Tracking Task")
 	assert.Contains(t, output, "30m")
 	assert.Contains(t, output, "Currently working on:")
 }
 
+func TestShowActiveTaskWithWeekPlaceholder(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	var buf bytes.Buffer
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	taskID := insertTestTask(t, db, "Weekly Quota", true)
+	// the task's week started 6 days ago
+	resetDay := now.AddDate(0, 0, 1).Weekday()
+	require.NoError(t, persistence.SetTaskWeekResetDay(db, int(taskID), &resetDay))
+
+	yesterday := today.AddDate(0, 0, -1).Add(12 * time.Hour)
+	insertTestTaskLog(t, db, taskID, yesterday, yesterday.Add(time.Hour), "this week")
+	aWeekAgo := today.AddDate(0, 0, -7).Add(12 * time.Hour)
+	insertTestTaskLog(t, db, taskID, aWeekAgo, aWeekAgo.Add(2*time.Hour), "before the reset")
+
+	_, err := db.Exec(
+		"INSERT INTO task_log (task_id, begin_ts, secs_spent, comment, active) VALUES (?, ?, ?, ?, ?)",
+		taskID, now.Add(-30*time.Minute), 0, "Active work", true,
+	)
+	require.NoError(t, err)
+
+	// WHEN
+	active, err := ShowActiveTask(db, &buf, "{{task}}: {{week}} this week")
+
+	// THEN
+	require.NoError(t, err)
+	assert.True(t, active)
+	assert.Equal(t, "Weekly Quota: 1h 30m this week", buf.String())
+}
+
 func TestShowActiveTaskTemplateSubstitution(t *testing.T) {
 	// GIVEN - no active task in database
 	db := setupTestDB(t)
@@ -382,9 +1412,55 @@ func TestShowActiveTaskTemplateSubstitution(t *testing.T) {
 
 	// WHEN - no active task means output should be empty
 	template := "Task: {{task}} - Time: {{time}}"
-	err := ShowActiveTask(db, &buf, template)
+	active, err := ShowActiveTask(db, &buf, template)
 
 	// THEN - since there's no active task being tracked, output is empty
 	require.NoError(t, err)
+	assert.False(t, active)
 	assert.Empty(t, buf.String())
 }
+
+func TestShowActiveTaskJSON(t *testing.T) {
+	t.Run("no active task", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		var buf bytes.Buffer
+
+		// WHEN
+		active, err := ShowActiveTaskJSON(db, &buf)
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, active)
+		assert.JSONEq(t, `{"active":false}`, buf.String())
+	})
+
+	t.Run("active task", func(t *testing.T) {
+		// GIVEN
+		db := setupTestDB(t)
+		defer db.Close()
+		var buf bytes.Buffer
+
+		taskID := insertTestTask(t, db, "Active Tracking Task", true)
+		_, err := db.Exec(
+			"INSERT INTO task_log (task_id, begin_ts, secs_spent, comment, active) VALUES (?, ?, ?, ?, ?)",
+			taskID, time.Now().Add(-30*time.Minute), 0, "Active work", true,
+		)
+		require.NoError(t, err)
+
+		// WHEN
+		active, err := ShowActiveTaskJSON(db, &buf)
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Len(t, got, 3)
+		assert.Equal(t, true, got["active"])
+		assert.Equal(t, "Active Tracking Task", got["task"])
+		assert.InDelta(t, 30*60, got["seconds"], 5)
+	})
+}
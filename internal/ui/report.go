@@ -2,9 +2,12 @@ package ui
 
 import (
 	"database/sql"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,10 +17,16 @@ import (
 	"github.com/dhth/hours/internal/utils"
 )
 
-var errCouldntGenerateReport = errors.New("couldn't generate report")
+var (
+	errCouldntGenerateReport = errors.New("couldn't generate report")
+	errReportCSVTruncated    = errors.New("some entries exceed the limit for a single day; increase --limit to export all of them")
+)
 
 const (
 	reportTimeCharsBudget = 6
+	// DefaultReportLimit is the default for the maximum number of entries
+	// shown for a single day in a report.
+	DefaultReportLimit = 100
 )
 
 // reportSummaryBudget returns the character width budget for task summary cells
@@ -36,69 +45,118 @@ func reportSummaryBudget(numDays int) int {
 	}
 }
 
+// ReportDayFilter restricts a multi-day report to the entries of one of the
+// boundary days of its date range. The report still has a column for every day
+// in the range.
+type ReportDayFilter uint8
+
+const (
+	ReportAllDays ReportDayFilter = iota
+	ReportFirstDayOnly
+	ReportLastDayOnly
+)
+
+// ReportFormat determines how a non-interactive report is written out.
+type ReportFormat uint8
+
+const (
+	ReportFormatTable ReportFormat = iota
+	ReportFormatMarkdown
+	ReportFormatCSV
+)
+
+var reportCSVHeader = []string{"date", "task", "secs_spent"}
+
 // reportGridEntry is the minimal interface needed by renderReportGrid to render
 // a single cell in the calendar-style report grid.
 type reportGridEntry interface {
+	reportTaskID() int
 	reportTaskSummary() string
 	reportSecsSpent() int
 }
 
 type taskLogEntryAdapter struct{ e types.TaskLogEntry }
 
+func (a taskLogEntryAdapter) reportTaskID() int         { return a.e.TaskID }
 func (a taskLogEntryAdapter) reportTaskSummary() string { return a.e.TaskSummary }
 func (a taskLogEntryAdapter) reportSecsSpent() int      { return a.e.SecsSpent }
 
 type taskReportEntryAdapter struct{ e types.TaskReportEntry }
 
+func (a taskReportEntryAdapter) reportTaskID() int         { return a.e.TaskID }
 func (a taskReportEntryAdapter) reportTaskSummary() string { return a.e.TaskSummary }
 func (a taskReportEntryAdapter) reportSecsSpent() int      { return a.e.SecsSpent }
 
-// perDayFetcher fetches the report entries for a single day [day, nextDay).
-type perDayFetcher func(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus) ([]reportGridEntry, error)
+// perDayFetcher fetches at most limit report entries for a single day
+// [day, nextDay), and reports whether the limit was reached.
+type perDayFetcher func(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int) ([]reportGridEntry, bool, error)
 
-func fetchTLEntriesForDay(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus) ([]reportGridEntry, error) {
-	raw, err := pers.FetchTLEntriesBetweenTS(db, day, nextDay, taskStatus, 100)
+func fetchTLEntriesForDay(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int) ([]reportGridEntry, bool, error) {
+	raw, limitReached, err := pers.FetchTLEntriesBetweenTS(db, day, nextDay, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, LogTag: logTag, TaskID: taskID}, limit)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	out := make([]reportGridEntry, len(raw))
 	for i, e := range raw {
 		out[i] = taskLogEntryAdapter{e}
 	}
-	return out, nil
+	return out, limitReached, nil
 }
 
-func fetchReportEntriesForDay(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus) ([]reportGridEntry, error) {
-	raw, err := pers.FetchReportBetweenTS(db, day, nextDay, taskStatus, 100)
+func fetchReportEntriesForDay(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int) ([]reportGridEntry, bool, error) {
+	raw, limitReached, err := pers.FetchReportBetweenTS(db, day, nextDay, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, LogTag: logTag, TaskID: taskID}, limit)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	out := make([]reportGridEntry, len(raw))
 	for i, e := range raw {
 		out[i] = taskReportEntryAdapter{e}
 	}
-	return out, nil
+	return out, limitReached, nil
 }
 
-// renderReportGrid is the shared rendering pipeline for both the plain and
-// aggregated report views.
-func renderReportGrid(db *sql.DB, style Style, start time.Time, numDays int, taskStatus types.TaskStatus, plain bool, fetch perDayFetcher) (string, error) {
+// filterReportDay returns a perDayFetcher that drops the entries fetched by
+// fetch for every day of dateRange except the boundary day chosen by filter.
+func filterReportDay(fetch perDayFetcher, dateRange types.DateRange, filter ReportDayFilter) perDayFetcher {
+	var keptDay time.Time
+	switch filter {
+	case ReportFirstDayOnly:
+		keptDay = dateRange.Start
+	case ReportLastDayOnly:
+		keptDay = dateRange.Start.AddDate(0, 0, dateRange.NumDays-1)
+	default:
+		return fetch
+	}
+
+	return func(db *sql.DB, day, nextDay time.Time, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int) ([]reportGridEntry, bool, error) {
+		entries, limitReached, err := fetch(db, day, nextDay, taskStatus, tag, logTag, taskID, limit)
+		if err != nil || !day.Equal(keptDay) {
+			return nil, false, err
+		}
+
+		return entries, limitReached, nil
+	}
+}
+
+// fetchReportGridData fetches the report entries for each of the numDays days
+// starting at start, keyed by the day's index. It also returns the number of
+// rows the grid needs, which is at least 1, and whether any day had more
+// entries than limit.
+func fetchReportGridData(db *sql.DB, start time.Time, numDays int, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int, fetch perDayFetcher) (map[int][]reportGridEntry, int, bool, error) {
 	day := start
 	var nextDay time.Time
 
-	var maxEntryForADay int
+	maxEntryForADay := 1
 	reportData := make(map[int][]reportGridEntry)
+	var truncated bool
 
-	noEntriesFound := true
 	for i := range numDays {
 		nextDay = day.AddDate(0, 0, 1)
-		entries, err := fetch(db, day, nextDay, taskStatus)
+		entries, limitReached, err := fetch(db, day, nextDay, taskStatus, tag, logTag, taskID, limit)
 		if err != nil {
-			return "", err
-		}
-		if noEntriesFound && len(entries) > 0 {
-			noEntriesFound = false
+			return nil, 0, false, err
 		}
+		truncated = truncated || limitReached
 
 		day = nextDay
 		reportData[i] = entries
@@ -107,8 +165,15 @@ func renderReportGrid(db *sql.DB, style Style, start time.Time, numDays int, tas
 		}
 	}
 
-	if noEntriesFound {
-		maxEntryForADay = 1
+	return reportData, maxEntryForADay, truncated, nil
+}
+
+// renderReportGrid is the shared rendering pipeline for both the plain and
+// aggregated report views.
+func renderReportGrid(db *sql.DB, style Style, start time.Time, numDays int, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int, plain bool, fetch perDayFetcher) (string, error) {
+	reportData, maxEntryForADay, truncated, err := fetchReportGridData(db, start, numDays, taskStatus, tag, logTag, taskID, limit, fetch)
+	if err != nil {
+		return "", err
 	}
 
 	data := make([][]string, maxEntryForADay)
@@ -168,7 +233,7 @@ func renderReportGrid(db *sql.DB, style Style, start time.Time, numDays int, tas
 	}
 
 	headersValues := make([]string, numDays)
-	day = start
+	day := start
 	counter := 0
 	for counter < numDays {
 		headersValues[counter] = day.Format(dateFormat)
@@ -181,44 +246,209 @@ func renderReportGrid(db *sql.DB, style Style, start time.Time, numDays int, tas
 		headers[i] = rs.headerStyle.Render(headersValues[i])
 	}
 
-	return renderRecordsTable(rs, headers, totalTimePerDay, data)
+	table, err := renderRecordsTable(rs, headers, totalTimePerDay, data)
+	if err != nil {
+		return "", err
+	}
+
+	// the column totals already cover a single day
+	if numDays > 1 {
+		var grandTotalSecs int
+		for _, ts := range totalSecsPerDay {
+			grandTotalSecs += ts
+		}
+		table += renderReportGrandTotal(rs, grandTotalSecs)
+	}
+
+	if truncated {
+		table += renderTruncationNotice(rs)
+	}
+
+	return table, nil
+}
+
+// renderReportGrandTotal returns the line shown below a multi-day report grid
+// with the time tracked over the whole range.
+func renderReportGrandTotal(rs reportStyles, totalSecs int) string {
+	return "\n" + rs.footerStyle.Render(fmt.Sprintf("Total: %s", types.HumanizeDuration(totalSecs))) + "\n"
+}
+
+// renderReportMarkdown renders the same data as renderReportGrid as a GitHub
+// flavored Markdown table, with a column per day and a final row holding the
+// total time tracked on each day.
+func renderReportMarkdown(db *sql.DB, start time.Time, numDays int, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int, fetch perDayFetcher) (string, error) {
+	reportData, maxEntryForADay, truncated, err := fetchReportGridData(db, start, numDays, taskStatus, tag, logTag, taskID, limit, fetch)
+	if err != nil {
+		return "", err
+	}
+
+	escapeCell := func(value string) string {
+		return strings.ReplaceAll(value, "|", `\|`)
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	headers := make([]string, numDays)
+	separators := make([]string, numDays)
+	for i := range numDays {
+		headers[i] = start.AddDate(0, 0, i).Format(dateFormat)
+		separators[i] = "---"
+	}
+	writeRow(headers)
+	writeRow(separators)
+
+	totalSecsPerDay := make([]int, numDays)
+	for rowIndex := range maxEntryForADay {
+		row := make([]string, numDays)
+		for colIndex := range numDays {
+			if rowIndex >= len(reportData[colIndex]) {
+				continue
+			}
+
+			tr := reportData[colIndex][rowIndex]
+			row[colIndex] = fmt.Sprintf("%s (%s)", escapeCell(tr.reportTaskSummary()), types.HumanizeDuration(tr.reportSecsSpent()))
+			totalSecsPerDay[colIndex] += tr.reportSecsSpent()
+		}
+		writeRow(row)
+	}
+
+	totals := make([]string, numDays)
+	for i, secs := range totalSecsPerDay {
+		if secs != 0 {
+			totals[i] = fmt.Sprintf("**%s**", types.HumanizeDuration(secs))
+		}
+	}
+	writeRow(totals)
+
+	if truncated {
+		sb.WriteString("\n_" + recordsTruncatedMsg + "_\n")
+	}
+
+	return sb.String(), nil
+}
+
+// renderReportCSV renders a row for each task tracked on each day in the period,
+// holding the total time spent on it that day, followed by a row with the total
+// time spent across the period. Cells are separated by delimiter, and quoted
+// when they contain it.
+func renderReportCSV(db *sql.DB, start time.Time, numDays int, taskStatus types.TaskStatus, tag, logTag string, taskID *int, limit int, fetch perDayFetcher, delimiter rune) (string, error) {
+	reportData, _, truncated, err := fetchReportGridData(db, start, numDays, taskStatus, tag, logTag, taskID, limit, fetch)
+	if err != nil {
+		return "", err
+	}
+
+	// a notice like the one below the table would corrupt the CSV, and
+	// silently leaving out entries would make the total row wrong
+	if truncated {
+		return "", errReportCSVTruncated
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = delimiter
+
+	if err := w.Write(reportCSVHeader); err != nil {
+		return "", err
+	}
+
+	var totalSecs int
+	for i := range numDays {
+		date := start.AddDate(0, 0, i).Format(dateFormat)
+
+		// tasks can share a summary, so rows are keyed by task ID
+		var taskIDs []int
+		summaries := make(map[int]string)
+		secsPerTask := make(map[int]int)
+		for _, entry := range reportData[i] {
+			id := entry.reportTaskID()
+			if _, ok := secsPerTask[id]; !ok {
+				taskIDs = append(taskIDs, id)
+				summaries[id] = entry.reportTaskSummary()
+			}
+			secsPerTask[id] += entry.reportSecsSpent()
+		}
+
+		for _, id := range taskIDs {
+			secs := secsPerTask[id]
+			if err := w.Write([]string{date, summaries[id], strconv.Itoa(secs)}); err != nil {
+				return "", err
+			}
+			totalSecs += secs
+		}
+	}
+
+	if err := w.Write([]string{"total", "", strconv.Itoa(totalSecs)}); err != nil {
+		return "", err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// ReportOptions holds the options for rendering a report.
+type ReportOptions struct {
+	RecordsOptions
+	DateRange types.DateRange
+	DayFilter ReportDayFilter
+	// Agg shows a row per task and day instead of one per task log entry.
+	Agg          bool
+	Format       ReportFormat
+	CSVDelimiter rune
 }
 
 func RenderReport(db *sql.DB,
 	style Style,
 	writer io.Writer,
-	plain bool,
-	dateRange types.DateRange,
-	period string,
-	taskStatus types.TaskStatus,
-	agg bool,
-	interactive bool,
+	opts ReportOptions,
 ) error {
 	var report string
 	var analyticsType recordsKind
+	var fetch perDayFetcher
 	var err error
 
-	if agg {
+	if opts.Agg {
 		analyticsType = reportAggRecords
-		report, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain, fetchReportEntriesForDay)
+		fetch = fetchReportEntriesForDay
 	} else {
 		analyticsType = reportRecords
-		report, err = renderReportGrid(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain, fetchTLEntriesForDay)
+		fetch = fetchTLEntriesForDay
+	}
+	fetch = filterReportDay(fetch, opts.DateRange, opts.DayFilter)
+
+	f := opts.Filter
+	switch {
+	case opts.Format == ReportFormatMarkdown && !opts.Interactive:
+		report, err = renderReportMarkdown(db, opts.DateRange.Start, opts.DateRange.NumDays, f.TaskStatus, f.Tag, f.LogTag, f.TaskID, opts.Limit, fetch)
+	case opts.Format == ReportFormatCSV && !opts.Interactive:
+		report, err = renderReportCSV(db, opts.DateRange.Start, opts.DateRange.NumDays, f.TaskStatus, f.Tag, f.LogTag, f.TaskID, opts.Limit, fetch, opts.CSVDelimiter)
+	default:
+		report, err = renderReportGrid(db, style, opts.DateRange.Start, opts.DateRange.NumDays, f.TaskStatus, f.Tag, f.LogTag, f.TaskID, opts.Limit, opts.Plain, fetch)
 	}
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateReport, err.Error())
 	}
 
-	if interactive {
+	if opts.Interactive {
 		p := tea.NewProgram(initialRecordsModel(
 			analyticsType,
 			db,
 			style,
 			types.RealTimeProvider{},
-			dateRange,
-			period,
-			taskStatus,
-			plain,
+			opts.DateRange,
+			opts.Period,
+			f.TaskStatus,
+			f.Tag,
+			f.LogTag,
+			f.TaskID,
+			opts.Limit,
+			opts.Plain,
 			report,
 		))
 		_, err := p.Run()
@@ -226,6 +456,10 @@ func RenderReport(db *sql.DB,
 			return err
 		}
 	} else {
+		// A header line would keep the CSV output from being imported as is
+		if opts.HeaderMeta != nil && opts.Format != ReportFormatCSV {
+			fmt.Fprint(writer, renderHeaderMeta(style, *opts.HeaderMeta, &opts.DateRange, f.TaskStatus, opts.Plain || opts.Format == ReportFormatMarkdown))
+		}
 		fmt.Fprint(writer, report)
 	}
 	return nil
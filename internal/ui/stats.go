@@ -2,9 +2,13 @@ package ui
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,51 +20,79 @@ import (
 var errCouldntGenerateStats = errors.New("couldn't generate stats")
 
 const (
+	// DefaultStatsLimit is the default for the maximum number of tasks shown
+	// in stats.
+	DefaultStatsLimit    = 100
 	statsLogEntriesLimit = 10000
 	statsTimeCharsBudget = 6
+	uncategorizedLabel   = "uncategorized"
 )
 
+// sparklineGlyphs holds the glyphs used for each day in a sparkline, from no
+// time tracked to the most time tracked on a single day in the range.
+var sparklineGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+// StatsOptions holds the options for rendering stats.
+type StatsOptions struct {
+	RecordsOptions
+	// DateRange is the range to show stats for; nil considers all log entries.
+	DateRange *types.DateRange
+	// Sparkline adds a line with a glyph for each day in the date range.
+	Sparkline bool
+}
+
 func RenderStats(db *sql.DB,
 	style Style,
 	writer io.Writer,
-	plain bool,
-	dateRange *types.DateRange,
-	period string,
-	taskStatus types.TaskStatus,
-	interactive bool,
+	opts StatsOptions,
 ) error {
 	var stats string
 	var err error
 
-	if interactive && dateRange == nil {
+	f := opts.Filter
+
+	if opts.Interactive && opts.DateRange == nil {
 		return fmt.Errorf("%w when period=all", errInteractiveModeNotApplicable)
 	}
 
-	if dateRange == nil {
-		stats, err = getStats(db, style, dateRange, taskStatus, plain)
+	if opts.DateRange == nil {
+		stats, err = getStats(db, style, opts.DateRange, f.TaskStatus, f.Tag, f.TaskID, opts.Limit, opts.Plain)
 		if err != nil {
 			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
 		}
 
+		streak, err := getStatsStreak(db, style, nil, f.TaskStatus, f.Tag, f.TaskID, opts.Plain)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+		}
+
+		if opts.HeaderMeta != nil {
+			fmt.Fprint(writer, renderHeaderMeta(style, *opts.HeaderMeta, nil, f.TaskStatus, opts.Plain))
+		}
 		fmt.Fprint(writer, stats)
+		fmt.Fprint(writer, streak)
 		return nil
 	}
 
-	stats, err = getStats(db, style, dateRange, taskStatus, plain)
+	stats, err = getStats(db, style, opts.DateRange, f.TaskStatus, f.Tag, f.TaskID, opts.Limit, opts.Plain)
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
 	}
 
-	if interactive {
+	if opts.Interactive {
 		p := tea.NewProgram(initialRecordsModel(
 			reportStats,
 			db,
 			style,
 			types.RealTimeProvider{},
-			*dateRange,
-			period,
-			taskStatus,
-			plain,
+			*opts.DateRange,
+			opts.Period,
+			f.TaskStatus,
+			f.Tag,
+			"",
+			f.TaskID,
+			opts.Limit,
+			opts.Plain,
 			stats,
 		))
 		_, err := p.Run()
@@ -68,15 +100,481 @@ func RenderStats(db *sql.DB,
 			return err
 		}
 	} else {
+		if opts.HeaderMeta != nil {
+			fmt.Fprint(writer, renderHeaderMeta(style, *opts.HeaderMeta, opts.DateRange, f.TaskStatus, opts.Plain))
+		}
 		fmt.Fprint(writer, stats)
+
+		streak, err := getStatsStreak(db, style, opts.DateRange, f.TaskStatus, f.Tag, f.TaskID, opts.Plain)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+		}
+		fmt.Fprint(writer, streak)
+
+		if opts.Sparkline {
+			line, err := getStatsSparkline(db, style, *opts.DateRange, f.TaskStatus, f.Tag, f.TaskID, opts.Plain, time.Now())
+			if err != nil {
+				return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+			}
+			fmt.Fprint(writer, line)
+		}
+	}
+	return nil
+}
+
+// RenderStatsGrouped writes the stats for each task in each period (as
+// determined by granularity) in dateRange, with a row per task and period.
+func RenderStatsGrouped(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	dateRange types.DateRange,
+	filter pers.TLFilter,
+	granularity types.Granularity,
+	headerMeta *HeaderMeta,
+) error {
+	entries, err := pers.FetchStatsGrouped(db, dateRange.Start, dateRange.End, filter, granularity)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	stats, err := renderGroupedStatsTable(style, entries, granularity, plain)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	if headerMeta != nil {
+		fmt.Fprint(writer, renderHeaderMeta(style, *headerMeta, &dateRange, filter.TaskStatus, plain))
+	}
+	fmt.Fprint(writer, stats)
+	return nil
+}
+
+// RenderStatsByCategory writes the time tracked in dateRange for each task log
+// category, as a table with a totals footer.
+func RenderStatsByCategory(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	dateRange types.DateRange,
+	filter pers.TLFilter,
+	headerMeta *HeaderMeta,
+) error {
+	entries, err := pers.FetchStatsByCategory(db, dateRange.Start, dateRange.End, filter)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	stats, err := renderCategoryStatsTable(style, entries, plain)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	if headerMeta != nil {
+		fmt.Fprint(writer, renderHeaderMeta(style, *headerMeta, &dateRange, filter.TaskStatus, plain))
+	}
+	fmt.Fprint(writer, stats)
+	return nil
+}
+
+// renderCategoryStatsTable renders category stats entries as a table with a
+// totals footer. Entries without a category are shown as "uncategorized".
+func renderCategoryStatsTable(style Style, entries []types.CategoryStatsEntry, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+
+	var totalSecs int
+	var totalNumEntries int
+	for _, entry := range entries {
+		totalSecs += entry.SecsSpent
+		totalNumEntries += entry.NumEntries
+	}
+
+	var data [][]string
+	for _, entry := range entries {
+		category := uncategorizedLabel
+		if entry.Category != nil {
+			category = *entry.Category
+		}
+
+		row := []string{
+			utils.RightPadTrim(category, 20, false),
+			fmt.Sprintf("%d", entry.NumEntries),
+			utils.RightPadTrim(types.HumanizeDuration(entry.SecsSpent), statsTimeCharsBudget, false),
+			statsShare(entry.SecsSpent, totalSecs),
+		}
+		if !plain {
+			rowStyle := style.getDynamicStyle(category)
+			for i := range row {
+				row[i] = rowStyle.Render(row[i])
+			}
+		}
+		data = append(data, row)
+	}
+
+	if len(data) == 0 {
+		data = [][]string{{utils.RightPadTrim("", 20, false), "", utils.RightPadTrim("", statsTimeCharsBudget, false), ""}}
+	}
+
+	headerValues := []string{"Category", "#LogEntries", "TimeSpent", "%"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+
+	var footer []string
+	if len(entries) > 0 {
+		footer = []string{
+			utils.RightPadTrim("Total", 20, false),
+			fmt.Sprintf("%d", totalNumEntries),
+			utils.RightPadTrim(types.HumanizeDuration(totalSecs), statsTimeCharsBudget, false),
+			statsShare(totalSecs, totalSecs),
+		}
+		if !plain {
+			for i := range footer {
+				footer[i] = rs.footerStyle.Render(footer[i])
+			}
+		}
+	}
+
+	return renderRecordsTable(rs, headers, footer, data)
+}
+
+// groupedStatsPeriodLabel returns how the period beginning at periodStart is
+// shown in the grouped stats table.
+func groupedStatsPeriodLabel(periodStart time.Time, granularity types.Granularity) string {
+	if granularity == types.GranularityMonth {
+		return periodStart.Format("2006/01")
+	}
+
+	return periodStart.Format(dateFormat)
+}
+
+// renderGroupedStatsTable renders grouped stats entries as a table with a
+// totals footer. A period's label is only shown on its first row.
+func renderGroupedStatsTable(style Style, entries []types.GroupedStatsEntry, granularity types.Granularity, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+	styleCache := make(map[string]lipgloss.Style)
+
+	var data [][]string
+	var totalSecs int
+	var totalNumEntries int
+	var lastPeriodLabel string
+	for _, entry := range entries {
+		totalSecs += entry.SecsSpent
+		totalNumEntries += entry.NumEntries
+
+		periodLabel := groupedStatsPeriodLabel(entry.PeriodStart, granularity)
+		periodCell := periodLabel
+		if periodLabel == lastPeriodLabel {
+			periodCell = ""
+		}
+		lastPeriodLabel = periodLabel
+
+		row := []string{
+			utils.RightPadTrim(entry.TaskSummary, 20, false),
+			fmt.Sprintf("%d", entry.NumEntries),
+			utils.RightPadTrim(types.HumanizeDuration(entry.SecsSpent), statsTimeCharsBudget, false),
+		}
+		if !plain {
+			rowStyle, ok := styleCache[entry.TaskSummary]
+			if !ok {
+				rowStyle = style.getDynamicStyle(entry.TaskSummary)
+				styleCache[entry.TaskSummary] = rowStyle
+			}
+			for i := range row {
+				row[i] = rowStyle.Render(row[i])
+			}
+		}
+		data = append(data, append([]string{periodCell}, row...))
+	}
+
+	if len(data) == 0 {
+		data = [][]string{{"", utils.RightPadTrim("", 20, false), "", utils.RightPadTrim("", statsTimeCharsBudget, false)}}
+	}
+
+	headerValues := []string{"Period", "Task", "#LogEntries", "TimeSpent"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+
+	var footer []string
+	if len(entries) > 0 {
+		footer = []string{
+			"",
+			utils.RightPadTrim("Total", 20, false),
+			fmt.Sprintf("%d", totalNumEntries),
+			utils.RightPadTrim(types.HumanizeDuration(totalSecs), statsTimeCharsBudget, false),
+		}
+		if !plain {
+			for i := range footer {
+				footer[i] = rs.footerStyle.Render(footer[i])
+			}
+		}
+	}
+
+	return renderRecordsTable(rs, headers, footer, data)
+}
+
+// statsJSONEntry is the shape of a single task's stats in the JSON output.
+type statsJSONEntry struct {
+	TaskID     int    `json:"taskId"`
+	Summary    string `json:"summary"`
+	NumEntries int    `json:"numEntries"`
+	SecsSpent  int    `json:"secsSpent"`
+}
+
+// RenderStatsJSON writes the same stats as RenderStats as a JSON array, with
+// an object per task, ordered by task ID. Only the limit tasks with the most
+// time tracked are included. A nil dateRange considers all log entries.
+func RenderStatsJSON(db *sql.DB,
+	writer io.Writer,
+	dateRange *types.DateRange,
+	filter pers.TLFilter,
+	limit int,
+) error {
+	var entries []types.TaskReportEntry
+	var err error
+
+	if dateRange == nil {
+		entries, err = pers.FetchStats(db, filter, limit)
+	} else {
+		entries, err = pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, filter, limit)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TaskID < entries[j].TaskID
+	})
+
+	output := make([]statsJSONEntry, len(entries))
+	for i, entry := range entries {
+		output[i] = statsJSONEntry{
+			TaskID:     entry.TaskID,
+			Summary:    entry.TaskSummary,
+			NumEntries: entry.NumEntries,
+			SecsSpent:  entry.SecsSpent,
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// RenderStatsExtremes writes the days with the most and the least time
+// tracked in dateRange. Days with no tracked time are only considered if
+// includeZero is set.
+func RenderStatsExtremes(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	dateRange types.DateRange,
+	filter pers.TLFilter,
+	includeZero bool,
+) error {
+	extremes, err := getStatsExtremes(db, style, dateRange, filter.TaskStatus, filter.Tag, filter.TaskID, includeZero, plain, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
 	}
+
+	fmt.Fprint(writer, extremes)
 	return nil
 }
 
+func getStatsExtremes(db *sql.DB,
+	style Style,
+	dateRange types.DateRange,
+	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	includeZero bool,
+	plain bool,
+	now time.Time,
+) (string, error) {
+	// days that haven't started yet shouldn't count as quiet ones
+	end := startedDaysEnd(dateRange.End, now)
+
+	totals, err := pers.FetchDailyTotalsBetweenTS(db, dateRange.Start, end, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID})
+	if err != nil {
+		return "", err
+	}
+
+	rs := style.getReportStyles(plain)
+	headerValues := []string{"", "Day", "TimeSpent"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+
+	busiest, quietest, ok := getDailyExtremes(totals, includeZero)
+	if !ok {
+		return renderRecordsTable(rs, headers, nil, [][]string{{"", "", ""}})
+	}
+
+	data := [][]string{
+		extremesRow("Busiest", busiest),
+		extremesRow("Quietest", quietest),
+	}
+
+	return renderRecordsTable(rs, headers, nil, data)
+}
+
+// startedDaysEnd returns end, capped at the end of the day now falls on, so
+// that days that haven't started yet are left out.
+func startedDaysEnd(end, now time.Time) time.Time {
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if end.After(tomorrow) {
+		return tomorrow
+	}
+
+	return end
+}
+
+// getStatsSparkline returns a line with a glyph for each day in dateRange that
+// has started, scaled to the most time tracked on a single day.
+func getStatsSparkline(db *sql.DB,
+	style Style,
+	dateRange types.DateRange,
+	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	plain bool,
+	now time.Time,
+) (string, error) {
+	end := startedDaysEnd(dateRange.End, now)
+
+	totals, err := pers.FetchDailyTotalsBetweenTS(db, dateRange.Start, end, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID})
+	if err != nil {
+		return "", err
+	}
+
+	if len(totals) == 0 {
+		return "", nil
+	}
+
+	rs := style.getReportStyles(plain)
+	line := fmt.Sprintf("%s %s %s",
+		totals[0].Day.Format(dateFormat),
+		rs.footerStyle.Render(renderSparkline(totals)),
+		totals[len(totals)-1].Day.Format(dateFormat),
+	)
+
+	return fmt.Sprintf("\n%s\n", line), nil
+}
+
+// getStatsStreak returns a line with the longest run of consecutive days with
+// any time tracked, limited to dateRange if it's not nil. It's empty if no
+// time was tracked.
+func getStatsStreak(db *sql.DB,
+	style Style,
+	dateRange *types.DateRange,
+	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	plain bool,
+) (string, error) {
+	days, err := pers.FetchDaysWithActivity(db, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID})
+	if err != nil {
+		return "", err
+	}
+
+	if dateRange != nil {
+		days = slices.DeleteFunc(days, func(day time.Time) bool {
+			return day.Before(dateRange.Start) || !day.Before(dateRange.End)
+		})
+	}
+
+	return renderStreak(style, days, plain), nil
+}
+
+// renderStreak returns a line with the longest streak in days, which must be
+// sorted in ascending order.
+func renderStreak(style Style, days []time.Time, plain bool) string {
+	length, start := types.LongestStreak(days)
+	if length == 0 {
+		return ""
+	}
+
+	unit := "days"
+	if length == 1 {
+		unit = "day"
+	}
+
+	rs := style.getReportStyles(plain)
+	end := start.AddDate(0, 0, length-1)
+	line := fmt.Sprintf("%s %d %s (%s - %s)",
+		rs.footerStyle.Render("Longest streak:"),
+		length,
+		unit,
+		start.Format(dateFormat),
+		end.Format(dateFormat),
+	)
+
+	return fmt.Sprintf("\n%s\n", line)
+}
+
+// renderSparkline returns a glyph per day in totals. Days with no time tracked
+// get the lowest glyph, and any tracked time gets at least the next one.
+func renderSparkline(totals []types.DailyTotal) string {
+	var maxSecs int
+	for _, total := range totals {
+		maxSecs = max(maxSecs, total.SecsSpent)
+	}
+
+	glyphs := make([]rune, len(totals))
+	for i, total := range totals {
+		level := 0
+		if total.SecsSpent > 0 {
+			level = 1 + (total.SecsSpent*(len(sparklineGlyphs)-2))/maxSecs
+		}
+		glyphs[i] = sparklineGlyphs[level]
+	}
+
+	return string(glyphs)
+}
+
+func extremesRow(label string, total types.DailyTotal) []string {
+	return []string{
+		label,
+		total.Day.Format("2006/01/02 (Mon)"),
+		types.HumanizeDuration(total.SecsSpent),
+	}
+}
+
+// getDailyExtremes returns the days with the most and the least time tracked;
+// ties go to the earlier day. ok is false if there are no days to consider.
+func getDailyExtremes(totals []types.DailyTotal, includeZero bool) (busiest, quietest types.DailyTotal, ok bool) {
+	for _, total := range totals {
+		if total.SecsSpent == 0 && !includeZero {
+			continue
+		}
+
+		if !ok {
+			busiest, quietest, ok = total, total, true
+			continue
+		}
+
+		if total.SecsSpent > busiest.SecsSpent {
+			busiest = total
+		}
+		if total.SecsSpent < quietest.SecsSpent {
+			quietest = total
+		}
+	}
+
+	return busiest, quietest, ok
+}
+
 func getStats(db *sql.DB,
 	style Style,
 	dateRange *types.DateRange,
 	taskStatus types.TaskStatus,
+	tag string,
+	taskID *int,
+	limit int,
 	plain bool) (string,
 	error,
 ) {
@@ -84,15 +582,42 @@ func getStats(db *sql.DB,
 	var err error
 
 	if dateRange == nil {
-		entries, err = pers.FetchStats(db, taskStatus, statsLogEntriesLimit)
+		entries, err = pers.FetchStats(db, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID}, statsLogEntriesLimit)
 	} else {
-		entries, err = pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, taskStatus, statsLogEntriesLimit)
+		entries, err = pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, pers.TLFilter{TaskStatus: taskStatus, Tag: tag, TaskID: taskID}, statsLogEntriesLimit)
 	}
 
 	if err != nil {
 		return "", err
 	}
 
+	return renderStatsTable(style, entries, limit, plain, false)
+}
+
+// renderStatsTable renders stats entries as a table with a totals footer. Only
+// the first limit entries get a row of their own; the rest are folded into a
+// single "+N more" row, so that the totals and shares still account for every
+// entry. The compact variant leaves out the number of log entries and each
+// task's share of the total time, which makes it suitable for displaying
+// inside the TUI.
+func renderStatsTable(style Style, entries []types.TaskReportEntry, limit int, plain bool, compact bool) (string, error) {
+	var totalSecs int
+	var totalNumEntries int
+	for _, entry := range entries {
+		totalSecs += entry.SecsSpent
+		totalNumEntries += entry.NumEntries
+	}
+
+	if len(entries) > limit {
+		var rest types.TaskReportEntry
+		for _, entry := range entries[limit:] {
+			rest.SecsSpent += entry.SecsSpent
+			rest.NumEntries += entry.NumEntries
+		}
+		rest.TaskSummary = fmt.Sprintf("+%d more", len(entries)-limit)
+		entries = append(entries[:limit:limit], rest)
+	}
+
 	var numEntriesInTable int
 	if len(entries) == 0 {
 		numEntriesInTable = 1
@@ -102,11 +627,12 @@ func getStats(db *sql.DB,
 
 	data := make([][]string, numEntriesInTable)
 	if len(entries) == 0 {
-		data[0] = []string{
+		data[0] = statsRow(compact,
 			utils.RightPadTrim("", 20, false),
 			"",
 			utils.RightPadTrim("", statsTimeCharsBudget, false),
-		}
+			"",
+		)
 	}
 
 	var timeSpentStr string
@@ -114,34 +640,33 @@ func getStats(db *sql.DB,
 	rs := style.getReportStyles(plain)
 	styleCache := make(map[string]lipgloss.Style)
 
-	var totalSecs int
-	var totalNumEntries int
 	for i, entry := range entries {
 		timeSpentStr = types.HumanizeDuration(entry.SecsSpent)
-		totalSecs += entry.SecsSpent
-		totalNumEntries += entry.NumEntries
+		shareStr := statsShare(entry.SecsSpent, totalSecs)
 
 		if plain {
-			data[i] = []string{
+			data[i] = statsRow(compact,
 				utils.RightPadTrim(entry.TaskSummary, 20, false),
 				fmt.Sprintf("%d", entry.NumEntries),
 				utils.RightPadTrim(timeSpentStr, statsTimeCharsBudget, false),
-			}
+				shareStr,
+			)
 		} else {
 			rowStyle, ok := styleCache[entry.TaskSummary]
 			if !ok {
 				rowStyle = style.getDynamicStyle(entry.TaskSummary)
 				styleCache[entry.TaskSummary] = rowStyle
 			}
-			data[i] = []string{
+			data[i] = statsRow(compact,
 				rowStyle.Render(utils.RightPadTrim(entry.TaskSummary, 20, false)),
 				rowStyle.Render(fmt.Sprintf("%d", entry.NumEntries)),
 				rowStyle.Render(utils.RightPadTrim(timeSpentStr, statsTimeCharsBudget, false)),
-			}
+				rowStyle.Render(shareStr),
+			)
 		}
 	}
 
-	headerValues := []string{"Task", "#LogEntries", "TimeSpent"}
+	headerValues := statsRow(compact, "Task", "#LogEntries", "TimeSpent", "%")
 	headers := make([]string, len(headerValues))
 	for i, h := range headerValues {
 		headers[i] = rs.headerStyle.Render(h)
@@ -150,20 +675,41 @@ func getStats(db *sql.DB,
 	var footer []string
 	if len(entries) > 0 {
 		totalTimeStr := types.HumanizeDuration(totalSecs)
+		totalShareStr := statsShare(totalSecs, totalSecs)
 		if plain {
-			footer = []string{
+			footer = statsRow(compact,
 				utils.RightPadTrim("Total", 20, false),
 				fmt.Sprintf("%d", totalNumEntries),
 				utils.RightPadTrim(totalTimeStr, statsTimeCharsBudget, false),
-			}
+				totalShareStr,
+			)
 		} else {
-			footer = []string{
+			footer = statsRow(compact,
 				rs.footerStyle.Render(utils.RightPadTrim("Total", 20, false)),
 				rs.footerStyle.Render(fmt.Sprintf("%d", totalNumEntries)),
 				rs.footerStyle.Render(utils.RightPadTrim(totalTimeStr, statsTimeCharsBudget, false)),
-			}
+				rs.footerStyle.Render(totalShareStr),
+			)
 		}
 	}
 
 	return renderRecordsTable(rs, headers, footer, data)
 }
+
+func statsRow(compact bool, task, numEntries, timeSpent, share string) []string {
+	if compact {
+		return []string{task, timeSpent}
+	}
+
+	return []string{task, numEntries, timeSpent, share}
+}
+
+// statsShare returns secs as a percentage of totalSecs. A range without any
+// time tracked gives every task a share of zero.
+func statsShare(secs, totalSecs int) string {
+	if totalSecs == 0 {
+		return "0.0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", float64(secs)*100/float64(totalSecs))
+}
@@ -40,12 +40,16 @@ type Style struct {
 	tlFormOkStyle        lipgloss.Style
 	tlFormWarnStyle      lipgloss.Style
 	tlFormErrStyle       lipgloss.Style
+	todayTotal           lipgloss.Style
+	weeklyGoalMet        lipgloss.Style
 	toolName             lipgloss.Style
 	tracking             lipgloss.Style
 	viewPort             lipgloss.Style
 }
 
 func NewStyle(theme theme.Theme) Style {
+	theme = theme.WithFullHexColors()
+
 	base := lipgloss.NewStyle().
 		PaddingLeft(1).
 		PaddingRight(1).
@@ -102,8 +106,10 @@ func NewStyle(theme theme.Theme) Style {
 		tlFormOkStyle:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TaskLogFormInfo)),
 		tlFormWarnStyle:      lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TaskLogFormWarn)),
 		tlFormErrStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TaskLogFormError)),
+		todayTotal:           lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color(theme.HelpSecondary)),
 		toolName:             base.Align(lipgloss.Center).Bold(true).Background(lipgloss.Color(theme.ToolName)),
 		tracking:             tracking,
+		weeklyGoalMet:        lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color(theme.ActiveTasks)),
 		viewPort:             lipgloss.NewStyle().PaddingTop(1).PaddingLeft(2).PaddingRight(2).PaddingBottom(1),
 	}
 }
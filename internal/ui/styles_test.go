@@ -3,6 +3,7 @@ package ui
 import (
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dhth/hours/internal/ui/theme"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +36,16 @@ func TestGetDynamicStyleHandlesEmptyTaskList(t *testing.T) {
 	// THEN
 	assert.NotNil(t, got)
 }
+
+func TestNewStyleExpandsShorthandHexColors(t *testing.T) {
+	// GIVEN
+	thm := theme.Default()
+	thm.RecordsHeader = "#d55"
+
+	// WHEN
+	style := NewStyle(thm)
+
+	// THEN
+	assert.Equal(t, "#dd5555", style.theme.RecordsHeader)
+	assert.Equal(t, lipgloss.Color("#dd5555"), style.recordsHeader.GetForeground())
+}
@@ -144,9 +144,9 @@ func (m *Model) handleSyncCompletedMsg(msg syncCompletedMsg) []tea.Cmd {
 	} else {
 		m.syncLastError = ""
 		m.syncLastSuccessAt = msg.attemptedAt
-		cmds = append(cmds, fetchTasks(m.db, true))
-		cmds = append(cmds, fetchTasks(m.db, false))
-		cmds = append(cmds, fetchTLS(m.db, nil))
+		cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
+		cmds = append(cmds, fetchTasks(m.db, false, m.inactiveTasksPage))
+		cmds = append(cmds, m.getCmdToRefreshTLS(nil))
 	}
 
 	if m.syncDirty {
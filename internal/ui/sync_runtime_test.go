@@ -145,10 +145,10 @@ func TestHandleMsgManualTLInsertedSuccessQueuesSyncWhenEnabled(t *testing.T) {
 	}
 
 	cmds := m.handleMsg(manualTLInsertedMsg{taskID: 1})
-	require.Len(t, cmds, 3)
-	require.NotNil(t, cmds[2])
+	require.Len(t, cmds, 4)
+	require.NotNil(t, cmds[3])
 
-	msg := cmds[2]()
+	msg := cmds[3]()
 	_, ok := msg.(syncCompletedMsg)
 	assert.True(t, ok)
 	assert.Equal(t, 1, calls)
@@ -166,10 +166,10 @@ func TestHandleMsgSavedTLEditedSuccessQueuesSyncWhenEnabled(t *testing.T) {
 	}
 
 	cmds := m.handleMsg(savedTLEditedMsg{taskID: 1, tlID: 5})
-	require.Len(t, cmds, 3)
-	require.NotNil(t, cmds[2])
+	require.Len(t, cmds, 4)
+	require.NotNil(t, cmds[3])
 
-	msg := cmds[2]()
+	msg := cmds[3]()
 	_, ok := msg.(syncCompletedMsg)
 	assert.True(t, ok)
 	assert.Equal(t, 1, calls)
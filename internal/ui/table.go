@@ -9,6 +9,8 @@ import (
 	"github.com/olekukonko/tablewriter/tw"
 )
 
+const recordsTruncatedMsg = "results truncated; increase --limit to see all entries"
+
 // newRecordsTable creates a tablewriter.Table with the shared configuration used
 // by the report, log, and stats renderers. headers and footer may be nil.
 func newRecordsTable(b *bytes.Buffer, rs reportStyles, headers []string, footer []string) (*tablewriter.Table, error) {
@@ -70,3 +72,9 @@ func renderRecordsTable(rs reportStyles, headers []string, footer []string, data
 
 	return b.String(), nil
 }
+
+// renderTruncationNotice returns the line shown below a table whose entries
+// were capped by a limit.
+func renderTruncationNotice(rs reportStyles) string {
+	return "\n" + rs.footerStyle.Render(recordsTruncatedMsg) + "\n"
+}
@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"io"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dhth/hours/internal/types"
 )
 
+const selectedTLMarker = "● "
+
 func newItemDelegate(titleColor, descColor, selectedColor lipgloss.Color) list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
 
@@ -26,3 +31,25 @@ func newItemDelegate(titleColor, descColor, selectedColor lipgloss.Color) list.D
 
 	return d
 }
+
+// taskLogDelegate renders task log entries like the default delegate, but
+// marks the ones selected to be moved together.
+type taskLogDelegate struct {
+	list.DefaultDelegate
+	selectedIDs map[int]struct{}
+}
+
+func newTaskLogDelegate(d list.DefaultDelegate, selectedIDs map[int]struct{}) taskLogDelegate {
+	return taskLogDelegate{d, selectedIDs}
+}
+
+func (d taskLogDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if entry, ok := item.(types.TaskLogEntry); ok {
+		if _, selected := d.selectedIDs[entry.ID]; selected {
+			entry.ListTitle = selectedTLMarker + entry.ListTitle
+			item = entry
+		}
+	}
+
+	d.DefaultDelegate.Render(w, m, index, item)
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -135,6 +136,103 @@ func TestHandleRequestToUpdateTask(t *testing.T) {
 	}
 }
 
+func TestGetCmdToCreateOrUpdateTaskRejectsBlankSummary(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setupModel func() Model
+	}{
+		{
+			name: "creating a task",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskListView
+				m.handleRequestToCreateTask()
+				return m
+			},
+		},
+		{
+			name: "updating a task",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskListView
+				task := createTestTask(1, "Task to update", true, false, m.timeProvider)
+				m.taskMap[1] = task
+				m.activeTasksList.SetItems([]list.Item{task})
+				m.activeTasksList.Select(0)
+				m.handleRequestToUpdateTask()
+				return m
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.setupModel()
+			m.taskInputs[summaryField].SetValue("    ")
+
+			cmd := m.getCmdToCreateOrUpdateTask()
+
+			assert.Nil(t, cmd)
+			assert.Equal(t, userMsgErr, m.message.kind)
+			assert.Equal(t, "Task summary cannot be empty", m.message.value)
+			assert.Equal(t, taskInputView, m.activeView)
+			assert.Equal(t, "    ", m.taskInputs[summaryField].Value())
+		})
+	}
+}
+
+func TestGetCmdToCreateOrUpdateTaskEnforcesMaxSummaryLength(t *testing.T) {
+	testCases := []struct {
+		name        string
+		summary     string
+		expectCmd   bool
+		expectedMsg string
+	}{
+		{
+			name:      "summary at the limit",
+			summary:   strings.Repeat("a", 20),
+			expectCmd: true,
+		},
+		{
+			name:        "summary one character over the limit",
+			summary:     strings.Repeat("a", 21),
+			expectedMsg: "Task summary cannot be longer than 20 characters",
+		},
+		{
+			name:      "multibyte summary at the limit",
+			summary:   strings.Repeat("ü", 20),
+			expectCmd: true,
+		},
+		{
+			name:        "multibyte summary over the limit",
+			summary:     strings.Repeat("日", 21),
+			expectedMsg: "Task summary cannot be longer than 20 characters",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := createTestModel()
+			m.maxSummaryLength = 20
+			m.activeView = taskListView
+			m.handleRequestToCreateTask()
+			m.taskInputs[summaryField].SetValue(tt.summary)
+
+			cmd := m.getCmdToCreateOrUpdateTask()
+
+			if tt.expectCmd {
+				assert.NotNil(t, cmd)
+				assert.Equal(t, taskListView, m.activeView)
+			} else {
+				assert.Nil(t, cmd)
+				assert.Equal(t, userMsgErr, m.message.kind)
+				assert.Equal(t, tt.expectedMsg, m.message.value)
+				assert.Equal(t, taskInputView, m.activeView)
+			}
+		})
+	}
+}
+
 func TestHandleRequestToStopTracking(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -328,6 +426,67 @@ func TestGetCmdToQuickSwitchTracking(t *testing.T) {
 
 // T-022: Task log operation tests
 
+func TestHandleRequestToCloneTask(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setupModel func() Model
+		expectCmd  bool
+		expectMsg  string
+	}{
+		{
+			name: "success - clones selected task",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskListView
+				task := createTestTask(1, "Task to clone", true, false, m.timeProvider)
+				m.taskMap[1] = task
+				m.activeTasksList.SetItems([]list.Item{task})
+				m.activeTasksList.Select(0)
+				return m
+			},
+			expectCmd: true,
+		},
+		{
+			name: "filtered list shows error message",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskListView
+				m.activeTasksList.SetFilterText("filter")
+				return m
+			},
+			expectCmd: false,
+			expectMsg: removeFilterMsg,
+		},
+		{
+			name: "no task selected shows error",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskListView
+				return m
+			},
+			expectCmd: false,
+			expectMsg: genericErrorMsg,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.setupModel()
+			cmd := m.handleRequestToCloneTask()
+
+			if tt.expectCmd {
+				assert.NotNil(t, cmd)
+			} else {
+				assert.Nil(t, cmd)
+			}
+			assert.Equal(t, taskListView, m.activeView)
+			if tt.expectMsg != "" {
+				assert.Equal(t, tt.expectMsg, m.message.value)
+			}
+		})
+	}
+}
+
 func TestGetCmdToDeactivateTask(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -507,6 +666,67 @@ func TestGetCmdToDeleteTL(t *testing.T) {
 	}
 }
 
+func TestGetCmdToDuplicateTL(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setupModel func() Model
+		expectCmd  bool
+		expectMsg  string
+	}{
+		{
+			name: "success - duplicates task log",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				entry := createTestTaskLogEntry(1, 1, "Standup", m.timeProvider)
+				m.taskLogList.SetItems([]list.Item{*entry})
+				m.taskLogList.Select(0)
+				return m
+			},
+			expectCmd: true,
+		},
+		{
+			name: "filtered list shows error",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				entry := createTestTaskLogEntry(1, 1, "Standup", m.timeProvider)
+				m.taskLogList.SetItems([]list.Item{*entry})
+				m.taskLogList.SetFilterText("filter")
+				return m
+			},
+			expectCmd: false,
+			expectMsg: removeFilterMsg,
+		},
+		{
+			name: "no entry selected shows error",
+			setupModel: func() Model {
+				m := createTestModel()
+				m.activeView = taskLogView
+				return m
+			},
+			expectCmd: false,
+			expectMsg: "No task log entry selected",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.setupModel()
+			cmd := m.getCmdToDuplicateTL()
+
+			if tt.expectCmd {
+				assert.NotNil(t, cmd)
+			} else {
+				assert.Nil(t, cmd)
+			}
+			if tt.expectMsg != "" {
+				assert.Equal(t, tt.expectMsg, m.message.value)
+			}
+		})
+	}
+}
+
 func TestHandleRequestToMoveTaskLog(t *testing.T) {
 	testCases := []struct {
 		name         string
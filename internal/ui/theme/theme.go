@@ -7,9 +7,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
+
+	"github.com/dhth/hours/internal/types"
 )
 
 const (
@@ -28,8 +28,6 @@ var (
 	ErrBuiltInThemeDoesntExist    = errors.New("built-in theme doesn't exist")
 )
 
-var hexCodeRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
-
 type Theme struct {
 	ActiveTask              string   `json:"activeTask,omitempty"`
 	ActiveTaskBeginTime     string   `json:"activeTaskBeginTime,omitempty"`
@@ -108,6 +106,12 @@ func Default() Theme {
 	return getBuiltInTheme(paletteGruvboxDark())
 }
 
+// DefaultBuiltIn returns the name of the built-in theme that Default is based
+// on.
+func DefaultBuiltIn() string {
+	return themeNameGruvboxDark
+}
+
 func BuiltIn() []string {
 	return []string{
 		themeNameCatppuccinMocha,
@@ -121,7 +125,25 @@ func BuiltIn() []string {
 	}
 }
 
-func loadCustom(themeJSON []byte) (Theme, error) {
+// Export returns the JSON representation of a theme, in the format expected of
+// custom theme files.
+func Export(t Theme) ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// InvalidColors returns the names of the fields (eg, "helpMsg", or "tasks[2]")
+// in the contents of a custom theme file that don't hold a valid color. It
+// returns an error if the contents can't be parsed as a theme at all.
+func InvalidColors(themeJSON []byte) ([]string, error) {
+	thm, err := decodeCustom(themeJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return getInvalidColors(thm), nil
+}
+
+func decodeCustom(themeJSON []byte) (Theme, error) {
 	thm := Default()
 	err := json.Unmarshal(themeJSON, &thm)
 	var syntaxError *json.SyntaxError
@@ -133,6 +155,15 @@ func loadCustom(themeJSON []byte) (Theme, error) {
 		return thm, fmt.Errorf("%w: %s", ErrThemeFileHasInvalidSchema, err.Error())
 	}
 
+	return thm, nil
+}
+
+func loadCustom(themeJSON []byte) (Theme, error) {
+	thm, err := decodeCustom(themeJSON)
+	if err != nil {
+		return thm, err
+	}
+
 	invalidColors := getInvalidColors(thm)
 	if len(invalidColors) > 0 {
 		return thm, fmt.Errorf("%w: %q", ErrThemeColorsAreInvalid, invalidColors)
@@ -175,52 +206,88 @@ type themeColorField struct {
 	value string
 }
 
+// themeColorFieldRef is like themeColorField, but points to the color, so
+// that it can be changed in place.
+type themeColorFieldRef struct {
+	name string
+	ptr  *string
+}
+
+// scalarColorFieldRefs returns references to all non-slice color fields of t
+// together with their JSON field names, in declaration order.
+func scalarColorFieldRefs(t *Theme) []themeColorFieldRef {
+	return []themeColorFieldRef{
+		{name: "activeTask", ptr: &t.ActiveTask},
+		{name: "activeTaskBeginTime", ptr: &t.ActiveTaskBeginTime},
+		{name: "activeTasks", ptr: &t.ActiveTasks},
+		{name: "formContext", ptr: &t.FormContext},
+		{name: "formFieldName", ptr: &t.FormFieldName},
+		{name: "formHelp", ptr: &t.FormHelp},
+		{name: "helpMsg", ptr: &t.HelpMsg},
+		{name: "helpPrimary", ptr: &t.HelpPrimary},
+		{name: "helpSecondary", ptr: &t.HelpSecondary},
+		{name: "inactiveTasks", ptr: &t.InactiveTasks},
+		{name: "initialHelpMsg", ptr: &t.InitialHelpMsg},
+		{name: "listItemDesc", ptr: &t.ListItemDesc},
+		{name: "listItemTitle", ptr: &t.ListItemTitle},
+		{name: "recordsBorder", ptr: &t.RecordsBorder},
+		{name: "recordsDateRange", ptr: &t.RecordsDateRange},
+		{name: "recordsFooter", ptr: &t.RecordsFooter},
+		{name: "recordsHeader", ptr: &t.RecordsHeader},
+		{name: "recordsHelp", ptr: &t.RecordsHelp},
+		{name: "taskEntry", ptr: &t.TaskEntry},
+		{name: "taskLogDetails", ptr: &t.TaskLogDetailsViewTitle},
+		{name: "taskLogEntry", ptr: &t.TaskLogEntry},
+		{name: "taskLogFormError", ptr: &t.TaskLogFormError},
+		{name: "taskLogFormInfo", ptr: &t.TaskLogFormInfo},
+		{name: "taskLogFormWarn", ptr: &t.TaskLogFormWarn},
+		{name: "taskLogList", ptr: &t.TaskLogList},
+		{name: "titleForeground", ptr: &t.TitleForeground},
+		{name: "toolName", ptr: &t.ToolName},
+		{name: "tracking", ptr: &t.Tracking},
+	}
+}
+
 // scalarColorFields returns the list of all non-slice color fields together
 // with their JSON field names, in declaration order.
 func scalarColorFields(t Theme) []themeColorField {
-	return []themeColorField{
-		{name: "activeTask", value: t.ActiveTask},
-		{name: "activeTaskBeginTime", value: t.ActiveTaskBeginTime},
-		{name: "activeTasks", value: t.ActiveTasks},
-		{name: "formContext", value: t.FormContext},
-		{name: "formFieldName", value: t.FormFieldName},
-		{name: "formHelp", value: t.FormHelp},
-		{name: "helpMsg", value: t.HelpMsg},
-		{name: "helpPrimary", value: t.HelpPrimary},
-		{name: "helpSecondary", value: t.HelpSecondary},
-		{name: "inactiveTasks", value: t.InactiveTasks},
-		{name: "initialHelpMsg", value: t.InitialHelpMsg},
-		{name: "listItemDesc", value: t.ListItemDesc},
-		{name: "listItemTitle", value: t.ListItemTitle},
-		{name: "recordsBorder", value: t.RecordsBorder},
-		{name: "recordsDateRange", value: t.RecordsDateRange},
-		{name: "recordsFooter", value: t.RecordsFooter},
-		{name: "recordsHeader", value: t.RecordsHeader},
-		{name: "recordsHelp", value: t.RecordsHelp},
-		{name: "taskEntry", value: t.TaskEntry},
-		{name: "taskLogDetails", value: t.TaskLogDetailsViewTitle},
-		{name: "taskLogEntry", value: t.TaskLogEntry},
-		{name: "taskLogFormError", value: t.TaskLogFormError},
-		{name: "taskLogFormInfo", value: t.TaskLogFormInfo},
-		{name: "taskLogFormWarn", value: t.TaskLogFormWarn},
-		{name: "taskLogList", value: t.TaskLogList},
-		{name: "titleForeground", value: t.TitleForeground},
-		{name: "toolName", value: t.ToolName},
-		{name: "tracking", value: t.Tracking},
+	refs := scalarColorFieldRefs(&t)
+	fields := make([]themeColorField, len(refs))
+	for i, ref := range refs {
+		fields[i] = themeColorField{name: ref.name, value: *ref.ptr}
 	}
+
+	return fields
+}
+
+// WithFullHexColors returns a copy of the theme where shorthand hex colors
+// (eg, "#fa0") are expanded to their full form (eg, "#ffaa00"), and CSS color
+// names are replaced by their hex codes.
+func (t Theme) WithFullHexColors() Theme {
+	for _, ref := range scalarColorFieldRefs(&t) {
+		*ref.ptr = resolveColor(*ref.ptr)
+	}
+
+	tasks := make([]string, len(t.Tasks))
+	for i, color := range t.Tasks {
+		tasks[i] = resolveColor(color)
+	}
+	t.Tasks = tasks
+
+	return t
 }
 
 func getInvalidColors(theme Theme) []string {
 	var invalidColors []string
 
 	for _, field := range scalarColorFields(theme) {
-		if !isValidColor(field.value) {
+		if !isValidThemeColor(field.value) {
 			invalidColors = append(invalidColors, field.name)
 		}
 	}
 
 	for i, color := range theme.Tasks {
-		if !isValidColor(color) {
+		if !isValidThemeColor(color) {
 			invalidColors = append(invalidColors, fmt.Sprintf("tasks[%d]", i+1))
 		}
 	}
@@ -228,23 +295,39 @@ func getInvalidColors(theme Theme) []string {
 	return invalidColors
 }
 
-func isValidColor(s string) bool {
-	if len(s) == 0 {
-		return false
+// isValidThemeColor reports whether s can be used as a color in a theme; on
+// top of what IsValidColor allows, themes can use CSS color names (eg,
+// "cornflowerblue"; case-insensitive).
+func isValidThemeColor(s string) bool {
+	if _, ok := cssColors[strings.ToLower(s)]; ok {
+		return true
 	}
 
-	if strings.HasPrefix(s, "#") {
-		return hexCodeRegex.MatchString(s)
-	}
+	return IsValidColor(s)
+}
 
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return false
+// IsValidColor reports whether s is a valid hex color code (eg, "#ff0000" or
+// "#f00") or an ANSI 256 color code (eg, "208").
+func IsValidColor(s string) bool {
+	return types.IsValidColor(s)
+}
+
+// resolveColor returns the full hex code for a CSS color name or a shorthand
+// hex color code. Any other value is returned as is.
+func resolveColor(s string) string {
+	if hex, ok := cssColors[strings.ToLower(s)]; ok {
+		return hex
 	}
 
-	if i < 0 || i > 255 {
-		return false
+	return ExpandHexColor(s)
+}
+
+// ExpandHexColor expands a shorthand hex color code (eg, "#f00") to its full
+// form (eg, "#ff0000"). Any other value is returned as is.
+func ExpandHexColor(s string) string {
+	if len(s) != 4 || !strings.HasPrefix(s, "#") || !types.IsValidColor(s) {
+		return s
 	}
 
-	return true
+	return string([]byte{'#', s[1], s[1], s[2], s[2], s[3], s[3]})
 }
@@ -132,3 +132,46 @@ func TestLoadCustomHandlesFailuresCorrectly(t *testing.T) {
 		})
 	}
 }
+
+func TestExportCanBeLoadedAgain(t *testing.T) {
+	for _, name := range BuiltIn() {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			thm, err := Get(name, t.TempDir())
+			require.NoError(t, err)
+
+			// WHEN
+			themeBytes, err := Export(thm)
+			require.NoError(t, err)
+			loaded, err := loadCustom(themeBytes)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Empty(t, getInvalidColors(loaded))
+			assert.Equal(t, thm, loaded)
+			assert.Contains(t, string(themeBytes), `"tasks": [`)
+		})
+	}
+}
+
+func TestInvalidColors(t *testing.T) {
+	t.Run("returns the fields with invalid colors", func(t *testing.T) {
+		got, err := InvalidColors(invalidThemeInvalidData)
+
+		require.NoError(t, err)
+		assert.Len(t, got, 5)
+	})
+
+	t.Run("returns nothing for a valid theme", func(t *testing.T) {
+		got, err := InvalidColors(validThemeWithEntireConfig)
+
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("fails for malformed json", func(t *testing.T) {
+		_, err := InvalidColors(invalidThemeMalformedJSON)
+
+		assert.ErrorIs(t, err, errThemeFileIsInvalidJSON)
+	})
+}
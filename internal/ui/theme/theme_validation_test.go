@@ -311,3 +311,118 @@ func TestGetInvalidColorsTaskFields(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidColor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "shorthand hex", input: "#abc", expected: true},
+		{name: "uppercase shorthand hex", input: "#ABC", expected: true},
+		{name: "full hex", input: "#abcdef", expected: true},
+		{name: "uppercase full hex", input: "#ABCDEF", expected: true},
+		{name: "two digit hex", input: "#ab", expected: false},
+		{name: "four digit hex", input: "#abcd", expected: false},
+		{name: "non hex digits", input: "#xyz", expected: false},
+		{name: "hex without hash", input: "abc", expected: false},
+		{name: "lowest terminal index", input: "0", expected: true},
+		{name: "highest terminal index", input: "255", expected: true},
+		{name: "out-of-range terminal index", input: "256", expected: false},
+		{name: "empty", input: "", expected: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidColor(tt.input))
+		})
+	}
+}
+
+func TestExpandHexColor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "shorthand hex", input: "#abc", expected: "#aabbcc"},
+		{name: "uppercase shorthand hex", input: "#F0A", expected: "#FF00AA"},
+		{name: "full hex is left as is", input: "#ABCDEF", expected: "#ABCDEF"},
+		{name: "invalid hex is left as is", input: "#ab", expected: "#ab"},
+		{name: "terminal index is left as is", input: "208", expected: "208"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExpandHexColor(tt.input))
+		})
+	}
+}
+
+func TestWithFullHexColors(t *testing.T) {
+	// GIVEN
+	thm := Default()
+	thm.ActiveTask = "#f80"
+	thm.Tracking = "208"
+	thm.Tasks = []string{"#abc", "#123456"}
+
+	// WHEN
+	got := thm.WithFullHexColors()
+
+	// THEN
+	assert.Equal(t, "#ff8800", got.ActiveTask)
+	assert.Equal(t, "208", got.Tracking)
+	assert.Equal(t, []string{"#aabbcc", "#123456"}, got.Tasks)
+	assert.Equal(t, "#f80", thm.ActiveTask)
+	assert.Equal(t, []string{"#abc", "#123456"}, thm.Tasks)
+}
+
+func TestIsValidThemeColor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "css color name", input: "cornflowerblue", expected: true},
+		{name: "css color name in mixed case", input: "CornflowerBlue", expected: true},
+		{name: "unknown color name", input: "notacolor", expected: false},
+		{name: "hex", input: "#abc", expected: true},
+		{name: "terminal index", input: "208", expected: true},
+		{name: "empty", input: "", expected: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isValidThemeColor(tt.input))
+		})
+	}
+}
+
+func TestGetInvalidColorsColorNames(t *testing.T) {
+	// GIVEN
+	thm := Default()
+	thm.ActiveTask = "Red"
+	thm.Tracking = "cornflowerblue"
+	thm.HelpMsg = "notacolor"
+	thm.Tasks = []string{"tomato", "reddish"}
+
+	// WHEN
+	got := getInvalidColors(thm)
+
+	// THEN
+	assert.Equal(t, []string{"helpMsg", "tasks[2]"}, got)
+}
+
+func TestWithFullHexColorsResolvesColorNames(t *testing.T) {
+	// GIVEN
+	thm := Default()
+	thm.ActiveTask = "Red"
+	thm.Tasks = []string{"cornflowerblue"}
+
+	// WHEN
+	got := thm.WithFullHexColors()
+
+	// THEN
+	assert.Equal(t, "#ff0000", got.ActiveTask)
+	assert.Equal(t, []string{"#6495ed"}, got.Tasks)
+}
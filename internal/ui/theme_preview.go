@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	themePreviewListWidth  = 100
+	themePreviewListHeight = 13
+)
+
+// RenderThemePreview renders a sample of what hours looks like with style: a
+// task list, the line shown while tracking, and a stats table. The data shown
+// is made up, and timestamped relative to timeProvider's current time.
+func RenderThemePreview(style Style, timeProvider types.TimeProvider) (string, error) {
+	now := timeProvider.Now()
+
+	tasks := []types.Task{
+		{ID: 1, Summary: "Write the quarterly report", SecsSpent: 5*60*60 + 30*60, UpdatedAt: now.Add(-10 * time.Minute), TrackingActive: true},
+		{ID: 2, Summary: "Review pull requests", SecsSpent: 2 * 60 * 60, UpdatedAt: now.Add(-3 * time.Hour)},
+		{ID: 3, Summary: "Plan the team offsite", SecsSpent: 45 * 60, UpdatedAt: now.Add(-26 * time.Hour)},
+	}
+
+	items := make([]list.Item, len(tasks))
+	for i := range tasks {
+		tasks[i].UpdateListTitle()
+		tasks[i].UpdateListDesc(timeProvider, types.DurationFormatFull)
+		items[i] = tasks[i]
+	}
+
+	taskList := list.New(items,
+		newItemDelegate(style.listItemTitleColor,
+			style.listItemDescColor,
+			lipgloss.Color(style.theme.ActiveTasks),
+		), themePreviewListWidth, themePreviewListHeight)
+	setupList(&taskList, "Tasks", "task", "tasks", lipgloss.Color(style.theme.ActiveTasks), style.titleForegroundColor, true)
+
+	trackingLine := fmt.Sprintf("%s%s%s",
+		style.tracking.Render("tracking:"),
+		style.activeTaskSummaryMsg.Render(tasks[0].Summary),
+		style.activeTaskBeginTime.Render(fmt.Sprintf("(since %s)", now.Add(-10*time.Minute).Format(timeOnlyFormat))),
+	)
+
+	statsEntries := make([]types.TaskReportEntry, len(tasks))
+	for i, task := range tasks {
+		statsEntries[i] = types.TaskReportEntry{
+			TaskID:      task.ID,
+			TaskSummary: task.Summary,
+			NumEntries:  len(tasks) - i,
+			SecsSpent:   task.SecsSpent,
+		}
+	}
+
+	statsTable, err := renderStatsTable(style, statsEntries, len(statsEntries), false, false)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(style.toolName.Render("hours"))
+	b.WriteString("\n\n")
+	b.WriteString(style.list.Render(taskList.View()))
+	b.WriteString("\n")
+	b.WriteString(trackingLine)
+	b.WriteString("\n\n")
+	b.WriteString(statsTable)
+
+	return b.String(), nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dhth/hours/internal/session"
@@ -18,16 +19,27 @@ var (
 	errCouldnCreateFramesDir      = errors.New("couldn't create frames directory")
 )
 
-func RenderUI(
-	db *sql.DB,
-	style Style,
-	timeProvider types.TimeProvider,
-	syncConfig SyncConfig,
-	syncConfigStatusErr string,
-	syncConfigPath string,
-	saveSyncConfig func(SyncConfig) error,
-	runSync syncRunFunc,
-) error {
+// Options holds the settings the TUI is started with.
+type Options struct {
+	TimeProvider        types.TimeProvider
+	SyncConfig          SyncConfig
+	SyncConfigStatusErr string
+	SyncConfigPath      string
+	SaveSyncConfig      func(SyncConfig) error
+	RunSync             func(context.Context, *sql.DB, string) error
+	DailyMax            time.Duration
+	IdleThreshold       time.Duration
+	Pomodoro            PomodoroConfig
+	TrackingReminder    time.Duration
+	MergeSameDay        bool
+	ShiftStep           time.Duration
+	RoundTo             time.Duration
+	MaxSummaryLength    int
+	ThemeName           string
+	ThemesDir           string
+}
+
+func RenderUI(db *sql.DB, style Style, opts Options) error {
 	if len(os.Getenv("DEBUG")) > 0 {
 		f, err := tea.LogToFile("debug.log", "debug")
 		if err != nil {
@@ -42,7 +54,7 @@ func RenderUI(
 		log: logFrames,
 	}
 	if logFrames {
-		framesDir := filepath.Join(".frames", fmt.Sprintf("%d", timeProvider.Now().Unix()))
+		framesDir := filepath.Join(".frames", fmt.Sprintf("%d", opts.TimeProvider.Now().Unix()))
 		err := os.MkdirAll(framesDir, 0o755)
 		if err != nil {
 			return fmt.Errorf("%w: %s", errCouldnCreateFramesDir, err.Error())
@@ -61,16 +73,26 @@ func RenderUI(
 	model := InitialModel(
 		db,
 		style,
-		timeProvider,
+		opts.TimeProvider,
 		debug,
 		logFramesCfg,
 		sessionMonitor,
-		syncConfig,
-		syncConfigStatusErr,
-		syncConfigPath,
-		saveSyncConfig,
+		opts.SyncConfig,
+		opts.SyncConfigStatusErr,
+		opts.SyncConfigPath,
+		opts.SaveSyncConfig,
 	)
-	model.runSync = runSync
+	model.runSync = opts.RunSync
+	model.dailyMax = opts.DailyMax
+	model.idleThreshold = opts.IdleThreshold
+	model.pomodoro = opts.Pomodoro
+	model.trackingReminder = opts.TrackingReminder
+	model.mergeSameDay = opts.MergeSameDay
+	model.shiftStep = opts.ShiftStep
+	model.roundTo = opts.RoundTo
+	model.maxSummaryLength = opts.MaxSummaryLength
+	model.themeName = opts.ThemeName
+	model.themesDir = opts.ThemesDir
 	p := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
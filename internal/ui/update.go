@@ -51,6 +51,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		m.recordInteraction(keyMsg)
+
 		// Delegate filter key handling to the appropriate list when filtering.
 		if exitEarly, exitCmds := m.handleFilteringKeys(keyMsg); exitEarly {
 			return m, tea.Batch(exitCmds...)
@@ -101,15 +103,28 @@ func (m *Model) handleFilteringKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []t
 
 // handleFormKeys handles key events that are only meaningful while a form view
 // is active: enter/ctrl+s (submit), esc (cancel), tab/shift+tab (field
-// navigation), and j/k/J/K/h/l (time-shifting).  Returns exitEarly=true when
+// navigation), j/k/J/K/p/n/h/l/w/W (time-shifting), and ctrl+l (avoiding overlaps).  Returns exitEarly=true when
 // the caller should return immediately after processing.
 func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cmd) {
 	switch keyMsg.String() {
 	case enter, "ctrl+s":
+		if m.activeView == commandPaletteView {
+			if runCmd := m.getCmdToRunPaletteCommand(); runCmd != nil {
+				return true, []tea.Cmd{runCmd}
+			}
+			return true, nil
+		}
+		if m.activeView == searchTaskLogsView {
+			if keyMsg.String() == enter {
+				return true, []tea.Cmd{m.getCmdToSearchTLsByComment()}
+			}
+			return false, nil
+		}
+
 		var bail bool
 		if keyMsg.String() == enter {
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 				if m.trackingFocussedField == entryComment {
 					bail = true
 				}
@@ -126,6 +141,8 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 			updateCmd = m.getCmdToCreateOrUpdateTask()
 		case editActiveTLView:
 			updateCmd = m.getCmdToUpdateActiveTL()
+		case startTrackingView:
+			updateCmd = m.getCmdToStartTrackingWithComment()
 		case finishActiveTLView:
 			updateCmd = m.getCmdToFinishTrackingActiveTL()
 		case manualTasklogEntryView, editSavedTLView:
@@ -134,6 +151,10 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 			if keyMsg.String() == enter {
 				updateCmd = m.handleTargetTaskSelection()
 			}
+		case filterTaskLogView:
+			if keyMsg.String() == enter {
+				updateCmd = m.handleTaskLogFilterSelection()
+			}
 		}
 		if updateCmd != nil {
 			return true, []tea.Cmd{updateCmd}
@@ -141,12 +162,30 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case escape:
 		switch m.activeView {
-		case taskInputView, editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, moveTaskLogView:
+		case taskInputView, editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, moveTaskLogView, filterTaskLogView:
 			m.handleEscapeInForms()
 			return true, nil
+		case commandPaletteView:
+			m.closeCommandPalette()
+			return true, nil
+		case searchTaskLogsView:
+			m.closeTLSearch()
+			return true, nil
+		}
+
+	case "ctrl+l":
+		if m.activeView == finishActiveTLView {
+			if clampCmd := m.getCmdToClampEndTSToNextTL(); clampCmd != nil {
+				return true, []tea.Cmd{clampCmd}
+			}
+			return true, nil
 		}
 
 	case "tab":
+		if m.activeView == commandPaletteView {
+			m.completePaletteInput()
+			return true, nil
+		}
 		m.goForwardInView()
 
 	case "shift+tab":
@@ -154,7 +193,7 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case "k":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftBackward, types.ShiftMinute); err != nil {
 				return true, nil
 			}
@@ -162,7 +201,7 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case "j":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftForward, types.ShiftMinute); err != nil {
 				return true, nil
 			}
@@ -170,7 +209,7 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case "K":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftBackward, types.ShiftFiveMinutes); err != nil {
 				return true, nil
 			}
@@ -178,15 +217,31 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case "J":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftForward, types.ShiftFiveMinutes); err != nil {
 				return true, nil
 			}
 		}
 
+	case "p":
+		switch m.activeView {
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			if err := m.shiftTime(types.ShiftBackward, types.ShiftHour); err != nil {
+				return true, nil
+			}
+		}
+
+	case "n":
+		switch m.activeView {
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			if err := m.shiftTime(types.ShiftForward, types.ShiftHour); err != nil {
+				return true, nil
+			}
+		}
+
 	case "h":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftBackward, types.ShiftDay); err != nil {
 				return true, nil
 			}
@@ -197,7 +252,7 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 
 	case "l":
 		switch m.activeView {
-		case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 			if err := m.shiftTime(types.ShiftForward, types.ShiftDay); err != nil {
 				return true, nil
 			}
@@ -205,6 +260,22 @@ func (m *Model) handleFormKeys(keyMsg tea.KeyMsg) (exitEarly bool, cmds []tea.Cm
 			m.taskLogList.CursorDown()
 			m.handleRequestToViewTLDetails()
 		}
+
+	case "W":
+		switch m.activeView {
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			if err := m.shiftTime(types.ShiftBackward, types.ShiftWeek); err != nil {
+				return true, nil
+			}
+		}
+
+	case "w":
+		switch m.activeView {
+		case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			if err := m.shiftTime(types.ShiftForward, types.ShiftWeek); err != nil {
+				return true, nil
+			}
+		}
 	}
 
 	return false, nil
@@ -231,7 +302,7 @@ func (m *Model) updateInputComponents(msg tea.Msg) (cmds []tea.Cmd, handled bool
 			cmds = append(cmds, cmd)
 		}
 		return cmds, true
-	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+	case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 		for i := range m.tLInputs {
 			m.tLInputs[i], cmd = m.tLInputs[i].Update(msg)
 			cmds = append(cmds, cmd)
@@ -239,6 +310,12 @@ func (m *Model) updateInputComponents(msg tea.Msg) (cmds []tea.Cmd, handled bool
 		m.tLCommentInput, cmd = m.tLCommentInput.Update(msg)
 		cmds = append(cmds, cmd)
 		return cmds, true
+	case commandPaletteView:
+		m.commandPaletteInput, cmd = m.commandPaletteInput.Update(msg)
+		return []tea.Cmd{cmd}, true
+	case searchTaskLogsView:
+		m.tLSearchInput, cmd = m.tLSearchInput.Update(msg)
+		return []tea.Cmd{cmd}, true
 	}
 	return nil, false
 }
@@ -246,23 +323,34 @@ func (m *Model) updateInputComponents(msg tea.Msg) (cmds []tea.Cmd, handled bool
 // handleListKeys handles key events that operate on lists and views (navigation
 // shortcuts, task/log actions, viewport scrolling, help).
 func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
+	if m.activeView == confirmDeleteTLView {
+		return m.handleConfirmDeleteTLKeys(keyMsg)
+	}
+	if m.activeView == confirmDeleteTaskTLsView {
+		return m.handleConfirmDeleteTaskTLsKeys(keyMsg)
+	}
+
 	var cmds []tea.Cmd
 	switch keyMsg.String() {
 	case "q", escape:
+		if m.activeView == taskLogView && m.taskLogCommentQuery != "" {
+			cmds = append(cmds, m.getCmdToClearTLCommentSearch())
+			break
+		}
 		if m.handleRequestToGoBackOrQuit() {
 			return []tea.Cmd{tea.Quit}
 		}
 	case "1":
 		if m.activeView != taskListView {
-			m.activeView = taskListView
+			m.switchToListView(taskListView)
 		}
 	case "2":
 		if m.activeView != taskLogView {
-			m.activeView = taskLogView
+			m.switchToListView(taskLogView)
 		}
 	case "3":
 		if m.activeView != inactiveTaskListView {
-			m.activeView = inactiveTaskListView
+			m.switchToListView(inactiveTaskListView)
 		}
 	case "ctrl+r":
 		if reloadCmd := m.getCmdToReloadData(); reloadCmd != nil {
@@ -270,6 +358,10 @@ func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
 		}
 	case "ctrl+t":
 		m.goToActiveTask()
+	case "ctrl+l":
+		if reloadCmd := m.getCmdToReloadTheme(); reloadCmd != nil {
+			cmds = append(cmds, reloadCmd)
+		}
 	case "f":
 		if m.activeView != taskListView {
 			break
@@ -308,13 +400,33 @@ func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
 		case taskListView:
 			handleCmd = m.getCmdToDeactivateTask()
 		case taskLogView:
-			handleCmd = m.getCmdToDeleteTL()
+			m.handleRequestToDeleteTL()
 		case inactiveTaskListView:
 			handleCmd = m.getCmdToActivateDeactivatedTask()
 		}
 		if handleCmd != nil {
 			cmds = append(cmds, handleCmd)
 		}
+	case "X":
+		if m.activeView == taskListView {
+			m.handleRequestToDeleteTaskTLs()
+		}
+	case "]", "[":
+		if m.activeView == taskListView {
+			if cmd := m.getCmdToCycleActiveTLComment(keyMsg.String() == "]"); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case "K", "J":
+		if m.activeView == taskListView {
+			direction := types.ShiftBackward
+			if keyMsg.String() == "J" {
+				direction = types.ShiftForward
+			}
+			if cmd := m.getCmdToNudgeActiveTLBeginTS(direction); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
 	case "ctrl+x":
 		if m.activeView == taskListView && m.trackingActive {
 			cmds = append(cmds, deleteActiveTL(m.db))
@@ -330,6 +442,15 @@ func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
 				m.handleRequestToStopTracking()
 			}
 		}
+	case "C":
+		if m.activeView != taskListView {
+			break
+		}
+		if m.trackingActive {
+			m.message = errMsg("A task is already being tracked; stop it first")
+			break
+		}
+		m.handleRequestToStartTrackingWithComment()
 	case "S":
 		if m.activeView != taskListView {
 			break
@@ -341,9 +462,23 @@ func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
 		if m.activeView == taskListView {
 			m.handleRequestToCreateTask()
 		}
+	case "D":
+		var handleCmd tea.Cmd
+		switch m.activeView {
+		case taskListView:
+			handleCmd = m.handleRequestToCloneTask()
+		case taskLogView:
+			handleCmd = m.getCmdToDuplicateTL()
+		}
+		if handleCmd != nil {
+			cmds = append(cmds, handleCmd)
+		}
 	case "c":
-		if m.activeView == taskListView || m.activeView == inactiveTaskListView {
+		switch m.activeView {
+		case taskListView, inactiveTaskListView:
 			m.handleCopyTaskSummary()
+		case taskLogView:
+			m.handleCopyTaskLogComment()
 		}
 	case "k":
 		m.handleRequestToScrollVPUp()
@@ -353,17 +488,74 @@ func (m *Model) handleListKeys(keyMsg tea.KeyMsg) []tea.Cmd {
 		if m.activeView == taskLogView {
 			m.handleRequestToViewTLDetails()
 		}
+	case " ":
+		if m.activeView == taskLogView {
+			m.handleRequestToToggleTLSelection()
+		}
 	case "m":
 		if m.activeView == taskLogView {
 			if cmd := m.handleRequestToMoveTaskLog(); cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 		}
+	case "t":
+		if m.activeView == taskLogView {
+			if cmd := m.handleRequestToFilterTaskLogByTask(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case "T":
+		if m.activeView == taskLogView {
+			m.goToTodaysTLs()
+		}
+	case "/":
+		if m.activeView == taskLogView {
+			m.handleRequestToSearchTLsByComment()
+		}
+	case "w":
+		if m.activeView == taskListView {
+			cmds = append(cmds, fetchWeeklyTotals(m.db, m.style, m.timeProvider.Now()))
+		}
 	case "A":
 		if m.activeView == taskListView {
 			twoWeeksAgo := m.timeProvider.Now().AddDate(0, 0, -14)
 			cmds = append(cmds, archiveStaleTasks(m.db, twoWeeksAgo))
 		}
+	case "R":
+		if m.activeView == taskListView {
+			cmds = append(cmds, restoreLastArchivedTask(m.db))
+		}
+	case "o":
+		m.handleRequestToCycleTaskSortOrder()
+	case "z":
+		m.handleRequestToToggleDurationFormat()
+	case "e":
+		m.handleRequestToToggleActiveTLElapsed()
+	case "W":
+		m.handleRequestToToggleWeekTotalInTitle()
+	case "n", "p":
+		if cmd := m.getCmdToChangeTasksPage(keyMsg.String() == "n"); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case "ctrl+z":
+		switch m.activeView {
+		case taskListView, taskLogView, inactiveTaskListView:
+			if cmd := m.getCmdToUndoLastTLAction(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case trimIdleTimeKey:
+		switch m.activeView {
+		case taskListView, taskLogView, inactiveTaskListView:
+			if cmd := m.getCmdToTrimActiveTLToIdleStart(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case ":":
+		switch m.activeView {
+		case taskListView, taskLogView, inactiveTaskListView:
+			m.handleRequestToOpenCommandPalette()
+		}
 	case "?":
 		m.lastView = m.activeView
 		m.activeView = helpView
@@ -379,18 +571,39 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 		if msg.err != nil {
 			m.message = errMsg(fmt.Sprintf("Error creating task: %s", msg.err))
 		} else {
-			cmds = append(cmds, fetchTasks(m.db, true))
+			cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
 			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 				cmds = append(cmds, syncCmd)
 			}
 		}
+	case themeReloadedMsg:
+		if msg.err != nil {
+			m.message = errMsg(fmt.Sprintf("Couldn't reload theme: %s", msg.err))
+		} else {
+			m.applyStyle(msg.style)
+			m.message = infoMsg("Theme reloaded")
+		}
 	case staleTasksArchivedMsg:
 		if msg.err != nil {
 			m.message = errMsg(fmt.Sprintf("Error archiving tasks: %s", msg.err))
 		} else {
 			m.message = infoMsg(fmt.Sprintf("Archived %d tasks", msg.count))
-			cmds = append(cmds, fetchTasks(m.db, true))
-			cmds = append(cmds, fetchTasks(m.db, false))
+			cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
+			cmds = append(cmds, fetchTasks(m.db, false, m.inactiveTasksPage))
+			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
+				cmds = append(cmds, syncCmd)
+			}
+		}
+	case lastArchivedTaskRestoredMsg:
+		switch {
+		case msg.err != nil:
+			m.message = errMsg(fmt.Sprintf("Error restoring task: %s", msg.err))
+		case msg.task == nil:
+			m.message = infoMsg("There are no archived tasks to restore")
+		default:
+			m.message = infoMsg(fmt.Sprintf("Restored task: %s", msg.task.Summary))
+			cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
+			cmds = append(cmds, fetchTasks(m.db, false, m.inactiveTasksPage))
 			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 				cmds = append(cmds, syncCmd)
 			}
@@ -400,6 +613,9 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 			m.message = errMsg(fmt.Sprintf("Error updating task: %s", msg.err))
 		} else {
 			msg.tsk.Summary = msg.summary
+			if msg.createdAt != nil {
+				msg.tsk.CreatedAt = *msg.createdAt
+			}
 			msg.tsk.UpdateListTitle()
 			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 				cmds = append(cmds, syncCmd)
@@ -419,6 +635,10 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 				cmds = append(cmds, syncCmd)
 			}
 		}
+	case recentCommentsFetchedMsg:
+		if cmd := m.handleRecentCommentsFetchedMsg(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	case manualTLInsertedMsg:
 		if handleCmds := m.handleManualTLInsertedMsg(msg); handleCmds != nil {
 			cmds = append(cmds, handleCmds...)
@@ -433,6 +653,8 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 		if cmd := m.handleActiveTaskFetchedMsg(msg); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case trackedTaskFetchedMsg:
+		m.handleTrackedTaskFetchedMsg(msg)
 	case trackingToggledMsg:
 		if updateCmds := m.handleTrackingToggledMsg(msg); updateCmds != nil {
 			cmds = append(cmds, updateCmds...)
@@ -445,32 +667,61 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 		if msg.err != nil {
 			m.message = errMsg(fmt.Sprintf("Error updating task status: %s", msg.err))
 		} else {
-			msg.tsk.UpdateListDesc(m.timeProvider)
+			msg.tsk.UpdateListDesc(m.timeProvider, m.durationFormat)
+		}
+	case tLActionUndoneMsg:
+		if updateCmds := m.handleTLActionUndoneMsg(msg); updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
 		}
 	case tLDeletedMsg:
 		if updateCmds := m.handleTLDeleted(msg); updateCmds != nil {
 			cmds = append(cmds, updateCmds...)
 		}
+	case taskTLsDeletedMsg:
+		if updateCmds := m.handleTaskTLsDeletedMsg(msg); updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
 	case taskLogMovedMsg:
 		if msg.err != nil {
 			m.message = errMsg(fmt.Sprintf("Error moving task log: %s", msg.err))
 		} else {
-			cmds = append(cmds, fetchTLS(m.db, nil))
-			cmds = append(cmds, fetchTasks(m.db, true))
+			m.lastUndoable = &undoableAction{
+				kind:      undoMoveTL,
+				entry:     types.TaskLogEntry{ID: msg.tlID, TaskID: msg.oldTaskID, SecsSpent: msg.secsSpent},
+				newTaskID: msg.newTaskID,
+			}
+			cmds = append(cmds, m.getCmdToRefreshTLS(nil))
+			cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
 			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 				cmds = append(cmds, syncCmd)
 			}
 		}
 		m.activeView = taskLogView
 		m.targetTasksList.ResetFilter()
+	case taskLogsMovedMsg:
+		if updateCmds := m.handleTaskLogsMovedMsg(msg); updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
+	case weeklyTotalsFetchedMsg:
+		if msg.err != nil {
+			m.message = errMsg(fmt.Sprintf("Error fetching weekly totals: %s", msg.err))
+		} else {
+			m.weeklyTotals = msg.totals
+			m.lastView = m.activeView
+			m.activeView = weeklyTotalsView
+		}
+	case overlappingTLFetchedMsg:
+		m.handleOverlappingTLFetchedMsg(msg)
+	case todayTotalFetchedMsg:
+		m.handleTodayTotalFetchedMsg(msg)
 	case activeTaskLogDeletedMsg:
 		m.handleActiveTLDeletedMsg(msg)
 	case taskActiveStatusUpdatedMsg:
 		if msg.err != nil {
 			m.message = errMsg("Error updating task's active status: " + msg.err.Error())
 		} else {
-			cmds = append(cmds, fetchTasks(m.db, true))
-			cmds = append(cmds, fetchTasks(m.db, false))
+			cmds = append(cmds, fetchTasks(m.db, true, m.activeTasksPage))
+			cmds = append(cmds, fetchTasks(m.db, false, m.inactiveTasksPage))
 			if syncCmd := m.requestSyncCmd(); syncCmd != nil {
 				cmds = append(cmds, syncCmd)
 			}
@@ -512,6 +763,16 @@ func (m *Model) handleMsg(msg tea.Msg) []tea.Cmd {
 		}
 	case hideHelpMsg:
 		m.showHelpIndicator = false
+	case idleCheckTickMsg:
+		if tickCmd := m.handleIdleCheckTickMsg(); tickCmd != nil {
+			cmds = append(cmds, tickCmd)
+		}
+	case pomodoroCheckTickMsg:
+		cmds = append(cmds, m.handlePomodoroCheckTickMsg()...)
+	case trackingReminderTickMsg:
+		if tickCmd := m.handleTrackingReminderTickMsg(); tickCmd != nil {
+			cmds = append(cmds, tickCmd)
+		}
 	}
 	return cmds
 }
@@ -531,7 +792,7 @@ func (m *Model) updateActiveView(msg tea.Msg) []tea.Cmd {
 	case inactiveTaskListView:
 		m.inactiveTasksList, cmd = m.inactiveTasksList.Update(msg)
 		cmds = append(cmds, cmd)
-	case moveTaskLogView:
+	case moveTaskLogView, filterTaskLogView:
 		m.targetTasksList, cmd = m.targetTasksList.Update(msg)
 		cmds = append(cmds, cmd)
 	case helpView:
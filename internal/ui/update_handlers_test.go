@@ -5,10 +5,14 @@ package ui
 // supplement (not replace) the existing update_test.go suite.
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dhth/hours/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -168,7 +172,7 @@ func TestHandleFormKeysShiftTabNavigatesBackwardInView(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// handleFormKeys – time-shift keys (k / j / K / J / h / l)
+// handleFormKeys – time-shift keys (k / j / K / J / p / n / h / l / w / W)
 // ---------------------------------------------------------------------------
 
 func TestHandleFormKeysTimeShiftKInFormViewShiftsTimeBackwardOneMinute(t *testing.T) {
@@ -231,6 +235,53 @@ func TestHandleFormKeysTimeShiftCapitalJInFormViewShiftsTimeForwardFiveMinutes(t
 	assert.Equal(t, "2025/08/16 09:35", m.tLInputs[entryBeginTS].Value())
 }
 
+func TestHandleFormKeysTimeShiftPInFormViewShiftsTimeBackwardOneHour(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = editActiveTLView
+	m.trackingFocussedField = entryBeginTS
+	m.tLInputs[entryBeginTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	exitEarly, _ := m.handleFormKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+
+	// THEN
+	assert.False(t, exitEarly)
+	assert.Equal(t, "2025/08/16 08:30", m.tLInputs[entryBeginTS].Value())
+}
+
+func TestHandleFormKeysTimeShiftNInFormViewShiftsTimeForwardOneHour(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = manualTasklogEntryView
+	m.trackingFocussedField = entryEndTS
+	m.tLInputs[entryEndTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	exitEarly, _ := m.handleFormKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+	// THEN
+	assert.False(t, exitEarly)
+	assert.Equal(t, "2025/08/16 10:30", m.tLInputs[entryEndTS].Value())
+}
+
+func TestHandleFormKeysTimeShiftHourIsRenderedInFocusedInput(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = editSavedTLView
+	m.trackingFocussedField = entryBeginTS
+	m.tLInputs[entryBeginTS].Focus()
+	m.tLInputs[entryBeginTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	model := newM.(Model)
+
+	// THEN
+	assert.Equal(t, "2025/08/16 10:30", model.tLInputs[entryBeginTS].Value())
+	assert.Contains(t, model.tLInputs[entryBeginTS].View(), "2025/08/16 10:30")
+}
+
 func TestHandleFormKeysTimeShiftHInFormViewShiftsTimeBackwardOneDay(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -261,6 +312,52 @@ func TestHandleFormKeysTimeShiftLInFormViewShiftsTimeForwardOneDay(t *testing.T)
 	assert.Equal(t, "2025/08/17 09:30", m.tLInputs[entryBeginTS].Value())
 }
 
+func TestHandleFormKeysTimeShiftCapitalWInFormViewShiftsTimeBackwardOneWeek(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = manualTasklogEntryView
+	m.trackingFocussedField = entryEndTS
+	m.tLInputs[entryEndTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	exitEarly, _ := m.handleFormKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+
+	// THEN
+	assert.False(t, exitEarly)
+	assert.Equal(t, "2025/08/09 09:30", m.tLInputs[entryEndTS].Value())
+}
+
+func TestHandleFormKeysTimeShiftWInFormViewShiftsTimeForwardOneWeek(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = editSavedTLView
+	m.trackingFocussedField = entryBeginTS
+	m.tLInputs[entryBeginTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	exitEarly, _ := m.handleFormKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+
+	// THEN
+	assert.False(t, exitEarly)
+	assert.Equal(t, "2025/08/23 09:30", m.tLInputs[entryBeginTS].Value())
+}
+
+func TestHandleFormKeysTimeShiftCapitalJUsesConfiguredShiftStep(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = finishActiveTLView
+	m.shiftStep = 15 * time.Minute
+	m.trackingFocussedField = entryBeginTS
+	m.tLInputs[entryBeginTS].SetValue("2025/08/16 09:30")
+
+	// WHEN
+	exitEarly, _ := m.handleFormKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+
+	// THEN
+	assert.False(t, exitEarly)
+	assert.Equal(t, "2025/08/16 09:45", m.tLInputs[entryBeginTS].Value())
+}
+
 func TestHandleFormKeysTimeShiftNotAppliedOutsideFormViews(t *testing.T) {
 	// GIVEN – taskListView: time-shift keys should have no effect
 	m := createTestModel()
@@ -583,6 +680,45 @@ func TestHandleMsgTaskActiveStatusUpdatedMsgSuccessFetchesBothTaskLists(t *testi
 	assert.Len(t, cmds, 2)
 }
 
+func TestHandleMsgLastArchivedTaskRestoredMsg(t *testing.T) {
+	t.Run("error sets an error message", func(t *testing.T) {
+		// GIVEN
+		m := createTestModel()
+
+		// WHEN
+		cmds := m.handleMsg(lastArchivedTaskRestoredMsg{err: errTestError})
+
+		// THEN
+		assert.Empty(t, cmds)
+		assert.Equal(t, userMsgErr, m.message.kind)
+	})
+
+	t.Run("no archived task sets an info message", func(t *testing.T) {
+		// GIVEN
+		m := createTestModel()
+
+		// WHEN
+		cmds := m.handleMsg(lastArchivedTaskRestoredMsg{})
+
+		// THEN
+		assert.Empty(t, cmds)
+		assert.Equal(t, userMsgInfo, m.message.kind)
+		assert.Contains(t, m.message.value, "no archived tasks")
+	})
+
+	t.Run("success names the task and fetches both task lists", func(t *testing.T) {
+		// GIVEN
+		m := createTestModel()
+
+		// WHEN
+		cmds := m.handleMsg(lastArchivedTaskRestoredMsg{task: &types.Task{ID: 1, Summary: "old task"}})
+
+		// THEN – fetchTasks(active) + fetchTasks(inactive) = 2 cmds
+		assert.Len(t, cmds, 2)
+		assert.Equal(t, "Restored task: old task", m.message.value)
+	})
+}
+
 func TestHandleMsgStaleTasksArchivedMsgWithErrorSetsErrMessage(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -734,14 +870,22 @@ func (v stateView) String() string {
 		return "editActiveTLView"
 	case finishActiveTLView:
 		return "finishActiveTLView"
+	case startTrackingView:
+		return "startTrackingView"
 	case manualTasklogEntryView:
 		return "manualTasklogEntryView"
 	case editSavedTLView:
 		return "editSavedTLView"
+	case confirmDeleteTLView:
+		return "confirmDeleteTLView"
+	case confirmDeleteTaskTLsView:
+		return "confirmDeleteTaskTLsView"
 	case taskInputView:
 		return "taskInputView"
 	case moveTaskLogView:
 		return "moveTaskLogView"
+	case commandPaletteView:
+		return "commandPaletteView"
 	case helpView:
 		return "helpView"
 	case insufficientDimensionsView:
@@ -753,3 +897,72 @@ func (v stateView) String() string {
 
 // errTestError is a sentinel error used in handler tests.
 var errTestError = types.ErrDurationNotLongEnough
+
+// ---------------------------------------------------------------------------
+// theme reloading
+// ---------------------------------------------------------------------------
+
+func reloadThemeViaKey(t *testing.T, m *Model) {
+	t.Helper()
+
+	cmds := m.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.Len(t, cmds, 1)
+	m.handleMsg(cmds[0]())
+}
+
+func TestHandleListKeysCtrlLReloadsCustomTheme(t *testing.T) {
+	// GIVEN
+	themesDir := t.TempDir()
+	themePath := filepath.Join(themesDir, "mine.json")
+	require.NoError(t, os.WriteFile(themePath, []byte(`{"activeTasks": "#111111"}`), 0o644))
+	m := createTestModel()
+	m.themeName = "custom:mine"
+	m.themesDir = themesDir
+
+	// WHEN
+	reloadThemeViaKey(t, &m)
+
+	// THEN
+	assert.Equal(t, "#111111", m.style.theme.ActiveTasks)
+	assert.Equal(t, lipgloss.Color("#111111"), m.activeTasksList.Styles.Title.GetBackground())
+	assert.Equal(t, "Theme reloaded", m.message.value)
+
+	// WHEN – the theme file changes
+	require.NoError(t, os.WriteFile(themePath, []byte(`{"activeTasks": "#222222"}`), 0o644))
+	reloadThemeViaKey(t, &m)
+
+	// THEN
+	assert.Equal(t, "#222222", m.style.theme.ActiveTasks)
+	assert.Equal(t, lipgloss.Color("#222222"), m.activeTasksList.Styles.Title.GetBackground())
+}
+
+func TestHandleListKeysCtrlLKeepsStyleWhenThemeIsInvalid(t *testing.T) {
+	// GIVEN
+	themesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(themesDir, "mine.json"), []byte(`{"activeTasks": `), 0o644))
+	m := createTestModel()
+	m.themeName = "custom:mine"
+	m.themesDir = themesDir
+	styleBefore := m.style.theme
+
+	// WHEN
+	reloadThemeViaKey(t, &m)
+
+	// THEN
+	assert.Equal(t, styleBefore, m.style.theme)
+	assert.Equal(t, userMsgErr, m.message.kind)
+	assert.Contains(t, m.message.value, "Couldn't reload theme")
+}
+
+func TestHandleListKeysCtrlLIgnoresBuiltInThemes(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.themeName = "dracula"
+
+	// WHEN
+	cmds := m.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlL})
+
+	// THEN
+	assert.Empty(t, cmds)
+	assert.Equal(t, "Only custom themes can be reloaded", m.message.value)
+}
@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dhth/hours/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -210,6 +214,44 @@ func TestEscapeFromTaskLogDetailsViewReturnsToTaskLogView(t *testing.T) {
 	assert.Nil(t, cmd)
 }
 
+func TestTaskLogDetailsShowRunningTotalForTask(t *testing.T) {
+	testCases := []struct {
+		name          string
+		index         int
+		expectedTotal string
+	}{
+		{name: "second entry", index: 0, expectedTotal: "Running total: 1h 30m"},
+		{name: "first entry", index: 2, expectedTotal: "Running total: 1h"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			h := newJourneyTestHarness(t)
+			defer h.cleanup()
+
+			now := h.timeProvider.Now()
+			task1ID := h.insertTask("Write docs", true)
+			task2ID := h.insertTask("Review PRs", true)
+			h.insertTaskLog(task1ID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "first draft")
+			h.insertTaskLog(task2ID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), "review")
+			h.insertTaskLog(task1ID, now.Add(-time.Hour), now.Add(-30*time.Minute), "second draft")
+			h.refreshTaskList()
+			h.refreshTaskLogList()
+			h.goToTaskLogView()
+			h.selectTaskLog(tt.index)
+
+			// WHEN
+			cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+
+			// THEN
+			assert.Empty(t, cmds)
+			h.assertView(taskLogDetailsView)
+			assert.Contains(t, h.model.tLDetailsVP.View(), tt.expectedTotal)
+		})
+	}
+}
+
 func TestEscapeFromTaskInputViewReturnsToTaskListView(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -661,3 +703,1196 @@ func TestWindowResizeFromInsufficientDimsDoesNotSwitchIfStillInsufficient(t *tes
 	assert.Equal(t, insufficientDimensionsView, model.activeView)
 	assert.Equal(t, taskListView, model.lastViewBeforeInsufficientDims)
 }
+
+// undo
+
+func pressKeyAndApply(h *journeyTestHarness, key tea.KeyMsg) {
+	h.t.Helper()
+	cmds := h.model.handleListKeys(key)
+	require.Len(h.t, cmds, 1)
+
+	msg := cmds[0]()
+	require.NotNil(h.t, msg)
+
+	newModel, _ := h.model.Update(msg)
+	h.model = newModel.(Model)
+}
+
+func TestUndoRestoresDeletedTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	beginTS := now.Add(-2 * time.Hour).Truncate(time.Second)
+	endTS := now.Add(-1 * time.Hour).Truncate(time.Second)
+	h.insertTaskLog(taskID, beginTS, endTS, "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlD})
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	h.assertDBTaskLogCount(0)
+	h.assertTaskSecsSpent(taskID, 0)
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyCtrlZ})
+
+	// THEN
+	h.assertDBTaskLogCount(1)
+	h.assertTaskSecsSpent(taskID, 3600)
+	var comment string
+	var gotBeginTS, gotEndTS time.Time
+	err := h.db.QueryRow("SELECT comment, begin_ts, end_ts FROM task_log WHERE task_id = ?", taskID).
+		Scan(&comment, &gotBeginTS, &gotEndTS)
+	require.NoError(t, err)
+	assert.Equal(t, "first draft", comment)
+	assert.True(t, beginTS.Equal(gotBeginTS))
+	assert.True(t, endTS.Equal(gotEndTS))
+	assert.Nil(t, h.model.lastUndoable)
+	h.assertMessage("Restored deleted task log entry")
+}
+
+func TestUndoRevertsEditedTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	tlID := h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	h.editSavedTaskLog(now.Add(-3*time.Hour), now.Add(-1*time.Hour), "second draft")
+	h.assertTaskSecsSpent(taskID, 7200)
+	h.goToTaskLogView()
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyCtrlZ})
+
+	// THEN
+	entry, err := h.getTaskLogByID(tlID)
+	require.NoError(t, err)
+	require.NotNil(t, entry.Comment)
+	assert.Equal(t, "first draft", *entry.Comment)
+	assert.Equal(t, 3600, entry.SecsSpent)
+	h.assertTaskSecsSpent(taskID, 3600)
+}
+
+func TestUndoMovesTaskLogBack(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	task1ID := h.insertTask("Write docs", true)
+	task2ID := h.insertTask("Review PRs", true)
+	tlID := h.insertTaskLog(task1ID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	h.moveTaskLogToTaskByID(task2ID)
+	h.assertTaskSecsSpent(task2ID, 3600)
+	h.goToTaskLogView()
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyCtrlZ})
+
+	// THEN
+	entry, err := h.getTaskLogByID(tlID)
+	require.NoError(t, err)
+	assert.Equal(t, task1ID, entry.TaskID)
+	h.assertTaskSecsSpent(task1ID, 3600)
+	h.assertTaskSecsSpent(task2ID, 0)
+}
+
+// moving several task logs at once
+
+func TestMovingSelectedTaskLogsMovesAllOfThem(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	task1ID := h.insertTask("Write docs", true)
+	task2ID := h.insertTask("Review PRs", true)
+	tl1ID := h.insertTaskLog(task1ID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "first draft")
+	tl2ID := h.insertTaskLog(task1ID, now.Add(-2*time.Hour), now.Add(-90*time.Minute), "second draft")
+	h.insertTaskLog(task1ID, now.Add(-time.Hour), now.Add(-30*time.Minute), "third draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	for i, item := range h.model.taskLogList.Items() {
+		entry, ok := item.(types.TaskLogEntry)
+		require.True(t, ok)
+		if entry.ID == tl1ID || entry.ID == tl2ID {
+			h.selectTaskLog(i)
+			h.model.handleListKeys(space)
+		}
+	}
+	require.Len(t, h.model.selectedTLIDs, 2)
+	assert.Equal(t, 2, strings.Count(h.model.View(), selectedTLMarker))
+
+	// WHEN
+	h.moveTaskLogToTaskByID(task2ID)
+
+	// THEN
+	tl1, err := h.getTaskLogByID(tl1ID)
+	require.NoError(t, err)
+	assert.Equal(t, task2ID, tl1.TaskID)
+	tl2, err := h.getTaskLogByID(tl2ID)
+	require.NoError(t, err)
+	assert.Equal(t, task2ID, tl2.TaskID)
+	h.assertTaskSecsSpent(task1ID, 1800)
+	h.assertTaskSecsSpent(task2ID, 5400)
+	h.assertView(taskLogView)
+	assert.Empty(t, h.model.selectedTLIDs)
+	assert.Equal(t, "Moved 2 task log entries", h.model.message.value)
+}
+
+func TestTogglingTaskLogSelectionTwiceUnselectsIt(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	h.model.handleListKeys(space)
+	require.Len(t, h.model.selectedTLIDs, 1)
+
+	// WHEN
+	h.model.handleListKeys(space)
+
+	// THEN
+	assert.Empty(t, h.model.selectedTLIDs)
+	assert.Equal(t, "No task log entries selected", h.model.message.value)
+}
+
+// jumping to today's task logs
+
+func TestGoingToTodaysTLsSelectsFirstEntryFromToday(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-26*time.Hour), now.Add(-25*time.Hour), "yesterday")
+	h.insertTaskLog(taskID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "earlier today")
+	todayTLID := h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "later today")
+	h.insertTaskLog(taskID, now.Add(24*time.Hour), now.Add(25*time.Hour), "tomorrow")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+	h.selectTaskLog(3)
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+
+	// THEN
+	assert.Empty(t, cmds)
+	entry, ok := h.model.selectedTaskLogEntry()
+	require.True(t, ok)
+	assert.Equal(t, todayTLID, entry.ID)
+	assert.Equal(t, 1, h.model.taskLogList.Index())
+}
+
+func TestGoingToTodaysTLsWithoutEntriesFromTodayShowsMessage(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-26*time.Hour), now.Add(-25*time.Hour), "yesterday")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	// WHEN
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+
+	// THEN
+	assert.Equal(t, userMsgErr, h.model.message.kind)
+	assert.Equal(t, "No task log entries from today", h.model.message.value)
+	assert.Equal(t, 0, h.model.taskLogList.Index())
+}
+
+func TestUndoWithNothingToUndoShowsMessage(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+
+	// WHEN
+	cmds := m.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlZ})
+
+	// THEN
+	assert.Empty(t, cmds)
+	assert.Equal(t, "Nothing to undo", m.message.value)
+}
+
+// task log deletion confirmation
+
+func TestDeletingTaskLogAsksForConfirmation(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlD})
+
+	// THEN
+	assert.Empty(t, cmds)
+	h.assertView(confirmDeleteTLView)
+	h.assertDBTaskLogCount(1)
+}
+
+func TestCancellingTaskLogDeletionKeepsEntry(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  tea.KeyMsg
+	}{
+		{name: "n", key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}},
+		{name: "esc", key: tea.KeyMsg{Type: tea.KeyEsc}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			h := newJourneyTestHarness(t)
+			defer h.cleanup()
+
+			now := h.timeProvider.Now()
+			taskID := h.insertTask("Write docs", true)
+			h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+			h.refreshTaskList()
+			h.refreshTaskLogList()
+			h.goToTaskLogView()
+			h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlD})
+
+			// WHEN
+			newModel, cmd := h.model.Update(tt.key)
+			h.model = newModel.(Model)
+
+			// THEN
+			assert.Nil(t, cmd)
+			h.assertView(taskLogView)
+			h.assertDBTaskLogCount(1)
+			assert.Len(t, h.model.taskLogList.Items(), 1)
+		})
+	}
+}
+
+func TestConfirmingTaskLogDeletionRemovesEntry(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.goToTaskLogView()
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyCtrlD})
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	// THEN
+	h.assertView(taskLogView)
+	h.assertDBTaskLogCount(0)
+	h.assertTaskSecsSpent(taskID, 0)
+}
+
+// deleting all task logs for a task
+
+func TestConfirmingTaskTLsDeletionRemovesAllEntries(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "first draft")
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "second draft")
+	h.refreshTaskList()
+	h.refreshTaskLogList()
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	h.assertView(confirmDeleteTaskTLsView)
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertDBTaskLogCount(0)
+	h.assertTaskSecsSpent(taskID, 0)
+	assert.Equal(t, "Deleted 2 task log entries", h.model.message.value)
+}
+
+func TestCancellingTaskTLsDeletionKeepsEntries(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "first draft")
+	h.refreshTaskList()
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+
+	// WHEN
+	cmds := h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+	// THEN
+	assert.Empty(t, cmds)
+	h.assertView(taskListView)
+	h.assertDBTaskLogCount(1)
+}
+
+// task sorting
+
+func TestCyclingTaskSortOrderReordersActiveTasks(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskListView
+	referenceTS := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	m.handleTasksFetchedMsg(tasksFetchedMsg{
+		active: true,
+		tasks: []types.Task{
+			{ID: 1, Summary: "write docs", SecsSpent: 3600, UpdatedAt: referenceTS.Add(2 * time.Hour), Active: true},
+			{ID: 2, Summary: "Review PRs", SecsSpent: 7200, UpdatedAt: referenceTS.Add(time.Hour), Active: true},
+			{ID: 3, Summary: "fix bugs", SecsSpent: 1800, UpdatedAt: referenceTS, Active: true},
+		},
+	})
+	m.activeTasksList.Select(1)
+
+	taskIDs := func() []int {
+		var ids []int
+		for _, item := range m.activeTasksList.Items() {
+			task, ok := item.(*types.Task)
+			require.True(t, ok)
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	assertOrder := func(expected []int) {
+		t.Helper()
+		assert.Equal(t, expected, taskIDs())
+		for i, id := range expected {
+			assert.Equal(t, i, m.taskIndexMap[id])
+		}
+		selected, ok := m.selectedActiveTask()
+		require.True(t, ok)
+		assert.Equal(t, 2, selected.ID, "selection should follow the task")
+	}
+	cycle := func() {
+		m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	}
+
+	assertOrder([]int{1, 2, 3})
+
+	// WHEN
+	cycle()
+
+	// THEN
+	assert.Equal(t, taskSortByTimeSpent, m.taskSortOrder)
+	assertOrder([]int{2, 1, 3})
+
+	// WHEN
+	cycle()
+
+	// THEN
+	assert.Equal(t, taskSortAlphabetically, m.taskSortOrder)
+	assertOrder([]int{3, 2, 1})
+
+	// WHEN
+	cycle()
+
+	// THEN
+	assert.Equal(t, taskSortByRecency, m.taskSortOrder)
+	assertOrder([]int{1, 2, 3})
+}
+
+func TestTaskSortOrderIsKeptWhenTasksAreRefetched(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.taskSortOrder = taskSortAlphabetically
+	referenceTS := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	// WHEN
+	m.handleTasksFetchedMsg(tasksFetchedMsg{
+		active: true,
+		tasks: []types.Task{
+			{ID: 1, Summary: "write docs", UpdatedAt: referenceTS.Add(time.Hour), Active: true},
+			{ID: 2, Summary: "fix bugs", UpdatedAt: referenceTS, Active: true},
+		},
+	})
+
+	// THEN
+	first, ok := m.activeTasksList.Items()[0].(*types.Task)
+	require.True(t, ok)
+	assert.Equal(t, 2, first.ID)
+	assert.Equal(t, 0, m.taskIndexMap[2])
+	assert.Equal(t, 1, m.taskIndexMap[1])
+}
+
+// avoiding overlaps when finishing the active task log
+
+func pressFormKeyAndApply(h *journeyTestHarness, key tea.KeyMsg) {
+	h.t.Helper()
+	exitEarly, cmds := h.model.handleFormKeys(key)
+	require.True(h.t, exitEarly)
+	require.Len(h.t, cmds, 1)
+
+	msg := cmds[0]()
+	require.NotNil(h.t, msg)
+
+	newModel, _ := h.model.Update(msg)
+	h.model = newModel.(Model)
+}
+
+func TestClampingEndTimeAvoidsOverlapWithNextTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Backfill", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+	nextBeginTS := now.Add(90 * time.Minute)
+	h.insertTaskLog(taskID, nextBeginTS, now.Add(2*time.Hour), "already logged")
+	h.startTracking()
+	h.stopTracking()
+	h.model.tLInputs[entryEndTS].SetValue(now.Add(3 * time.Hour).Format(timeFormat))
+
+	// WHEN
+	pressFormKeyAndApply(h, tea.KeyMsg{Type: tea.KeyCtrlL})
+
+	// THEN
+	h.assertView(finishActiveTLView)
+	assert.Equal(t, nextBeginTS.Format(timeFormat), h.model.tLInputs[entryEndTS].Value())
+	h.assertMessage("Moved end time back to the begin of the next saved entry")
+}
+
+func TestClampingEndTimeIsANoOpWithoutOverlap(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Backfill", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+	h.insertTaskLog(taskID, now.Add(3*time.Hour), now.Add(4*time.Hour), "later on")
+	h.startTracking()
+	h.stopTracking()
+	endTS := now.Add(2 * time.Hour).Format(timeFormat)
+	h.model.tLInputs[entryEndTS].SetValue(endTS)
+
+	// WHEN
+	pressFormKeyAndApply(h, tea.KeyMsg{Type: tea.KeyCtrlL})
+
+	// THEN
+	assert.Equal(t, endTS, h.model.tLInputs[entryEndTS].Value())
+	h.assertMessage("End time doesn't overlap with any saved entry for this task")
+}
+
+// idle detection
+
+func startTrackingAt(h *journeyTestHarness, taskID int, beginTS time.Time) {
+	h.t.Helper()
+	cmd := h.model.getCmdToStartTrackingTaskAt(taskID, beginTS)
+	require.NotNil(h.t, cmd)
+
+	newModel, _ := h.model.Update(cmd())
+	h.model = newModel.(Model)
+}
+
+func TestIdleCheckOffersToTrimActiveTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Left running", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-3*time.Hour))
+	h.model.idleThreshold = 30 * time.Minute
+	h.model.lastInteractionAt = now.Add(-2 * time.Hour)
+
+	// WHEN
+	newModel, cmd := h.model.Update(idleCheckTickMsg{})
+	h.model = newModel.(Model)
+
+	// THEN
+	assert.NotNil(t, cmd)
+	h.assertMessage(fmt.Sprintf("You've been idle since %s; press i to trim the active task log back to then",
+		now.Add(-2*time.Hour).Format(timeFormat)))
+	h.assertTrackingState(true, taskID)
+}
+
+func TestIdleCheckDoesNothingWithinThreshold(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Busy", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-3*time.Hour))
+	h.model.idleThreshold = 30 * time.Minute
+	h.model.lastInteractionAt = now.Add(-10 * time.Minute)
+
+	// WHEN
+	newModel, _ := h.model.Update(idleCheckTickMsg{})
+	h.model = newModel.(Model)
+
+	// THEN
+	assert.True(t, h.model.idleSince.IsZero())
+	assert.Empty(t, h.model.message.value)
+}
+
+func TestPressingAnotherKeyWithdrawsOfferToTrimActiveTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Left running", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-3*time.Hour))
+	h.model.idleThreshold = 30 * time.Minute
+	h.model.lastInteractionAt = now.Add(-2 * time.Hour)
+	newModel, _ := h.model.Update(idleCheckTickMsg{})
+	h.model = newModel.(Model)
+	require.False(t, h.model.idleSince.IsZero())
+
+	// WHEN
+	for _, key := range []rune{'j', 'i'} {
+		newModel, _ = h.model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		h.model = newModel.(Model)
+	}
+
+	// THEN
+	assert.True(t, h.model.idleSince.IsZero())
+	h.assertTrackingState(true, taskID)
+	h.assertMessage("No idle time to trim")
+}
+
+func TestPressingTrimKeyKeepsOfferToTrimActiveTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Left running", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-3*time.Hour))
+	h.model.idleThreshold = 30 * time.Minute
+	h.model.lastInteractionAt = now.Add(-2 * time.Hour)
+	newModel, _ := h.model.Update(idleCheckTickMsg{})
+	h.model = newModel.(Model)
+
+	// WHEN
+	newModel, cmd := h.model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	h.model = newModel.(Model)
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.True(t, h.model.idleSince.IsZero())
+	assert.Equal(t, now.Add(-2*time.Hour), h.model.activeTLEndTS)
+}
+
+func TestTrimmingActiveTaskLogFinishesItWhenIdleStarted(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Left running", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-3*time.Hour))
+	h.model.idleThreshold = 30 * time.Minute
+	h.model.lastInteractionAt = now.Add(-2 * time.Hour)
+	newModel, _ := h.model.Update(idleCheckTickMsg{})
+	h.model = newModel.(Model)
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+
+	// THEN
+	h.assertTrackingState(false, -1)
+	h.assertDBTaskLogCount(1)
+	h.assertTaskSecsSpent(taskID, 60*60)
+	assert.True(t, h.model.idleSince.IsZero())
+}
+
+// task creation time
+
+func TestUpdatingTaskChangesItsCreationTime(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	taskID := h.insertTask("Migrated task", true)
+	h.refreshTaskList()
+	h.model.handleRequestToUpdateTask()
+	h.model.goForwardInView()
+	require.Equal(t, createdAtField, h.model.taskInputFocussedField)
+	h.model.taskInputs[createdAtField].SetValue("2023/03/15 09:30")
+
+	// WHEN
+	cmd := h.model.getCmdToCreateOrUpdateTask()
+	require.NotNil(t, cmd)
+	newModel, _ := h.model.Update(cmd())
+	h.model = newModel.(Model)
+
+	// THEN
+	expected := time.Date(2023, 3, 15, 9, 30, 0, 0, time.Local)
+	task, err := h.getTaskByID(taskID)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(task.CreatedAt))
+	selected, ok := h.model.selectedActiveTask()
+	require.True(t, ok)
+	assert.True(t, expected.Equal(selected.CreatedAt))
+}
+
+func TestCreatingTaskTrimsItsSummary(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	// WHEN
+	h.createTask("  Write docs  ")
+	h.refreshTaskList()
+
+	// THEN
+	task, ok := h.model.selectedActiveTask()
+	require.True(t, ok)
+	assert.Equal(t, "Write docs", task.Summary)
+}
+
+func TestUpdatingTaskWithInvalidCreationTimeShowsError(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	h.insertTask("Migrated task", true)
+	h.refreshTaskList()
+	h.model.handleRequestToUpdateTask()
+	h.model.taskInputs[createdAtField].SetValue("last tuesday")
+
+	// WHEN
+	cmd := h.model.getCmdToCreateOrUpdateTask()
+
+	// THEN
+	assert.Nil(t, cmd)
+	assert.Equal(t, userMsgErr, h.model.message.kind)
+	assert.Equal(t, taskInputView, h.model.activeView)
+}
+
+// pomodoro mode
+
+func TestPomodoroCheckPromptsForBreakAfterWorkLength(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Focused work", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now)
+	h.model.pomodoro = PomodoroConfig{Work: 25 * time.Minute, Break: 5 * time.Minute}
+
+	newModel, _ := h.model.Update(pomodoroCheckTickMsg{})
+	h.model = newModel.(Model)
+	assert.Empty(t, h.model.message.value)
+
+	// WHEN
+	h.model.timeProvider = types.TestTimeProvider{FixedTime: now.Add(26 * time.Minute)}
+	newModel, cmd := h.model.Update(pomodoroCheckTickMsg{})
+	h.model = newModel.(Model)
+
+	// THEN
+	assert.NotNil(t, cmd)
+	h.assertMessage("You've worked for 25m; time for a 5m break")
+	h.assertTrackingState(true, taskID)
+
+	// the break is only suggested once per task log
+	h.model.message = userMsg{}
+	newModel, _ = h.model.Update(pomodoroCheckTickMsg{})
+	h.model = newModel.(Model)
+	assert.Empty(t, h.model.message.value)
+}
+
+func TestPomodoroCheckAutoStopsActiveTaskLog(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Focused work", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-30*time.Minute))
+	h.model.pomodoro = PomodoroConfig{Work: 25 * time.Minute, Break: 5 * time.Minute, AutoStop: true}
+
+	// WHEN
+	cmds := h.model.handlePomodoroCheckTickMsg()
+	require.Len(t, cmds, 2)
+	newModel, _ := h.model.Update(cmds[1]())
+	h.model = newModel.(Model)
+
+	// THEN
+	h.assertMessage("You've worked for 25m; stopped tracking, time for a 5m break")
+	h.assertTrackingState(false, -1)
+	h.assertDBTaskLogCount(1)
+	h.assertTaskSecsSpent(taskID, 30*60)
+}
+
+// tracking reminders
+
+func TestTrackingReminderShowsMessageWhenCrossingInterval(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Long running", true)
+	h.refreshTaskList()
+	startTrackingAt(h, taskID, now.Add(-2*time.Hour-50*time.Minute))
+	h.model.trackingReminder = time.Hour
+
+	newModel, _ := h.model.Update(trackingReminderTickMsg{})
+	h.model = newModel.(Model)
+	h.assertMessage("Tracking for 2h")
+	h.model.message = userMsg{}
+
+	// WHEN - still within the same hour
+	h.model.timeProvider = types.TestTimeProvider{FixedTime: now.Add(5 * time.Minute)}
+	newModel, _ = h.model.Update(trackingReminderTickMsg{})
+	h.model = newModel.(Model)
+
+	// THEN
+	assert.Empty(t, h.model.message.value)
+
+	// WHEN - past the next hour boundary
+	h.model.timeProvider = types.TestTimeProvider{FixedTime: now.Add(11 * time.Minute)}
+	newModel, cmd := h.model.Update(trackingReminderTickMsg{})
+	h.model = newModel.(Model)
+
+	// THEN
+	assert.NotNil(t, cmd)
+	h.assertMessage("Tracking for 3h")
+}
+
+func TestTrackingReminderIsOffByDefault(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+
+	// WHEN
+	cmd := scheduleTrackingReminderCheckCmd(m.trackingReminder)
+
+	// THEN
+	assert.Nil(t, cmd)
+}
+
+// command palette
+
+func runPaletteCommand(h *journeyTestHarness, input string) {
+	h.t.Helper()
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	require.Equal(h.t, commandPaletteView, h.model.activeView)
+	h.model.commandPaletteInput.SetValue(input)
+
+	exitEarly, cmds := h.model.handleFormKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(h.t, exitEarly)
+	for _, cmd := range cmds {
+		newModel, _ := h.model.Update(cmd())
+		h.model = newModel.(Model)
+	}
+}
+
+func TestLookupPaletteActions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		expectedNames []string
+		expectedArg   string
+	}{
+		{name: "exact name", input: "start", expectedNames: []string{"start"}},
+		{name: "exact name preferred over longer names", input: "archive", expectedNames: []string{"archive"}},
+		{name: "unique prefix", input: "sw", expectedNames: []string{"switch-to"}},
+		{name: "ambiguous prefix", input: "st", expectedNames: []string{"start", "stop"}},
+		{name: "fuzzy match isn't used", input: "arcstl"},
+		{name: "with argument", input: "switch-to  write docs ", expectedNames: []string{"switch-to"}, expectedArg: "write docs"},
+		{name: "unknown", input: "xyz"},
+		{name: "empty", input: "  "},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, arg := lookupPaletteActions(tt.input)
+
+			var names []string
+			for _, action := range actions {
+				names = append(names, action.name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Equal(t, tt.expectedArg, arg)
+		})
+	}
+}
+
+func TestCommandPaletteEscapeReturnsToPreviousView(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	h.goToTaskLogView()
+	h.model.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	h.model.commandPaletteInput.SetValue("sta")
+
+	// WHEN
+	exitEarly, _ := h.model.handleFormKeys(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// THEN
+	assert.True(t, exitEarly)
+	h.assertView(taskLogView)
+	assert.Empty(t, h.model.commandPaletteInput.Value())
+}
+
+func TestCommandPaletteStartTracksNamedTask(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	h.insertTask("write docs", true)
+	fixBugsID := h.insertTask("fix bugs", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+
+	// WHEN
+	runPaletteCommand(h, "start fix")
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertTrackingState(true, fixBugsID)
+}
+
+func TestCommandPaletteStartTracksSelectedTaskWithoutArgument(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	taskID := h.insertTask("write docs", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+	h.goToTaskLogView()
+
+	// WHEN
+	runPaletteCommand(h, "start")
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertTrackingState(true, taskID)
+}
+
+func TestCommandPaletteArchiveDeactivatesNamedTask(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	h.insertTask("write docs", true)
+	oldTaskID := h.insertTask("old project", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+
+	// WHEN
+	runPaletteCommand(h, "archive old")
+
+	// THEN
+	task, err := h.getTaskByID(oldTaskID)
+	require.NoError(t, err)
+	assert.False(t, task.Active)
+}
+
+func TestCommandPaletteReportsUnknownCommands(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	// WHEN
+	runPaletteCommand(h, "xyz now")
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertMessage(`Unknown command: "xyz"`)
+}
+
+func TestCommandPaletteReportsAmbiguousCommands(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+	h.insertTask("write docs", true)
+	h.refreshTaskList()
+	h.selectTask(0)
+
+	// WHEN
+	runPaletteCommand(h, "st")
+
+	// THEN
+	h.assertView(taskListView)
+	h.assertMessage(`Ambiguous command: "st" (could be start, stop)`)
+	h.assertTrackingState(false, 0)
+}
+
+func TestRecordsViewSwitchesToAllTimeAndBack(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+	taskID := insertTestTask(t, db, "Old task", true)
+	oldBeginTS := referenceTime.AddDate(0, -3, 0)
+	insertTestTaskLog(t, db, taskID, oldBeginTS, oldBeginTS.Add(2*time.Hour), "Long ago")
+
+	dateRange := types.DateRange{
+		Start:   referenceTime.AddDate(0, 0, -2),
+		End:     referenceTime.AddDate(0, 0, 1),
+		NumDays: 3,
+	}
+	m := initialRecordsModel(reportRecords, db, getTestStyle(), types.TestTimeProvider{FixedTime: referenceTime},
+		dateRange, "3d", types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, "")
+
+	// WHEN
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = newM.(recordsModel)
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.True(t, m.busy)
+	msg, ok := cmd().(recordsDataFetchedMsg)
+	require.True(t, ok)
+	assert.Nil(t, msg.dateRange)
+
+	newM, _ = m.Update(msg)
+	m = newM.(recordsModel)
+	assert.True(t, m.allTime)
+	assert.False(t, m.busy)
+	assert.Contains(t, m.View(), "Old task")
+	assert.Contains(t, m.View(), "all time")
+
+	// WHEN
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = newM.(recordsModel)
+
+	// THEN
+	require.NotNil(t, cmd)
+	msg, ok = cmd().(recordsDataFetchedMsg)
+	require.True(t, ok)
+	require.NotNil(t, msg.dateRange)
+	assert.Equal(t, dateRange, *msg.dateRange)
+
+	newM, _ = m.Update(msg)
+	m = newM.(recordsModel)
+	assert.False(t, m.allTime)
+	assert.NotContains(t, m.View(), "Old task")
+}
+
+func TestRecordsViewMovesRangeByAWeek(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	dateRange := types.DateRange{
+		Start:   referenceTime.AddDate(0, 0, -2),
+		End:     referenceTime.AddDate(0, 0, 1),
+		NumDays: 3,
+	}
+	m := initialRecordsModel(reportRecords, db, getTestStyle(), types.TestTimeProvider{FixedTime: referenceTime},
+		dateRange, "3d", types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, "")
+
+	// WHEN
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	m = newM.(recordsModel)
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.True(t, m.busy)
+	msg, ok := cmd().(recordsDataFetchedMsg)
+	require.True(t, ok)
+	require.NotNil(t, msg.dateRange)
+	assert.Equal(t, dateRange.Start.AddDate(0, 0, 7), msg.dateRange.Start)
+	assert.Equal(t, dateRange.End.AddDate(0, 0, 7), msg.dateRange.End)
+	assert.Equal(t, 3, msg.dateRange.NumDays)
+
+	newM, _ = m.Update(msg)
+	m = newM.(recordsModel)
+
+	// WHEN
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+
+	// THEN
+	require.NotNil(t, cmd)
+	msg, ok = cmd().(recordsDataFetchedMsg)
+	require.True(t, ok)
+	require.NotNil(t, msg.dateRange)
+	assert.Equal(t, dateRange, *msg.dateRange)
+}
+
+func TestSearchTaskLogsByCommentNarrowsAndRestoresList(t *testing.T) {
+	// GIVEN
+	h := newJourneyTestHarness(t)
+	defer h.cleanup()
+
+	now := h.timeProvider.Now()
+	taskID := h.insertTask("Write docs", true)
+	h.insertTaskLog(taskID, now.Add(-5*time.Hour), now.Add(-4*time.Hour), "Review README")
+	h.insertTaskLog(taskID, now.Add(-4*time.Hour), now.Add(-3*time.Hour), "fix typos")
+	h.insertTaskLog(taskID, now.Add(-2*time.Hour), now.Add(-1*time.Hour), "readme screenshots")
+	h.refreshTaskList()
+	h.goToTaskLogView()
+	h.refreshTaskLogList()
+	require.Len(t, h.model.taskLogList.Items(), 3)
+
+	// WHEN
+	newModel, _ := h.model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	h.model = newModel.(Model)
+	h.assertView(searchTaskLogsView)
+	newModel, _ = h.model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("readme")})
+	h.model = newModel.(Model)
+	newModel, cmd := h.model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	h.model = newModel.(Model)
+	require.NotNil(t, cmd)
+	newModel, _ = h.model.Update(cmd())
+	h.model = newModel.(Model)
+
+	// THEN
+	h.assertView(taskLogView)
+	items := h.model.taskLogList.Items()
+	require.Len(t, items, 2)
+	var comments []string
+	for _, item := range items {
+		entry, ok := item.(types.TaskLogEntry)
+		require.True(t, ok)
+		comments = append(comments, *entry.Comment)
+	}
+	assert.Equal(t, []string{"readme screenshots", "Review README"}, comments)
+	assert.Equal(t, `Task Logs matching "readme"`, h.model.taskLogList.Title)
+
+	// WHEN
+	pressKeyAndApply(h, tea.KeyMsg{Type: tea.KeyEsc})
+
+	// THEN
+	h.assertView(taskLogView)
+	assert.Len(t, h.model.taskLogList.Items(), 3)
+	assert.Equal(t, taskLogListTitle, h.model.taskLogList.Title)
+	assert.Empty(t, h.model.taskLogCommentQuery)
+}
+
+func TestRecordsViewShowsLoadingIndicatorWhileBusy(t *testing.T) {
+	// GIVEN
+	db := setupTestDB(t)
+	defer db.Close()
+
+	dateRange := types.DateRange{
+		Start:   referenceTime.AddDate(0, 0, -2),
+		End:     referenceTime.AddDate(0, 0, 1),
+		NumDays: 3,
+	}
+	m := initialRecordsModel(reportRecords, db, getTestStyle(), types.TestTimeProvider{FixedTime: referenceTime},
+		dateRange, "3d", types.TaskStatusAny, "", "", nil, DefaultReportLimit, true, "")
+	assert.NotContains(t, m.View(), "loading…")
+
+	// WHEN
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = newM.(recordsModel)
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.True(t, m.busy)
+	assert.Contains(t, m.View(), "loading…")
+
+	msg, ok := cmd().(recordsDataFetchedMsg)
+	require.True(t, ok)
+	newM, _ = m.Update(msg)
+	m = newM.(recordsModel)
+	assert.False(t, m.busy)
+	assert.NotContains(t, m.View(), "loading…")
+}
+
+func TestSwitchingViewsRestoresTaskLogListSelection(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	entries := make([]types.TaskLogEntry, 5)
+	for i := range entries {
+		entries[i] = *createTestTaskLogEntry(i+1, 1, "task", m.timeProvider)
+	}
+	m.handleTLSFetchedMsg(tLsFetchedMsg{entries: entries})
+	m.activeView = taskLogView
+	m.taskLogList.Select(2)
+
+	// WHEN
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = newM.(Model)
+	require.Equal(t, taskListView, m.activeView)
+	// a refresh while away resets the list's selection
+	m.handleTLSFetchedMsg(tLsFetchedMsg{entries: entries})
+	require.Equal(t, 0, m.taskLogList.Index())
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newM.(Model)
+
+	// THEN
+	assert.Equal(t, taskLogView, m.activeView)
+	assert.Equal(t, 2, m.taskLogList.Index())
+
+	// WHEN
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	m = newM.(Model)
+	m.handleTLSFetchedMsg(tLsFetchedMsg{entries: entries})
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = newM.(Model)
+
+	// THEN
+	assert.Equal(t, taskLogView, m.activeView)
+	assert.Equal(t, 2, m.taskLogList.Index())
+}
+
+func TestSwitchingViewsClampsRestoredSelectionToListBounds(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	entries := make([]types.TaskLogEntry, 5)
+	for i := range entries {
+		entries[i] = *createTestTaskLogEntry(i+1, 1, "task", m.timeProvider)
+	}
+	m.handleTLSFetchedMsg(tLsFetchedMsg{entries: entries})
+	m.activeView = taskLogView
+	m.taskLogList.Select(4)
+
+	// WHEN
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = newM.(Model)
+	m.handleTLSFetchedMsg(tLsFetchedMsg{entries: entries[:2]})
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newM.(Model)
+
+	// THEN
+	assert.Equal(t, taskLogView, m.activeView)
+	assert.Equal(t, 1, m.taskLogList.Index())
+}
@@ -46,7 +46,12 @@ func (m Model) View() string {
 		if ok {
 			taskSummaryMsg = utils.Trim(task.Summary, 50)
 			if m.activeView != finishActiveTLView {
-				taskStartedSinceMsg = fmt.Sprintf("(since %s)", m.activeTLBeginTS.Format(timeOnlyFormat))
+				if m.showActiveTLElapsed {
+					elapsedSecs := int(m.timeProvider.Now().Sub(m.activeTLBeginTS).Seconds())
+					taskStartedSinceMsg = fmt.Sprintf("(%s elapsed)", m.durationFormat.Format(max(elapsedSecs, 0)))
+				} else {
+					taskStartedSinceMsg = fmt.Sprintf("(since %s)", m.activeTLBeginTS.Format(timeOnlyFormat))
+				}
 			}
 		}
 		activeMsg = fmt.Sprintf("%s%s%s",
@@ -60,6 +65,9 @@ func (m Model) View() string {
 	formBeginTimeHelp := "Begin Time* (format: 2006/01/02 15:04)"
 	formEndTimeHelp := "End Time* (format: 2006/01/02 15:04)"
 	formTimeShiftHelp := "(j/k/J/K/h/l moves time)"
+	formTagsHelp := "Tags (optional, comma separated)"
+	formCategoryHelp := "Category (optional)"
+	formCreatedAtHelp := "Created At (format: 2006/01/02 15:04)"
 
 	var formCommentContext string
 	if m.tLCommentInput.Length() == 0 {
@@ -89,7 +97,7 @@ func (m Model) View() string {
 	switch m.activeView {
 	case taskInputView:
 		formSubmitHelp = "Press <ctrl+s>/<enter> to submit"
-	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+	case editActiveTLView, startTrackingView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 		if submissionValidity != tlSubmitErr {
 			if m.trackingFocussedField == entryComment {
 				formSubmitHelp = m.style.formHelp.Render("Press <ctrl+s> to submit")
@@ -121,6 +129,35 @@ func (m Model) View() string {
 		case taskUpdateCxt:
 			formTitle = "Update task"
 		}
+
+		if m.taskMgmtContext == taskUpdateCxt {
+			content = fmt.Sprintf(
+				`
+  %s
+
+  %s
+
+  %s
+
+  %s
+
+  %s
+
+  %s
+`,
+				m.style.taskEntryHeading.Render(formTitle),
+				m.style.formHelp.Render(formHelp),
+				m.taskInputs[summaryField].View(),
+				m.style.formFieldName.Render(formCreatedAtHelp),
+				m.taskInputs[createdAtField].View(),
+				m.style.formHelp.Render(formSubmitHelp),
+			)
+			for range m.terminalHeight - 15 {
+				content += "\n"
+			}
+			break
+		}
+
 		content = fmt.Sprintf(
 			`
   %s
@@ -157,6 +194,14 @@ func (m Model) View() string {
 
   %s
 
+  %s
+
+  %s
+
+  %s
+
+  %s
+
 %s
 
   %s
@@ -172,16 +217,23 @@ func (m Model) View() string {
 			m.style.formFieldName.Render(formEndTimeHelp),
 			m.tLInputs[entryEndTS].View(),
 			m.style.formHelp.Render(formTimeShiftHelp),
+			m.style.formFieldName.Render(formTagsHelp),
+			m.tLInputs[entryTags].View(),
+			m.style.formFieldName.Render(formCategoryHelp),
+			m.tLInputs[entryCategory].View(),
 			m.style.formFieldName.Render(formCommentHelp),
 			m.tLCommentInput.View(),
 			submissionCtx,
 			formSubmitHelp,
 		)
-		for range m.terminalHeight - 34 {
+		for range m.terminalHeight - 42 {
 			content += "\n"
 		}
-	case editActiveTLView:
+	case editActiveTLView, startTrackingView:
 		formHeadingText := "Updating log entry. Enter the following details."
+		if m.activeView == startTrackingView {
+			formHeadingText = "Starting to track time. Enter the following details."
+		}
 
 		content = fmt.Sprintf(
 			`
@@ -238,6 +290,14 @@ func (m Model) View() string {
 
   %s
 
+  %s
+
+  %s
+
+  %s
+
+  %s
+
 %s
 
   %s
@@ -253,17 +313,76 @@ func (m Model) View() string {
 			m.style.formFieldName.Render(formEndTimeHelp),
 			m.tLInputs[entryEndTS].View(),
 			m.style.formHelp.Render(formTimeShiftHelp),
+			m.style.formFieldName.Render(formTagsHelp),
+			m.tLInputs[entryTags].View(),
+			m.style.formFieldName.Render(formCategoryHelp),
+			m.tLInputs[entryCategory].View(),
 			m.style.formFieldName.Render(formCommentHelp),
 			m.tLCommentInput.View(),
 			submissionCtx,
 			formSubmitHelp,
 		)
-		for range m.terminalHeight - 34 {
+		for range m.terminalHeight - 42 {
 			content += "\n"
 		}
 	case moveTaskLogView:
 		helpText := "Press <enter> to move task log, <esc>/<q> to cancel"
 		content = m.style.list.Render(m.targetTasksList.View()) + "\n\n" + m.style.formHelp.Render(helpText)
+	case filterTaskLogView:
+		helpText := "Press <enter> to show this task's logs, <esc>/<q> to cancel"
+		content = m.style.list.Render(m.targetTasksList.View()) + "\n\n" + m.style.formHelp.Render(helpText)
+	case confirmDeleteTLView:
+		var entryDetails string
+		if tl, ok := m.selectedTaskLogEntry(); ok {
+			entryDetails = fmt.Sprintf("%s\n%s → %s (%s)",
+				utils.Trim(tl.TaskSummary, 50),
+				tl.BeginTS.Format(timeFormat),
+				tl.EndTS.Format(timeFormat),
+				types.HumanizeDuration(tl.SecsSpent),
+			)
+		}
+		overlay := fmt.Sprintf("%s\n\n%s\n\n%s",
+			m.style.helpTitle.Render("Delete task log entry?"),
+			entryDetails,
+			m.style.formHelp.Render("Press y to delete, n/<esc> to cancel"),
+		)
+		content = lipgloss.Place(m.terminalWidth, m.terminalHeight-2, lipgloss.Center, lipgloss.Center, overlay)
+	case confirmDeleteTaskTLsView:
+		var taskDetails string
+		if task, ok := m.selectedActiveTask(); ok {
+			taskDetails = fmt.Sprintf("%s\n%s spent so far",
+				utils.Trim(task.Summary, 50),
+				types.HumanizeDuration(task.SecsSpent),
+			)
+		}
+		overlay := fmt.Sprintf("%s\n\n%s\n\n%s",
+			m.style.helpTitle.Render("Delete all task log entries for this task?"),
+			taskDetails,
+			m.style.formHelp.Render("Press y to delete, n/<esc> to cancel"),
+		)
+		content = lipgloss.Place(m.terminalWidth, m.terminalHeight-2, lipgloss.Center, lipgloss.Center, overlay)
+	case commandPaletteView:
+		overlay := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
+			m.style.helpTitle.Render("Command palette"),
+			m.commandPaletteInput.View(),
+			m.getCommandPaletteSuggestions(),
+			m.style.formHelp.Render("Press <enter> to run, <tab> to complete, <esc> to close"),
+		)
+		content = lipgloss.Place(m.terminalWidth, m.terminalHeight-2, lipgloss.Center, lipgloss.Center, overlay)
+	case searchTaskLogsView:
+		overlay := fmt.Sprintf("%s\n\n%s\n\n%s",
+			m.style.helpTitle.Render("Search task logs by comment"),
+			m.tLSearchInput.View(),
+			m.style.formHelp.Render("Press <enter> to search, <esc> to cancel"),
+		)
+		content = lipgloss.Place(m.terminalWidth, m.terminalHeight-2, lipgloss.Center, lipgloss.Center, overlay)
+	case weeklyTotalsView:
+		overlay := fmt.Sprintf("%s\n\n%s\n%s",
+			m.style.helpTitle.Render("This week"),
+			m.weeklyTotals,
+			m.style.formHelp.Render("Press <esc>/<q> to close"),
+		)
+		content = lipgloss.Place(m.terminalWidth, m.terminalHeight-2, lipgloss.Center, lipgloss.Center, overlay)
 	case helpView:
 		if !m.helpVPReady {
 			content = "\n  Initializing..."
@@ -304,9 +423,28 @@ func (m Model) View() string {
 		helpMsg += " " + m.style.helpMsg.Render("Press ? for help")
 	}
 
-	footer = fmt.Sprintf("%s%s%s",
+	var todayTotalMsg string
+	if m.todayTotalSecs > 0 {
+		todayTotalMsg = m.style.todayTotal.Render("today: " + m.durationFormat.Format(m.todayTotalSecs))
+	}
+
+	var weeklyGoalMsg string
+	if m.weeklyGoalSecs > 0 {
+		weeklyGoalStyle := m.style.todayTotal
+		if m.weekTotalSecs >= m.weeklyGoalSecs {
+			weeklyGoalStyle = m.style.weeklyGoalMet
+		}
+		weeklyGoalMsg = weeklyGoalStyle.Render(fmt.Sprintf("%s / %s this week",
+			m.durationFormat.Format(m.weekTotalSecs),
+			m.durationFormat.Format(m.weeklyGoalSecs),
+		))
+	}
+
+	footer = fmt.Sprintf("%s%s%s%s%s",
 		m.style.toolName.Render("hours"),
 		helpMsg,
+		todayTotalMsg,
+		weeklyGoalMsg,
 		activeMsg,
 	)
 
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dhth/hours/internal/utils"
+)
+
+const commandPaletteMaxSuggestions = 8
+
+// paletteAction is an action that can be run by name from the command
+// palette. arg holds whatever was typed after the action's name.
+type paletteAction struct {
+	name        string
+	usage       string
+	description string
+	run         func(m *Model, arg string) tea.Cmd
+}
+
+var paletteActions = []paletteAction{
+	{name: "start", usage: "[task]", description: "Start tracking time on the selected (or named) task", run: (*Model).runPaletteStart},
+	{name: "stop", description: "Stop tracking time and save the active task log", run: (*Model).runPaletteStop},
+	{name: "finish", description: "Finish the active task log without opening the form", run: (*Model).runPaletteFinish},
+	{name: "switch-to", usage: "<task>", description: "Quick switch tracking to the named task", run: (*Model).runPaletteSwitchTo},
+	{name: "create", usage: "[summary]", description: "Add a task", run: (*Model).runPaletteCreate},
+	{name: "archive", usage: "[task]", description: "Deactivate the selected (or named) task", run: (*Model).runPaletteArchive},
+	{name: "archive-stale", description: "Archive all tasks with no log entries in the last 2 weeks", run: (*Model).runPaletteArchiveStale},
+	{name: "undo", description: "Undo the last task log deletion, move, or edit", run: (*Model).runPaletteUndo},
+	{name: "help", description: "Show help view", run: (*Model).runPaletteHelp},
+}
+
+// splitPaletteInput splits palette input into the action name and its
+// argument.
+func splitPaletteInput(input string) (string, string) {
+	input = strings.TrimSpace(input)
+	name, arg, _ := strings.Cut(input, " ")
+	return name, strings.TrimSpace(arg)
+}
+
+// matchPaletteActions returns the actions whose names fuzzy match query, best
+// match first. Exact name matches come first, followed by names that start
+// with query; an empty query matches all actions.
+func matchPaletteActions(query string) []paletteAction {
+	if query == "" {
+		return paletteActions
+	}
+
+	names := make([]string, len(paletteActions))
+	for i, action := range paletteActions {
+		names[i] = action.name
+	}
+
+	ranks := list.DefaultFilter(query, names)
+	closeness := func(name string) int {
+		switch {
+		case name == query:
+			return 0
+		case strings.HasPrefix(name, query):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(ranks, func(i, j int) bool {
+		return closeness(names[ranks[i].Index]) < closeness(names[ranks[j].Index])
+	})
+
+	matches := make([]paletteAction, len(ranks))
+	for i, rank := range ranks {
+		matches[i] = paletteActions[rank.Index]
+	}
+
+	return matches
+}
+
+// lookupPaletteActions returns the actions the name in input refers to, along
+// with the argument to run them with. A name refers to the action with exactly
+// that name, or else to every action whose name starts with it; unlike the
+// suggestions, it never fuzzy matches.
+func lookupPaletteActions(input string) ([]paletteAction, string) {
+	name, arg := splitPaletteInput(input)
+	if name == "" {
+		return nil, ""
+	}
+
+	var matches []paletteAction
+	for _, action := range paletteActions {
+		if action.name == name {
+			return []paletteAction{action}, arg
+		}
+		if strings.HasPrefix(action.name, name) {
+			matches = append(matches, action)
+		}
+	}
+
+	return matches, arg
+}
+
+func (m *Model) handleRequestToOpenCommandPalette() {
+	m.lastView = m.activeView
+	m.activeView = commandPaletteView
+	m.commandPaletteInput.SetValue("")
+	m.commandPaletteInput.Focus()
+}
+
+func (m *Model) closeCommandPalette() {
+	m.activeView = m.lastView
+	m.commandPaletteInput.Blur()
+	m.commandPaletteInput.SetValue("")
+}
+
+// completePaletteInput replaces the action name in the palette input with the
+// name of the best matching action.
+func (m *Model) completePaletteInput() {
+	name, arg := splitPaletteInput(m.commandPaletteInput.Value())
+	if name == "" {
+		return
+	}
+
+	matches := matchPaletteActions(name)
+	if len(matches) == 0 {
+		return
+	}
+
+	m.commandPaletteInput.SetValue(matches[0].name + " " + arg)
+	m.commandPaletteInput.CursorEnd()
+}
+
+func (m *Model) getCmdToRunPaletteCommand() tea.Cmd {
+	input := m.commandPaletteInput.Value()
+	m.closeCommandPalette()
+
+	name, _ := splitPaletteInput(input)
+	if name == "" {
+		return nil
+	}
+
+	actions, arg := lookupPaletteActions(input)
+	switch len(actions) {
+	case 0:
+		m.message = errMsg(fmt.Sprintf("Unknown command: %q", name))
+		return nil
+	case 1:
+		return actions[0].run(m, arg)
+	default:
+		names := make([]string, len(actions))
+		for i, action := range actions {
+			names[i] = action.name
+		}
+		m.message = errMsg(fmt.Sprintf("Ambiguous command: %q (could be %s)", name, strings.Join(names, ", ")))
+		return nil
+	}
+}
+
+// selectActiveTaskMatching selects the active task whose summary best fuzzy
+// matches query. If query is empty, the current selection is left as is.
+func (m *Model) selectActiveTaskMatching(query string) bool {
+	if query == "" {
+		return true
+	}
+
+	if m.activeTasksList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return false
+	}
+
+	items := m.activeTasksList.Items()
+	summaries := make([]string, len(items))
+	for i, item := range items {
+		summaries[i] = item.FilterValue()
+	}
+
+	ranks := list.DefaultFilter(query, summaries)
+	if len(ranks) == 0 {
+		m.message = errMsg(fmt.Sprintf("No active task matches %q", query))
+		return false
+	}
+
+	m.activeTasksList.Select(ranks[0].Index)
+	return true
+}
+
+func (m *Model) runPaletteStart(arg string) tea.Cmd {
+	if m.trackingActive {
+		m.message = errMsg("A task is already being tracked; stop it first")
+		return nil
+	}
+
+	if !m.selectActiveTaskMatching(arg) {
+		return nil
+	}
+
+	m.activeView = taskListView
+	return m.getCmdToStartTracking()
+}
+
+func (m *Model) runPaletteStop(_ string) tea.Cmd {
+	if !m.trackingActive {
+		m.message = errMsg("Nothing is being tracked right now")
+		return nil
+	}
+
+	m.handleRequestToStopTracking()
+	return nil
+}
+
+func (m *Model) runPaletteFinish(_ string) tea.Cmd {
+	if !m.trackingActive {
+		m.message = errMsg("Nothing is being tracked right now")
+		return nil
+	}
+
+	return m.getCmdToFinishActiveTL()
+}
+
+func (m *Model) runPaletteSwitchTo(arg string) tea.Cmd {
+	if arg == "" {
+		m.message = errMsg("switch-to needs the task to switch to")
+		return nil
+	}
+
+	if !m.selectActiveTaskMatching(arg) {
+		return nil
+	}
+
+	m.activeView = taskListView
+	return m.getCmdToQuickSwitchTracking()
+}
+
+func (m *Model) runPaletteCreate(arg string) tea.Cmd {
+	m.activeView = taskListView
+	m.handleRequestToCreateTask()
+	if m.activeView == taskInputView && arg != "" {
+		m.taskInputs[summaryField].SetValue(arg)
+	}
+
+	return nil
+}
+
+func (m *Model) runPaletteArchive(arg string) tea.Cmd {
+	if !m.selectActiveTaskMatching(arg) {
+		return nil
+	}
+
+	m.activeView = taskListView
+	return m.getCmdToDeactivateTask()
+}
+
+func (m *Model) runPaletteArchiveStale(_ string) tea.Cmd {
+	m.activeView = taskListView
+	twoWeeksAgo := m.timeProvider.Now().AddDate(0, 0, -14)
+	return archiveStaleTasks(m.db, twoWeeksAgo)
+}
+
+func (m *Model) runPaletteUndo(_ string) tea.Cmd {
+	return m.getCmdToUndoLastTLAction()
+}
+
+func (m *Model) runPaletteHelp(_ string) tea.Cmd {
+	m.lastView = m.activeView
+	m.activeView = helpView
+	return nil
+}
+
+func (m *Model) getCommandPaletteSuggestions() string {
+	name, _ := splitPaletteInput(m.commandPaletteInput.Value())
+	matches := matchPaletteActions(name)
+	if len(matches) == 0 {
+		return m.style.formHelp.Render("No matching commands")
+	}
+
+	var sb strings.Builder
+	for i, action := range matches {
+		if i == commandPaletteMaxSuggestions {
+			break
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s  %s",
+			utils.RightPadTrim(strings.TrimSpace(action.name+" "+action.usage), 24, true),
+			m.style.formHelp.Render(action.description),
+		))
+	}
+
+	return sb.String()
+}
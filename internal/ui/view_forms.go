@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 )
 
@@ -27,6 +28,30 @@ func (m *Model) getCmdToUpdateActiveTL() tea.Cmd {
 	return updateActiveTL(m.db, beginTS, comment)
 }
 
+func (m *Model) getCmdToStartTrackingWithComment() tea.Cmd {
+	beginTS, err := time.ParseInLocation(timeFormat, m.tLInputs[entryBeginTS].Value(), time.Local)
+	if err != nil {
+		m.message = errMsgQuick(err.Error())
+		return nil
+	}
+
+	if beginTS.After(m.timeProvider.Now()) {
+		m.message = errMsgQuick(beginTsCannotBeInTheFutureMsg)
+		return nil
+	}
+
+	task, ok := m.selectedActiveTask()
+	if !ok {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	comment := commentPtrFromInput(m.tLCommentInput)
+
+	m.activeView = taskListView
+	return m.getCmdToStartTrackingTaskWithComment(task.ID, beginTS, comment)
+}
+
 func (m *Model) getCmdToFinishTrackingActiveTL() tea.Cmd {
 	beginTS, endTS, err := types.ParseTaskLogTimes(m.tLInputs[entryBeginTS].Value(), m.tLInputs[entryEndTS].Value())
 	if err != nil {
@@ -38,10 +63,40 @@ func (m *Model) getCmdToFinishTrackingActiveTL() tea.Cmd {
 	m.activeTLEndTS = endTS
 
 	comment := commentPtrFromInput(m.tLCommentInput)
+	category := categoryPtrFromInput(m.tLInputs[entryCategory])
+	tags := types.ParseLogTags(m.tLInputs[entryTags].Value())
 
 	m.activeView = taskListView
 
-	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, comment)
+	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, comment, category, tags, m.finishTLOptions())
+}
+
+// getCmdToClampEndTSToNextTL returns a command to look up the first saved
+// entry for the active task that the log being finished would overlap with,
+// so that its end time can be moved back to that entry's begin time.
+func (m *Model) getCmdToClampEndTSToNextTL() tea.Cmd {
+	beginTS, err := time.ParseInLocation(timeFormat, m.tLInputs[entryBeginTS].Value(), time.Local)
+	if err != nil {
+		m.message = errMsg(fmt.Sprintf("Begin time is invalid: %s", err.Error()))
+		return nil
+	}
+
+	endTS, err := time.ParseInLocation(timeFormat, m.tLInputs[entryEndTS].Value(), time.Local)
+	if err != nil {
+		m.message = errMsg(fmt.Sprintf("End time is invalid: %s", err.Error()))
+		return nil
+	}
+
+	return fetchFirstOverlappingTL(m.db, m.activeTaskID, beginTS, endTS)
+}
+
+// finishTLOptions returns the adjustments made to task log entries finished
+// via the TUI.
+func (m *Model) finishTLOptions() pers.FinishTLOptions {
+	return pers.FinishTLOptions{
+		MergeSameDay: m.mergeSameDay,
+		RoundTo:      m.roundTo,
+	}
 }
 
 func (m *Model) getCmdToFinishActiveTL() tea.Cmd {
@@ -60,7 +115,7 @@ func (m *Model) getCmdToFinishActiveTL() tea.Cmd {
 
 	m.activeTLEndTS = now
 
-	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, m.activeTLComment)
+	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, m.activeTLComment, nil, nil, m.finishTLOptions())
 }
 
 func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
@@ -71,9 +126,13 @@ func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
 	}
 
 	comment := commentPtrFromInput(m.tLCommentInput)
+	category := categoryPtrFromInput(m.tLInputs[entryCategory])
+	tags := types.ParseLogTags(m.tLInputs[entryTags].Value())
 
 	m.blurTLTrackingInputs()
 	m.tLCommentInput.SetValue("")
+	m.tLInputs[entryTags].SetValue("")
+	m.tLInputs[entryCategory].SetValue("")
 	m.activeTLComment = nil
 
 	var cmd tea.Cmd
@@ -85,7 +144,7 @@ func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
 			m.message = errMsg(genericErrorMsg)
 			return nil
 		}
-		cmd = insertManualTL(m.db, task.ID, beginTS, endTS, comment)
+		cmd = insertManualTL(m.db, task.ID, beginTS, endTS, comment, category, tags, m.roundTo)
 	case tasklogUpdate:
 		m.activeView = taskLogView
 		tl, ok := m.selectedTaskLogEntry()
@@ -93,7 +152,7 @@ func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
 			m.message = errMsg(genericErrorMsg)
 			return nil
 		}
-		cmd = editSavedTL(m.db, tl.ID, tl.TaskID, beginTS, endTS, comment)
+		cmd = editSavedTL(m.db, tl, beginTS, endTS, comment, category, tags)
 	}
 
 	return cmd
@@ -114,6 +173,21 @@ func (m *Model) handleRequestToEditActiveTL() {
 	m.trackingFocussedField = entryBeginTS
 }
 
+func (m *Model) handleRequestToStartTrackingWithComment() {
+	if _, ok := m.selectedActiveTask(); !ok {
+		m.message = errMsg(genericErrorMsg)
+		return
+	}
+
+	m.clearAllTaskLogInputs()
+	m.activeView = startTrackingView
+	m.tLInputs[entryBeginTS].SetValue(m.timeProvider.Now().Format(timeFormat))
+
+	m.blurTLTrackingInputs()
+	m.tLCommentInput.Focus()
+	m.trackingFocussedField = entryComment
+}
+
 func (m *Model) handleRequestToCreateManualTL() {
 	m.clearAllTaskLogInputs()
 	m.activeView = manualTasklogEntryView
@@ -158,8 +232,13 @@ func (m *Model) handleEscapeInForms() {
 	case editActiveTLView:
 		m.tLInputs[entryBeginTS].SetValue("")
 		m.activeView = taskListView
+	case startTrackingView:
+		m.activeView = taskListView
+		m.clearAllTaskLogInputs()
 	case finishActiveTLView:
 		m.activeView = taskListView
+		m.tLInputs[entryTags].SetValue("")
+		m.tLInputs[entryCategory].SetValue("")
 		m.tLCommentInput.SetValue("")
 	case manualTasklogEntryView:
 		if m.tasklogSaveType == tasklogInsert {
@@ -167,7 +246,7 @@ func (m *Model) handleEscapeInForms() {
 		}
 	case editSavedTLView:
 		m.activeView = taskLogView
-	case moveTaskLogView:
+	case moveTaskLogView, filterTaskLogView:
 		m.activeView = taskLogView
 		m.targetTasksList.ResetFilter()
 	}
@@ -175,13 +254,15 @@ func (m *Model) handleEscapeInForms() {
 
 func (m *Model) goForwardInView() {
 	switch m.activeView {
+	case taskInputView:
+		m.toggleTaskInputFocus()
 	case taskListView:
-		m.activeView = taskLogView
+		m.switchToListView(taskLogView)
 	case taskLogView:
-		m.activeView = inactiveTaskListView
+		m.switchToListView(inactiveTaskListView)
 	case inactiveTaskListView:
-		m.activeView = taskListView
-	case editActiveTLView:
+		m.switchToListView(taskListView)
+	case editActiveTLView, startTrackingView:
 		switch m.trackingFocussedField {
 		case entryBeginTS:
 			m.trackingFocussedField = entryComment
@@ -199,8 +280,16 @@ func (m *Model) goForwardInView() {
 			m.tLInputs[entryBeginTS].Blur()
 			m.tLInputs[entryEndTS].Focus()
 		case entryEndTS:
-			m.trackingFocussedField = entryComment
+			m.trackingFocussedField = entryTags
 			m.tLInputs[entryEndTS].Blur()
+			m.tLInputs[entryTags].Focus()
+		case entryTags:
+			m.trackingFocussedField = entryCategory
+			m.tLInputs[entryTags].Blur()
+			m.tLInputs[entryCategory].Focus()
+		case entryCategory:
+			m.trackingFocussedField = entryComment
+			m.tLInputs[entryCategory].Blur()
 			m.tLCommentInput.Focus()
 		case entryComment:
 			m.trackingFocussedField = entryBeginTS
@@ -212,13 +301,15 @@ func (m *Model) goForwardInView() {
 
 func (m *Model) goBackwardInView() {
 	switch m.activeView {
+	case taskInputView:
+		m.toggleTaskInputFocus()
 	case taskLogView:
-		m.activeView = taskListView
+		m.switchToListView(taskListView)
 	case taskListView:
-		m.activeView = inactiveTaskListView
+		m.switchToListView(inactiveTaskListView)
 	case inactiveTaskListView:
-		m.activeView = taskLogView
-	case editActiveTLView:
+		m.switchToListView(taskLogView)
+	case editActiveTLView, startTrackingView:
 		switch m.trackingFocussedField {
 		case entryBeginTS:
 			m.trackingFocussedField = entryComment
@@ -239,9 +330,17 @@ func (m *Model) goBackwardInView() {
 			m.trackingFocussedField = entryBeginTS
 			m.tLInputs[entryBeginTS].Focus()
 			m.tLInputs[entryEndTS].Blur()
-		case entryComment:
+		case entryTags:
 			m.trackingFocussedField = entryEndTS
 			m.tLInputs[entryEndTS].Focus()
+			m.tLInputs[entryTags].Blur()
+		case entryCategory:
+			m.trackingFocussedField = entryTags
+			m.tLInputs[entryTags].Focus()
+			m.tLInputs[entryCategory].Blur()
+		case entryComment:
+			m.trackingFocussedField = entryCategory
+			m.tLInputs[entryCategory].Focus()
 			m.tLCommentInput.Blur()
 		}
 	}
@@ -255,7 +354,13 @@ func (m *Model) shiftTime(direction types.TimeShiftDirection, duration types.Tim
 			return err
 		}
 
-		newTs := types.GetShiftedTime(ts, direction, duration)
+		var newTs time.Time
+		if duration == types.ShiftFiveMinutes {
+			// the "five minute" step can be changed via --shift-step
+			newTs = types.ShiftTimeBy(ts, direction, m.shiftStep)
+		} else {
+			newTs = types.GetShiftedTime(ts, direction, duration)
+		}
 
 		m.tLInputs[m.trackingFocussedField].SetValue(newTs.Format(timeFormat))
 	}
@@ -269,3 +374,20 @@ func (m *Model) clearAllTaskLogInputs() {
 	}
 	m.tLCommentInput.SetValue("")
 }
+
+// toggleTaskInputFocus moves focus between the summary and the creation time
+// fields of the task form. The creation time can only be changed for existing
+// tasks.
+func (m *Model) toggleTaskInputFocus() {
+	if m.taskMgmtContext != taskUpdateCxt {
+		return
+	}
+
+	m.taskInputs[m.taskInputFocussedField].Blur()
+	if m.taskInputFocussedField == summaryField {
+		m.taskInputFocussedField = createdAtField
+	} else {
+		m.taskInputFocussedField = summaryField
+	}
+	m.taskInputs[m.taskInputFocussedField].Focus()
+}
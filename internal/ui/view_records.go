@@ -8,6 +8,10 @@ import (
 	"github.com/dhth/hours/internal/types"
 )
 
+const recordsLoadingIndicator = `
+ loading…
+`
+
 func (m recordsModel) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("Something went wrong: %s\n", m.err)
@@ -16,7 +20,11 @@ func (m recordsModel) View() string {
 
 	var dateRangeStr string
 	var dateRange string
-	if m.dateRange.NumDays > 1 {
+	if m.allTime {
+		dateRangeStr = `
+ range:             all time
+`
+	} else if m.dateRange.NumDays > 1 {
 		dateRangeStr = fmt.Sprintf(`
  range:             %s...%s
  `,
@@ -31,20 +39,34 @@ func (m recordsModel) View() string {
 	helpStr := `
  go backwards:      h or <-
  go forwards:       l or ->
+ week backwards:    [
+ week forwards:     ]
  go to today:       ctrl+t
+ all time:          a
+ back to period:    p
 
  press ctrl+c/q to quit
 `
 
+	// shown while the data for a newly requested period is being fetched, as
+	// that can take a while for large ranges
+	var loading string
+	if m.busy {
+		loading = recordsLoadingIndicator
+	}
+
 	if m.plain {
 		help = helpStr
 		dateRange = dateRangeStr
 	} else {
 		help = m.style.recordsHelp.Render(helpStr)
 		dateRange = m.style.recordsDateRange.Render(dateRangeStr)
+		if loading != "" {
+			loading = m.style.recordsDateRange.Render(loading)
+		}
 	}
 
-	return fmt.Sprintf("%s%s%s", m.report, dateRange, help)
+	return fmt.Sprintf("%s%s%s%s", m.report, dateRange, loading, help)
 }
 
 func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -71,7 +93,7 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, &dr, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
 				m.busy = true
 			}
 		case "right", "l":
@@ -92,7 +114,23 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, &dr, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
+				m.busy = true
+			}
+		case "[", "]":
+			// unlike h/l, these move the range by a week, whatever its length
+			if !m.busy {
+				days := 7
+				if msg.String() == "[" {
+					days = -7
+				}
+
+				dr := types.DateRange{
+					Start:   m.dateRange.Start.AddDate(0, 0, days),
+					NumDays: m.dateRange.NumDays,
+				}
+				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, &dr, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
 				m.busy = true
 			}
 		case "ctrl+t":
@@ -115,7 +153,18 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, &dr, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
+				m.busy = true
+			}
+		case "a":
+			if !m.busy && !m.allTime {
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, nil, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
+				m.busy = true
+			}
+		case "p":
+			if !m.busy && m.allTime {
+				dr := m.dateRange
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, &dr, m.taskStatus, m.tag, m.logTag, m.taskID, m.limit, m.plain))
 				m.busy = true
 			}
 		}
@@ -126,7 +175,10 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		m.dateRange = msg.dateRange
+		if msg.dateRange != nil {
+			m.dateRange = *msg.dateRange
+		}
+		m.allTime = msg.dateRange == nil
 		m.report = msg.report
 		m.busy = false
 	}
@@ -1,11 +1,16 @@
 package ui
 
 import (
+	"cmp"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dhth/hours/internal/types"
 )
@@ -32,6 +37,80 @@ func (m *Model) goToActiveTask() {
 	m.activeTasksList.Select(activeIndex)
 }
 
+func (o taskSortOrder) String() string {
+	switch o {
+	case taskSortByTimeSpent:
+		return "most time spent"
+	case taskSortAlphabetically:
+		return "summary"
+	default:
+		return "most recent update"
+	}
+}
+
+func (m *Model) handleRequestToCycleTaskSortOrder() {
+	if m.activeView != taskListView {
+		return
+	}
+
+	if m.activeTasksList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return
+	}
+
+	switch m.taskSortOrder {
+	case taskSortByRecency:
+		m.taskSortOrder = taskSortByTimeSpent
+	case taskSortByTimeSpent:
+		m.taskSortOrder = taskSortAlphabetically
+	default:
+		m.taskSortOrder = taskSortByRecency
+	}
+
+	m.sortActiveTasks()
+	m.message = infoMsg("Sorting tasks by " + m.taskSortOrder.String())
+}
+
+// sortActiveTasks orders the active tasks list as per m.taskSortOrder, keeping
+// the selected task selected, and taskIndexMap in sync with the new order.
+func (m *Model) sortActiveTasks() {
+	selectedTaskID := -1
+	if task, ok := m.selectedActiveTask(); ok {
+		selectedTaskID = task.ID
+	}
+
+	items := slices.Clone(m.activeTasksList.Items())
+	slices.SortStableFunc(items, func(a, b list.Item) int {
+		taskA, aOk := a.(*types.Task)
+		taskB, bOk := b.(*types.Task)
+		if !aOk || !bOk {
+			return 0
+		}
+
+		switch m.taskSortOrder {
+		case taskSortByTimeSpent:
+			return cmp.Compare(taskB.SecsSpent, taskA.SecsSpent)
+		case taskSortAlphabetically:
+			return cmp.Compare(strings.ToLower(taskA.Summary), strings.ToLower(taskB.Summary))
+		default:
+			return taskB.UpdatedAt.Compare(taskA.UpdatedAt)
+		}
+	})
+
+	m.activeTasksList.SetItems(items)
+	m.taskIndexMap = make(map[int]int, len(items))
+	for i, item := range items {
+		task, ok := item.(*types.Task)
+		if !ok {
+			continue
+		}
+		m.taskIndexMap[task.ID] = i
+		if task.ID == selectedTaskID {
+			m.activeTasksList.Select(i)
+		}
+	}
+}
+
 func (m *Model) handleRequestToCreateTask() {
 	if m.activeTasksList.IsFiltered() {
 		m.message = errMsg(removeFilterMsg)
@@ -41,6 +120,7 @@ func (m *Model) handleRequestToCreateTask() {
 	m.activeView = taskInputView
 	m.taskInputFocussedField = summaryField
 	m.taskInputs[summaryField].Focus()
+	m.taskInputs[createdAtField].Blur()
 	m.taskMgmtContext = taskCreateCxt
 }
 
@@ -60,19 +140,44 @@ func (m *Model) handleRequestToUpdateTask() {
 	m.taskInputFocussedField = summaryField
 	m.taskInputs[summaryField].Focus()
 	m.taskInputs[summaryField].SetValue(task.Summary)
+	m.taskInputs[createdAtField].Blur()
+	m.taskInputs[createdAtField].SetValue(task.CreatedAt.Format(timeFormat))
 	m.taskMgmtContext = taskUpdateCxt
 }
 
+// handleRequestToCloneTask creates a new task with the same summary as the
+// selected one. The clone starts out with no time tracked against it.
+func (m *Model) handleRequestToCloneTask() tea.Cmd {
+	if m.activeTasksList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return nil
+	}
+
+	task, ok := m.selectedActiveTask()
+	if !ok {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	return createTask(m.db, task.Summary)
+}
+
 func (m *Model) getCmdToCreateOrUpdateTask() tea.Cmd {
-	if strings.TrimSpace(m.taskInputs[summaryField].Value()) == "" {
+	// the form stays open, so that the summary can be fixed
+	summary := strings.TrimSpace(m.taskInputs[summaryField].Value())
+	if summary == "" {
 		m.message = errMsg("Task summary cannot be empty")
 		return nil
 	}
+	if utf8.RuneCountInString(summary) > m.maxSummaryLength {
+		m.message = errMsg(fmt.Sprintf("Task summary cannot be longer than %d characters", m.maxSummaryLength))
+		return nil
+	}
 
 	var cmd tea.Cmd
 	switch m.taskMgmtContext {
 	case taskCreateCxt:
-		cmd = createTask(m.db, m.taskInputs[summaryField].Value())
+		cmd = createTask(m.db, summary)
 		m.taskInputs[summaryField].SetValue("")
 	case taskUpdateCxt:
 		selectedTask, ok := m.selectedActiveTask()
@@ -80,8 +185,24 @@ func (m *Model) getCmdToCreateOrUpdateTask() tea.Cmd {
 			m.message = errMsg("Something went wrong")
 			return nil
 		}
-		cmd = updateTask(m.db, selectedTask, m.taskInputs[summaryField].Value())
+
+		var createdAt *time.Time
+		if strings.TrimSpace(m.taskInputs[createdAtField].Value()) != selectedTask.CreatedAt.Format(timeFormat) {
+			ts, err := types.ParseTaskLogTime(m.taskInputs[createdAtField].Value())
+			if err != nil {
+				m.message = errMsg(err.Error())
+				return nil
+			}
+			if ts.After(m.timeProvider.Now()) {
+				m.message = errMsg(createdAtCannotBeInTheFutureMsg)
+				return nil
+			}
+			createdAt = &ts
+		}
+
+		cmd = updateTask(m.db, selectedTask, summary, createdAt)
 		m.taskInputs[summaryField].SetValue("")
+		m.taskInputs[createdAtField].SetValue("")
 	}
 
 	m.activeView = taskListView
@@ -111,6 +232,12 @@ func (m *Model) normalizedTrackingTS(ts time.Time) time.Time {
 }
 
 func (m *Model) getCmdToStartTrackingTaskAt(taskID int, startedAt time.Time) tea.Cmd {
+	return m.getCmdToStartTrackingTaskWithComment(taskID, startedAt, nil)
+}
+
+// getCmdToStartTrackingTaskWithComment starts tracking a task; if comment is
+// non-nil, it's set on the new task log right away.
+func (m *Model) getCmdToStartTrackingTaskWithComment(taskID int, startedAt time.Time, comment *string) tea.Cmd {
 	if _, ok := m.taskMap[taskID]; !ok {
 		m.message = errMsg(genericErrorMsg)
 		return nil
@@ -123,7 +250,10 @@ func (m *Model) getCmdToStartTrackingTaskAt(taskID int, startedAt time.Time) tea
 	m.autoResumeAt = time.Time{}
 	m.changesLocked = true
 	m.activeTLBeginTS = m.normalizedTrackingTS(startedAt)
-	return toggleTracking(m.db, taskID, m.activeTLBeginTS, m.activeTLEndTS, nil)
+	if comment != nil {
+		return startTrackingWithComment(m.db, taskID, m.activeTLBeginTS, comment)
+	}
+	return toggleTracking(m.db, taskID, m.activeTLBeginTS, m.activeTLEndTS, nil, nil, nil, m.finishTLOptions())
 }
 
 func (m *Model) getCmdToQuickSwitchTracking() tea.Cmd {
@@ -141,7 +271,66 @@ func (m *Model) getCmdToQuickSwitchTracking() tea.Cmd {
 		return m.getCmdToStartTrackingTask(task.ID)
 	}
 
-	return quickSwitchActiveIssue(m.db, task.ID, m.timeProvider.Now())
+	return quickSwitchActiveIssue(m.db, task.ID, m.timeProvider.Now(), m.finishTLOptions())
+}
+
+// getCmdToCycleActiveTLComment fetches the active task's recent comments so
+// that the next (or previous) one can be applied to the active task log.
+func (m *Model) getCmdToCycleActiveTLComment(forward bool) tea.Cmd {
+	if !m.trackingActive {
+		m.message = errMsg("Nothing is being tracked right now")
+		return nil
+	}
+
+	if m.changesLocked {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	return fetchRecentComments(m.db, m.activeTaskID, forward)
+}
+
+// getCmdToNudgeActiveTLBeginTS moves the begin time of the active task log by
+// a minute without going through the "edit active task log" form.
+func (m *Model) getCmdToNudgeActiveTLBeginTS(direction types.TimeShiftDirection) tea.Cmd {
+	if !m.trackingActive {
+		m.message = errMsg("Nothing is being tracked right now")
+		return nil
+	}
+
+	if m.changesLocked {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	beginTS := types.GetShiftedTime(m.activeTLBeginTS, direction, types.ShiftMinute)
+	if beginTS.After(m.timeProvider.Now()) {
+		m.message = errMsgQuick(beginTsCannotBeInTheFutureMsg)
+		return nil
+	}
+
+	return updateActiveTL(m.db, beginTS, m.activeTLComment)
+}
+
+// nextRecentComment returns the comment that comes after (or before) the
+// current one in comments, wrapping around at either end. If current isn't
+// one of comments, cycling starts from the first (or last) one.
+func nextRecentComment(comments []string, current *string, forward bool) string {
+	index := -1
+	if current != nil {
+		index = slices.Index(comments, *current)
+	}
+
+	switch {
+	case index == -1 && forward:
+		return comments[0]
+	case index == -1:
+		return comments[len(comments)-1]
+	case forward:
+		return comments[(index+1)%len(comments)]
+	default:
+		return comments[(index-1+len(comments))%len(comments)]
+	}
 }
 
 func (m *Model) getCmdToAutoStopTrackingAt(stoppedAt time.Time) tea.Cmd {
@@ -157,7 +346,7 @@ func (m *Model) getCmdToAutoStopTrackingAt(stoppedAt time.Time) tea.Cmd {
 	m.changesLocked = true
 	m.activeTLEndTS = m.normalizedTrackingTS(stoppedAt)
 
-	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, m.activeTLComment)
+	return toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, m.activeTLComment, nil, nil, m.finishTLOptions())
 }
 
 func (m *Model) getCmdToResumeAutoStoppedTaskAt(resumedAt time.Time) tea.Cmd {
@@ -204,6 +393,40 @@ func (m *Model) getCmdToDeactivateTask() tea.Cmd {
 	return updateTaskActiveStatus(m.db, task, false)
 }
 
+func (m *Model) handleRequestToDeleteTaskTLs() {
+	if m.activeTasksList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return
+	}
+
+	if _, ok := m.selectedActiveTask(); !ok {
+		m.message = errMsg(msgCouldntSelectATask)
+		return
+	}
+
+	m.activeView = confirmDeleteTaskTLsView
+}
+
+// handleConfirmDeleteTaskTLsKeys handles key events while
+// confirmDeleteTaskTLsView is active: y deletes all saved task logs for the
+// selected task, n/esc/q cancel.
+func (m *Model) handleConfirmDeleteTaskTLsKeys(keyMsg tea.KeyMsg) []tea.Cmd {
+	switch keyMsg.String() {
+	case "y":
+		m.activeView = taskListView
+		task, ok := m.selectedActiveTask()
+		if !ok {
+			m.message = errMsg(msgCouldntSelectATask)
+			return nil
+		}
+		return []tea.Cmd{deleteAllTLsForTask(m.db, task.ID)}
+	case "n", "q", escape:
+		m.activeView = taskListView
+	}
+
+	return nil
+}
+
 func (m *Model) handleCopyTaskSummary() {
 	var selectedTask *types.Task
 	var ok bool
@@ -2,12 +2,54 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/aymanbagabas/go-osc52/v2"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dhth/hours/internal/types"
 )
 
+// getCmdToRefreshTLS refetches the task log list, keeping the task filter
+// applied via handleRequestToFilterTaskLogByTask or the comment search applied
+// via getCmdToSearchTLsByComment, if any.
+func (m *Model) getCmdToRefreshTLS(tlIDToFocusOn *int) tea.Cmd {
+	if m.taskLogCommentQuery != "" {
+		return fetchTLSMatchingComment(m.db, m.taskLogCommentQuery, tlIDToFocusOn)
+	}
+	if m.taskLogFilterTaskID != -1 {
+		return fetchTLSForTask(m.db, m.taskLogFilterTaskID, tlIDToFocusOn)
+	}
+	return fetchTLS(m.db, tlIDToFocusOn)
+}
+
+func (m *Model) handleRequestToDeleteTL() {
+	if _, ok := m.selectedTaskLogEntry(); !ok {
+		m.message = errMsg("Couldn't delete task log entry")
+		return
+	}
+
+	m.activeView = confirmDeleteTLView
+}
+
+// handleConfirmDeleteTLKeys handles key events while confirmDeleteTLView is
+// active: y deletes the selected task log, n/esc/q cancel.
+func (m *Model) handleConfirmDeleteTLKeys(keyMsg tea.KeyMsg) []tea.Cmd {
+	switch keyMsg.String() {
+	case "y":
+		m.activeView = taskLogView
+		if cmd := m.getCmdToDeleteTL(); cmd != nil {
+			return []tea.Cmd{cmd}
+		}
+	case "n", "q", escape:
+		m.activeView = taskLogView
+	}
+
+	return nil
+}
+
 func (m *Model) getCmdToDeleteTL() tea.Cmd {
 	entry, ok := m.selectedTaskLogEntry()
 	if !ok {
@@ -17,6 +59,25 @@ func (m *Model) getCmdToDeleteTL() tea.Cmd {
 	return deleteTL(m.db, &entry)
 }
 
+// getCmdToDuplicateTL saves a copy of the selected task log entry, with the
+// same duration and comment, that ends now.
+func (m *Model) getCmdToDuplicateTL() tea.Cmd {
+	if m.taskLogList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return nil
+	}
+
+	entry, ok := m.selectedTaskLogEntry()
+	if !ok {
+		m.message = errMsg("No task log entry selected")
+		return nil
+	}
+
+	endTS := m.timeProvider.Now().Truncate(time.Second)
+	beginTS := endTS.Add(-time.Duration(entry.SecsSpent) * time.Second)
+	return duplicateTL(m.db, entry, beginTS, endTS)
+}
+
 func (m *Model) handleRequestToEditSavedTL() {
 	if len(m.taskLogList.Items()) == 0 {
 		return
@@ -41,6 +102,12 @@ func (m *Model) handleRequestToEditSavedTL() {
 
 	m.tLInputs[entryBeginTS].SetValue(beginTimeStr)
 	m.tLInputs[entryEndTS].SetValue(endTimeStr)
+	m.tLInputs[entryTags].SetValue(strings.Join(tl.Tags, ", "))
+	if tl.Category != nil {
+		m.tLInputs[entryCategory].SetValue(*tl.Category)
+	} else {
+		m.tLInputs[entryCategory].SetValue("")
+	}
 	m.tLCommentInput.SetValue(comment)
 
 	m.blurTLTrackingInputs()
@@ -48,22 +115,75 @@ func (m *Model) handleRequestToEditSavedTL() {
 	m.tLInputs[entryBeginTS].Focus()
 }
 
+// handleRequestToToggleTLSelection adds the highlighted task log entry to the
+// entries that get moved together, or removes it if it's already there.
+func (m *Model) handleRequestToToggleTLSelection() {
+	entry, ok := m.selectedTaskLogEntry()
+	if !ok {
+		m.message = errMsg("No task log entry selected")
+		return
+	}
+
+	if _, selected := m.selectedTLIDs[entry.ID]; selected {
+		delete(m.selectedTLIDs, entry.ID)
+	} else {
+		m.selectedTLIDs[entry.ID] = struct{}{}
+	}
+
+	if len(m.selectedTLIDs) == 0 {
+		m.message = infoMsg("No task log entries selected")
+		return
+	}
+	m.message = infoMsg(fmt.Sprintf("%d task log entries selected; press m to move them", len(m.selectedTLIDs)))
+}
+
+// markedTLEntries returns the entries in the task log list that have been
+// selected via handleRequestToToggleTLSelection.
+func (m *Model) markedTLEntries() []types.TaskLogEntry {
+	var entries []types.TaskLogEntry
+	for _, item := range m.taskLogList.Items() {
+		entry, ok := item.(types.TaskLogEntry)
+		if !ok {
+			continue
+		}
+		if _, selected := m.selectedTLIDs[entry.ID]; selected {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
 func (m *Model) handleRequestToMoveTaskLog() tea.Cmd {
 	if m.taskLogList.IsFiltered() {
 		m.message = errMsg(removeFilterMsg)
 		return nil
 	}
 
-	entry, ok := m.selectedTaskLogEntry()
-	if !ok {
-		m.message = errMsg(genericErrorMsg)
-		return nil
-	}
+	m.moveTLs = m.markedTLEntries()
 
-	// Store the log entry details
-	m.moveTLID = entry.ID
-	m.moveOldTaskID = entry.TaskID
-	m.moveSecsSpent = entry.SecsSpent
+	var parentTaskIDs map[int]struct{}
+	if len(m.moveTLs) > 0 {
+		// Only exclude the current parent if all selected entries share it
+		parentTaskIDs = make(map[int]struct{})
+		for _, entry := range m.moveTLs {
+			parentTaskIDs[entry.TaskID] = struct{}{}
+		}
+		if len(parentTaskIDs) > 1 {
+			parentTaskIDs = nil
+		}
+	} else {
+		entry, ok := m.selectedTaskLogEntry()
+		if !ok {
+			m.message = errMsg(genericErrorMsg)
+			return nil
+		}
+
+		// Store the log entry details
+		m.moveTLID = entry.ID
+		m.moveOldTaskID = entry.TaskID
+		m.moveSecsSpent = entry.SecsSpent
+		parentTaskIDs = map[int]struct{}{entry.TaskID: {}}
+	}
 
 	// Initialize target list with active tasks, excluding current parent
 	items := m.activeTasksList.Items()
@@ -74,7 +194,7 @@ func (m *Model) handleRequestToMoveTaskLog() tea.Cmd {
 			continue
 		}
 		// Exclude the current parent task
-		if task.ID != entry.TaskID {
+		if _, isParent := parentTaskIDs[task.ID]; !isParent {
 			targetItems = append(targetItems, task)
 		}
 	}
@@ -83,12 +203,66 @@ func (m *Model) handleRequestToMoveTaskLog() tea.Cmd {
 		return nil
 	}
 
+	m.targetTasksList.Title = "Select Target Task"
 	m.targetTasksList.SetItems(targetItems)
 
 	m.activeView = moveTaskLogView
 	return nil
 }
 
+// handleRequestToFilterTaskLogByTask lets the user pick a task whose entries
+// the task log list gets filtered to. If the list is already filtered, the
+// filter is removed instead.
+func (m *Model) handleRequestToFilterTaskLogByTask() tea.Cmd {
+	if m.taskLogFilterTaskID != -1 {
+		m.taskLogList.ResetSelected()
+		return fetchTLS(m.db, nil)
+	}
+
+	var targetItems []list.Item
+	for _, items := range [][]list.Item{m.activeTasksList.Items(), m.inactiveTasksList.Items()} {
+		for i := range items {
+			if task, ok := items[i].(*types.Task); ok {
+				targetItems = append(targetItems, task)
+			}
+		}
+	}
+	if len(targetItems) == 0 {
+		m.message = errMsg("No tasks to filter task logs by")
+		return nil
+	}
+
+	m.targetTasksList.Title = "Filter Task Logs By Task"
+	m.targetTasksList.SetItems(targetItems)
+
+	m.activeView = filterTaskLogView
+	return nil
+}
+
+// goToTodaysTLs selects the first entry in the task log list that ended
+// today.
+func (m *Model) goToTodaysTLs() {
+	if m.taskLogList.IsFiltered() {
+		m.taskLogList.ResetFilter()
+	}
+
+	now := m.timeProvider.Now()
+	year, month, day := now.Date()
+	for i, item := range m.taskLogList.Items() {
+		entry, ok := item.(types.TaskLogEntry)
+		if !ok {
+			continue
+		}
+		y, mo, d := entry.EndTS.In(now.Location()).Date()
+		if y == year && mo == month && d == day {
+			m.taskLogList.Select(i)
+			return
+		}
+	}
+
+	m.message = errMsg("No task log entries from today")
+}
+
 func (m *Model) handleTargetTaskSelection() tea.Cmd {
 	task, ok := m.selectedTargetTask()
 	if !ok {
@@ -96,9 +270,26 @@ func (m *Model) handleTargetTaskSelection() tea.Cmd {
 		return nil
 	}
 
+	if len(m.moveTLs) > 0 {
+		return moveTaskLogs(m.db, m.moveTLs, task.ID)
+	}
+
 	return moveTaskLog(m.db, m.moveTLID, m.moveOldTaskID, task.ID, m.moveSecsSpent)
 }
 
+func (m *Model) handleTaskLogFilterSelection() tea.Cmd {
+	task, ok := m.selectedTargetTask()
+	if !ok {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	m.activeView = taskLogView
+	m.targetTasksList.ResetFilter()
+	m.taskLogList.ResetSelected()
+	return fetchTLSForTask(m.db, task.ID, nil)
+}
+
 func (m *Model) handleRequestToViewTLDetails() {
 	if len(m.taskLogList.Items()) == 0 {
 		return
@@ -118,10 +309,16 @@ func (m *Model) handleRequestToViewTLDetails() {
 
 	timeSpentStr := types.HumanizeDuration(tl.SecsSpent)
 
+	var tagDetails string
+	if len(tl.Tags) > 0 {
+		tagDetails = fmt.Sprintf("\nTags: %s\n", strings.Join(tl.Tags, ", "))
+	}
+
 	details := fmt.Sprintf(`Task: %s
 
 %s → %s (%s)
-
+Running total: %s
+%s
 ---
 
 %s
@@ -129,8 +326,80 @@ func (m *Model) handleRequestToViewTLDetails() {
 		tl.BeginTS.Format(timeFormat),
 		tl.EndTS.Format(timeFormat),
 		timeSpentStr,
+		types.HumanizeDuration(m.tlRunningTotal(tl)),
+		tagDetails,
 		tl.GetComment())
 
 	m.tLDetailsVP.SetContent(details)
 	m.activeView = taskLogDetailsView
 }
+
+// tlRunningTotal returns the time spent on tl's task across the entries in the
+// task log list, in chronological order, up to and including tl.
+func (m *Model) tlRunningTotal(tl types.TaskLogEntry) int {
+	var total int
+	for _, item := range m.taskLogList.Items() {
+		entry, ok := item.(types.TaskLogEntry)
+		if !ok || entry.TaskID != tl.TaskID {
+			continue
+		}
+		if entry.BeginTS.Before(tl.BeginTS) || (entry.BeginTS.Equal(tl.BeginTS) && entry.ID <= tl.ID) {
+			total += entry.SecsSpent
+		}
+	}
+	return total
+}
+
+func (m *Model) handleRequestToSearchTLsByComment() {
+	m.activeView = searchTaskLogsView
+	m.tLSearchInput.SetValue(m.taskLogCommentQuery)
+	m.tLSearchInput.CursorEnd()
+	m.tLSearchInput.Focus()
+}
+
+func (m *Model) closeTLSearch() {
+	m.activeView = taskLogView
+	m.tLSearchInput.Blur()
+	m.tLSearchInput.SetValue("")
+}
+
+// getCmdToSearchTLsByComment repopulates the task log list with entries whose
+// comment contains the text in the search input. An empty query brings back
+// the full list.
+func (m *Model) getCmdToSearchTLsByComment() tea.Cmd {
+	query := strings.TrimSpace(m.tLSearchInput.Value())
+	m.closeTLSearch()
+
+	m.taskLogList.ResetSelected()
+	if query == "" {
+		return fetchTLS(m.db, nil)
+	}
+	return fetchTLSMatchingComment(m.db, query, nil)
+}
+
+// getCmdToClearTLCommentSearch brings back the full task log list if it's
+// currently narrowed down by a comment search.
+func (m *Model) getCmdToClearTLCommentSearch() tea.Cmd {
+	if m.taskLogCommentQuery == "" {
+		return nil
+	}
+
+	m.taskLogList.ResetSelected()
+	return fetchTLS(m.db, nil)
+}
+
+func (m *Model) handleCopyTaskLogComment() {
+	entry, ok := m.selectedTaskLogEntry()
+	if !ok {
+		m.message = errMsg("No task log entry selected")
+		return
+	}
+
+	if entry.Comment == nil || *entry.Comment == "" {
+		m.message = errMsg("No comment to copy")
+		return
+	}
+
+	_, _ = osc52.New(*entry.Comment).WriteTo(os.Stderr)
+	m.message = infoMsg("Copied to clipboard")
+}
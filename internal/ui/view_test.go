@@ -195,6 +195,7 @@ func TestUpdateTaskView(t *testing.T) {
 	m.activeView = taskInputView
 	m.taskMgmtContext = taskUpdateCxt
 	m.taskInputs[summaryField].SetValue("a task to be updated")
+	m.taskInputs[createdAtField].SetValue("2025/08/17 09:00")
 
 	// WHEN
 	result := m.View()
@@ -436,7 +437,7 @@ func TestTerminalWidthResizingWorks(t *testing.T) {
 func TestCreateTestModelInitializesTaskSummaryInputWidth(t *testing.T) {
 	m := createTestModel()
 
-	assert.Len(t, m.taskInputs, 1)
+	assert.Len(t, m.taskInputs, 2)
 	assert.Equal(t, textInputWidth, m.taskInputs[summaryField].Width)
 }
 
@@ -480,7 +481,7 @@ func createTestTask(id int, summary string, active bool, trackingActive bool, tp
 	}
 
 	task.UpdateListTitle()
-	task.UpdateListDesc(tp)
+	task.UpdateListDesc(tp, types.DurationFormatFull)
 
 	return task
 }
@@ -500,7 +501,7 @@ func createTestTaskLogEntry(id int, taskID int, taskSummary string, tp types.Tim
 	}
 
 	entry.UpdateListTitle()
-	entry.UpdateListDesc(tp)
+	entry.UpdateListDesc(tp, types.DurationFormatFull)
 
 	return entry
 }
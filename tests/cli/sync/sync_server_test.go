@@ -50,7 +50,7 @@ func TestHoursServerBinarySupportsSeedBootstrapAcrossClients(t *testing.T) {
 	comment := "seed work"
 	beginTS := time.Date(2026, time.March, 4, 9, 0, 0, 0, time.UTC)
 	endTS := beginTS.Add(90 * time.Minute)
-	_, err = pers.InsertManualTL(clientADB, taskID, beginTS, endTS, &comment)
+	_, err = pers.InsertManualTL(clientADB, taskID, beginTS, endTS, &comment, nil, nil, false)
 	require.NoError(t, err)
 
 	require.NoError(t, clientpkg.RunOnce(context.Background(), clientADB, serverURL))
@@ -63,7 +63,7 @@ func TestHoursServerBinarySupportsSeedBootstrapAcrossClients(t *testing.T) {
 
 	secondBeginTS := endTS.Add(15 * time.Minute)
 	secondEndTS := secondBeginTS.Add(30 * time.Minute)
-	_, err = pers.InsertManualTL(clientBDB, clientBTask.LocalID, secondBeginTS, secondEndTS, nil)
+	_, err = pers.InsertManualTL(clientBDB, clientBTask.LocalID, secondBeginTS, secondEndTS, nil, nil, nil, false)
 	require.NoError(t, err)
 
 	require.NoError(t, clientpkg.RunOnce(context.Background(), clientBDB, serverURL))